@@ -0,0 +1,83 @@
+package main
+
+import (
+	"blog-api/internal/config"
+	"blog-api/internal/repositories"
+	"blog-api/internal/services"
+	"blog-api/pkg/metrics"
+	"blog-api/pkg/pubsub"
+	"context"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+// app holds the services the commands use, wired the same way as the HTTP routes.
+type app struct {
+	db  *gorm.DB
+	bus pubsub.Bus
+
+	userRepo        repositories.UserRepository
+	postRepo        repositories.PostRepository
+	authService     *services.AuthService
+	userService     *services.UserService
+	postService     *services.PostService
+	categoryService *services.CategoryService
+}
+
+func newApp(cfg *config.Config, db *gorm.DB) *app {
+	// with PUBSUB_DRIVER=postgres, post events reach the running servers, so changes made
+	// here invalidate their caches too
+	bus := &lazyBus{connect: func() pubsub.Bus { return config.ConnectBus(cfg, db) }}
+
+	userRepo := repositories.NewUserRepository(db)
+	postRepo := repositories.NewPostRepository(db)
+	categoryRepo := repositories.NewCategoryRepository(db)
+	notificationService := services.NewNotificationService(repositories.NewNotificationRepository(db))
+	mentionService := services.NewMentionService(repositories.NewMentionRepository(db), userRepo)
+
+	return &app{
+		db:              db,
+		bus:             bus,
+		userRepo:        userRepo,
+		postRepo:        postRepo,
+		authService:     services.NewAuthService(userRepo, []byte(cfg.JWTSecret), metrics.New()), // the CLI does not export metrics
+		userService:     services.NewUserService(userRepo, notificationService, repositories.NewFollowRepository(db), postRepo, cfg.Site.URL, []byte(cfg.JWTSecret), config.NewMailer(cfg.SMTP)),
+		postService:     services.NewPostService(postRepo, categoryRepo, userRepo, mentionService, notificationService, repositories.NewReactionRepository(db), repositories.NewBookmarkRepository(db), repositories.NewMediaRepository(db), bus),
+		categoryService: services.NewCategoryService(categoryRepo),
+	}
+}
+
+func (a *app) close() {
+	a.bus.Close()
+}
+
+// lazyBus connects to the event bus on the first event, so that the commands publishing
+// none do not open a listener connection.
+type lazyBus struct {
+	connect func() pubsub.Bus
+
+	once sync.Once
+	bus  pubsub.Bus
+}
+
+func (b *lazyBus) get() pubsub.Bus {
+	b.once.Do(func() { b.bus = b.connect() })
+	return b.bus
+}
+
+func (b *lazyBus) Publish(ctx context.Context, topic, eventType string, data interface{}) error {
+	return b.get().Publish(ctx, topic, eventType, data)
+}
+
+func (b *lazyBus) Subscribe(ctx context.Context, topic string, lastEventID uint64) (<-chan pubsub.Event, error) {
+	return b.get().Subscribe(ctx, topic, lastEventID)
+}
+
+// Close closes the bus if it was connected.
+func (b *lazyBus) Close() error {
+	if b.bus == nil {
+		return nil
+	}
+	return b.bus.Close()
+}
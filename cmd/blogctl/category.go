@@ -0,0 +1,25 @@
+package main
+
+import (
+	"blog-api/internal/dto"
+	"blog-api/pkg/utils"
+	"fmt"
+)
+
+const categoryMergeUsage = "category merge FROM_SLUG INTO_SLUG"
+
+func categoryMerge(a *app, args []string) error {
+	if len(args) != 2 {
+		return errUsage(categoryMergeUsage)
+	}
+	req := dto.MergeCategoriesRequest{From: args[0], Into: args[1]}
+	if errs := utils.ValidateStruct(&req); errs != nil {
+		return validationError(errs)
+	}
+	moved, err := a.categoryService.MergeCategories(&req)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Merged %s into %s, moving %d posts\n", req.From, req.Into, moved)
+	return nil
+}
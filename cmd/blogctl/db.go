@@ -0,0 +1,50 @@
+package main
+
+import (
+	"blog-api/internal/config"
+	"blog-api/internal/seed"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+func migrateCommand(a *app, args []string) error {
+	migrator, err := config.NewMigrator(a.db)
+	if err != nil {
+		return err
+	}
+	return migrator.Command(context.Background(), args, os.Stdout)
+}
+
+// seedCommand fills the database with generated data; running it again only adds what is
+// missing, so a larger preset can be applied on top of a smaller one.
+func seedCommand(a *app, args []string) error {
+	presets := make([]string, 0, len(seed.Presets))
+	for name := range seed.Presets {
+		presets = append(presets, name)
+	}
+	sort.Strings(presets)
+	usage := errUsage("seed [--preset " + strings.Join(presets, "|") + "]")
+
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	name := fs.String("preset", "small", "")
+	if rest, err := parseFlags(fs, args); err != nil || len(rest) != 0 {
+		return usage
+	}
+	preset, ok := seed.Presets[*name]
+	if !ok {
+		return usage
+	}
+
+	fmt.Printf("Seeding the %s data set...\n", *name)
+	result, err := seed.NewSeeder(a.db, os.Stdout).Run(preset)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created %d users, %d categories, %d posts, %d comments\n",
+		result.Users, result.Categories, result.Posts, result.Comments)
+	return nil
+}
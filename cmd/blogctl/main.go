@@ -0,0 +1,124 @@
+// Command blogctl runs operations tasks against the blog database, going through the same
+// services and request validation as the HTTP API.
+//
+//	blogctl user create --username NAME --email EMAIL [--password PASS] [--admin]
+//	blogctl user set-role USERNAME admin|client
+//	blogctl user ban USERNAME [--lift]
+//	blogctl post publish POST_ID [--as USERNAME]
+//	blogctl category merge FROM_SLUG INTO_SLUG
+//	blogctl migrate up | down [n] | status
+//	blogctl seed [--preset small|medium|large]
+//
+// It loads the same configuration (.env, CONFIG_FILE, DB_*, SITE_URL, PUBSUB_DRIVER...) as the
+// server, except JWT_SECRET: none of the commands issues tokens.
+package main
+
+import (
+	"blog-api/internal/config"
+	"blog-api/pkg/migrate"
+	"blog-api/pkg/utils"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// errUsage reports a malformed command line; the message is the usage of the command.
+type errUsage string
+
+func (e errUsage) Error() string { return "usage: blogctl " + string(e) }
+
+type command func(app *app, args []string) error
+
+var commands = map[string]map[string]command{
+	"user": {
+		"create":   userCreate,
+		"set-role": userSetRole,
+		"ban":      userBan,
+	},
+	"post": {
+		"publish": postPublish,
+	},
+	"category": {
+		"merge": categoryMerge,
+	},
+}
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		var usage errUsage
+		if errors.As(err, &usage) || errors.Is(err, migrate.ErrUsage) {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		return errUsage("<" + strings.Join(groups(), "|") + "> ...")
+	}
+
+	var cmd command
+	switch args[0] {
+	case "migrate":
+		cmd, args = migrateCommand, args[1:]
+	case "seed":
+		cmd, args = seedCommand, args[1:]
+	default:
+		group, ok := commands[args[0]]
+		if !ok {
+			return errUsage("<" + strings.Join(groups(), "|") + "> ...")
+		}
+		if len(args) < 2 || group[args[1]] == nil {
+			return errUsage(args[0] + " <" + strings.Join(names(group), "|") + "> ...")
+		}
+		cmd, args = group[args[1]], args[2:]
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return err
+	}
+	db := config.ConnectDB(cfg.DB)
+	if err := utils.RegisterValidators(); err != nil {
+		return err
+	}
+	app := newApp(cfg, db)
+	defer app.close()
+	return cmd(app, args)
+}
+
+func groups() []string {
+	list := []string{"migrate", "seed"}
+	for name := range commands {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
+
+func names(group map[string]command) []string {
+	list := make([]string, 0, len(group))
+	for name := range group {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// validationError turns the field errors of a DTO into one error.
+func validationError(errs map[string]string) error {
+	fields := make([]string, 0, len(errs))
+	for field := range errs {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	msgs := make([]string, 0, len(fields))
+	for _, field := range fields {
+		msgs = append(msgs, field+": "+errs[field])
+	}
+	return errors.New("invalid input: " + strings.Join(msgs, "; "))
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"blog-api/internal/dto"
+	"blog-api/pkg/utils"
+	"flag"
+	"fmt"
+	"strconv"
+)
+
+const postPublishUsage = "post publish POST_ID [--as USERNAME]"
+
+// postPublish publishes a draft. With --as, the post is published on behalf of that user,
+// and the author is notified of the approval as when an admin publishes it over the API.
+func postPublish(a *app, args []string) error {
+	fs := flag.NewFlagSet("post publish", flag.ContinueOnError)
+	as := fs.String("as", "", "")
+	rest, err := parseFlags(fs, args)
+	if err != nil || len(rest) != 1 {
+		return errUsage(postPublishUsage)
+	}
+	id, err := strconv.ParseUint(rest[0], 10, 64)
+	if err != nil {
+		return errUsage(postPublishUsage)
+	}
+	post, err := a.postService.GetPostByID(uint(id))
+	if err != nil {
+		return fmt.Errorf("post %d: %w", id, err)
+	}
+	actorID := post.AuthorID
+	if *as != "" {
+		actor, err := a.findUser(*as)
+		if err != nil {
+			return err
+		}
+		actorID = uint(actor.ID)
+	}
+
+	status := "published"
+	req := dto.UpdatePostRequest{Status: &status}
+	if errs := utils.ValidateStruct(&req); errs != nil {
+		return validationError(errs)
+	}
+	if err := a.postService.UpdatePost(post.ID, &req, actorID); err != nil {
+		return err
+	}
+	fmt.Printf("Published post %d (%s)\n", post.ID, post.Slug)
+	return nil
+}
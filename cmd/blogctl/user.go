@@ -0,0 +1,106 @@
+package main
+
+import (
+	"blog-api/internal/dto"
+	"blog-api/internal/entities"
+	"blog-api/pkg/utils"
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	userCreateUsage  = "user create --username NAME --email EMAIL [--password PASS] [--admin]"
+	userSetRoleUsage = "user set-role USERNAME admin|client"
+	userBanUsage     = "user ban USERNAME [--lift]"
+)
+
+// userCreate registers an account. Without --password the password is read from the first
+// line of standard input, so it stays out of the shell history.
+func userCreate(a *app, args []string) error {
+	fs := flag.NewFlagSet("user create", flag.ContinueOnError)
+	username := fs.String("username", "", "")
+	email := fs.String("email", "", "")
+	password := fs.String("password", "", "")
+	admin := fs.Bool("admin", false, "")
+	if rest, err := parseFlags(fs, args); err != nil || len(rest) > 0 {
+		return errUsage(userCreateUsage)
+	}
+	if *password == "" {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("read password from stdin: %w", err)
+		}
+		*password = strings.TrimRight(line, "\r\n")
+	}
+
+	req := dto.UserRegisterRequest{Username: *username, Email: *email, Password: *password}
+	if errs := utils.ValidateStruct(&req); errs != nil {
+		return validationError(errs)
+	}
+	role := "client"
+	if *admin {
+		role = "admin"
+	}
+	user, err := a.authService.CreateUser(req.Email, req.Password, req.Username, role)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Created %s %s (id %d)\n", user.Role, user.Username, user.ID)
+	return nil
+}
+
+func userSetRole(a *app, args []string) error {
+	if len(args) != 2 {
+		return errUsage(userSetRoleUsage)
+	}
+	req := dto.ChangeUserRole{Role: args[1]}
+	if errs := utils.ValidateStruct(&req); errs != nil {
+		return validationError(errs)
+	}
+	user, err := a.findUser(args[0])
+	if err != nil {
+		return err
+	}
+	if err := a.userService.ChangeUserRole(uint(user.ID), req.Role); err != nil {
+		return err
+	}
+	fmt.Printf("%s is now %s\n", user.Username, req.Role)
+	return nil
+}
+
+// userBan blocks a user from posting, the same restriction admins apply over the API.
+func userBan(a *app, args []string) error {
+	fs := flag.NewFlagSet("user ban", flag.ContinueOnError)
+	lift := fs.Bool("lift", false, "")
+	rest, err := parseFlags(fs, args)
+	if err != nil || len(rest) != 1 {
+		return errUsage(userBanUsage)
+	}
+	user, err := a.findUser(rest[0])
+	if err != nil {
+		return err
+	}
+	if err := a.userService.UpdateCanPost(uint(user.ID), *lift); err != nil {
+		return err
+	}
+	if *lift {
+		fmt.Printf("%s may post again\n", user.Username)
+	} else {
+		fmt.Printf("%s is blocked from posting\n", user.Username)
+	}
+	return nil
+}
+
+func (a *app) findUser(username string) (*entities.User, error) {
+	user, err := a.userRepo.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, fmt.Errorf("user %s not found", username)
+	}
+	return user, nil
+}
@@ -10,91 +10,88 @@
 package main
 
 import (
-	_ "blog-api/docs"
 	"blog-api/internal/config"
-	"blog-api/internal/routes"
+	"blog-api/internal/server"
+	"blog-api/pkg/metrics"
+	"blog-api/pkg/tracing"
+	"context"
 	"log"
-	"os"
-	"regexp"
-
-	"github.com/gin-contrib/cors"
-	"github.com/gin-gonic/gin"
-	"github.com/gin-gonic/gin/binding"
-	"github.com/go-playground/validator/v10"
-	"github.com/joho/godotenv"
-	"github.com/swaggo/files"
-	"github.com/swaggo/gin-swagger"
+	"os/signal"
+	"syscall"
 )
 
-func init() {
-	if os.Getenv("RENDER") == "" {
-		if err := godotenv.Load(); err != nil {
-			log.Println("No .env file found (this is OK in production)")
-		}
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
 	}
-}
+	if err := cfg.ValidateServer(); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Config: %+v", *cfg)
 
-func SlugValidator(fl validator.FieldLevel) bool {
-	value := fl.Field().String()
-	matched, _ := regexp.MatchString(`^[a-z0-9]+(?:-[a-z0-9]+)*$`, value)
-	return matched
-}
+	db := config.ConnectDB(cfg.DB)
+	config.InitDB(cfg.DB, db)
 
-func UsernameValidator(fl validator.FieldLevel) bool {
-	value := fl.Field().String()
-	matched, _ := regexp.MatchString(`^[a-zA-Z0-9_-]+$`, value)
-	return matched
-}
+	bus := config.ConnectBus(cfg, db)
 
-func StrongPasswordValidator(fl validator.FieldLevel) bool {
-	value := fl.Field().String()
-	if len(value) < 6 {
-		return false
+	m := metrics.New()
+	if err := m.InstrumentDB(db); err != nil {
+		log.Fatal("Instrument database failed: ", err)
+	}
+	if err := tracing.InstrumentDB(db); err != nil {
+		log.Fatal("Instrument database failed: ", err)
+	}
+	tracer, flushSpans, err := config.NewTracerProvider(context.Background(), cfg.Tracing)
+	if err != nil {
+		log.Fatal("Create tracer provider failed: ", err)
 	}
-	hasLower := regexp.MustCompile(`[a-z]`).MatchString(value)
-	hasUpper := regexp.MustCompile(`[A-Z]`).MatchString(value)
-	hasDigit := regexp.MustCompile(`\d`).MatchString(value)
-	hasSpecial := regexp.MustCompile(`[^a-zA-Z0-9]`).MatchString(value)
-	return hasLower && hasUpper && hasDigit && hasSpecial
-}
-
-func main() {
-	config.LoadEnv()
-	config.ConnectDB()
-	config.InitDB()
-
-	r := gin.Default()
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-	// r.Use(cors.Default())
-	r.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:4200"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
-	}))
 
-	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
-		validations := map[string]validator.Func{
-			"slug":     SlugValidator,
-			"username": UsernameValidator,
-			"strong":   StrongPasswordValidator,
+	srv, err := server.NewServer(server.Config{
+		App:     cfg,
+		Bus:     bus,
+		Storage: config.ConnectStorage(cfg),
+		Metrics: m,
+		Tracer:  tracer,
+	}, db)
+	if err != nil {
+		log.Fatal("Build server failed: ", err)
+	}
+	srv.OnShutdown("event bus", func(context.Context) error { return bus.Close() })
+	srv.OnShutdown("tracer provider", flushSpans)
+	srv.OnShutdown("database", func(context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
 		}
+		return sqlDB.Close()
+	})
+	srv.Start()
 
-		for tag, fn := range validations {
-			if err := v.RegisterValidation(tag, fn); err != nil {
-				return
-			}
-		}
+	stop, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	serveErr := make(chan error, 2)
+	go func() { serveErr <- srv.ListenAndServe() }()
+	log.Println("Listening on", srv.HTTP.Addr)
+	if srv.MetricsHTTP != nil {
+		go func() { serveErr <- srv.ListenAndServeMetrics() }()
+		log.Println("Serving metrics on", srv.MetricsHTTP.Addr)
 	}
 
-	routes.SetupUserRoutes(r, config.DB)
-	routes.SetupCategoryRoutes(r, config.DB)
-	routes.SetupPostRoutes(r, config.DB)
-	routes.SetupCommentRoutes(r, config.DB)
+	select {
+	case err := <-serveErr:
+		if err != nil {
+			log.Println("Serve failed:", err)
+		}
+	case <-stop.Done():
+		log.Println("Stop signal received, shutting down")
+	}
+	cancel() // a second signal kills the process
 
-	err := r.Run(":" + os.Getenv("PORT"))
-	if err != nil {
-		return
+	ctx, cancelShutdown := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
+	defer cancelShutdown()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Fatal("Shutdown incomplete: ", err)
 	}
+	log.Println("Shutdown complete")
 }
@@ -0,0 +1,34 @@
+// Command migrate applies or rolls back the database migrations.
+//
+//	go run ./cmd/migrate up          apply every pending migration
+//	go run ./cmd/migrate down [n]    roll back the last n migrations (default 1)
+//	go run ./cmd/migrate status      list migrations and when they were applied
+package main
+
+import (
+	"blog-api/internal/config"
+	"blog-api/pkg/migrate"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+)
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	migrator, err := config.NewMigrator(config.ConnectDB(cfg.DB))
+	if err != nil {
+		log.Fatal("Load migrations failed: ", err)
+	}
+	if err := migrator.Command(context.Background(), os.Args[1:], os.Stdout); err != nil {
+		if errors.Is(err, migrate.ErrUsage) {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		log.Fatal(err)
+	}
+}
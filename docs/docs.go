@@ -197,6 +197,72 @@ const docTemplate = `{
                 }
             }
         },
+        "/admin/posts/top": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Báo cáo các bài viết có nhiều lượt xem nhất toàn site trong khoảng thời gian gần nhất",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Bài viết được xem nhiều nhất (admin)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Số ngày gần nhất (mặc định 30, tối đa 365)",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Số bài viết (mặc định 10, tối đa 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Danh sách bài viết",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dto.TopPostResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Tham số không hợp lệ",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Không có quyền",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/admin/users": {
             "get": {
                 "security": [
@@ -458,95 +524,83 @@ const docTemplate = `{
                 }
             }
         },
-        "/comments/{comment_id}": {
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Cập nhật nội dung bình luận (yêu cầu đăng nhập, chỉ chủ sở hữu mới được sửa)",
-                "consumes": [
-                    "application/json"
-                ],
+        "/categories/{slug}/feed.atom": {
+            "get": {
+                "description": "Feed RSS 2.0, Atom 1.0 hoặc JSON Feed 1.1 của các bài viết mới nhất trong một danh mục",
                 "produces": [
+                    "text/xml",
                     "application/json"
                 ],
                 "tags": [
-                    "comments"
+                    "feeds"
                 ],
-                "summary": "Cập nhật bình luận",
+                "summary": "Feed bài viết theo danh mục",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID bình luận",
-                        "name": "comment_id",
+                        "type": "string",
+                        "description": "Slug danh mục",
+                        "name": "slug",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Nội dung cập nhật",
-                        "name": "comment",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UpdateCommentRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Cập nhật thành công",
+                        "description": "Feed",
                         "schema": {
-                            "$ref": "#/definitions/utils.APIResponse"
+                            "type": "string"
                         }
                     },
-                    "400": {
-                        "description": "Lỗi xác thực",
+                    "304": {
+                        "description": "Feed không thay đổi",
                         "schema": {
-                            "$ref": "#/definitions/utils.APIResponse"
+                            "type": "string"
                         }
                     },
                     "404": {
-                        "description": "Không tìm thấy bình luận",
+                        "description": "Không tìm thấy danh mục",
                         "schema": {
                             "$ref": "#/definitions/utils.APIResponse"
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Xóa bình luận (yêu cầu đăng nhập, chỉ chủ sở hữu bình luận hoặc chủ bài viết mới được xóa)",
+            }
+        },
+        "/categories/{slug}/feed.json": {
+            "get": {
+                "description": "Feed RSS 2.0, Atom 1.0 hoặc JSON Feed 1.1 của các bài viết mới nhất trong một danh mục",
                 "produces": [
+                    "text/xml",
                     "application/json"
                 ],
                 "tags": [
-                    "comments"
+                    "feeds"
                 ],
-                "summary": "Xóa bình luận",
+                "summary": "Feed bài viết theo danh mục",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID bình luận",
-                        "name": "comment_id",
+                        "type": "string",
+                        "description": "Slug danh mục",
+                        "name": "slug",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Xóa thành công",
+                        "description": "Feed",
                         "schema": {
-                            "$ref": "#/definitions/utils.APIResponse"
+                            "type": "string"
+                        }
+                    },
+                    "304": {
+                        "description": "Feed không thay đổi",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "404": {
-                        "description": "Không tìm thấy bình luận",
+                        "description": "Không tìm thấy danh mục",
                         "schema": {
                             "$ref": "#/definitions/utils.APIResponse"
                         }
@@ -554,112 +608,119 @@ const docTemplate = `{
                 }
             }
         },
-        "/posts": {
+        "/categories/{slug}/feed.rss": {
             "get": {
-                "description": "Lấy danh sách bài viết, có thể lọc theo tiêu đề, nội dung, danh mục, tác giả, phân trang",
+                "description": "Feed RSS 2.0, Atom 1.0 hoặc JSON Feed 1.1 của các bài viết mới nhất trong một danh mục",
                 "produces": [
+                    "text/xml",
                     "application/json"
                 ],
                 "tags": [
-                    "posts"
+                    "feeds"
                 ],
-                "summary": "Lấy danh sách bài viết",
+                "summary": "Feed bài viết theo danh mục",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Lọc theo tiêu đề",
-                        "name": "title",
-                        "in": "query"
+                        "description": "Slug danh mục",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Feed",
+                        "schema": {
+                            "type": "string"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "description": "Lọc theo nội dung",
-                        "name": "content",
-                        "in": "query"
+                    "304": {
+                        "description": "Feed không thay đổi",
+                        "schema": {
+                            "type": "string"
+                        }
                     },
+                    "404": {
+                        "description": "Không tìm thấy danh mục",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/categories/{slug}/follow": {
+            "post": {
+                "security": [
                     {
-                        "type": "string",
-                        "description": "Lọc theo danh mục",
-                        "name": "category",
-                        "in": "query"
-                    },
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "User hiện tại theo dõi một danh mục, bài viết trong danh mục sẽ xuất hiện trong feed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "follows"
+                ],
+                "summary": "Theo dõi danh mục",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "Lọc theo tác giả",
-                        "name": "author",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Trang hiện tại",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Số lượng mỗi trang",
-                        "name": "page_size",
-                        "in": "query"
+                        "description": "Slug danh mục",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Danh sách bài viết và meta",
+                        "description": "Đã theo dõi",
                         "schema": {
                             "$ref": "#/definitions/utils.APIResponse"
                         }
                     },
-                    "500": {
-                        "description": "Lỗi server",
+                    "404": {
+                        "description": "Không tìm thấy danh mục",
                         "schema": {
                             "$ref": "#/definitions/utils.APIResponse"
                         }
                     }
                 }
             },
-            "post": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Tạo một bài viết mới, yêu cầu đăng nhập",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "User hiện tại bỏ theo dõi một danh mục",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "posts"
+                    "follows"
                 ],
-                "summary": "Tạo bài viết mới",
+                "summary": "Bỏ theo dõi danh mục",
                 "parameters": [
                     {
-                        "description": "Thông tin bài viết",
-                        "name": "post",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.CreatePostRequest"
-                        }
+                        "type": "string",
+                        "description": "Slug danh mục",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Tạo bài viết thành công",
-                        "schema": {
-                            "$ref": "#/definitions/utils.APIResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Lỗi xác thực hoặc dữ liệu không hợp lệ",
+                    "200": {
+                        "description": "Đã bỏ theo dõi",
                         "schema": {
                             "$ref": "#/definitions/utils.APIResponse"
                         }
                     },
-                    "500": {
-                        "description": "Lỗi server",
+                    "404": {
+                        "description": "Không tìm thấy danh mục hoặc chưa theo dõi",
                         "schema": {
                             "$ref": "#/definitions/utils.APIResponse"
                         }
@@ -667,14 +728,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/posts/{id}": {
+        "/comments/{comment_id}": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Cập nhật bài viết, chỉ chủ sở hữu hoặc admin mới được phép",
+                "description": "Cập nhật nội dung bình luận (yêu cầu đăng nhập, chỉ chủ sở hữu mới được sửa)",
                 "consumes": [
                     "application/json"
                 ],
@@ -682,24 +743,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "posts"
+                    "comments"
                 ],
-                "summary": "Cập nhật bài viết",
+                "summary": "Cập nhật bình luận",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID bài viết",
-                        "name": "id",
+                        "description": "ID bình luận",
+                        "name": "comment_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Thông tin cập nhật bài viết",
-                        "name": "post",
+                        "description": "Nội dung cập nhật",
+                        "name": "comment",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UpdatePostRequest"
+                            "$ref": "#/definitions/dto.UpdateCommentRequest"
                         }
                     }
                 ],
@@ -711,7 +772,25 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Lỗi xác thực hoặc không tìm thấy bài viết",
+                        "description": "Lỗi xác thực",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bài viết đã khóa bình luận",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Không tìm thấy bình luận hoặc bài viết",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Lỗi server",
                         "schema": {
                             "$ref": "#/definitions/utils.APIResponse"
                         }
@@ -724,19 +803,19 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Xóa bài viết, chỉ chủ sở hữu hoặc admin mới được phép",
+                "description": "Xóa bình luận (yêu cầu đăng nhập, chỉ chủ sở hữu bình luận hoặc chủ bài viết mới được xóa)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "posts"
+                    "comments"
                 ],
-                "summary": "Xóa bài viết",
+                "summary": "Xóa bình luận",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID bài viết",
-                        "name": "id",
+                        "description": "ID bình luận",
+                        "name": "comment_id",
                         "in": "path",
                         "required": true
                     }
@@ -749,7 +828,13 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Không tìm thấy bài viết",
+                        "description": "Không tìm thấy bình luận",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Lỗi server",
                         "schema": {
                             "$ref": "#/definitions/utils.APIResponse"
                         }
@@ -757,34 +842,57 @@ const docTemplate = `{
                 }
             }
         },
-        "/posts/{post_id}": {
-            "get": {
-                "description": "Lấy chi tiết một bài viết theo ID",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "posts"
-                ],
-                "summary": "Lấy chi tiết bài viết",
+        "/comments/{comment_id}/reactions": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Thêm, đổi hoặc bỏ cảm xúc của user hiện tại với bình luận (gửi lại cùng loại để bỏ)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reactions"
+                ],
+                "summary": "Thả cảm xúc cho bình luận",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "ID bài viết",
-                        "name": "post_id",
+                        "description": "ID bình luận",
+                        "name": "comment_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Loại cảm xúc",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReactRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Chi tiết bài viết",
+                        "description": "Cảm xúc hiện tại và số lượng theo loại",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Loại cảm xúc không hợp lệ",
                         "schema": {
                             "$ref": "#/definitions/utils.APIResponse"
                         }
                     },
                     "404": {
-                        "description": "Không tìm thấy bài viết",
+                        "description": "Không tìm thấy bình luận",
                         "schema": {
                             "$ref": "#/definitions/utils.APIResponse"
                         }
@@ -792,102 +900,161 @@ const docTemplate = `{
                 }
             }
         },
-        "/posts/{post_id}/comments": {
+        "/feed.atom": {
             "get": {
-                "description": "Lấy danh sách bình luận theo bài viết, có phân trang",
+                "description": "Feed RSS 2.0, Atom 1.0 hoặc JSON Feed 1.1 của các bài viết đã xuất bản mới nhất. Hỗ trợ ETag/If-None-Match và Last-Modified/If-Modified-Since.",
                 "produces": [
+                    "text/xml",
                     "application/json"
                 ],
                 "tags": [
-                    "comments"
+                    "feeds"
                 ],
-                "summary": "Lấy danh sách bình luận của bài viết",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "ID bài viết",
-                        "name": "post_id",
-                        "in": "path",
-                        "required": true
+                "summary": "Feed bài viết mới nhất",
+                "responses": {
+                    "200": {
+                        "description": "Feed",
+                        "schema": {
+                            "type": "string"
+                        }
                     },
-                    {
-                        "type": "integer",
-                        "description": "Trang hiện tại",
-                        "name": "page",
-                        "in": "query"
+                    "304": {
+                        "description": "Feed không thay đổi",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/feed.json": {
+            "get": {
+                "description": "Feed RSS 2.0, Atom 1.0 hoặc JSON Feed 1.1 của các bài viết đã xuất bản mới nhất. Hỗ trợ ETag/If-None-Match và Last-Modified/If-Modified-Since.",
+                "produces": [
+                    "text/xml",
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Feed bài viết mới nhất",
+                "responses": {
+                    "200": {
+                        "description": "Feed",
+                        "schema": {
+                            "type": "string"
+                        }
                     },
-                    {
-                        "type": "integer",
-                        "description": "Số lượng mỗi trang",
-                        "name": "page_size",
-                        "in": "query"
+                    "304": {
+                        "description": "Feed không thay đổi",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
+                }
+            }
+        },
+        "/feed.rss": {
+            "get": {
+                "description": "Feed RSS 2.0, Atom 1.0 hoặc JSON Feed 1.1 của các bài viết đã xuất bản mới nhất. Hỗ trợ ETag/If-None-Match và Last-Modified/If-Modified-Since.",
+                "produces": [
+                    "text/xml",
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
                 ],
+                "summary": "Feed bài viết mới nhất",
                 "responses": {
                     "200": {
-                        "description": "Danh sách bình luận",
+                        "description": "Feed",
                         "schema": {
-                            "$ref": "#/definitions/utils.APIResponse"
+                            "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Lỗi server",
+                    "304": {
+                        "description": "Feed không thay đổi",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Luôn trả về 200 khi tiến trình còn phục vụ được request; không kiểm tra các phụ thuộc",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Kiểm tra sống (liveness)",
+                "responses": {
+                    "200": {
+                        "description": "Dịch vụ đang chạy",
                         "schema": {
                             "$ref": "#/definitions/utils.APIResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/media": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Tạo bình luận cho một bài viết (yêu cầu đăng nhập)",
+                "description": "Tải một ảnh (JPEG, PNG, GIF, WebP) vào thư viện của user hiện tại. Loại file được nhận diện từ nội dung, metadata (EXIF, GPS...) bị xóa, chỉ giữ lại hướng xoay của ảnh JPEG. Dùng id trả về làm thumbnail_media_id của bài viết.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "comments"
+                    "media"
                 ],
-                "summary": "Tạo bình luận mới",
+                "summary": "Tải ảnh lên thư viện",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID bài viết",
-                        "name": "post_id",
-                        "in": "path",
+                        "type": "file",
+                        "description": "File ảnh",
+                        "name": "file",
+                        "in": "formData",
                         "required": true
-                    },
-                    {
-                        "description": "Nội dung bình luận",
-                        "name": "comment",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.CreateCommentRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Tạo bình luận thành công",
-                        "schema": {
-                            "$ref": "#/definitions/utils.APIResponse"
+                        "description": "Tải lên thành công",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.MediaResponse"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
-                        "description": "Lỗi xác thực hoặc dữ liệu không hợp lệ",
+                        "description": "Thiếu file, định dạng không hỗ trợ hoặc ảnh quá nhiều điểm ảnh",
                         "schema": {
                             "$ref": "#/definitions/utils.APIResponse"
                         }
                     },
-                    "500": {
-                        "description": "Lỗi server",
+                    "413": {
+                        "description": "File quá lớn",
                         "schema": {
                             "$ref": "#/definitions/utils.APIResponse"
                         }
@@ -895,54 +1062,128 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/change-password": {
-            "put": {
+        "/media/{id}": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Đổi mật khẩu cho user hiện tại",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Lấy một ảnh trong thư viện của user hiện tại, kèm trạng thái xử lý (pending, processing, ready, failed), lỗi nếu có và các biến thể kích thước",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "media"
                 ],
-                "summary": "Đổi mật khẩu",
+                "summary": "Lấy chi tiết ảnh",
                 "parameters": [
                     {
-                        "description": "Thông tin đổi mật khẩu",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
+                        "type": "integer",
+                        "description": "ID ảnh",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Chi tiết ảnh",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.MediaResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Không tìm thấy ảnh",
                         "schema": {
-                            "$ref": "#/definitions/dto.ChangePasswordRequest"
+                            "$ref": "#/definitions/utils.APIResponse"
                         }
                     }
+                }
+            }
+        },
+        "/posts": {
+            "get": {
+                "description": "Lấy danh sách bài viết, có thể lọc theo tiêu đề, nội dung, danh mục, tác giả, phân trang",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Lấy danh sách bài viết",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Lọc theo tiêu đề",
+                        "name": "title",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Lọc theo nội dung",
+                        "name": "content",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Lọc theo danh mục",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Lọc theo tác giả",
+                        "name": "author",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Trang hiện tại",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Số lượng mỗi trang",
+                        "name": "page_size",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Đổi mật khẩu thành công",
+                        "description": "Danh sách bài viết và meta",
                         "schema": {
                             "$ref": "#/definitions/utils.APIResponse"
                         }
                     },
-                    "400": {
-                        "description": "Lỗi xác thực hoặc mật khẩu cũ sai",
+                    "500": {
+                        "description": "Lỗi server",
                         "schema": {
                             "$ref": "#/definitions/utils.APIResponse"
                         }
                     }
                 }
-            }
-        },
-        "/users/login": {
+            },
             "post": {
-                "description": "Đăng nhập với email và password",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Tạo một bài viết mới, yêu cầu đăng nhập",
                 "consumes": [
                     "application/json"
                 ],
@@ -950,36 +1191,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "posts"
                 ],
-                "summary": "Đăng nhập",
+                "summary": "Tạo bài viết mới",
                 "parameters": [
                     {
-                        "description": "Thông tin đăng nhập",
-                        "name": "user",
+                        "description": "Thông tin bài viết",
+                        "name": "post",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UserLoginRequest"
+                            "$ref": "#/definitions/dto.CreatePostRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Đăng nhập thành công, trả về token và thông tin user",
+                    "201": {
+                        "description": "Tạo bài viết thành công",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/utils.APIResponse"
                         }
                     },
                     "400": {
-                        "description": "Lỗi xác thực",
+                        "description": "Lỗi xác thực hoặc dữ liệu không hợp lệ",
                         "schema": {
                             "$ref": "#/definitions/utils.APIResponse"
                         }
                     },
-                    "401": {
-                        "description": "Sai thông tin đăng nhập",
+                    "500": {
+                        "description": "Lỗi server",
                         "schema": {
                             "$ref": "#/definitions/utils.APIResponse"
                         }
@@ -987,31 +1227,51 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/me": {
-            "get": {
+        "/posts/{id}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Lấy thông tin user từ token",
+                "description": "Cập nhật bài viết, chỉ chủ sở hữu hoặc admin mới được phép",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "posts"
+                ],
+                "summary": "Cập nhật bài viết",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID bài viết",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Thông tin cập nhật bài viết",
+                        "name": "post",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdatePostRequest"
+                        }
+                    }
                 ],
-                "summary": "Lấy thông tin người dùng hiện tại",
                 "responses": {
                     "200": {
-                        "description": "Thông tin user",
+                        "description": "Cập nhật thành công",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/utils.APIResponse"
                         }
                     },
-                    "404": {
-                        "description": "Không tìm thấy user",
+                    "400": {
+                        "description": "Lỗi xác thực hoặc không tìm thấy bài viết",
                         "schema": {
                             "$ref": "#/definitions/utils.APIResponse"
                         }
@@ -1024,14 +1284,23 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Xóa user hiện tại (self-delete)",
+                "description": "Xóa bài viết, chỉ chủ sở hữu hoặc admin mới được phép",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "posts"
+                ],
+                "summary": "Xóa bài viết",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID bài viết",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Xóa tài khoản của chính mình",
                 "responses": {
                     "200": {
                         "description": "Xóa thành công",
@@ -1040,7 +1309,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Không tìm thấy user",
+                        "description": "Không tìm thấy bài viết",
                         "schema": {
                             "$ref": "#/definitions/utils.APIResponse"
                         }
@@ -1048,9 +1317,54 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/register": {
-            "post": {
-                "description": "Đăng ký tài khoản với email, password và username",
+        "/posts/{id}/bookmark": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Xóa bài viết khỏi danh sách đọc của user hiện tại",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookmarks"
+                ],
+                "summary": "Bỏ lưu bài viết",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID bài viết",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Đã bỏ lưu",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Bài viết chưa được lưu",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{id}/comments/lock": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Khóa hoặc mở lại phần bình luận của bài viết, chỉ chủ sở hữu hoặc admin mới được phép",
                 "consumes": [
                     "application/json"
                 ],
@@ -1058,143 +1372,2114 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "posts"
                 ],
-                "summary": "Đăng ký người dùng mới",
+                "summary": "Khóa/mở khóa bình luận bài viết",
                 "parameters": [
                     {
-                        "description": "Thông tin đăng ký",
-                        "name": "user",
+                        "type": "integer",
+                        "description": "ID bài viết",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Trạng thái khóa bình luận",
+                        "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UserRegisterRequest"
+                            "$ref": "#/definitions/dto.LockCommentsRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Đăng ký thành công",
+                    "200": {
+                        "description": "Cập nhật thành công",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/utils.APIResponse"
                         }
                     },
                     "400": {
-                        "description": "Lỗi xác thực hoặc đăng ký",
+                        "description": "Lỗi xác thực",
                         "schema": {
                             "$ref": "#/definitions/utils.APIResponse"
                         }
                     },
-                    "409": {
-                        "description": "Email đã tồn tại",
+                    "404": {
+                        "description": "Không tìm thấy bài viết",
                         "schema": {
                             "$ref": "#/definitions/utils.APIResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dto.ChangePasswordRequest": {
+        },
+        "/posts/{post_id}": {
+            "get": {
+                "description": "Lấy chi tiết một bài viết theo ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Lấy chi tiết bài viết",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID bài viết",
+                        "name": "post_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Chi tiết bài viết",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Không tìm thấy bài viết",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{post_id}/bookmark": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lưu bài viết đã xuất bản vào danh sách đọc của user hiện tại, có thể chọn bộ sưu tập (lưu lại để chuyển bộ sưu tập)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookmarks"
+                ],
+                "summary": "Lưu bài viết vào danh sách đọc",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID bài viết",
+                        "name": "post_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tên bộ sưu tập",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BookmarkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Đã lưu bài viết",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Lỗi xác thực",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Không tìm thấy bài viết",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{post_id}/comments": {
+            "get": {
+                "description": "Lấy danh sách bình luận theo bài viết, có phân trang",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Lấy danh sách bình luận của bài viết",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID bài viết",
+                        "name": "post_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Trang hiện tại",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Số lượng mỗi trang",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Danh sách bình luận",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Lỗi server",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Tạo bình luận cho một bài viết (yêu cầu đăng nhập)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Tạo bình luận mới",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID bài viết",
+                        "name": "post_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Nội dung bình luận",
+                        "name": "comment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateCommentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Tạo bình luận thành công",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Lỗi xác thực hoặc dữ liệu không hợp lệ",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Bài viết đã khóa bình luận",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Không tìm thấy bài viết",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Lỗi server",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{post_id}/comments/stream": {
+            "get": {
+                "description": "Stream Server-Sent Events các sự kiện comment.created, comment.updated, comment.deleted của bài viết. Gửi header Last-Event-ID để tiếp tục từ sự kiện cuối cùng đã nhận. Nếu không thể gửi lại mọi sự kiện đã bỏ lỡ, server gửi sự kiện reset rồi đóng luồng; client cần tải lại bình luận.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "comments"
+                ],
+                "summary": "Theo dõi bình luận theo thời gian thực",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID bài viết",
+                        "name": "post_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID sự kiện cuối cùng đã nhận",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Luồng sự kiện",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Last-Event-ID không hợp lệ",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Không tìm thấy bài viết",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{post_id}/meta": {
+            "get": {
+                "description": "Trả về thẻ OpenGraph, Twitter card và JSON-LD BlogPosting của một bài viết đã xuất bản, kèm bản HTML sẵn để chèn vào \u003chead\u003e",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Lấy metadata SEO của bài viết",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID bài viết",
+                        "name": "post_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Metadata bài viết",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.PostMetaResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "ID không hợp lệ",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Không tìm thấy bài viết",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{post_id}/reactions": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Thêm, đổi hoặc bỏ cảm xúc của user hiện tại với bài viết (gửi lại cùng loại để bỏ)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reactions"
+                ],
+                "summary": "Thả cảm xúc cho bài viết",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID bài viết",
+                        "name": "post_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Loại cảm xúc",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReactRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cảm xúc hiện tại và số lượng theo loại",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Loại cảm xúc không hợp lệ",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Không tìm thấy bài viết",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/posts/{post_id}/stats": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lượt xem theo ngày, nguồn truy cập, số bình luận và reaction của một bài viết. Chỉ tác giả hoặc admin. Lượt xem được ghi theo lô nên có thể trễ vài chục giây.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Thống kê bài viết",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID bài viết",
+                        "name": "post_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Số ngày gần nhất (mặc định 30, tối đa 365)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Thống kê bài viết",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.PostStatsResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Tham số không hợp lệ",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Không có quyền",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Không tìm thấy bài viết",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Kiểm tra kết nối cơ sở dữ liệu, migration chưa áp dụng và các worker nền, mỗi mục có thời gian chờ riêng. Trả về 503 khi có mục lỗi hoặc khi server đang tắt.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Kiểm tra sẵn sàng (readiness)",
+                "responses": {
+                    "200": {
+                        "description": "Sẵn sàng nhận request",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/health.Report"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "503": {
+                        "description": "Chưa sẵn sàng",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/health.Report"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/sitemap.xml": {
+            "get": {
+                "description": "Sitemap index liệt kê các sitemap con của bài viết, danh mục và tác giả. Hỗ trợ ETag/If-None-Match và Last-Modified/If-Modified-Since.",
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "sitemaps"
+                ],
+                "summary": "Sitemap index",
+                "responses": {
+                    "200": {
+                        "description": "Sitemap index",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "304": {
+                        "description": "Sitemap không thay đổi",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/sitemaps/{file}": {
+            "get": {
+                "description": "Sitemap của bài viết đã xuất bản (posts-N.xml, tối đa 50.000 URL mỗi file), danh mục (categories.xml) hoặc tác giả (authors-N.xml). Thêm đuôi .gz để nhận bản nén gzip.",
+                "produces": [
+                    "text/xml"
+                ],
+                "tags": [
+                    "sitemaps"
+                ],
+                "summary": "Sitemap con",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tên file, ví dụ posts-1.xml hoặc posts-1.xml.gz",
+                        "name": "file",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sitemap",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "304": {
+                        "description": "Sitemap không thay đổi",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Không tìm thấy sitemap",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/change-password": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Đổi mật khẩu cho user hiện tại",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Đổi mật khẩu",
+                "parameters": [
+                    {
+                        "description": "Thông tin đổi mật khẩu",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ChangePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Đổi mật khẩu thành công",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Lỗi xác thực hoặc mật khẩu cũ sai",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/confirm-email": {
+            "get": {
+                "description": "Xác nhận đổi email bằng token trong link đã gửi tới địa chỉ mới",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Xác nhận email mới",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token xác nhận",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Đổi email thành công",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Token không hợp lệ hoặc hết hạn",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Email đã tồn tại",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/login": {
+            "post": {
+                "description": "Đăng nhập với email và password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Đăng nhập",
+                "parameters": [
+                    {
+                        "description": "Thông tin đăng nhập",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Đăng nhập thành công, trả về token và thông tin user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Lỗi xác thực",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Sai thông tin đăng nhập",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lấy thông tin user từ token",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Lấy thông tin người dùng hiện tại",
+                "responses": {
+                    "200": {
+                        "description": "Thông tin user",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Không tìm thấy user",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Xóa user hiện tại (self-delete)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Xóa tài khoản của chính mình",
+                "responses": {
+                    "200": {
+                        "description": "Xóa thành công",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Không tìm thấy user",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Đổi username và/hoặc email của user hiện tại. Username được kiểm tra trùng và đổi ngay; email mới chỉ có hiệu lực sau khi xác nhận qua link gửi tới địa chỉ đó. Không thể đổi role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Cập nhật tài khoản của chính mình",
+                "parameters": [
+                    {
+                        "description": "Username/email mới",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateMeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cập nhật thành công",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Lỗi xác thực",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Username hoặc email đã tồn tại",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/avatar": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Tải ảnh lên thư viện của user hiện tại và đặt làm avatar",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "media"
+                ],
+                "summary": "Tải ảnh đại diện",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "File ảnh",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Cập nhật avatar thành công",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.MediaResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Thiếu file, định dạng không hỗ trợ hoặc ảnh quá nhiều điểm ảnh",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "File quá lớn",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/bookmark-collections": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lấy các bộ sưu tập trong danh sách đọc của user hiện tại",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookmarks"
+                ],
+                "summary": "Lấy danh sách bộ sưu tập",
+                "responses": {
+                    "200": {
+                        "description": "Danh sách bộ sưu tập",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Lỗi server",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/bookmarks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lấy các bài viết đã lưu của user hiện tại, mới lưu trước, có thể lọc theo bộ sưu tập. Bài viết đã xóa hoặc chưa xuất bản được ẩn.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "bookmarks"
+                ],
+                "summary": "Lấy danh sách đọc",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tên bộ sưu tập",
+                        "name": "collection",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Trang hiện tại",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Số lượng mỗi trang",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Danh sách bài viết đã lưu và meta",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Lỗi server",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/feed": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lấy bài viết của các tác giả và danh mục mà user hiện tại theo dõi, mới xuất bản trước, phân trang bằng cursor",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Lấy feed cá nhân",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor (next_cursor của trang trước)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Số lượng mỗi trang (tối đa 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Danh sách bài viết",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Tham số không hợp lệ",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Lỗi server",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/media": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lấy các ảnh đã tải lên của user hiện tại, mới nhất trước, có phân trang",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "media"
+                ],
+                "summary": "Lấy thư viện ảnh",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Trang hiện tại",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Số lượng mỗi trang",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Danh sách ảnh và meta",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Lỗi server",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/notification-preferences": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lấy danh sách loại thông báo và trạng thái bật/tắt của user hiện tại",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Lấy cài đặt thông báo",
+                "responses": {
+                    "200": {
+                        "description": "Cài đặt thông báo",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Lỗi server",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Bật/tắt từng loại thông báo cho user hiện tại",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Cập nhật cài đặt thông báo",
+                "parameters": [
+                    {
+                        "description": "Loại thông báo và trạng thái bật/tắt",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateNotificationPreferencesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cập nhật thành công",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Lỗi xác thực hoặc loại thông báo không hợp lệ",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/notifications": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lấy thông báo của user hiện tại, mới nhất trước, phân trang bằng cursor, kèm số thông báo chưa đọc",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Lấy danh sách thông báo",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Cursor (next_cursor của trang trước)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Số lượng mỗi trang (tối đa 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Chỉ lấy thông báo chưa đọc",
+                        "name": "unread_only",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Danh sách thông báo",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Tham số không hợp lệ",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Lỗi server",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/notifications/read-all": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Đánh dấu tất cả thông báo của user hiện tại là đã đọc",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Đánh dấu tất cả đã đọc",
+                "responses": {
+                    "200": {
+                        "description": "Đã đánh dấu tất cả đã đọc",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Lỗi server",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/notifications/{id}/read": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Đánh dấu một thông báo của user hiện tại là đã đọc",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Đánh dấu đã đọc",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID thông báo",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Đã đánh dấu đã đọc",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Không tìm thấy thông báo",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/profile": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cập nhật tên hiển thị, giới thiệu, avatar và website của user hiện tại. Chuỗi rỗng sẽ xóa trường tương ứng.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Cập nhật hồ sơ công khai",
+                "parameters": [
+                    {
+                        "description": "Thông tin hồ sơ",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cập nhật thành công",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Lỗi xác thực",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/register": {
+            "post": {
+                "description": "Đăng ký tài khoản với email, password và username",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Đăng ký người dùng mới",
+                "parameters": [
+                    {
+                        "description": "Thông tin đăng ký",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserRegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Đăng ký thành công",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Lỗi xác thực hoặc đăng ký",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Email đã tồn tại",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}": {
+            "get": {
+                "description": "Lấy hồ sơ công khai theo username: tên hiển thị, giới thiệu, avatar, website, ngày tham gia, số bài viết và số người theo dõi. Không bao gồm email và role.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Lấy hồ sơ công khai của tác giả",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Hồ sơ công khai",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dto.PublicProfileResponse"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Không tìm thấy user",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/feed.atom": {
+            "get": {
+                "description": "Feed RSS 2.0, Atom 1.0 hoặc JSON Feed 1.1 của các bài viết mới nhất của một tác giả",
+                "produces": [
+                    "text/xml",
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Feed bài viết theo tác giả",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username tác giả",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "304": {
+                        "description": "Feed không thay đổi",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Không tìm thấy tác giả",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/feed.json": {
+            "get": {
+                "description": "Feed RSS 2.0, Atom 1.0 hoặc JSON Feed 1.1 của các bài viết mới nhất của một tác giả",
+                "produces": [
+                    "text/xml",
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Feed bài viết theo tác giả",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username tác giả",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "304": {
+                        "description": "Feed không thay đổi",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Không tìm thấy tác giả",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/feed.rss": {
+            "get": {
+                "description": "Feed RSS 2.0, Atom 1.0 hoặc JSON Feed 1.1 của các bài viết mới nhất của một tác giả",
+                "produces": [
+                    "text/xml",
+                    "application/json"
+                ],
+                "tags": [
+                    "feeds"
+                ],
+                "summary": "Feed bài viết theo tác giả",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username tác giả",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Feed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "304": {
+                        "description": "Feed không thay đổi",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Không tìm thấy tác giả",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/follow": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "User hiện tại theo dõi một tác giả, bài viết của tác giả sẽ xuất hiện trong feed",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "follows"
+                ],
+                "summary": "Theo dõi tác giả",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username tác giả",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Đã theo dõi",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Không thể tự theo dõi",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Không tìm thấy user",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "User hiện tại bỏ theo dõi một tác giả",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "follows"
+                ],
+                "summary": "Bỏ theo dõi tác giả",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username tác giả",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Đã bỏ theo dõi",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Không tìm thấy user hoặc chưa theo dõi",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{username}/posts": {
+            "get": {
+                "description": "Lấy các bài viết đã xuất bản của một tác giả, mới nhất trước, có phân trang",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "posts"
+                ],
+                "summary": "Lấy bài viết của tác giả",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username tác giả",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Trang hiện tại",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Số lượng mỗi trang",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Danh sách bài viết và meta",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Không tìm thấy user",
+                        "schema": {
+                            "$ref": "#/definitions/utils.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/version": {
+            "get": {
+                "description": "Commit git, thời điểm build và phiên bản Go của bản build đang chạy",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Thông tin phiên bản",
+                "responses": {
+                    "200": {
+                        "description": "Thông tin phiên bản",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/utils.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/version.Info"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "dto.BookmarkRequest": {
+            "type": "object",
+            "properties": {
+                "collection": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "dto.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "old_password"
+            ],
+            "properties": {
+                "new_password": {
+                    "type": "string"
+                },
+                "old_password": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ChangeUserRole": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "admin",
+                        "client"
+                    ]
+                }
+            }
+        },
+        "dto.CreateCategoryRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "slug"
+            ],
+            "properties": {
+                "comments_auto_close_days": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "comments_enabled": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 2
+                },
+                "slug": {
+                    "type": "string",
+                    "maxLength": 50,
+                    "minLength": 3
+                }
+            }
+        },
+        "dto.CreateCommentRequest": {
+            "type": "object",
+            "required": [
+                "content",
+                "post_id"
+            ],
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "post_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.CreatePostRequest": {
+            "type": "object",
+            "required": [
+                "category_id",
+                "content",
+                "slug",
+                "status",
+                "title"
+            ],
+            "properties": {
+                "canonical_url": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "comments_enabled": {
+                    "type": "boolean"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "meta_description": {
+                    "type": "string",
+                    "maxLength": 300
+                },
+                "meta_title": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "noindex": {
+                    "type": "boolean"
+                },
+                "og_image": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "published"
+                    ]
+                },
+                "summary": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "thumbnail": {
+                    "type": "string"
+                },
+                "thumbnail_media_id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 200,
+                    "minLength": 2
+                }
+            }
+        },
+        "dto.DailyViewsResponse": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "views": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.LockCommentsRequest": {
             "type": "object",
             "required": [
-                "new_password",
-                "old_password"
+                "locked"
             ],
             "properties": {
-                "new_password": {
+                "locked": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dto.MediaResponse": {
+            "type": "object",
+            "properties": {
+                "content_type": {
                     "type": "string"
                 },
-                "old_password": {
+                "created_at": {
+                    "type": "string"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "original_name": {
+                    "type": "string"
+                },
+                "processing_error": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "srcset": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "url": {
                     "type": "string"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.MediaVariantResponse"
+                    }
+                },
+                "width": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.ChangeUserRole": {
+        "dto.MediaVariantResponse": {
             "type": "object",
-            "required": [
-                "role"
-            ],
             "properties": {
-                "role": {
-                    "type": "string",
-                    "enum": [
-                        "admin",
-                        "client"
-                    ]
+                "content_type": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "width": {
+                    "type": "integer"
                 }
             }
         },
-        "dto.CreateCategoryRequest": {
+        "dto.MetaTag": {
             "type": "object",
-            "required": [
-                "name",
-                "slug"
-            ],
             "properties": {
+                "content": {
+                    "type": "string"
+                },
                 "name": {
-                    "type": "string",
-                    "maxLength": 100,
-                    "minLength": 2
+                    "type": "string"
                 },
-                "slug": {
-                    "type": "string",
-                    "maxLength": 50,
-                    "minLength": 3
+                "property": {
+                    "type": "string"
                 }
             }
         },
-        "dto.CreateCommentRequest": {
+        "dto.PostMetaResponse": {
             "type": "object",
-            "required": [
-                "content",
-                "post_id"
-            ],
             "properties": {
-                "content": {
+                "canonical_url": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "html": {
+                    "description": "HTML holds all of the above as tags ready to paste into \u003chead\u003e",
                     "type": "string"
                 },
+                "json_ld": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "open_graph": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.MetaTag"
+                    }
+                },
+                "robots": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "twitter": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.MetaTag"
+                    }
+                }
+            }
+        },
+        "dto.PostStatsResponse": {
+            "type": "object",
+            "properties": {
+                "comments_count": {
+                    "type": "integer"
+                },
+                "days": {
+                    "type": "integer"
+                },
+                "period_views": {
+                    "type": "integer"
+                },
                 "post_id": {
                     "type": "integer"
+                },
+                "reactions": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "referrers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ReferrerViewsResponse"
+                    }
+                },
+                "total_views": {
+                    "type": "integer"
+                },
+                "views": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.DailyViewsResponse"
+                    }
                 }
             }
         },
-        "dto.CreatePostRequest": {
+        "dto.PublicProfileResponse": {
             "type": "object",
-            "required": [
-                "category_id",
-                "content",
-                "slug",
-                "status",
-                "thumbnail",
-                "title"
-            ],
             "properties": {
-                "category_id": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "bio": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "followers_count": {
                     "type": "integer"
                 },
-                "content": {
+                "following_count": {
+                    "type": "integer"
+                },
+                "joined_at": {
                     "type": "string"
                 },
-                "slug": {
+                "post_count": {
+                    "type": "integer"
+                },
+                "username": {
                     "type": "string"
                 },
-                "status": {
-                    "type": "string",
-                    "enum": [
-                        "draft",
-                        "published"
-                    ]
+                "website": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReactRequest": {
+            "type": "object",
+            "required": [
+                "type"
+            ],
+            "properties": {
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReferrerViewsResponse": {
+            "type": "object",
+            "properties": {
+                "referrer": {
+                    "description": "Referrer is the referring host, or empty for direct visits",
+                    "type": "string"
                 },
-                "thumbnail": {
+                "views": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.TopPostResponse": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string"
+                },
+                "post_id": {
+                    "type": "integer"
+                },
+                "slug": {
                     "type": "string"
                 },
                 "title": {
-                    "type": "string",
-                    "maxLength": 200,
-                    "minLength": 2
+                    "type": "string"
+                },
+                "views": {
+                    "type": "integer"
                 }
             }
         },
@@ -1216,6 +3501,13 @@ const docTemplate = `{
                 "slug"
             ],
             "properties": {
+                "comments_auto_close_days": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "comments_enabled": {
+                    "type": "boolean"
+                },
                 "name": {
                     "type": "string",
                     "maxLength": 100,
@@ -1239,21 +3531,62 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.UpdatePostRequest": {
+        "dto.UpdateMeRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string",
+                    "maxLength": 20,
+                    "minLength": 3
+                }
+            }
+        },
+        "dto.UpdateNotificationPreferencesRequest": {
             "type": "object",
             "required": [
-                "category_id",
-                "content",
-                "slug",
-                "title"
+                "preferences"
             ],
             "properties": {
+                "preferences": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "boolean"
+                    }
+                }
+            }
+        },
+        "dto.UpdatePostRequest": {
+            "type": "object",
+            "properties": {
+                "canonical_url": {
+                    "type": "string"
+                },
                 "category_id": {
                     "type": "integer"
                 },
+                "comments_enabled": {
+                    "type": "boolean"
+                },
                 "content": {
                     "type": "string"
                 },
+                "meta_description": {
+                    "type": "string",
+                    "maxLength": 300
+                },
+                "meta_title": {
+                    "type": "string",
+                    "maxLength": 200
+                },
+                "noindex": {
+                    "type": "boolean"
+                },
+                "og_image": {
+                    "type": "string"
+                },
                 "slug": {
                     "type": "string"
                 },
@@ -1264,9 +3597,16 @@ const docTemplate = `{
                         "published"
                     ]
                 },
+                "summary": {
+                    "type": "string",
+                    "maxLength": 500
+                },
                 "thumbnail": {
                     "type": "string"
                 },
+                "thumbnail_media_id": {
+                    "type": "integer"
+                },
                 "title": {
                     "type": "string",
                     "maxLength": 200,
@@ -1274,6 +3614,26 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.UpdateProfileRequest": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "bio": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "display_name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "website": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
         "dto.UserLoginRequest": {
             "type": "object",
             "required": [
@@ -1311,6 +3671,37 @@ const docTemplate = `{
                 }
             }
         },
+        "health.Report": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/health.Result"
+                    }
+                },
+                "ready": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "health.Result": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "ok": {
+                    "type": "boolean"
+                }
+            }
+        },
         "utils.APIResponse": {
             "type": "object",
             "properties": {
@@ -1325,6 +3716,20 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "version.Info": {
+            "type": "object",
+            "properties": {
+                "build_time": {
+                    "type": "string"
+                },
+                "commit": {
+                    "type": "string"
+                },
+                "go_version": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -0,0 +1,507 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is every setting of the server and the command-line tools. It is loaded once at
+// startup and handed to the components that need it.
+//
+// Settings come from, in increasing precedence: the defaults, the YAML file named by
+// CONFIG_FILE, and environment variables, which may be set in a .env file. The variable
+// of each setting is given next to it.
+type Config struct {
+	Port          string   `yaml:"port"`           // PORT
+	JWTSecret     Secret   `yaml:"jwt_secret"`     // JWT_SECRET
+	AllowOrigins  []string `yaml:"allow_origins"`  // CORS_ALLOW_ORIGINS, comma-separated
+	ReactionTypes []string `yaml:"reaction_types"` // REACTION_TYPES, comma-separated
+	// PubSubDriver is "memory" for a single instance, or "postgres" to share events
+	// between replicas through LISTEN/NOTIFY.
+	PubSubDriver string `yaml:"pubsub_driver"` // PUBSUB_DRIVER
+
+	HTTP    HTTPConfig    `yaml:"http"`
+	Metrics MetricsConfig `yaml:"metrics"`
+	Tracing TracingConfig `yaml:"tracing"`
+	DB      DBConfig      `yaml:"db"`
+	Site    SiteConfig    `yaml:"site"`
+	SMTP    SMTPConfig    `yaml:"smtp"`
+	Storage StorageConfig `yaml:"storage"`
+}
+
+// HTTPConfig bounds how long clients may hold the server's connections. Durations are
+// written like "30s" or "2m".
+type HTTPConfig struct {
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"` // HTTP_READ_HEADER_TIMEOUT
+	// ReadTimeout covers the whole request, body included, so it must leave time for uploads.
+	ReadTimeout time.Duration `yaml:"read_timeout"` // HTTP_READ_TIMEOUT
+	// WriteTimeout does not apply to event streams, which stay open.
+	WriteTimeout   time.Duration `yaml:"write_timeout"`    // HTTP_WRITE_TIMEOUT
+	IdleTimeout    time.Duration `yaml:"idle_timeout"`     // HTTP_IDLE_TIMEOUT
+	MaxHeaderBytes int           `yaml:"max_header_bytes"` // HTTP_MAX_HEADER_BYTES
+	// ShutdownTimeout is how long in-flight requests and background workers are given to
+	// finish once a stop signal is received.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"` // SHUTDOWN_TIMEOUT
+	// ShutdownDelay keeps serving with /readyz failing for a while before the shutdown
+	// proper, for load balancers to stop sending traffic. It counts in ShutdownTimeout.
+	ShutdownDelay time.Duration `yaml:"shutdown_delay"` // SHUTDOWN_DELAY
+}
+
+// MetricsConfig controls who can read the Prometheus metrics.
+type MetricsConfig struct {
+	// Listen is the address of a separate listener for /metrics, e.g. "127.0.0.1:9100",
+	// to keep the metrics off the public port. Without it the API serves /metrics.
+	Listen string `yaml:"listen"` // METRICS_LISTEN
+	// Username and Password require basic authentication to read the metrics.
+	Username string `yaml:"username"` // METRICS_USERNAME
+	Password Secret `yaml:"password"` // METRICS_PASSWORD
+}
+
+// TracingConfig selects where the OpenTelemetry spans of requests are exported.
+type TracingConfig struct {
+	// Exporter is "none", "stdout", which prints the spans for local debugging, or "otlp",
+	// which sends them to a collector over OTLP/HTTP.
+	Exporter string `yaml:"exporter"` // TRACING_EXPORTER
+	// Endpoint is the base URL of the OTLP collector, e.g. "http://localhost:4318"; the
+	// spans are sent to its /v1/traces path.
+	Endpoint    string `yaml:"endpoint"`     // OTEL_EXPORTER_OTLP_ENDPOINT
+	ServiceName string `yaml:"service_name"` // OTEL_SERVICE_NAME
+	// SampleRatio is the share of traces started by the API that are recorded, from 0 to
+	// 1. Requests continuing a trace follow the sampling decision of the caller.
+	SampleRatio float64 `yaml:"sample_ratio"` // TRACING_SAMPLE_RATIO
+}
+
+type DBConfig struct {
+	Host     string `yaml:"host"`     // DB_HOST
+	Port     string `yaml:"port"`     // DB_PORT
+	User     string `yaml:"user"`     // DB_USER
+	Password Secret `yaml:"password"` // DB_PASSWORD
+	Name     string `yaml:"name"`     // DB_NAME
+	// AutoMigrate applies pending migrations at startup; without it they are left to the
+	// migrate command.
+	AutoMigrate bool `yaml:"auto_migrate"` // DB_AUTO_MIGRATE
+}
+
+type SiteConfig struct {
+	// URL is the public base URL of the site, used to build absolute links in emails,
+	// feeds and sitemaps. It has no trailing slash.
+	URL   string `yaml:"url"`   // SITE_URL
+	Title string `yaml:"title"` // SITE_TITLE
+	// SitemapGzip makes the sitemap index link to gzip-compressed child sitemaps.
+	SitemapGzip bool `yaml:"sitemap_gzip"` // SITEMAP_GZIP
+}
+
+type SMTPConfig struct {
+	Host     string `yaml:"host"`     // SMTP_HOST
+	Port     int    `yaml:"port"`     // SMTP_PORT
+	User     string `yaml:"user"`     // SMTP_USER
+	Password Secret `yaml:"password"` // SMTP_PASS
+	From     string `yaml:"from"`     // SMTP_FROM
+}
+
+type StorageConfig struct {
+	// Driver is "local", which writes below UploadDir and is served by the API under
+	// /uploads, or "s3".
+	Driver         string `yaml:"driver"`           // STORAGE_DRIVER
+	UploadDir      string `yaml:"upload_dir"`       // UPLOAD_DIR
+	UploadMaxBytes int64  `yaml:"upload_max_bytes"` // UPLOAD_MAX_BYTES
+	// VariantWidths are the widths resized image variants are generated at.
+	VariantWidths []int    `yaml:"variant_widths"` // MEDIA_VARIANT_WIDTHS, comma-separated
+	S3            S3Config `yaml:"s3"`
+}
+
+type S3Config struct {
+	Endpoint  string `yaml:"endpoint"`   // S3_ENDPOINT
+	Region    string `yaml:"region"`     // S3_REGION
+	Bucket    string `yaml:"bucket"`     // S3_BUCKET
+	AccessKey string `yaml:"access_key"` // S3_ACCESS_KEY
+	SecretKey Secret `yaml:"secret_key"` // S3_SECRET_KEY
+	// PublicURL defaults to Endpoint/Bucket.
+	PublicURL string `yaml:"public_url"` // S3_PUBLIC_URL
+}
+
+// Secret is a setting that must not end up in logs: it prints as "[redacted]".
+type Secret string
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return "[redacted]"
+}
+
+func (s Secret) GoString() string {
+	return strconv.Quote(s.String())
+}
+
+// MarshalText redacts the secret in encoded output too, e.g. JSON logs.
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Default returns the settings used when nothing else is configured. It has no JWT
+// secret, so it does not validate as is.
+func Default() *Config {
+	return &Config{
+		Port:          "9090",
+		AllowOrigins:  []string{"http://localhost:4200"},
+		ReactionTypes: []string{"like", "love", "insightful"},
+		PubSubDriver:  "memory",
+		HTTP: HTTPConfig{
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       30 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       2 * time.Minute,
+			MaxHeaderBytes:    1 << 20,
+			ShutdownTimeout:   20 * time.Second,
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			Endpoint:    "http://localhost:4318",
+			ServiceName: "blog-api",
+			SampleRatio: 1,
+		},
+		DB: DBConfig{
+			Host:        "localhost",
+			Port:        "5432",
+			AutoMigrate: true,
+		},
+		Site: SiteConfig{
+			URL:   "http://localhost:9090",
+			Title: "Blog",
+		},
+		SMTP: SMTPConfig{Port: 587},
+		Storage: StorageConfig{
+			Driver:         "local",
+			UploadDir:      "uploads",
+			UploadMaxBytes: 5 << 20,
+			VariantWidths:  []int{320, 768, 1280},
+		},
+	}
+}
+
+// Load reads the configuration and validates it. The .env file of the working directory
+// is read unless RENDER is set, as the platform provides the environment there.
+func Load() (*Config, error) {
+	if os.Getenv("RENDER") == "" {
+		if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("load .env: %w", err)
+		}
+	}
+	return load(os.Getenv("CONFIG_FILE"), os.LookupEnv)
+}
+
+func load(file string, lookup func(string) (string, bool)) (*Config, error) {
+	cfg := Default()
+	if file != "" {
+		if err := cfg.loadYAML(file); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.loadEnv(lookup); err != nil {
+		return nil, err
+	}
+	cfg.Site.URL = strings.TrimRight(cfg.Site.URL, "/")
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// loadYAML overrides the settings present in a YAML file. Unknown keys are rejected, so a
+// misspelt setting is not silently ignored.
+func (c *Config) loadYAML(file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	defer f.Close()
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parse config file %s: %w", file, err)
+	}
+	return nil
+}
+
+// loadEnv overrides the settings whose variable is set and not empty.
+func (c *Config) loadEnv(lookup func(string) (string, bool)) error {
+	var errs []error
+	get := func(name string) (string, bool) {
+		v, ok := lookup(name)
+		v = strings.TrimSpace(v)
+		return v, ok && v != ""
+	}
+	str := func(dst *string, name string) {
+		if v, ok := get(name); ok {
+			*dst = v
+		}
+	}
+	secret := func(dst *Secret, name string) {
+		if v, ok := get(name); ok {
+			*dst = Secret(v)
+		}
+	}
+	boolean := func(dst *bool, name string) {
+		if v, ok := get(name); ok {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not a boolean", name, v))
+				return
+			}
+			*dst = b
+		}
+	}
+	integer := func(dst *int64, name string) {
+		if v, ok := get(name); ok {
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not a number", name, v))
+				return
+			}
+			*dst = n
+		}
+	}
+	float := func(dst *float64, name string) {
+		if v, ok := get(name); ok {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not a number", name, v))
+				return
+			}
+			*dst = f
+		}
+	}
+	duration := func(dst *time.Duration, name string) {
+		if v, ok := get(name); ok {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not a duration", name, v))
+				return
+			}
+			*dst = d
+		}
+	}
+	list := func(dst *[]string, name string) {
+		if v, ok := get(name); ok {
+			*dst = splitList(v)
+		}
+	}
+
+	str(&c.Port, "PORT")
+	secret(&c.JWTSecret, "JWT_SECRET")
+	list(&c.AllowOrigins, "CORS_ALLOW_ORIGINS")
+	list(&c.ReactionTypes, "REACTION_TYPES")
+	str(&c.PubSubDriver, "PUBSUB_DRIVER")
+
+	duration(&c.HTTP.ReadHeaderTimeout, "HTTP_READ_HEADER_TIMEOUT")
+	duration(&c.HTTP.ReadTimeout, "HTTP_READ_TIMEOUT")
+	duration(&c.HTTP.WriteTimeout, "HTTP_WRITE_TIMEOUT")
+	duration(&c.HTTP.IdleTimeout, "HTTP_IDLE_TIMEOUT")
+	maxHeader := int64(c.HTTP.MaxHeaderBytes)
+	integer(&maxHeader, "HTTP_MAX_HEADER_BYTES")
+	c.HTTP.MaxHeaderBytes = int(maxHeader)
+	duration(&c.HTTP.ShutdownTimeout, "SHUTDOWN_TIMEOUT")
+	duration(&c.HTTP.ShutdownDelay, "SHUTDOWN_DELAY")
+
+	str(&c.Metrics.Listen, "METRICS_LISTEN")
+	str(&c.Metrics.Username, "METRICS_USERNAME")
+	secret(&c.Metrics.Password, "METRICS_PASSWORD")
+
+	str(&c.Tracing.Exporter, "TRACING_EXPORTER")
+	str(&c.Tracing.Endpoint, "OTEL_EXPORTER_OTLP_ENDPOINT")
+	str(&c.Tracing.ServiceName, "OTEL_SERVICE_NAME")
+	float(&c.Tracing.SampleRatio, "TRACING_SAMPLE_RATIO")
+
+	str(&c.DB.Host, "DB_HOST")
+	str(&c.DB.Port, "DB_PORT")
+	str(&c.DB.User, "DB_USER")
+	secret(&c.DB.Password, "DB_PASSWORD")
+	str(&c.DB.Name, "DB_NAME")
+	boolean(&c.DB.AutoMigrate, "DB_AUTO_MIGRATE")
+
+	str(&c.Site.URL, "SITE_URL")
+	str(&c.Site.Title, "SITE_TITLE")
+	boolean(&c.Site.SitemapGzip, "SITEMAP_GZIP")
+
+	str(&c.SMTP.Host, "SMTP_HOST")
+	smtpPort := int64(c.SMTP.Port)
+	integer(&smtpPort, "SMTP_PORT")
+	c.SMTP.Port = int(smtpPort)
+	str(&c.SMTP.User, "SMTP_USER")
+	secret(&c.SMTP.Password, "SMTP_PASS")
+	str(&c.SMTP.From, "SMTP_FROM")
+
+	str(&c.Storage.Driver, "STORAGE_DRIVER")
+	str(&c.Storage.UploadDir, "UPLOAD_DIR")
+	integer(&c.Storage.UploadMaxBytes, "UPLOAD_MAX_BYTES")
+	if v, ok := get("MEDIA_VARIANT_WIDTHS"); ok {
+		c.Storage.VariantWidths = nil
+		for _, part := range splitList(v) {
+			w, err := strconv.Atoi(part)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("MEDIA_VARIANT_WIDTHS: %q is not a number", part))
+				continue
+			}
+			c.Storage.VariantWidths = append(c.Storage.VariantWidths, w)
+		}
+	}
+	str(&c.Storage.S3.Endpoint, "S3_ENDPOINT")
+	str(&c.Storage.S3.Region, "S3_REGION")
+	str(&c.Storage.S3.Bucket, "S3_BUCKET")
+	str(&c.Storage.S3.AccessKey, "S3_ACCESS_KEY")
+	secret(&c.Storage.S3.SecretKey, "S3_SECRET_KEY")
+	str(&c.Storage.S3.PublicURL, "S3_PUBLIC_URL")
+
+	return errors.Join(errs...)
+}
+
+func splitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ValidateServer reports the settings the API server needs on top of Validate. It signs
+// and checks tokens with JWT_SECRET, which the commands that issue none run without.
+func (c *Config) ValidateServer() error {
+	if c.JWTSecret == "" {
+		return errors.New("invalid configuration: JWT_SECRET: must be set")
+	}
+	return nil
+}
+
+// Validate reports every invalid setting at once, so that they can all be fixed before
+// the next start.
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(setting, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", setting, fmt.Sprintf(format, args...)))
+	}
+
+	if port, err := strconv.Atoi(c.Port); err != nil || port < 1 || port > 65535 {
+		fail("PORT", "%q is not a valid port", c.Port)
+	}
+	for _, origin := range c.AllowOrigins {
+		if !isHTTPURL(origin) {
+			fail("CORS_ALLOW_ORIGINS", "%q is not an http(s) origin", origin)
+		}
+	}
+	if len(c.ReactionTypes) == 0 {
+		fail("REACTION_TYPES", "must list at least one reaction")
+	}
+	if c.PubSubDriver != "memory" && c.PubSubDriver != "postgres" {
+		fail("PUBSUB_DRIVER", "%q is not one of memory, postgres", c.PubSubDriver)
+	}
+
+	for _, timeout := range []struct {
+		setting string
+		value   time.Duration
+	}{
+		{"HTTP_READ_HEADER_TIMEOUT", c.HTTP.ReadHeaderTimeout},
+		{"HTTP_READ_TIMEOUT", c.HTTP.ReadTimeout},
+		{"HTTP_WRITE_TIMEOUT", c.HTTP.WriteTimeout},
+		{"HTTP_IDLE_TIMEOUT", c.HTTP.IdleTimeout},
+		{"SHUTDOWN_TIMEOUT", c.HTTP.ShutdownTimeout},
+	} {
+		if timeout.value <= 0 {
+			fail(timeout.setting, "must be positive")
+		}
+	}
+	if c.HTTP.ShutdownDelay < 0 || c.HTTP.ShutdownDelay >= c.HTTP.ShutdownTimeout {
+		fail("SHUTDOWN_DELAY", "must be at least 0 and less than SHUTDOWN_TIMEOUT")
+	}
+	if c.HTTP.MaxHeaderBytes <= 0 {
+		fail("HTTP_MAX_HEADER_BYTES", "must be positive")
+	}
+
+	if c.Metrics.Listen != "" {
+		if _, port, err := net.SplitHostPort(c.Metrics.Listen); err != nil || port == "" {
+			fail("METRICS_LISTEN", "%q is not a host:port address", c.Metrics.Listen)
+		}
+	}
+	if (c.Metrics.Username == "") != (c.Metrics.Password == "") {
+		fail("METRICS_USERNAME, METRICS_PASSWORD", "must be set together")
+	}
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout":
+	case "otlp":
+		if !isHTTPURL(c.Tracing.Endpoint) {
+			fail("OTEL_EXPORTER_OTLP_ENDPOINT", "%q is not an absolute http(s) URL", c.Tracing.Endpoint)
+		}
+	default:
+		fail("TRACING_EXPORTER", "%q is not one of none, stdout, otlp", c.Tracing.Exporter)
+	}
+	if c.Tracing.ServiceName == "" {
+		fail("OTEL_SERVICE_NAME", "must be set")
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		fail("TRACING_SAMPLE_RATIO", "%v is not between 0 and 1", c.Tracing.SampleRatio)
+	}
+
+	if c.DB.Host == "" {
+		fail("DB_HOST", "must be set")
+	}
+	if port, err := strconv.Atoi(c.DB.Port); err != nil || port < 1 || port > 65535 {
+		fail("DB_PORT", "%q is not a valid port", c.DB.Port)
+	}
+	if c.DB.User == "" {
+		fail("DB_USER", "must be set")
+	}
+	if c.DB.Name == "" {
+		fail("DB_NAME", "must be set")
+	}
+
+	if !isHTTPURL(c.Site.URL) {
+		fail("SITE_URL", "%q is not an absolute http(s) URL", c.Site.URL)
+	}
+	if c.SMTP.Port < 1 || c.SMTP.Port > 65535 {
+		fail("SMTP_PORT", "%d is not a valid port", c.SMTP.Port)
+	}
+
+	switch c.Storage.Driver {
+	case "local":
+		if c.Storage.UploadDir == "" {
+			fail("UPLOAD_DIR", "must be set for the local storage driver")
+		}
+	case "s3":
+		if c.Storage.S3.Endpoint == "" || c.Storage.S3.Bucket == "" {
+			fail("S3_ENDPOINT, S3_BUCKET", "must be set for the s3 storage driver")
+		}
+	default:
+		fail("STORAGE_DRIVER", "%q is not one of local, s3", c.Storage.Driver)
+	}
+	if c.Storage.UploadMaxBytes <= 0 {
+		fail("UPLOAD_MAX_BYTES", "must be positive")
+	}
+	if len(c.Storage.VariantWidths) == 0 {
+		fail("MEDIA_VARIANT_WIDTHS", "must list at least one width")
+	}
+	for _, w := range c.Storage.VariantWidths {
+		if w <= 0 {
+			fail("MEDIA_VARIANT_WIDTHS", "%d is not a positive width", w)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
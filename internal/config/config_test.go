@@ -0,0 +1,171 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+}
+
+// minimal is the environment of a valid configuration: the settings without a default.
+func minimal() map[string]string {
+	return map[string]string{"JWT_SECRET": "jwt-secret", "DB_USER": "blog", "DB_NAME": "blog"}
+}
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := load("", env(minimal()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Default()
+	want.JWTSecret, want.DB.User, want.DB.Name = "jwt-secret", "blog", "blog"
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("config = %+v; want %+v", cfg, want)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := writeFile(t, `
+port: "8000"
+site:
+  url: https://yaml.example.com/
+  title: From YAML
+db:
+  host: db.internal
+storage:
+  variant_widths: [100, 200]
+http:
+  write_timeout: 1m
+`)
+	vars := minimal()
+	vars["PORT"] = "8080"
+	vars["SITE_TITLE"] = "From env"
+	vars["DB_HOST"] = "" // empty variables do not override
+	vars["REACTION_TYPES"] = "like, , clap"
+	vars["SHUTDOWN_TIMEOUT"] = "5s"
+
+	cfg, err := load(file, env(vars))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Port != "8080" || cfg.Site.Title != "From env" {
+		t.Errorf("environment does not override the file: port %q, title %q", cfg.Port, cfg.Site.Title)
+	}
+	if cfg.DB.Host != "db.internal" || cfg.Site.URL != "https://yaml.example.com" {
+		t.Errorf("file settings lost: db host %q, site url %q", cfg.DB.Host, cfg.Site.URL)
+	}
+	if !reflect.DeepEqual(cfg.Storage.VariantWidths, []int{100, 200}) || !reflect.DeepEqual(cfg.ReactionTypes, []string{"like", "clap"}) {
+		t.Errorf("lists: widths %v, reactions %v", cfg.Storage.VariantWidths, cfg.ReactionTypes)
+	}
+	if cfg.HTTP.WriteTimeout != time.Minute || cfg.HTTP.ShutdownTimeout != 5*time.Second {
+		t.Errorf("durations: write timeout %v, shutdown timeout %v", cfg.HTTP.WriteTimeout, cfg.HTTP.ShutdownTimeout)
+	}
+	if cfg.SMTP.Port != 587 {
+		t.Errorf("smtp port = %d; want the default", cfg.SMTP.Port)
+	}
+}
+
+func TestLoadRejectsUnknownKeys(t *testing.T) {
+	file := writeFile(t, "site:\n  titel: Typo\n")
+	if _, err := load(file, env(minimal())); err == nil || !strings.Contains(err.Error(), "titel") {
+		t.Errorf("err = %v; want the unknown key reported", err)
+	}
+}
+
+func TestLoadReportsEveryError(t *testing.T) {
+	vars := map[string]string{
+		"PORT":                 "http",
+		"PUBSUB_DRIVER":        "redis",
+		"STORAGE_DRIVER":       "s3",
+		"SMTP_PORT":            "25x",
+		"UPLOAD_MAX_BYTES":     "0",
+		"HTTP_IDLE_TIMEOUT":    "0s",
+		"SHUTDOWN_DELAY":       "1h",
+		"METRICS_LISTEN":       "9100",
+		"METRICS_USERNAME":     "prometheus",
+		"TRACING_EXPORTER":     "jaeger",
+		"TRACING_SAMPLE_RATIO": "1.5",
+	}
+	_, err := load("", env(vars))
+	if err == nil {
+		t.Fatal("invalid configuration loaded")
+	}
+	// parse errors are reported before validation
+	if !strings.Contains(err.Error(), "SMTP_PORT") {
+		t.Errorf("err = %v; want SMTP_PORT", err)
+	}
+
+	vars["SMTP_PORT"] = "25"
+	_, err = load("", env(vars))
+	if err == nil {
+		t.Fatal("invalid configuration loaded")
+	}
+	for _, setting := range []string{"PORT", "PUBSUB_DRIVER", "DB_USER", "DB_NAME", "S3_ENDPOINT", "UPLOAD_MAX_BYTES", "HTTP_IDLE_TIMEOUT", "SHUTDOWN_DELAY", "METRICS_LISTEN", "METRICS_PASSWORD", "TRACING_EXPORTER", "TRACING_SAMPLE_RATIO"} {
+		if !strings.Contains(err.Error(), setting) {
+			t.Errorf("err does not mention %s:\n%v", setting, err)
+		}
+	}
+}
+
+func TestJWTSecretOnlyForTheServer(t *testing.T) {
+	vars := minimal()
+	delete(vars, "JWT_SECRET")
+	cfg, err := load("", env(vars))
+	if err != nil {
+		t.Fatalf("loading without JWT_SECRET for a maintenance command: %v", err)
+	}
+	if err := cfg.ValidateServer(); err == nil || !strings.Contains(err.Error(), "JWT_SECRET") {
+		t.Errorf("ValidateServer = %v; want JWT_SECRET reported", err)
+	}
+
+	cfg, err = load("", env(minimal()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cfg.ValidateServer(); err != nil {
+		t.Errorf("ValidateServer = %v", err)
+	}
+}
+
+func TestSecretsAreRedacted(t *testing.T) {
+	vars := minimal()
+	vars["DB_PASSWORD"] = "db-password"
+	vars["SMTP_PASS"] = "smtp-password"
+	vars["S3_SECRET_KEY"] = "s3-secret"
+	vars["METRICS_USERNAME"] = "prometheus"
+	vars["METRICS_PASSWORD"] = "metrics-password"
+	cfg, err := load("", env(vars))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+		out := fmt.Sprintf(format, *cfg)
+		for _, secret := range []string{"jwt-secret", "db-password", "smtp-password", "s3-secret", "metrics-password"} {
+			if strings.Contains(out, secret) {
+				t.Errorf("%s prints %s:\n%s", format, secret, out)
+			}
+		}
+	}
+	if !strings.Contains(cfg.DB.DSN(), "db-password") {
+		t.Errorf("DSN() = %q; want the real password", cfg.DB.DSN())
+	}
+}
@@ -1,55 +1,62 @@
 package config
 
 import (
-	"blog-api/internal/entities"
+	"blog-api/internal/migrations"
+	"blog-api/pkg/migrate"
+	"context"
 	"fmt"
 	"log"
-	"os"
 
-	"github.com/joho/godotenv"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-var DB *gorm.DB
-
-func LoadEnv(){
-	if os.Getenv("RENDER") == "" {
-        if err := godotenv.Load(); err != nil {
-            log.Println("No .env file found (this is OK in production)")
-        }
-    }
-}
-
-func ConnectDB(){
-	dsn := fmt.Sprintf(
+// DSN is the connection string of the database.
+func (c DBConfig) DSN() string {
+	return fmt.Sprintf(
 		"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASSWORD"),
-		os.Getenv("DB_NAME"),
-		os.Getenv("DB_PORT"),
+		c.Host,
+		c.User,
+		string(c.Password),
+		c.Name,
+		c.Port,
 	)
+}
 
-	var err error
-
-	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+// ConnectDB opens the database. The handle is passed to whatever needs it.
+func ConnectDB(cfg DBConfig) *gorm.DB {
+	db, err := gorm.Open(postgres.Open(cfg.DSN()), &gorm.Config{})
 	if err != nil {
 		log.Fatal("Cannot connect database: ", err)
 	}
-
-	fmt.Println("Connected")
+	log.Println("Connected to database", cfg.Name, "on", cfg.Host)
+	return db
 }
 
-func InitDB() {
-	err := DB.AutoMigrate(
-		&entities.User{},
-		&entities.Category{},
-		&entities.Post{},
-		&entities.Comment{},
-	)
+// InitDB brings the schema up to date by applying pending migrations, unless
+// auto-migration is turned off, in which case they are left to the migrate command.
+func InitDB(cfg DBConfig, db *gorm.DB) {
+	if !cfg.AutoMigrate {
+		return
+	}
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		log.Fatal("Load migrations failed: ", err)
+	}
+	applied, err := migrator.Up(context.Background())
+	if err != nil {
+		log.Fatal("Migration failed: ", err)
+	}
+	for _, m := range applied {
+		log.Printf("Applied migration %04d_%s", m.Version, m.Name)
+	}
+}
 
-	if err != nil{
-		log.Fatal("AutoMigrate failed: ", err)
+// NewMigrator returns a migrator for the embedded migrations on db.
+func NewMigrator(db *gorm.DB) (*migrate.Migrator, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
 	}
-}
\ No newline at end of file
+	return migrate.New(sqlDB, migrations.FS)
+}
@@ -0,0 +1,8 @@
+package config
+
+import "blog-api/pkg/helper"
+
+// NewMailer returns the mailer sending account emails through the configured SMTP server.
+func NewMailer(cfg SMTPConfig) *helper.Mailer {
+	return helper.NewMailer(cfg.Host, cfg.Port, cfg.User, string(cfg.Password), cfg.From)
+}
@@ -0,0 +1,15 @@
+package config
+
+import (
+	"blog-api/pkg/pubsub"
+
+	"gorm.io/gorm"
+)
+
+// ConnectBus creates the event bus selected by the PubSubDriver setting.
+func ConnectBus(cfg *Config, db *gorm.DB) pubsub.Bus {
+	if cfg.PubSubDriver == "postgres" {
+		return pubsub.NewPostgresBus(db, cfg.DB.DSN())
+	}
+	return pubsub.NewMemoryBus(1000)
+}
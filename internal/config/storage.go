@@ -0,0 +1,33 @@
+package config
+
+import (
+	"blog-api/pkg/storage"
+	"log"
+)
+
+// ConnectStorage creates the file storage selected by the storage driver setting: local
+// files below the upload directory, served by the API under /uploads, or an
+// S3-compatible bucket.
+func ConnectStorage(cfg *Config) storage.Storage {
+	if cfg.Storage.Driver == "s3" {
+		s3 := cfg.Storage.S3
+		store, err := storage.NewS3(storage.S3Config{
+			Endpoint:  s3.Endpoint,
+			Region:    s3.Region,
+			Bucket:    s3.Bucket,
+			AccessKey: s3.AccessKey,
+			SecretKey: string(s3.SecretKey),
+			PublicURL: s3.PublicURL,
+		})
+		if err != nil {
+			log.Fatal("Cannot configure S3 storage: ", err)
+		}
+		return store
+	}
+
+	store, err := storage.NewLocal(cfg.Storage.UploadDir, cfg.Site.URL+"/uploads")
+	if err != nil {
+		log.Fatal("Cannot create upload directory: ", err)
+	}
+	return store
+}
@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"blog-api/internal/services"
+	"blog-api/pkg/utils"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type AnalyticsController struct {
+	service *services.AnalyticsService
+}
+
+func NewAnalyticsController(service *services.AnalyticsService) *AnalyticsController {
+	return &AnalyticsController{service: service}
+}
+
+// GetPostStats godoc
+// @Summary Thống kê bài viết
+// @Description Lượt xem theo ngày, nguồn truy cập, số bình luận và reaction của một bài viết. Chỉ tác giả hoặc admin. Lượt xem được ghi theo lô nên có thể trễ vài chục giây.
+// @Tags posts
+// @Security BearerAuth
+// @Produce  json
+// @Param   post_id  path   int  true   "ID bài viết"
+// @Param   days     query  int  false  "Số ngày gần nhất (mặc định 30, tối đa 365)"
+// @Success 200 {object} utils.APIResponse{data=dto.PostStatsResponse} "Thống kê bài viết"
+// @Failure 400 {object} utils.APIResponse "Tham số không hợp lệ"
+// @Failure 403 {object} utils.APIResponse "Không có quyền"
+// @Failure 404 {object} utils.APIResponse "Không tìm thấy bài viết"
+// @Router /posts/{post_id}/stats [get]
+func (c *AnalyticsController) GetPostStats(ctx *gin.Context) {
+	id, ok := utils.GetUintIDParam(ctx, "post_id", utils.ErrInvalidPostID)
+	if !ok {
+		return
+	}
+	days, ok := positiveIntQuery(ctx, "days", utils.ErrInvalidDaysParam)
+	if !ok {
+		return
+	}
+	stats, err := c.service.PostStats(id, days)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.SendFail(ctx, http.StatusNotFound, "404", utils.ErrPostNotFound, nil)
+			return
+		}
+		utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
+		return
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgPostStatsFetched, stats)
+}
+
+// GetTopPosts godoc
+// @Summary Bài viết được xem nhiều nhất (admin)
+// @Description Báo cáo các bài viết có nhiều lượt xem nhất toàn site trong khoảng thời gian gần nhất
+// @Tags admin
+// @Security BearerAuth
+// @Produce  json
+// @Param   days   query  int  false  "Số ngày gần nhất (mặc định 30, tối đa 365)"
+// @Param   limit  query  int  false  "Số bài viết (mặc định 10, tối đa 100)"
+// @Success 200 {object} utils.APIResponse{data=[]dto.TopPostResponse} "Danh sách bài viết"
+// @Failure 400 {object} utils.APIResponse "Tham số không hợp lệ"
+// @Failure 403 {object} utils.APIResponse "Không có quyền"
+// @Router /admin/posts/top [get]
+func (c *AnalyticsController) GetTopPosts(ctx *gin.Context) {
+	days, ok := positiveIntQuery(ctx, "days", utils.ErrInvalidDaysParam)
+	if !ok {
+		return
+	}
+	limit, ok := positiveIntQuery(ctx, "limit", utils.ErrInvalidLimitParam)
+	if !ok {
+		return
+	}
+	posts, err := c.service.TopPosts(days, limit)
+	if err != nil {
+		utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
+		return
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgTopPostsFetched, posts)
+}
+
+// positiveIntQuery reads an optional positive integer query parameter; 0 means it is absent.
+func positiveIntQuery(ctx *gin.Context, name, invalidMsg string) (int, bool) {
+	v := ctx.Query(name)
+	if v == "" {
+		return 0, true
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		utils.SendFail(ctx, http.StatusBadRequest, "400", invalidMsg, nil)
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,159 @@
+package controllers
+
+import (
+	"blog-api/internal/dto"
+	"blog-api/internal/services"
+	"blog-api/pkg/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type BookmarkController struct {
+	service *services.BookmarkService
+}
+
+func NewBookmarkController(service *services.BookmarkService) *BookmarkController {
+	return &BookmarkController{service: service}
+}
+
+// AddBookmark godoc
+// @Summary Lưu bài viết vào danh sách đọc
+// @Description Lưu bài viết đã xuất bản vào danh sách đọc của user hiện tại, có thể chọn bộ sưu tập (lưu lại để chuyển bộ sưu tập)
+// @Tags bookmarks
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param   post_id  path  int                  true   "ID bài viết"
+// @Param   body     body  dto.BookmarkRequest  false  "Tên bộ sưu tập"
+// @Success 200 {object} utils.APIResponse "Đã lưu bài viết"
+// @Failure 400 {object} utils.APIResponse "Lỗi xác thực"
+// @Failure 404 {object} utils.APIResponse "Không tìm thấy bài viết"
+// @Router /posts/{post_id}/bookmark [post]
+func (c *BookmarkController) AddBookmark(ctx *gin.Context) {
+	postID, ok := utils.GetUintIDParam(ctx, "post_id", utils.ErrInvalidPostID)
+	if !ok {
+		return
+	}
+
+	var req dto.BookmarkRequest
+	if ctx.Request.ContentLength > 0 {
+		if validationErrs := utils.BindAndValidate(ctx, &req); validationErrs != nil {
+			utils.SendFail(ctx, http.StatusBadRequest, "400", "VALIDATION_FAILED", validationErrs)
+			return
+		}
+	}
+
+	uid, ok := utils.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.service.AddBookmark(uid, postID, req.Collection); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.SendFail(ctx, http.StatusNotFound, "404", utils.ErrPostNotFound, nil)
+			return
+		}
+		utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
+		return
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgBookmarkAdded, nil)
+}
+
+// RemoveBookmark godoc
+// @Summary Bỏ lưu bài viết
+// @Description Xóa bài viết khỏi danh sách đọc của user hiện tại
+// @Tags bookmarks
+// @Security BearerAuth
+// @Produce  json
+// @Param   id  path  int  true  "ID bài viết"
+// @Success 200 {object} utils.APIResponse "Đã bỏ lưu"
+// @Failure 404 {object} utils.APIResponse "Bài viết chưa được lưu"
+// @Router /posts/{id}/bookmark [delete]
+func (c *BookmarkController) RemoveBookmark(ctx *gin.Context) {
+	postID, ok := utils.GetUintIDParam(ctx, "id", utils.ErrInvalidPostID)
+	if !ok {
+		return
+	}
+	uid, ok := utils.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.service.RemoveBookmark(uid, postID); err != nil {
+		utils.SendFail(ctx, http.StatusNotFound, "404", utils.ErrBookmarkNotFound, nil)
+		return
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgBookmarkRemoved, nil)
+}
+
+// ListBookmarks godoc
+// @Summary Lấy danh sách đọc
+// @Description Lấy các bài viết đã lưu của user hiện tại, mới lưu trước, có thể lọc theo bộ sưu tập. Bài viết đã xóa hoặc chưa xuất bản được ẩn.
+// @Tags bookmarks
+// @Security BearerAuth
+// @Produce  json
+// @Param   collection  query  string  false  "Tên bộ sưu tập"
+// @Param   page        query  int     false  "Trang hiện tại"
+// @Param   page_size   query  int     false  "Số lượng mỗi trang"
+// @Success 200 {object} utils.APIResponse "Danh sách bài viết đã lưu và meta"
+// @Failure 500 {object} utils.APIResponse "Lỗi server"
+// @Router /users/me/bookmarks [get]
+func (c *BookmarkController) ListBookmarks(ctx *gin.Context) {
+	uid, ok := utils.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	page, pageSize, ok := utils.GetPaginationParams(ctx)
+	if !ok {
+		return
+	}
+
+	bookmarks, total, err := c.service.ListBookmarks(uid, ctx.Query("collection"), page, pageSize)
+	if err != nil {
+		utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
+		return
+	}
+
+	resp := []dto.BookmarkResponse{}
+	for _, b := range bookmarks {
+		post := dto.NewPostResponse(&b.Post)
+		post.Bookmarked = true
+		item := dto.BookmarkResponse{BookmarkedAt: b.CreatedAt, Post: post}
+		if b.Collection != nil {
+			item.Collection = &b.Collection.Name
+		}
+		resp = append(resp, item)
+	}
+
+	meta := gin.H{"page": page, "page_size": pageSize, "total": total}
+	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgBookmarksFetched, gin.H{"bookmarks": resp, "meta": meta})
+}
+
+// ListCollections godoc
+// @Summary Lấy danh sách bộ sưu tập
+// @Description Lấy các bộ sưu tập trong danh sách đọc của user hiện tại
+// @Tags bookmarks
+// @Security BearerAuth
+// @Produce  json
+// @Success 200 {object} utils.APIResponse "Danh sách bộ sưu tập"
+// @Failure 500 {object} utils.APIResponse "Lỗi server"
+// @Router /users/me/bookmark-collections [get]
+func (c *BookmarkController) ListCollections(ctx *gin.Context) {
+	uid, ok := utils.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	collections, err := c.service.ListCollections(uid)
+	if err != nil {
+		utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
+		return
+	}
+	resp := []dto.BookmarkCollectionResponse{}
+	for _, col := range collections {
+		resp = append(resp, dto.BookmarkCollectionResponse{ID: col.ID, Name: col.Name})
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgBookmarksFetched, gin.H{"collections": resp})
+}
@@ -122,9 +122,11 @@ func (c *CategoryController) ListCategories(ctx *gin.Context) {
 	var resp []dto.CategoryResponse
 	for _, cat := range categories {
 		resp = append(resp, dto.CategoryResponse{
-			ID:   cat.ID,
-			Name: cat.Name,
-			Slug: cat.Slug,
+			ID:                    cat.ID,
+			Name:                  cat.Name,
+			Slug:                  cat.Slug,
+			CommentsEnabled:       cat.CommentsEnabled == nil || *cat.CommentsEnabled,
+			CommentsAutoCloseDays: cat.CommentsAutoCloseDays,
 		})
 	}
 	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgCategoriesFetched, gin.H{"categories": resp})
@@ -155,11 +157,13 @@ func (c *CategoryController) AdminListCategories(ctx *gin.Context) {
 			})
 		}
 		resp = append(resp, dto.AdminCategoryResponse{
-			ID:        cat.ID,
-			Name:      cat.Name,
-			Slug:      cat.Slug,
-			PostCount: len(posts),
-			Posts:     posts,
+			ID:                    cat.ID,
+			Name:                  cat.Name,
+			Slug:                  cat.Slug,
+			CommentsEnabled:       cat.CommentsEnabled == nil || *cat.CommentsEnabled,
+			CommentsAutoCloseDays: cat.CommentsAutoCloseDays,
+			PostCount:             len(posts),
+			Posts:                 posts,
 		})
 	}
 	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgAdminCategoriesFetched, gin.H{"categories": resp})
@@ -3,12 +3,22 @@ package controllers
 import (
 	"blog-api/internal/dto"
 	"blog-api/internal/services"
+	"blog-api/pkg/pubsub"
 	"blog-api/pkg/utils"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
+// sseKeepAlive is how often an idle comment stream sends a comment line so proxies keep it open.
+const sseKeepAlive = 15 * time.Second
+
 type CommentController struct {
 	service *services.CommentService
 }
@@ -28,6 +38,8 @@ func NewCommentController(service *services.CommentService) *CommentController {
 // @Param   comment  body  dto.CreateCommentRequest  true  "Nội dung bình luận"
 // @Success 201 {object} utils.APIResponse "Tạo bình luận thành công"
 // @Failure 400 {object} utils.APIResponse "Lỗi xác thực hoặc dữ liệu không hợp lệ"
+// @Failure 403 {object} utils.APIResponse "Bài viết đã khóa bình luận"
+// @Failure 404 {object} utils.APIResponse "Không tìm thấy bài viết"
 // @Failure 500 {object} utils.APIResponse "Lỗi server"
 // @Router /posts/{post_id}/comments [post]
 func (c *CommentController) CreateComment(ctx *gin.Context) {
@@ -49,6 +61,18 @@ func (c *CommentController) CreateComment(ctx *gin.Context) {
 	req.PostID = postID
 
 	if err := c.service.CreateComment(&req, uint(uid)); err != nil {
+		if errors.Is(err, services.ErrCommentsLocked) {
+			utils.SendFail(ctx, http.StatusForbidden, "COMMENTS_LOCKED", utils.ErrCommentsLocked, nil)
+			return
+		}
+		if errors.Is(err, services.ErrPostNotFound) {
+			utils.SendFail(ctx, http.StatusNotFound, "404", utils.ErrPostNotFound, nil)
+			return
+		}
+		if errors.Is(err, services.ErrInvalidParent) {
+			utils.SendFail(ctx, http.StatusBadRequest, "400", utils.ErrInvalidParentComment, nil)
+			return
+		}
 		utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
 		return
 	}
@@ -66,7 +90,9 @@ func (c *CommentController) CreateComment(ctx *gin.Context) {
 // @Param   comment     body  dto.UpdateCommentRequest  true  "Nội dung cập nhật"
 // @Success 200 {object} utils.APIResponse "Cập nhật thành công"
 // @Failure 400 {object} utils.APIResponse "Lỗi xác thực"
-// @Failure 404 {object} utils.APIResponse "Không tìm thấy bình luận"
+// @Failure 403 {object} utils.APIResponse "Bài viết đã khóa bình luận"
+// @Failure 404 {object} utils.APIResponse "Không tìm thấy bình luận hoặc bài viết"
+// @Failure 500 {object} utils.APIResponse "Lỗi server"
 // @Router /comments/{comment_id} [put]
 func (c *CommentController) UpdateComment(ctx *gin.Context) {
 	var req dto.UpdateCommentRequest
@@ -81,7 +107,16 @@ func (c *CommentController) UpdateComment(ctx *gin.Context) {
 	}
 
 	if err := c.service.UpdateComment(commentID, req.Content); err != nil {
-		utils.SendFail(ctx, http.StatusNotFound, "404", utils.ErrCategoryNotFound, nil)
+		switch {
+		case errors.Is(err, services.ErrCommentsLocked):
+			utils.SendFail(ctx, http.StatusForbidden, "COMMENTS_LOCKED", utils.ErrCommentsLocked, nil)
+		case errors.Is(err, services.ErrCommentNotFound):
+			utils.SendFail(ctx, http.StatusNotFound, "404", utils.ErrCommentNotFound, nil)
+		case errors.Is(err, services.ErrPostNotFound):
+			utils.SendFail(ctx, http.StatusNotFound, "404", utils.ErrPostNotFound, nil)
+		default:
+			utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
+		}
 		return
 	}
 	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgCategoryUpdated, nil)
@@ -96,6 +131,7 @@ func (c *CommentController) UpdateComment(ctx *gin.Context) {
 // @Param   comment_id  path  int  true  "ID bình luận"
 // @Success 200 {object} utils.APIResponse "Xóa thành công"
 // @Failure 404 {object} utils.APIResponse "Không tìm thấy bình luận"
+// @Failure 500 {object} utils.APIResponse "Lỗi server"
 // @Router /comments/{comment_id} [delete]
 func (c *CommentController) DeleteComment(ctx *gin.Context) {
 	commentID, ok := utils.GetUintIDParam(ctx, "comment_id", utils.ErrInvalidCommentID)
@@ -103,7 +139,11 @@ func (c *CommentController) DeleteComment(ctx *gin.Context) {
 		return
 	}
 	if err := c.service.DeleteComment(commentID); err != nil {
-		utils.SendFail(ctx, http.StatusNotFound, "404", utils.ErrCategoryNotFound, nil)
+		if errors.Is(err, services.ErrCommentNotFound) {
+			utils.SendFail(ctx, http.StatusNotFound, "404", utils.ErrCommentNotFound, nil)
+			return
+		}
+		utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
 		return
 	}
 	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgCategoryDeleted, nil)
@@ -121,7 +161,7 @@ func (c *CommentController) DeleteComment(ctx *gin.Context) {
 // @Failure 500 {object} utils.APIResponse "Lỗi server"
 // @Router /posts/{post_id}/comments [get]
 func (c *CommentController) GetCommentsByPost(ctx *gin.Context) {
-	postID, ok := utils.GetUintIDParam(ctx, "id", utils.ErrInvalidPostID)
+	postID, ok := utils.GetUintIDParam(ctx, "post_id", utils.ErrInvalidPostID)
 	if !ok {
 		return
 	}
@@ -139,14 +179,20 @@ func (c *CommentController) GetCommentsByPost(ctx *gin.Context) {
 
 	var resp []dto.CommentResponse
 	for _, cmt := range comments {
-		resp = append(resp, dto.CommentResponse{
-			ID:        cmt.ID,
-			PostID:    cmt.PostID,
-			UserID:    cmt.UserID,
-			Content:   cmt.Content,
-			CreatedAt: cmt.CreatedAt,
-			UpdatedAt: cmt.CreatedAt,
-		})
+		resp = append(resp, dto.NewCommentResponse(&cmt))
+	}
+	if uid, ok := utils.GetOptionalUserID(ctx); ok && len(resp) > 0 {
+		ids := make([]uint, len(resp))
+		for i, cmt := range resp {
+			ids[i] = cmt.ID
+		}
+		if reactions, err := c.service.ViewerReactions(ids, uid); err == nil {
+			for i := range resp {
+				if reaction, ok := reactions[resp[i].ID]; ok {
+					resp[i].MyReaction = &reaction
+				}
+			}
+		}
 	}
 
 	utils.SendSuccess(ctx, http.StatusOK, "COMMENTS_FETCHED", "Lấy danh sách bình luận thành công", gin.H{
@@ -157,4 +203,78 @@ func (c *CommentController) GetCommentsByPost(ctx *gin.Context) {
         "page_size": pageSize,
     },
 })
-}
\ No newline at end of file
+}
+
+// StreamComments godoc
+// @Summary Theo dõi bình luận theo thời gian thực
+// @Description Stream Server-Sent Events các sự kiện comment.created, comment.updated, comment.deleted của bài viết. Gửi header Last-Event-ID để tiếp tục từ sự kiện cuối cùng đã nhận. Nếu không thể gửi lại mọi sự kiện đã bỏ lỡ, server gửi sự kiện reset rồi đóng luồng; client cần tải lại bình luận.
+// @Tags comments
+// @Produce  text/event-stream
+// @Param   post_id        path    int     true   "ID bài viết"
+// @Param   Last-Event-ID  header  string  false  "ID sự kiện cuối cùng đã nhận"
+// @Success 200 {string} string "Luồng sự kiện"
+// @Failure 400 {object} utils.APIResponse "Last-Event-ID không hợp lệ"
+// @Failure 404 {object} utils.APIResponse "Không tìm thấy bài viết"
+// @Router /posts/{post_id}/comments/stream [get]
+func (c *CommentController) StreamComments(ctx *gin.Context) {
+	postID, ok := utils.GetUintIDParam(ctx, "post_id", utils.ErrInvalidPostID)
+	if !ok {
+		return
+	}
+
+	lastEventID := ctx.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = ctx.Query("last_event_id")
+	}
+	var lastID uint64
+	if lastEventID != "" {
+		parsed, err := strconv.ParseUint(lastEventID, 10, 64)
+		if err != nil {
+			utils.SendFail(ctx, http.StatusBadRequest, "400", utils.ErrInvalidLastEventID, nil)
+			return
+		}
+		lastID = parsed
+	}
+
+	events, err := c.service.SubscribeComments(ctx.Request.Context(), postID, lastID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.SendFail(ctx, http.StatusNotFound, "404", utils.ErrPostNotFound, nil)
+			return
+		}
+		utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
+		return
+	}
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	// the stream outlives the server's write timeout
+	http.NewResponseController(ctx.Writer).SetWriteDeadline(time.Time{})
+	ctx.Status(http.StatusOK)
+	fmt.Fprint(ctx.Writer, "retry: 3000\n\n")
+	ctx.Writer.Flush()
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+
+	ctx.Stream(func(w io.Writer) bool {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				return false
+			}
+			if ev.Type == pubsub.TypeReset {
+				// clear the client's Last-Event-ID, so it reconnects without resuming
+				fmt.Fprintf(w, "id: \nevent: %s\ndata: %s\n\n", ev.Type, ev.Data)
+				return true
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, ev.Data)
+			return true
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			return true
+		}
+	})
+}
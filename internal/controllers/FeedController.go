@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"blog-api/internal/services"
+	"blog-api/pkg/feed"
+	"blog-api/pkg/utils"
+	"errors"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type FeedController struct {
+	service *services.FeedService
+}
+
+func NewFeedController(service *services.FeedService) *FeedController {
+	return &FeedController{service: service}
+}
+
+// SiteFeed godoc
+// @Summary Feed bài viết mới nhất
+// @Description Feed RSS 2.0, Atom 1.0 hoặc JSON Feed 1.1 của các bài viết đã xuất bản mới nhất. Hỗ trợ ETag/If-None-Match và Last-Modified/If-Modified-Since.
+// @Tags feeds
+// @Produce  xml
+// @Produce  json
+// @Success 200 {string} string "Feed"
+// @Success 304 {string} string "Feed không thay đổi"
+// @Router /feed.rss [get]
+// @Router /feed.atom [get]
+// @Router /feed.json [get]
+func (c *FeedController) SiteFeed(ctx *gin.Context) {
+	f, err := c.service.SiteFeed(ctx.Request.Context(), ctx.Request.URL.Path)
+	c.serve(ctx, f, err)
+}
+
+// CategoryFeed godoc
+// @Summary Feed bài viết theo danh mục
+// @Description Feed RSS 2.0, Atom 1.0 hoặc JSON Feed 1.1 của các bài viết mới nhất trong một danh mục
+// @Tags feeds
+// @Produce  xml
+// @Produce  json
+// @Param   slug  path  string  true  "Slug danh mục"
+// @Success 200 {string} string "Feed"
+// @Success 304 {string} string "Feed không thay đổi"
+// @Failure 404 {object} utils.APIResponse "Không tìm thấy danh mục"
+// @Router /categories/{slug}/feed.rss [get]
+// @Router /categories/{slug}/feed.atom [get]
+// @Router /categories/{slug}/feed.json [get]
+func (c *FeedController) CategoryFeed(ctx *gin.Context) {
+	f, err := c.service.CategoryFeed(ctx.Request.Context(), ctx.Param("slug"), ctx.Request.URL.Path)
+	c.serve(ctx, f, err)
+}
+
+// AuthorFeed godoc
+// @Summary Feed bài viết theo tác giả
+// @Description Feed RSS 2.0, Atom 1.0 hoặc JSON Feed 1.1 của các bài viết mới nhất của một tác giả
+// @Tags feeds
+// @Produce  xml
+// @Produce  json
+// @Param   username  path  string  true  "Username tác giả"
+// @Success 200 {string} string "Feed"
+// @Success 304 {string} string "Feed không thay đổi"
+// @Failure 404 {object} utils.APIResponse "Không tìm thấy tác giả"
+// @Router /users/{username}/feed.rss [get]
+// @Router /users/{username}/feed.atom [get]
+// @Router /users/{username}/feed.json [get]
+func (c *FeedController) AuthorFeed(ctx *gin.Context) {
+	f, err := c.service.AuthorFeed(ctx.Request.Context(), ctx.Param("username"), ctx.Request.URL.Path)
+	c.serve(ctx, f, err)
+}
+
+// serve renders the feed in the format named by the route's extension.
+func (c *FeedController) serve(ctx *gin.Context, f *feed.Feed, err error) {
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.SendFail(ctx, http.StatusNotFound, "404", utils.ErrFeedNotFound, nil)
+			return
+		}
+		utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
+		return
+	}
+
+	format := strings.TrimPrefix(path.Ext(ctx.FullPath()), ".")
+	ctx.Header("Cache-Control", "public, max-age=300")
+	if utils.NotModified(ctx, f.ETag(), f.Updated()) {
+		return
+	}
+	body, err := feed.Render(f, format)
+	if err != nil {
+		utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
+		return
+	}
+	ctx.Data(http.StatusOK, feed.Formats[format], body)
+}
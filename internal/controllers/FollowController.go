@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"blog-api/internal/services"
+	"blog-api/pkg/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type FollowController struct {
+	service *services.FollowService
+}
+
+func NewFollowController(service *services.FollowService) *FollowController {
+	return &FollowController{service: service}
+}
+
+// FollowUser godoc
+// @Summary Theo dõi tác giả
+// @Description User hiện tại theo dõi một tác giả, bài viết của tác giả sẽ xuất hiện trong feed
+// @Tags follows
+// @Security BearerAuth
+// @Produce  json
+// @Param   username  path  string  true  "Username tác giả"
+// @Success 200 {object} utils.APIResponse "Đã theo dõi"
+// @Failure 400 {object} utils.APIResponse "Không thể tự theo dõi"
+// @Failure 404 {object} utils.APIResponse "Không tìm thấy user"
+// @Router /users/{username}/follow [post]
+func (c *FollowController) FollowUser(ctx *gin.Context) {
+	uid, ok := utils.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	if err := c.service.FollowUser(uid, ctx.Param("username")); err != nil {
+		c.sendUserError(ctx, err)
+		return
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgFollowed, nil)
+}
+
+// UnfollowUser godoc
+// @Summary Bỏ theo dõi tác giả
+// @Description User hiện tại bỏ theo dõi một tác giả
+// @Tags follows
+// @Security BearerAuth
+// @Produce  json
+// @Param   username  path  string  true  "Username tác giả"
+// @Success 200 {object} utils.APIResponse "Đã bỏ theo dõi"
+// @Failure 404 {object} utils.APIResponse "Không tìm thấy user hoặc chưa theo dõi"
+// @Router /users/{username}/follow [delete]
+func (c *FollowController) UnfollowUser(ctx *gin.Context) {
+	uid, ok := utils.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	if err := c.service.UnfollowUser(uid, ctx.Param("username")); err != nil {
+		c.sendUserError(ctx, err)
+		return
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgUnfollowed, nil)
+}
+
+// FollowCategory godoc
+// @Summary Theo dõi danh mục
+// @Description User hiện tại theo dõi một danh mục, bài viết trong danh mục sẽ xuất hiện trong feed
+// @Tags follows
+// @Security BearerAuth
+// @Produce  json
+// @Param   slug  path  string  true  "Slug danh mục"
+// @Success 200 {object} utils.APIResponse "Đã theo dõi"
+// @Failure 404 {object} utils.APIResponse "Không tìm thấy danh mục"
+// @Router /categories/{slug}/follow [post]
+func (c *FollowController) FollowCategory(ctx *gin.Context) {
+	uid, ok := utils.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	if err := c.service.FollowCategory(uid, ctx.Param("slug")); err != nil {
+		c.sendCategoryError(ctx, err)
+		return
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgFollowed, nil)
+}
+
+// UnfollowCategory godoc
+// @Summary Bỏ theo dõi danh mục
+// @Description User hiện tại bỏ theo dõi một danh mục
+// @Tags follows
+// @Security BearerAuth
+// @Produce  json
+// @Param   slug  path  string  true  "Slug danh mục"
+// @Success 200 {object} utils.APIResponse "Đã bỏ theo dõi"
+// @Failure 404 {object} utils.APIResponse "Không tìm thấy danh mục hoặc chưa theo dõi"
+// @Router /categories/{slug}/follow [delete]
+func (c *FollowController) UnfollowCategory(ctx *gin.Context) {
+	uid, ok := utils.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	if err := c.service.UnfollowCategory(uid, ctx.Param("slug")); err != nil {
+		c.sendCategoryError(ctx, err)
+		return
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgUnfollowed, nil)
+}
+
+func (c *FollowController) sendUserError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrCannotFollowSelf):
+		utils.SendFail(ctx, http.StatusBadRequest, "400", utils.ErrCannotFollowSelf, nil)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		utils.SendFail(ctx, http.StatusNotFound, "404", utils.ErrFollowNotFound, nil)
+	default:
+		utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
+	}
+}
+
+func (c *FollowController) sendCategoryError(ctx *gin.Context, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		utils.SendFail(ctx, http.StatusNotFound, "404", utils.ErrFollowNotFound, nil)
+		return
+	}
+	utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
+}
@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"blog-api/pkg/health"
+	"blog-api/pkg/utils"
+	"blog-api/pkg/version"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthController struct {
+	checker *health.Checker
+}
+
+func NewHealthController(checker *health.Checker) *HealthController {
+	return &HealthController{checker: checker}
+}
+
+// Healthz godoc
+// @Summary Kiểm tra sống (liveness)
+// @Description Luôn trả về 200 khi tiến trình còn phục vụ được request; không kiểm tra các phụ thuộc
+// @Tags health
+// @Produce  json
+// @Success 200 {object} utils.APIResponse "Dịch vụ đang chạy"
+// @Router /healthz [get]
+func (c *HealthController) Healthz(ctx *gin.Context) {
+	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgAlive, nil)
+}
+
+// Readyz godoc
+// @Summary Kiểm tra sẵn sàng (readiness)
+// @Description Kiểm tra kết nối cơ sở dữ liệu, migration chưa áp dụng và các worker nền, mỗi mục có thời gian chờ riêng. Trả về 503 khi có mục lỗi hoặc khi server đang tắt.
+// @Tags health
+// @Produce  json
+// @Success 200 {object} utils.APIResponse{data=health.Report} "Sẵn sàng nhận request"
+// @Failure 503 {object} utils.APIResponse{data=health.Report} "Chưa sẵn sàng"
+// @Router /readyz [get]
+func (c *HealthController) Readyz(ctx *gin.Context) {
+	report := c.checker.Run(ctx.Request.Context())
+	if !report.Ready {
+		utils.SendFail(ctx, http.StatusServiceUnavailable, "503", utils.ErrNotReady, report)
+		return
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgReady, report)
+}
+
+// Version godoc
+// @Summary Thông tin phiên bản
+// @Description Commit git, thời điểm build và phiên bản Go của bản build đang chạy
+// @Tags health
+// @Produce  json
+// @Success 200 {object} utils.APIResponse{data=version.Info} "Thông tin phiên bản"
+// @Router /version [get]
+func (c *HealthController) Version(ctx *gin.Context) {
+	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgVersionFetched, version.Get())
+}
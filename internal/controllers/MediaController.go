@@ -0,0 +1,159 @@
+package controllers
+
+import (
+	"blog-api/internal/dto"
+	"blog-api/internal/entities"
+	"blog-api/internal/services"
+	"blog-api/pkg/utils"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MediaController struct {
+	service *services.MediaService
+}
+
+func NewMediaController(service *services.MediaService) *MediaController {
+	return &MediaController{service: service}
+}
+
+// multipartOverhead leaves room for the multipart boundaries and headers around the file.
+const multipartOverhead = 64 << 10
+
+// UploadMedia godoc
+// @Summary Tải ảnh lên thư viện
+// @Description Tải một ảnh (JPEG, PNG, GIF, WebP) vào thư viện của user hiện tại. Loại file được nhận diện từ nội dung, metadata (EXIF, GPS...) bị xóa, chỉ giữ lại hướng xoay của ảnh JPEG. Dùng id trả về làm thumbnail_media_id của bài viết.
+// @Tags media
+// @Security BearerAuth
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param   file  formData  file  true  "File ảnh"
+// @Success 201 {object} utils.APIResponse{data=dto.MediaResponse} "Tải lên thành công"
+// @Failure 400 {object} utils.APIResponse "Thiếu file, định dạng không hỗ trợ hoặc ảnh quá nhiều điểm ảnh"
+// @Failure 413 {object} utils.APIResponse "File quá lớn"
+// @Router /media [post]
+func (c *MediaController) UploadMedia(ctx *gin.Context) {
+	c.upload(ctx, c.service.Upload, utils.MsgMediaUploaded)
+}
+
+// UploadAvatar godoc
+// @Summary Tải ảnh đại diện
+// @Description Tải ảnh lên thư viện của user hiện tại và đặt làm avatar
+// @Tags media
+// @Security BearerAuth
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param   file  formData  file  true  "File ảnh"
+// @Success 201 {object} utils.APIResponse{data=dto.MediaResponse} "Cập nhật avatar thành công"
+// @Failure 400 {object} utils.APIResponse "Thiếu file, định dạng không hỗ trợ hoặc ảnh quá nhiều điểm ảnh"
+// @Failure 413 {object} utils.APIResponse "File quá lớn"
+// @Router /users/me/avatar [post]
+func (c *MediaController) UploadAvatar(ctx *gin.Context) {
+	c.upload(ctx, c.service.UploadAvatar, utils.MsgAvatarUpdated)
+}
+
+type uploadFunc func(ctx context.Context, ownerID uint, filename string, r io.Reader) (*entities.Media, error)
+
+func (c *MediaController) upload(ctx *gin.Context, store uploadFunc, message string) {
+	uid, ok := utils.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, c.service.MaxBytes()+multipartOverhead)
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			utils.SendFail(ctx, http.StatusRequestEntityTooLarge, "413", utils.ErrFileTooLarge, nil)
+			return
+		}
+		utils.SendFail(ctx, http.StatusBadRequest, "400", utils.ErrMissingFile, nil)
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		utils.SendFail(ctx, http.StatusBadRequest, "400", utils.ErrMissingFile, nil)
+		return
+	}
+	defer file.Close()
+
+	media, err := store(ctx.Request.Context(), uid, header.Filename, file)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrFileTooLarge):
+			utils.SendFail(ctx, http.StatusRequestEntityTooLarge, "413", utils.ErrFileTooLarge, nil)
+		case errors.Is(err, services.ErrUnsupportedMedia), errors.Is(err, services.ErrImageTooLarge):
+			utils.SendFail(ctx, http.StatusBadRequest, "400", err.Error(), nil)
+		default:
+			utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
+		}
+		return
+	}
+	utils.SendSuccess(ctx, http.StatusCreated, "201", message, dto.NewMediaResponse(media))
+}
+
+// GetMedia godoc
+// @Summary Lấy chi tiết ảnh
+// @Description Lấy một ảnh trong thư viện của user hiện tại, kèm trạng thái xử lý (pending, processing, ready, failed), lỗi nếu có và các biến thể kích thước
+// @Tags media
+// @Security BearerAuth
+// @Produce  json
+// @Param   id  path  int  true  "ID ảnh"
+// @Success 200 {object} utils.APIResponse{data=dto.MediaResponse} "Chi tiết ảnh"
+// @Failure 404 {object} utils.APIResponse "Không tìm thấy ảnh"
+// @Router /media/{id} [get]
+func (c *MediaController) GetMedia(ctx *gin.Context) {
+	id, ok := utils.GetUintIDParam(ctx, "id", utils.ErrInvalidMediaID)
+	if !ok {
+		return
+	}
+	uid, ok := utils.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	media, err := c.service.GetMedia(uid, id)
+	if err != nil {
+		utils.SendFail(ctx, http.StatusNotFound, "404", utils.ErrMediaNotFound, nil)
+		return
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgMediaFetched, dto.NewMediaResponse(media))
+}
+
+// ListMedia godoc
+// @Summary Lấy thư viện ảnh
+// @Description Lấy các ảnh đã tải lên của user hiện tại, mới nhất trước, có phân trang
+// @Tags media
+// @Security BearerAuth
+// @Produce  json
+// @Param   page       query  int  false  "Trang hiện tại"
+// @Param   page_size  query  int  false  "Số lượng mỗi trang"
+// @Success 200 {object} utils.APIResponse "Danh sách ảnh và meta"
+// @Failure 500 {object} utils.APIResponse "Lỗi server"
+// @Router /users/me/media [get]
+func (c *MediaController) ListMedia(ctx *gin.Context) {
+	uid, ok := utils.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	page, pageSize, ok := utils.GetPaginationParams(ctx)
+	if !ok {
+		return
+	}
+
+	items, total, err := c.service.ListMedia(uid, page, pageSize)
+	if err != nil {
+		utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
+		return
+	}
+	resp := []dto.MediaResponse{}
+	for _, m := range items {
+		resp = append(resp, dto.NewMediaResponse(&m))
+	}
+	meta := gin.H{"page": page, "page_size": pageSize, "total": total}
+	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgMediaFetched, gin.H{"media": resp, "meta": meta})
+}
@@ -0,0 +1,189 @@
+package controllers
+
+import (
+	"blog-api/internal/dto"
+	"blog-api/internal/services"
+	"blog-api/pkg/utils"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type NotificationController struct {
+	service *services.NotificationService
+}
+
+func NewNotificationController(service *services.NotificationService) *NotificationController {
+	return &NotificationController{service: service}
+}
+
+// ListNotifications godoc
+// @Summary Lấy danh sách thông báo
+// @Description Lấy thông báo của user hiện tại, mới nhất trước, phân trang bằng cursor, kèm số thông báo chưa đọc
+// @Tags notifications
+// @Security BearerAuth
+// @Produce  json
+// @Param   cursor       query  int   false  "Cursor (next_cursor của trang trước)"
+// @Param   limit        query  int   false  "Số lượng mỗi trang (tối đa 100)"
+// @Param   unread_only  query  bool  false  "Chỉ lấy thông báo chưa đọc"
+// @Success 200 {object} utils.APIResponse "Danh sách thông báo"
+// @Failure 400 {object} utils.APIResponse "Tham số không hợp lệ"
+// @Failure 500 {object} utils.APIResponse "Lỗi server"
+// @Router /users/me/notifications [get]
+func (c *NotificationController) ListNotifications(ctx *gin.Context) {
+	uid, ok := utils.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	var cursor uint64
+	if v := ctx.Query("cursor"); v != "" {
+		parsed, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			utils.SendFail(ctx, http.StatusBadRequest, "400", utils.ErrInvalidCursorParam, nil)
+			return
+		}
+		cursor = parsed
+	}
+	limit := 0
+	if v := ctx.Query("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			utils.SendFail(ctx, http.StatusBadRequest, "400", utils.ErrInvalidLimitParam, nil)
+			return
+		}
+		limit = parsed
+	}
+	unreadOnly := ctx.Query("unread_only") == "true"
+
+	notifications, next, err := c.service.ListNotifications(uid, uint(cursor), limit, unreadOnly)
+	if err != nil {
+		utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
+		return
+	}
+	unread, err := c.service.CountUnread(uid)
+	if err != nil {
+		utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
+		return
+	}
+
+	resp := []dto.NotificationResponse{}
+	for _, n := range notifications {
+		resp = append(resp, dto.NewNotificationResponse(&n))
+	}
+	meta := gin.H{"unread_count": unread, "next_cursor": nil}
+	if next > 0 {
+		meta["next_cursor"] = next
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgNotificationsFetched, gin.H{"notifications": resp, "meta": meta})
+}
+
+// MarkRead godoc
+// @Summary Đánh dấu đã đọc
+// @Description Đánh dấu một thông báo của user hiện tại là đã đọc
+// @Tags notifications
+// @Security BearerAuth
+// @Produce  json
+// @Param   id  path  int  true  "ID thông báo"
+// @Success 200 {object} utils.APIResponse "Đã đánh dấu đã đọc"
+// @Failure 404 {object} utils.APIResponse "Không tìm thấy thông báo"
+// @Router /users/me/notifications/{id}/read [put]
+func (c *NotificationController) MarkRead(ctx *gin.Context) {
+	uid, ok := utils.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	id, ok := utils.GetUintIDParam(ctx, "id", utils.ErrInvalidNotificationID)
+	if !ok {
+		return
+	}
+
+	if err := c.service.MarkRead(uid, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.SendFail(ctx, http.StatusNotFound, "404", utils.ErrNotificationNotFound, nil)
+			return
+		}
+		utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
+		return
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgNotificationRead, nil)
+}
+
+// MarkAllRead godoc
+// @Summary Đánh dấu tất cả đã đọc
+// @Description Đánh dấu tất cả thông báo của user hiện tại là đã đọc
+// @Tags notifications
+// @Security BearerAuth
+// @Produce  json
+// @Success 200 {object} utils.APIResponse "Đã đánh dấu tất cả đã đọc"
+// @Failure 500 {object} utils.APIResponse "Lỗi server"
+// @Router /users/me/notifications/read-all [put]
+func (c *NotificationController) MarkAllRead(ctx *gin.Context) {
+	uid, ok := utils.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	if err := c.service.MarkAllRead(uid); err != nil {
+		utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
+		return
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgNotificationsRead, nil)
+}
+
+// GetPreferences godoc
+// @Summary Lấy cài đặt thông báo
+// @Description Lấy danh sách loại thông báo và trạng thái bật/tắt của user hiện tại
+// @Tags notifications
+// @Security BearerAuth
+// @Produce  json
+// @Success 200 {object} utils.APIResponse "Cài đặt thông báo"
+// @Failure 500 {object} utils.APIResponse "Lỗi server"
+// @Router /users/me/notification-preferences [get]
+func (c *NotificationController) GetPreferences(ctx *gin.Context) {
+	uid, ok := utils.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	prefs, err := c.service.GetPreferences(uid)
+	if err != nil {
+		utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
+		return
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgPreferencesFetched, gin.H{"preferences": prefs})
+}
+
+// UpdatePreferences godoc
+// @Summary Cập nhật cài đặt thông báo
+// @Description Bật/tắt từng loại thông báo cho user hiện tại
+// @Tags notifications
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param   body  body  dto.UpdateNotificationPreferencesRequest  true  "Loại thông báo và trạng thái bật/tắt"
+// @Success 200 {object} utils.APIResponse "Cập nhật thành công"
+// @Failure 400 {object} utils.APIResponse "Lỗi xác thực hoặc loại thông báo không hợp lệ"
+// @Router /users/me/notification-preferences [put]
+func (c *NotificationController) UpdatePreferences(ctx *gin.Context) {
+	uid, ok := utils.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	var req dto.UpdateNotificationPreferencesRequest
+	if validationErrs := utils.BindAndValidate(ctx, &req); validationErrs != nil {
+		utils.SendFail(ctx, http.StatusBadRequest, "400", "VALIDATION_FAILED", validationErrs)
+		return
+	}
+	if err := c.service.UpdatePreferences(uid, req.Preferences); err != nil {
+		utils.SendFail(ctx, http.StatusBadRequest, "400", err.Error(), nil)
+		return
+	}
+	prefs, err := c.service.GetPreferences(uid)
+	if err != nil {
+		utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
+		return
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgPreferencesUpdated, gin.H{"preferences": prefs})
+}
@@ -2,19 +2,28 @@ package controllers
 
 import (
 	"blog-api/internal/dto"
+	"blog-api/internal/entities"
 	"blog-api/internal/services"
 	"blog-api/pkg/utils"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type PostController struct {
-	service *services.PostService
+	service     *services.PostService
+	viewCounter *services.ViewCounter
 }
 
-func NewPostController(service *services.PostService) *PostController {
-	return &PostController{service: service}
+func NewPostController(service *services.PostService, viewCounter *services.ViewCounter) *PostController {
+	return &PostController{service: service, viewCounter: viewCounter}
 }
 
 // CreatePost godoc
@@ -68,6 +77,10 @@ func (c *PostController) CreatePost(ctx *gin.Context) {
 
 	// Create the post
 	if err := c.service.CreatePost(&req, uint(uid)); err != nil {
+		if errors.Is(err, services.ErrInvalidThumbnailMedia) {
+			utils.SendFail(ctx, http.StatusBadRequest, "400", err.Error(), nil)
+			return
+		}
 		utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
 		return
 	}
@@ -99,13 +112,55 @@ func (c *PostController) UpdatePost(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.service.UpdatePost(uint(id), &req); err != nil {
+	uid, ok := utils.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.service.UpdatePost(uint(id), &req, uid); err != nil {
 		utils.SendFail(ctx, http.StatusBadRequest, "400", err.Error(), nil)
 		return
 	}
 	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgPostUpdated, nil)
 }
 
+// LockComments godoc
+// @Summary Khóa/mở khóa bình luận bài viết
+// @Description Khóa hoặc mở lại phần bình luận của bài viết, chỉ chủ sở hữu hoặc admin mới được phép
+// @Tags posts
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param   id    path  int  true  "ID bài viết"
+// @Param   body  body  dto.LockCommentsRequest  true  "Trạng thái khóa bình luận"
+// @Success 200 {object} utils.APIResponse "Cập nhật thành công"
+// @Failure 400 {object} utils.APIResponse "Lỗi xác thực"
+// @Failure 404 {object} utils.APIResponse "Không tìm thấy bài viết"
+// @Router /posts/{id}/comments/lock [put]
+func (c *PostController) LockComments(ctx *gin.Context) {
+	id, ok := utils.GetUintIDParam(ctx, "id", utils.ErrInvalidPostID)
+	if !ok {
+		return
+	}
+
+	var req dto.LockCommentsRequest
+	if validationErrs := utils.BindAndValidate(ctx, &req); validationErrs != nil {
+		utils.SendFail(ctx, http.StatusBadRequest, "400", "VALIDATION_FAILED", validationErrs)
+		return
+	}
+
+	if err := c.service.SetCommentsLocked(id, *req.Locked); err != nil {
+		utils.SendFail(ctx, http.StatusNotFound, "404", utils.ErrPostNotFound, nil)
+		return
+	}
+
+	msg := utils.MsgCommentsUnlocked
+	if *req.Locked {
+		msg = utils.MsgCommentsLocked
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "200", msg, nil)
+}
+
 // DeletePost godoc
 // @Summary Xóa bài viết
 // @Description Xóa bài viết, chỉ chủ sở hữu hoặc admin mới được phép
@@ -154,7 +209,7 @@ func (c *PostController) GetAllPosts(ctx *gin.Context) {
 		return
 	}
 
-	posts, total, err := c.service.ListPosts(title, content, category, author, "published", page, pageSize)
+	posts, total, err := c.service.ListPosts(ctx.Request.Context(), title, content, category, author, "published", page, pageSize)
 	if err != nil {
 		utils.SendFail(ctx, http.StatusInternalServerError, "500", utils.ErrCouldNotFetchPosts, nil)
 		return
@@ -163,12 +218,49 @@ func (c *PostController) GetAllPosts(ctx *gin.Context) {
 	for _, p := range posts {
 		resp = append(resp, dto.NewPostResponse(&p))
 	}
+	c.applyViewerState(ctx, resp)
 
 	meta := gin.H{"page": page, "page_size": pageSize, "total": total}
 	data := gin.H{"posts": resp, "meta": meta}
 	utils.SendSuccess(ctx, http.StatusOK, "200", utils.SearchSuccess, data)
 }
 
+// GetPostsByAuthor godoc
+// @Summary Lấy bài viết của tác giả
+// @Description Lấy các bài viết đã xuất bản của một tác giả, mới nhất trước, có phân trang
+// @Tags posts
+// @Produce  json
+// @Param   username   path   string  true   "Username tác giả"
+// @Param   page       query  int     false  "Trang hiện tại"
+// @Param   page_size  query  int     false  "Số lượng mỗi trang"
+// @Success 200 {object} utils.APIResponse "Danh sách bài viết và meta"
+// @Failure 404 {object} utils.APIResponse "Không tìm thấy user"
+// @Router /users/{username}/posts [get]
+func (c *PostController) GetPostsByAuthor(ctx *gin.Context) {
+	page, pageSize, ok := utils.GetPaginationParams(ctx)
+	if !ok {
+		return
+	}
+
+	posts, total, err := c.service.ListPostsByAuthor(ctx.Request.Context(), ctx.Param("username"), page, pageSize)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.SendFail(ctx, http.StatusNotFound, "404", utils.ErrUserNotFound, nil)
+			return
+		}
+		utils.SendFail(ctx, http.StatusInternalServerError, "500", utils.ErrCouldNotFetchPosts, nil)
+		return
+	}
+	resp := []dto.PostResponse{}
+	for _, p := range posts {
+		resp = append(resp, dto.NewPostResponse(&p))
+	}
+	c.applyViewerState(ctx, resp)
+
+	meta := gin.H{"page": page, "page_size": pageSize, "total": total}
+	utils.SendSuccess(ctx, http.StatusOK, "200", utils.SearchSuccess, gin.H{"posts": resp, "meta": meta})
+}
+
 // GetPostDetail godoc
 // @Summary Lấy chi tiết bài viết
 // @Description Lấy chi tiết một bài viết theo ID
@@ -188,5 +280,115 @@ func (c *PostController) GetPostDetail(ctx *gin.Context) {
 		utils.SendFail(ctx, http.StatusNotFound, "404", utils.ErrPostNotFound, nil)
 		return
 	}
-	utils.SendSuccess(ctx, http.StatusOK, "200", "article details successfully retrieved", gin.H{"post": dto.NewPostResponse(post)})
-}
\ No newline at end of file
+	resp := []dto.PostResponse{dto.NewPostResponse(post)}
+	c.applyViewerState(ctx, resp)
+	c.recordView(ctx, post)
+	utils.SendSuccess(ctx, http.StatusOK, "200", "article details successfully retrieved", gin.H{"post": resp[0]})
+}
+
+// recordView counts a read of a published post. Crawlers and authors reading their own
+// posts are not counted.
+func (c *PostController) recordView(ctx *gin.Context, post *entities.Post) {
+	if post.Status != "published" || utils.IsBot(ctx.GetHeader("User-Agent")) {
+		return
+	}
+	var visitor string
+	if uid, ok := utils.GetOptionalUserID(ctx); ok {
+		if uid == post.AuthorID {
+			return
+		}
+		visitor = "user:" + strconv.FormatUint(uint64(uid), 10)
+	} else {
+		// anonymous readers are told apart by address and browser, hashed so neither is kept
+		sum := sha256.Sum256([]byte(ctx.ClientIP() + "|" + ctx.GetHeader("User-Agent")))
+		visitor = hex.EncodeToString(sum[:16])
+	}
+	c.viewCounter.Record(post.ID, visitor, referrerHost(ctx.GetHeader("Referer")))
+}
+
+// referrerHost reduces a Referer header to its host, without a leading "www.".
+func referrerHost(referer string) string {
+	u, err := url.Parse(referer)
+	if err != nil || u.Hostname() == "" {
+		return ""
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if len(host) > 255 {
+		return ""
+	}
+	return host
+}
+
+// applyViewerState fills in the parts of post responses that depend on who is asking.
+func (c *PostController) applyViewerState(ctx *gin.Context, posts []dto.PostResponse) {
+	uid, ok := utils.GetOptionalUserID(ctx)
+	if !ok || len(posts) == 0 {
+		return
+	}
+	ids := make([]uint, len(posts))
+	for i, p := range posts {
+		ids[i] = p.ID
+	}
+	reactions, err := c.service.ViewerReactions(ids, uid)
+	if err != nil {
+		return
+	}
+	bookmarks, err := c.service.ViewerBookmarks(ids, uid)
+	if err != nil {
+		return
+	}
+	for i := range posts {
+		if reaction, ok := reactions[posts[i].ID]; ok {
+			posts[i].MyReaction = &reaction
+		}
+		posts[i].Bookmarked = bookmarks[posts[i].ID]
+	}
+}
+// GetFeed godoc
+// @Summary Lấy feed cá nhân
+// @Description Lấy bài viết của các tác giả và danh mục mà user hiện tại theo dõi, mới xuất bản trước, phân trang bằng cursor
+// @Tags posts
+// @Security BearerAuth
+// @Produce  json
+// @Param   cursor  query  string  false  "Cursor (next_cursor của trang trước)"
+// @Param   limit   query  int     false  "Số lượng mỗi trang (tối đa 100)"
+// @Success 200 {object} utils.APIResponse "Danh sách bài viết"
+// @Failure 400 {object} utils.APIResponse "Tham số không hợp lệ"
+// @Failure 500 {object} utils.APIResponse "Lỗi server"
+// @Router /users/me/feed [get]
+func (c *PostController) GetFeed(ctx *gin.Context) {
+	uid, ok := utils.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+	limit := 0
+	if v := ctx.Query("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			utils.SendFail(ctx, http.StatusBadRequest, "400", utils.ErrInvalidLimitParam, nil)
+			return
+		}
+		limit = parsed
+	}
+
+	posts, next, err := c.service.Feed(ctx.Request.Context(), uid, ctx.Query("cursor"), limit)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCursor) {
+			utils.SendFail(ctx, http.StatusBadRequest, "400", utils.ErrInvalidCursorParam, nil)
+			return
+		}
+		utils.SendFail(ctx, http.StatusInternalServerError, "500", utils.ErrCouldNotFetchPosts, nil)
+		return
+	}
+	resp := []dto.PostResponse{}
+	for _, p := range posts {
+		resp = append(resp, dto.NewPostResponse(&p))
+	}
+	c.applyViewerState(ctx, resp)
+
+	meta := gin.H{"next_cursor": nil}
+	if next != "" {
+		meta["next_cursor"] = next
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgFeedFetched, gin.H{"posts": resp, "meta": meta})
+}
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"blog-api/internal/services"
+	"blog-api/pkg/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type PostMetaController struct {
+	service *services.PostMetaService
+}
+
+func NewPostMetaController(service *services.PostMetaService) *PostMetaController {
+	return &PostMetaController{service: service}
+}
+
+// GetPostMeta godoc
+// @Summary Lấy metadata SEO của bài viết
+// @Description Trả về thẻ OpenGraph, Twitter card và JSON-LD BlogPosting của một bài viết đã xuất bản, kèm bản HTML sẵn để chèn vào <head>
+// @Tags posts
+// @Produce  json
+// @Param   post_id  path  int  true  "ID bài viết"
+// @Success 200 {object} utils.APIResponse{data=dto.PostMetaResponse} "Metadata bài viết"
+// @Failure 400 {object} utils.APIResponse "ID không hợp lệ"
+// @Failure 404 {object} utils.APIResponse "Không tìm thấy bài viết"
+// @Router /posts/{post_id}/meta [get]
+func (c *PostMetaController) GetPostMeta(ctx *gin.Context) {
+	id, ok := utils.GetUintIDParam(ctx, "post_id", utils.ErrInvalidPostID)
+	if !ok {
+		return
+	}
+	meta, err := c.service.PostMeta(uint(id))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.SendFail(ctx, http.StatusNotFound, "404", utils.ErrPostNotFound, nil)
+			return
+		}
+		utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
+		return
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgPostMetaFetched, meta)
+}
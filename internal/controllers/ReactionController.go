@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"blog-api/internal/dto"
+	"blog-api/internal/services"
+	"blog-api/pkg/utils"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type ReactionController struct {
+	service *services.ReactionService
+}
+
+func NewReactionController(service *services.ReactionService) *ReactionController {
+	return &ReactionController{service: service}
+}
+
+// ReactToPost godoc
+// @Summary Thả cảm xúc cho bài viết
+// @Description Thêm, đổi hoặc bỏ cảm xúc của user hiện tại với bài viết (gửi lại cùng loại để bỏ)
+// @Tags reactions
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param   post_id  path  int               true  "ID bài viết"
+// @Param   body     body  dto.ReactRequest  true  "Loại cảm xúc"
+// @Success 200 {object} utils.APIResponse "Cảm xúc hiện tại và số lượng theo loại"
+// @Failure 400 {object} utils.APIResponse "Loại cảm xúc không hợp lệ"
+// @Failure 404 {object} utils.APIResponse "Không tìm thấy bài viết"
+// @Router /posts/{post_id}/reactions [post]
+func (c *ReactionController) ReactToPost(ctx *gin.Context) {
+	postID, ok := utils.GetUintIDParam(ctx, "post_id", utils.ErrInvalidPostID)
+	if !ok {
+		return
+	}
+	c.react(ctx, utils.ErrPostNotFound, func(uid uint, reactionType string) (string, map[string]int64, error) {
+		return c.service.TogglePostReaction(postID, uid, reactionType)
+	})
+}
+
+// ReactToComment godoc
+// @Summary Thả cảm xúc cho bình luận
+// @Description Thêm, đổi hoặc bỏ cảm xúc của user hiện tại với bình luận (gửi lại cùng loại để bỏ)
+// @Tags reactions
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param   comment_id  path  int               true  "ID bình luận"
+// @Param   body        body  dto.ReactRequest  true  "Loại cảm xúc"
+// @Success 200 {object} utils.APIResponse "Cảm xúc hiện tại và số lượng theo loại"
+// @Failure 400 {object} utils.APIResponse "Loại cảm xúc không hợp lệ"
+// @Failure 404 {object} utils.APIResponse "Không tìm thấy bình luận"
+// @Router /comments/{comment_id}/reactions [post]
+func (c *ReactionController) ReactToComment(ctx *gin.Context) {
+	commentID, ok := utils.GetUintIDParam(ctx, "comment_id", utils.ErrInvalidCommentID)
+	if !ok {
+		return
+	}
+	c.react(ctx, utils.ErrCommentNotFound, func(uid uint, reactionType string) (string, map[string]int64, error) {
+		return c.service.ToggleCommentReaction(commentID, uid, reactionType)
+	})
+}
+
+func (c *ReactionController) react(ctx *gin.Context, notFoundMsg string, toggle func(uid uint, reactionType string) (string, map[string]int64, error)) {
+	var req dto.ReactRequest
+	if validationErrs := utils.BindAndValidate(ctx, &req); validationErrs != nil {
+		utils.SendFail(ctx, http.StatusBadRequest, "400", "VALIDATION_FAILED", validationErrs)
+		return
+	}
+
+	uid, ok := utils.GetUserIDFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	current, counts, err := toggle(uid, req.Type)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidReaction):
+			utils.SendFail(ctx, http.StatusBadRequest, "INVALID_REACTION", utils.ErrInvalidReaction, gin.H{"allowed": c.service.Types()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utils.SendFail(ctx, http.StatusNotFound, "404", notFoundMsg, nil)
+		default:
+			utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
+		}
+		return
+	}
+
+	resp := dto.ReactionResponse{Reactions: counts}
+	if current != "" {
+		resp.MyReaction = &current
+	}
+	utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgReactionUpdated, resp)
+}
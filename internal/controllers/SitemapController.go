@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"blog-api/internal/services"
+	"blog-api/pkg/utils"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SitemapController struct {
+	service *services.SitemapService
+}
+
+func NewSitemapController(service *services.SitemapService) *SitemapController {
+	return &SitemapController{service: service}
+}
+
+// GetIndex godoc
+// @Summary Sitemap index
+// @Description Sitemap index liệt kê các sitemap con của bài viết, danh mục và tác giả. Hỗ trợ ETag/If-None-Match và Last-Modified/If-Modified-Since.
+// @Tags sitemaps
+// @Produce  xml
+// @Success 200 {string} string "Sitemap index"
+// @Success 304 {string} string "Sitemap không thay đổi"
+// @Router /sitemap.xml [get]
+func (c *SitemapController) GetIndex(ctx *gin.Context) {
+	doc, err := c.service.Index()
+	c.serve(ctx, doc, false, err)
+}
+
+// GetSitemap godoc
+// @Summary Sitemap con
+// @Description Sitemap của bài viết đã xuất bản (posts-N.xml, tối đa 50.000 URL mỗi file), danh mục (categories.xml) hoặc tác giả (authors-N.xml). Thêm đuôi .gz để nhận bản nén gzip.
+// @Tags sitemaps
+// @Produce  xml
+// @Param   file  path  string  true  "Tên file, ví dụ posts-1.xml hoặc posts-1.xml.gz"
+// @Success 200 {string} string "Sitemap"
+// @Success 304 {string} string "Sitemap không thay đổi"
+// @Failure 404 {object} utils.APIResponse "Không tìm thấy sitemap"
+// @Router /sitemaps/{file} [get]
+func (c *SitemapController) GetSitemap(ctx *gin.Context) {
+	file := ctx.Param("file")
+	doc, err := c.service.Sitemap(file)
+	c.serve(ctx, doc, strings.HasSuffix(file, ".gz"), err)
+}
+
+func (c *SitemapController) serve(ctx *gin.Context, doc *services.SitemapDocument, gzipped bool, err error) {
+	if err != nil {
+		if errors.Is(err, services.ErrSitemapNotFound) {
+			utils.SendFail(ctx, http.StatusNotFound, "404", utils.ErrSitemapNotFound, nil)
+			return
+		}
+		utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
+		return
+	}
+
+	ctx.Header("Cache-Control", "public, max-age=300")
+	if gzipped {
+		if utils.NotModified(ctx, strings.TrimSuffix(doc.ETag, `"`)+`-gz"`, doc.LastMod) {
+			return
+		}
+		ctx.Data(http.StatusOK, "application/gzip", doc.Gzipped)
+		return
+	}
+	if utils.NotModified(ctx, doc.ETag, doc.LastMod) {
+		return
+	}
+	ctx.Data(http.StatusOK, "application/xml; charset=utf-8", doc.Body)
+}
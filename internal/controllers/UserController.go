@@ -4,9 +4,11 @@ import (
 	"blog-api/internal/dto"
 	"blog-api/internal/services"
 	"blog-api/pkg/utils"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type UserController struct {
@@ -115,15 +117,135 @@ func (c *UserController) GetMe(context *gin.Context){
         utils.SendFail(context, http.StatusNotFound, "404", utils.ErrUserNotFound, nil)
         return
     }
+    followers, following, err := c.UserService.FollowCounts(uint(uid))
+    if err != nil {
+        utils.SendFail(context, http.StatusInternalServerError, "500", err.Error(), nil)
+        return
+    }
 
     utils.SendSuccess(context, http.StatusOK, "200", "user information successfully retrieved", gin.H{
-        "id":       user.ID,
-        "email":    user.Email,
-        "username": user.Username,
-        "role":     user.Role,
+        "id":              user.ID,
+        "email":           user.Email,
+        "username":        user.Username,
+        "role":            user.Role,
+        "followers_count": followers,
+        "following_count": following,
     })
 }
 
+// GetPublicProfile godoc
+// @Summary Lấy hồ sơ công khai của tác giả
+// @Description Lấy hồ sơ công khai theo username: tên hiển thị, giới thiệu, avatar, website, ngày tham gia, số bài viết và số người theo dõi. Không bao gồm email và role.
+// @Tags users
+// @Produce  json
+// @Param   username  path  string  true  "Username"
+// @Success 200 {object} utils.APIResponse{data=dto.PublicProfileResponse} "Hồ sơ công khai"
+// @Failure 404 {object} utils.APIResponse "Không tìm thấy user"
+// @Router /users/{username} [get]
+func (c *UserController) GetPublicProfile(ctx *gin.Context) {
+    profile, err := c.UserService.GetPublicProfile(ctx.Param("username"))
+    if err != nil {
+        if errors.Is(err, gorm.ErrRecordNotFound) {
+            utils.SendFail(ctx, http.StatusNotFound, "404", utils.ErrUserNotFound, nil)
+            return
+        }
+        utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
+        return
+    }
+    utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgProfileFetched, profile)
+}
+
+// UpdateProfile godoc
+// @Summary Cập nhật hồ sơ công khai
+// @Description Cập nhật tên hiển thị, giới thiệu, avatar và website của user hiện tại. Chuỗi rỗng sẽ xóa trường tương ứng.
+// @Tags users
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param   body  body  dto.UpdateProfileRequest  true  "Thông tin hồ sơ"
+// @Success 200 {object} utils.APIResponse "Cập nhật thành công"
+// @Failure 400 {object} utils.APIResponse "Lỗi xác thực"
+// @Router /users/me/profile [put]
+func (c *UserController) UpdateProfile(ctx *gin.Context) {
+    var req dto.UpdateProfileRequest
+    if validationErrs := utils.BindAndValidate(ctx, &req); validationErrs != nil {
+        utils.SendFail(ctx, http.StatusBadRequest, "400", "VALIDATION_FAILED", validationErrs)
+        return
+    }
+    uid, ok := utils.GetUserIDFromContext(ctx)
+    if !ok {
+        return
+    }
+    if err := c.UserService.UpdateProfile(uid, &req); err != nil {
+        utils.SendFail(ctx, http.StatusBadRequest, "400", err.Error(), nil)
+        return
+    }
+    utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgProfileUpdated, nil)
+}
+
+// UpdateMe godoc
+// @Summary Cập nhật tài khoản của chính mình
+// @Description Đổi username và/hoặc email của user hiện tại. Username được kiểm tra trùng và đổi ngay; email mới chỉ có hiệu lực sau khi xác nhận qua link gửi tới địa chỉ đó. Không thể đổi role.
+// @Tags users
+// @Security BearerAuth
+// @Accept  json
+// @Produce  json
+// @Param   body  body  dto.UpdateMeRequest  true  "Username/email mới"
+// @Success 200 {object} utils.APIResponse "Cập nhật thành công"
+// @Failure 400 {object} utils.APIResponse "Lỗi xác thực"
+// @Failure 409 {object} utils.APIResponse "Username hoặc email đã tồn tại"
+// @Router /users/me [patch]
+func (c *UserController) UpdateMe(ctx *gin.Context) {
+    var req dto.UpdateMeRequest
+    if validationErrs := utils.BindAndValidate(ctx, &req); validationErrs != nil {
+        utils.SendFail(ctx, http.StatusBadRequest, "400", "VALIDATION_FAILED", validationErrs)
+        return
+    }
+    uid, ok := utils.GetUserIDFromContext(ctx)
+    if !ok {
+        return
+    }
+
+    emailPending, err := c.UserService.UpdateMe(ctx.Request.Context(), uid, &req)
+    if err != nil {
+        if errors.Is(err, services.ErrUsernameTaken) || errors.Is(err, services.ErrEmailTaken) {
+            utils.SendFail(ctx, http.StatusConflict, "409", err.Error(), nil)
+            return
+        }
+        utils.SendFail(ctx, http.StatusBadRequest, "400", err.Error(), nil)
+        return
+    }
+
+    message := utils.MsgProfileUpdated
+    if emailPending {
+        message = utils.MsgEmailChangePending
+    }
+    utils.SendSuccess(ctx, http.StatusOK, "200", message, gin.H{"email_pending": emailPending})
+}
+
+// ConfirmEmailChange godoc
+// @Summary Xác nhận email mới
+// @Description Xác nhận đổi email bằng token trong link đã gửi tới địa chỉ mới
+// @Tags users
+// @Produce  json
+// @Param   token  query  string  true  "Token xác nhận"
+// @Success 200 {object} utils.APIResponse "Đổi email thành công"
+// @Failure 400 {object} utils.APIResponse "Token không hợp lệ hoặc hết hạn"
+// @Failure 409 {object} utils.APIResponse "Email đã tồn tại"
+// @Router /users/confirm-email [get]
+func (c *UserController) ConfirmEmailChange(ctx *gin.Context) {
+    err := c.UserService.ConfirmEmailChange(ctx.Query("token"))
+    if err != nil {
+        if errors.Is(err, services.ErrEmailTaken) {
+            utils.SendFail(ctx, http.StatusConflict, "409", err.Error(), nil)
+            return
+        }
+        utils.SendFail(ctx, http.StatusBadRequest, "400", err.Error(), nil)
+        return
+    }
+    utils.SendSuccess(ctx, http.StatusOK, "200", utils.MsgEmailChanged, nil)
+}
+
 // ChangePassword godoc
 // @Summary Đổi mật khẩu
 // @Description Đổi mật khẩu cho user hiện tại
@@ -273,11 +395,18 @@ func (c *UserController) GetUserDetail(ctx *gin.Context) {
         utils.SendFail(ctx, http.StatusNotFound, "404", utils.ErrUserNotFound, nil)
         return
     }
+    followers, following, err := c.UserService.FollowCounts(userID)
+    if err != nil {
+        utils.SendFail(ctx, http.StatusInternalServerError, "500", err.Error(), nil)
+        return
+    }
     utils.SendSuccess(ctx, http.StatusOK, "200", "user information successfully retrieved", gin.H{
-        "id":       user.ID,
-        "email":    user.Email,
-        "username": user.Username,
-        "role":     user.Role,
+        "id":              user.ID,
+        "email":           user.Email,
+        "username":        user.Username,
+        "role":            user.Role,
+        "followers_count": followers,
+        "following_count": following,
     })
 }
 
@@ -6,9 +6,11 @@ type AdminCategoryPost struct {
 }
 
 type AdminCategoryResponse struct {
-    ID        uint               `json:"id"`
-    Name      string             `json:"name"`
-    Slug      string             `json:"slug"`
-    PostCount int                `json:"post_count"`
-    Posts     []AdminCategoryPost `json:"posts"`
+    ID                    uint                `json:"id"`
+    Name                  string              `json:"name"`
+    Slug                  string              `json:"slug"`
+    CommentsEnabled       bool                `json:"comments_enabled"`
+    CommentsAutoCloseDays int                 `json:"comments_auto_close_days"`
+    PostCount             int                 `json:"post_count"`
+    Posts                 []AdminCategoryPost `json:"posts"`
 }
\ No newline at end of file
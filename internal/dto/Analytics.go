@@ -0,0 +1,31 @@
+package dto
+
+type DailyViewsResponse struct {
+	Date  string `json:"date"`
+	Views int64  `json:"views"`
+}
+
+type ReferrerViewsResponse struct {
+	// Referrer is the referring host, or empty for direct visits
+	Referrer string `json:"referrer"`
+	Views    int64  `json:"views"`
+}
+
+type PostStatsResponse struct {
+	PostID        uint                    `json:"post_id"`
+	Days          int                     `json:"days"`
+	TotalViews    int64                   `json:"total_views"`
+	PeriodViews   int64                   `json:"period_views"`
+	Views         []DailyViewsResponse    `json:"views"`
+	Referrers     []ReferrerViewsResponse `json:"referrers"`
+	CommentsCount int64                   `json:"comments_count"`
+	Reactions     map[string]int64        `json:"reactions"`
+}
+
+type TopPostResponse struct {
+	PostID uint   `json:"post_id"`
+	Title  string `json:"title"`
+	Slug   string `json:"slug"`
+	Author string `json:"author"`
+	Views  int64  `json:"views"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+type BookmarkRequest struct {
+	Collection string `json:"collection" binding:"omitempty,max=100"`
+}
+
+type BookmarkResponse struct {
+	Collection   *string      `json:"collection"`
+	BookmarkedAt time.Time    `json:"bookmarked_at"`
+	Post         PostResponse `json:"post"`
+}
+
+type BookmarkCollectionResponse struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
@@ -1,17 +1,28 @@
 package dto
 
 type CreateCategoryRequest struct {
-	Name string `json:"name" binding:"required,min=2,max=100"`
-	Slug string `json:"slug" binding:"required,min=3,max=50,slug"`
+	Name                  string `json:"name" binding:"required,min=2,max=100"`
+	Slug                  string `json:"slug" binding:"required,min=3,max=50,slug"`
+	CommentsEnabled       *bool  `json:"comments_enabled,omitempty"`
+	CommentsAutoCloseDays *int   `json:"comments_auto_close_days,omitempty" binding:"omitempty,min=0"`
 }
 
 type UpdateCategoryRequest struct {
-	Name string `json:"name" binding:"required,min=2,max=100"`
-	Slug string `json:"slug" binding:"required,min=3,max=50,slug"`
+	Name                  string `json:"name" binding:"required,min=2,max=100"`
+	Slug                  string `json:"slug" binding:"required,min=3,max=50,slug"`
+	CommentsEnabled       *bool  `json:"comments_enabled,omitempty"`
+	CommentsAutoCloseDays *int   `json:"comments_auto_close_days,omitempty" binding:"omitempty,min=0"`
 }
 
 type CategoryResponse struct {
-    ID   uint   `json:"id"`
-    Name string `json:"name"`
-    Slug string `json:"slug"`
-}
\ No newline at end of file
+    ID                    uint   `json:"id"`
+    Name                  string `json:"name"`
+    Slug                  string `json:"slug"`
+    CommentsEnabled       bool   `json:"comments_enabled"`
+    CommentsAutoCloseDays int    `json:"comments_auto_close_days"`
+}
+// MergeCategoriesRequest names the category to fold into another one by their slugs.
+type MergeCategoriesRequest struct {
+	From string `json:"from" binding:"required,slug"`
+	Into string `json:"into" binding:"required,slug,nefield=From"`
+}
@@ -1,10 +1,14 @@
 package dto
 
-import "time"
+import (
+	"blog-api/internal/entities"
+	"time"
+)
 
 type CreateCommentRequest struct {
-	PostID  uint   `json:"post_id" binding:"required"`
-	Content string `json:"content" binding:"required"`
+	PostID   uint   `json:"post_id" binding:"required"`
+	ParentID *uint  `json:"parent_id,omitempty"`
+	Content  string `json:"content" binding:"required"`
 }
 
 type UpdateCommentRequest struct {
@@ -12,19 +16,45 @@ type UpdateCommentRequest struct {
 }
 
 type CommentResponse struct {
-	ID        uint      `json:"id"`
-	PostID    uint      `json:"post_id"`
-	UserID    uint      `json:"user_id"`
-	Content   string    `json:"content"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID              uint              `json:"id"`
+	PostID          uint              `json:"post_id"`
+	ParentID        *uint             `json:"parent_id,omitempty"`
+	UserID          uint              `json:"user_id"`
+	Content         string            `json:"content"`
+	RenderedContent string            `json:"rendered_content"`
+	Mentions        []MentionResponse `json:"mentions"`
+	Reactions       map[string]int64  `json:"reactions"`
+	MyReaction      *string           `json:"my_reaction"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+}
+
+func NewCommentResponse(c *entities.Comment) CommentResponse {
+	resp := CommentResponse{
+		ID:        c.ID,
+		PostID:    c.PostID,
+		ParentID:  c.ParentID,
+		UserID:    c.UserID,
+		Content:   c.Content,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+	resp.Mentions, resp.RenderedContent = newMentions(c.Mentions, c.Content)
+	resp.Reactions = NewReactionCounts(c.ReactionCounts)
+	return resp
+}
+
+// DeletedCommentEvent is the payload of comment.deleted stream events.
+type DeletedCommentEvent struct {
+	ID     uint `json:"id"`
+	PostID uint `json:"post_id"`
 }
 
 type ListCommentResponse struct {
-    Comments []CommentResponse `json:"comments"`
-    Total    int               `json:"total"`
+	Comments []CommentResponse `json:"comments"`
+	Total    int               `json:"total"`
 }
 
 type ContentResponse struct {
 	Content string `json:"content" binding:"required"`
-}
\ No newline at end of file
+}
@@ -0,0 +1,71 @@
+package dto
+
+import (
+	"blog-api/internal/entities"
+	"fmt"
+	"strings"
+)
+
+type MediaVariantResponse struct {
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	Format      string `json:"format"`
+	ContentType string `json:"content_type"`
+	URL         string `json:"url"`
+	Size        int64  `json:"size"`
+}
+
+type MediaResponse struct {
+	ID              uint                   `json:"id"`
+	URL             string                 `json:"url"`
+	ContentType     string                 `json:"content_type"`
+	Size            int64                  `json:"size"`
+	Width           int                    `json:"width"`
+	Height          int                    `json:"height"`
+	OriginalName    string                 `json:"original_name"`
+	Status          string                 `json:"status"`
+	ProcessingError string                 `json:"processing_error,omitempty"`
+	Variants        []MediaVariantResponse `json:"variants"`
+	Srcset          map[string]string      `json:"srcset"`
+	CreatedAt       string                 `json:"created_at"`
+}
+
+func NewMediaResponse(m *entities.Media) MediaResponse {
+	resp := MediaResponse{
+		ID:              m.ID,
+		URL:             m.URL,
+		ContentType:     m.ContentType,
+		Size:            m.Size,
+		Width:           m.Width,
+		Height:          m.Height,
+		OriginalName:    m.OriginalName,
+		Status:          m.Status,
+		ProcessingError: m.ProcessingError,
+		CreatedAt:       m.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+	resp.Variants, resp.Srcset = newMediaVariants(m.Variants)
+	return resp
+}
+
+// newMediaVariants maps variants to responses and builds a srcset attribute value per format,
+// e.g. {"jpeg": "https://.../a-320w.jpg 320w, https://.../a-768w.jpg 768w"}.
+func newMediaVariants(variants []entities.MediaVariant) ([]MediaVariantResponse, map[string]string) {
+	resp := []MediaVariantResponse{}
+	candidates := make(map[string][]string)
+	for _, v := range variants {
+		resp = append(resp, MediaVariantResponse{
+			Width:       v.Width,
+			Height:      v.Height,
+			Format:      v.Format,
+			ContentType: v.ContentType,
+			URL:         v.URL,
+			Size:        v.Size,
+		})
+		candidates[v.Format] = append(candidates[v.Format], fmt.Sprintf("%s %dw", v.URL, v.Width))
+	}
+	srcset := make(map[string]string, len(candidates))
+	for format, list := range candidates {
+		srcset[format] = strings.Join(list, ", ")
+	}
+	return resp, srcset
+}
@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"blog-api/internal/entities"
+	"blog-api/pkg/utils"
+)
+
+type MentionResponse struct {
+	UserID     uint   `json:"user_id"`
+	Username   string `json:"username"`
+	ProfileURL string `json:"profile_url"`
+}
+
+// newMentions maps preloaded mentions to responses and renders them as profile links in content.
+func newMentions(mentions []entities.Mention, content string) ([]MentionResponse, string) {
+	resp := []MentionResponse{}
+	usernames := make(map[string]bool)
+	for _, m := range mentions {
+		username := m.MentionedUser.Username
+		if username == "" {
+			continue
+		}
+		usernames[username] = true
+		resp = append(resp, MentionResponse{
+			UserID:     m.MentionedUserID,
+			Username:   username,
+			ProfileURL: utils.ProfilePath(username),
+		})
+	}
+	return resp, utils.RenderMentions(content, usernames)
+}
@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"blog-api/internal/entities"
+	"time"
+)
+
+type NotificationActor struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+}
+
+type NotificationResponse struct {
+	ID        uint               `json:"id"`
+	Type      string             `json:"type"`
+	Actor     *NotificationActor `json:"actor,omitempty"`
+	PostID    *uint              `json:"post_id,omitempty"`
+	CommentID *uint              `json:"comment_id,omitempty"`
+	Message   string             `json:"message"`
+	Read      bool               `json:"read"`
+	ReadAt    *time.Time         `json:"read_at,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type UpdateNotificationPreferencesRequest struct {
+	Preferences map[string]bool `json:"preferences" binding:"required"`
+}
+
+func NewNotificationResponse(n *entities.Notification) NotificationResponse {
+	resp := NotificationResponse{
+		ID:        n.ID,
+		Type:      n.Type,
+		PostID:    n.PostID,
+		CommentID: n.CommentID,
+		Message:   n.Message,
+		Read:      n.ReadAt != nil,
+		ReadAt:    n.ReadAt,
+		CreatedAt: n.CreatedAt,
+	}
+	if n.Actor != nil {
+		resp.Actor = &NotificationActor{ID: uint(n.Actor.ID), Username: n.Actor.Username}
+	}
+	return resp
+}
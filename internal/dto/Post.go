@@ -1,53 +1,121 @@
 package dto
 
-import "blog-api/internal/entities"
+import (
+	"blog-api/internal/entities"
+	"time"
+)
 
 type CreatePostRequest struct {
-	Title      string `json:"title" binding:"required,min=2,max=200"`
-	Slug       string `json:"slug" binding:"required,slug"`
-	Content    string `json:"content" binding:"required"`
-	Thumbnail  string `json:"thumbnail" binding:"required,url"`	
-	CategoryID uint   `json:"category_id" binding:"required,number"`
-	Status     string `json:"status" binding:"required,oneof=draft published"`
+	Title           string `json:"title" binding:"required,min=2,max=200"`
+	Slug            string `json:"slug" binding:"required,slug"`
+	Content         string `json:"content" binding:"required"`
+	Thumbnail       string `json:"thumbnail" binding:"required_without=ThumbnailMediaID,omitempty,url"`
+	ThumbnailMediaID *uint `json:"thumbnail_media_id,omitempty"`
+	CategoryID      uint   `json:"category_id" binding:"required,number"`
+	Status          string `json:"status" binding:"required,oneof=draft published"`
+	CommentsEnabled *bool  `json:"comments_enabled,omitempty"`
+	Summary         string `json:"summary,omitempty" binding:"omitempty,max=500"`
+	MetaTitle       string `json:"meta_title,omitempty" binding:"omitempty,max=200"`
+	MetaDescription string `json:"meta_description,omitempty" binding:"omitempty,max=300"`
+	CanonicalURL    string `json:"canonical_url,omitempty" binding:"omitempty,url"`
+	OGImage         string `json:"og_image,omitempty" binding:"omitempty,url"`
+	Noindex         bool   `json:"noindex,omitempty"`
 }
 
 type UpdatePostRequest struct {
-	Title      *string `json:"title,omitempty" binding:"omitempty,min=2,max=200"`
-	Slug       *string `json:"slug" binding:"omitempty"`
-	Content    *string `json:"content,omitempty" binding:"omitempty"`
-	Thumbnail  *string `json:"thumbnail,omitempty" binding:"omitempty,url"`
-	CategoryID *uint   `json:"category_id,omitempty" binding:"omitempty,number"`
-	Status     *string `json:"status,omitempty" binding:"omitempty,oneof=draft published"`
+	Title           *string `json:"title,omitempty" binding:"omitempty,min=2,max=200"`
+	Slug            *string `json:"slug" binding:"omitempty"`
+	Content         *string `json:"content,omitempty" binding:"omitempty"`
+	Thumbnail       *string `json:"thumbnail,omitempty" binding:"omitempty,url,excluded_with=ThumbnailMediaID"`
+	ThumbnailMediaID *uint  `json:"thumbnail_media_id,omitempty"`
+	CategoryID      *uint   `json:"category_id,omitempty" binding:"omitempty,number"`
+	Status          *string `json:"status,omitempty" binding:"omitempty,oneof=draft published"`
+	CommentsEnabled *bool   `json:"comments_enabled,omitempty"`
+	Summary         *string `json:"summary,omitempty" binding:"omitempty,max=500"`
+	MetaTitle       *string `json:"meta_title,omitempty" binding:"omitempty,max=200"`
+	MetaDescription *string `json:"meta_description,omitempty" binding:"omitempty,max=300"`
+	CanonicalURL    *string `json:"canonical_url,omitempty" binding:"omitempty,url"`
+	OGImage         *string `json:"og_image,omitempty" binding:"omitempty,url"`
+	Noindex         *bool   `json:"noindex,omitempty"`
+}
+
+type LockCommentsRequest struct {
+	Locked *bool `json:"locked" binding:"required"`
 }
 
 type PostResponse struct {
-	ID         uint   `json:"id"`
-	Title      string `json:"title"`
-	Slug       string `json:"slug"`
-	Content    string `json:"content"`
-	Thumbnail  string `json:"thumbnail"`
-	CategoryID uint   `json:"category_id"`
-	Category   string `json:"category"`
-	AuthorID   uint   `json:"author_id"`
-	Author     string `json:"author"`
-	Status     string `json:"status"`
-	CreatedAt  string `json:"created_at"`
-	UpdatedAt  string `json:"updated_at"`
+	ID               uint              `json:"id"`
+	Title            string            `json:"title"`
+	Slug             string            `json:"slug"`
+	Content          string            `json:"content"`
+	RenderedContent  string            `json:"rendered_content"`
+	Summary          string            `json:"summary"`
+	Excerpt          string            `json:"excerpt"`
+	MetaTitle        string            `json:"meta_title"`
+	MetaDescription  string            `json:"meta_description"`
+	CanonicalURL     string            `json:"canonical_url"`
+	OGImage          string            `json:"og_image"`
+	Noindex          bool              `json:"noindex"`
+	Thumbnail        string            `json:"thumbnail"`
+	ThumbnailMediaID *uint             `json:"thumbnail_media_id"`
+	ThumbnailVariants []MediaVariantResponse `json:"thumbnail_variants"`
+	ThumbnailSrcset  map[string]string `json:"thumbnail_srcset"`
+	CategoryID       uint              `json:"category_id"`
+	Category         string            `json:"category"`
+	AuthorID         uint              `json:"author_id"`
+	Author           string            `json:"author"`
+	AuthorProfile    AuthorSummary     `json:"author_profile"`
+	Status           string            `json:"status"`
+	PublishedAt      string            `json:"published_at,omitempty"`
+	CommentsEnabled  bool              `json:"comments_enabled"`
+	CommentsLockedAt string            `json:"comments_locked_at,omitempty"`
+	CommentsOpen     bool              `json:"comments_open"`
+	Mentions         []MentionResponse `json:"mentions"`
+	Reactions        map[string]int64  `json:"reactions"`
+	MyReaction       *string           `json:"my_reaction"`
+	Bookmarked       bool              `json:"bookmarked"`
+	CreatedAt        string            `json:"created_at"`
+	UpdatedAt        string            `json:"updated_at"`
 }
 
 func NewPostResponse(p *entities.Post) PostResponse {
-    return PostResponse{
-        ID:         p.ID,
-        Title:      p.Title,
-        Slug:       p.Slug,
-        Content:    p.Content,
-        Thumbnail:  p.Thumbnail,
-        CategoryID: p.CategoryID,
-        Category:   p.Category.Name,
-        AuthorID:   p.AuthorID,
-        Author:     p.Author.Username,
-        Status:     p.Status,
-        CreatedAt:  p.CreatedAt.Format("2006-01-02 15:04:05"),
-        UpdatedAt:  p.UpdatedAt.Format("2006-01-02 15:04:05"),
-    }
-}
\ No newline at end of file
+	resp := PostResponse{
+		ID:              p.ID,
+		Title:           p.Title,
+		Slug:            p.Slug,
+		Content:         p.Content,
+		Summary:         p.Summary,
+		Excerpt:         p.Excerpt(),
+		MetaTitle:       p.MetaTitle,
+		MetaDescription: p.MetaDescription,
+		CanonicalURL:    p.CanonicalURL,
+		OGImage:         p.OGImage,
+		Noindex:         p.Noindex,
+		Thumbnail:       p.Thumbnail,
+		ThumbnailMediaID: p.ThumbnailMediaID,
+		CategoryID:      p.CategoryID,
+		Category:        p.Category.Name,
+		AuthorID:        p.AuthorID,
+		Author:          p.Author.Username,
+		AuthorProfile:   NewAuthorSummary(&p.Author),
+		Status:          p.Status,
+		CommentsEnabled: p.CommentsEnabled == nil || *p.CommentsEnabled,
+		CommentsOpen:    p.CommentsOpen(time.Now()),
+		CreatedAt:       p.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:       p.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+	resp.Mentions, resp.RenderedContent = newMentions(p.Mentions, p.Content)
+	resp.Reactions = NewReactionCounts(p.ReactionCounts)
+	if p.ThumbnailMedia != nil {
+		resp.ThumbnailVariants, resp.ThumbnailSrcset = newMediaVariants(p.ThumbnailMedia.Variants)
+	} else {
+		resp.ThumbnailVariants, resp.ThumbnailSrcset = newMediaVariants(nil)
+	}
+	if p.PublishedAt != nil {
+		resp.PublishedAt = p.PublishedAt.Format("2006-01-02 15:04:05")
+	}
+	if p.CommentsLockedAt != nil {
+		resp.CommentsLockedAt = p.CommentsLockedAt.Format("2006-01-02 15:04:05")
+	}
+	return resp
+}
@@ -0,0 +1,143 @@
+package dto
+
+import (
+	"blog-api/internal/entities"
+	"blog-api/pkg/utils"
+	"encoding/json"
+	"html"
+	"strings"
+	"time"
+)
+
+// MetaTag is a <meta> tag: OpenGraph tags use the property attribute, Twitter cards use name.
+type MetaTag struct {
+	Property string `json:"property,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Content  string `json:"content"`
+}
+
+type PostMetaResponse struct {
+	Title        string                 `json:"title"`
+	Description  string                 `json:"description"`
+	CanonicalURL string                 `json:"canonical_url"`
+	Robots       string                 `json:"robots"`
+	OpenGraph    []MetaTag              `json:"open_graph"`
+	Twitter      []MetaTag              `json:"twitter"`
+	JSONLD       map[string]interface{} `json:"json_ld"`
+	// HTML holds all of the above as tags ready to paste into <head>
+	HTML string `json:"html"`
+}
+
+// NewPostMetaResponse builds the head metadata of a post page. The post's own SEO fields win;
+// otherwise the title, excerpt, thumbnail and public URL are used.
+func NewPostMetaResponse(p *entities.Post, siteURL, siteTitle string) (PostMetaResponse, error) {
+	title := p.Title
+	if p.MetaTitle != "" {
+		title = p.MetaTitle
+	}
+	description := p.MetaDescription
+	if description == "" {
+		description = p.Excerpt()
+	}
+	canonical := p.CanonicalURL
+	if canonical == "" {
+		canonical = siteURL + "/posts/" + p.Slug
+	}
+	image := p.OGImage
+	if image == "" {
+		image = p.Thumbnail
+	}
+	if strings.HasPrefix(image, "/") {
+		image = siteURL + image
+	}
+	robots := "index, follow"
+	if p.Noindex {
+		robots = "noindex, nofollow"
+	}
+	published := p.CreatedAt
+	if p.PublishedAt != nil {
+		published = *p.PublishedAt
+	}
+	authorName := p.Author.Username
+	if p.Author.DisplayName != "" {
+		authorName = p.Author.DisplayName
+	}
+	authorURL := siteURL + utils.ProfilePath(p.Author.Username)
+
+	resp := PostMetaResponse{
+		Title:        title,
+		Description:  description,
+		CanonicalURL: canonical,
+		Robots:       robots,
+		OpenGraph: []MetaTag{
+			{Property: "og:type", Content: "article"},
+			{Property: "og:site_name", Content: siteTitle},
+			{Property: "og:title", Content: title},
+			{Property: "og:description", Content: description},
+			{Property: "og:url", Content: canonical},
+		},
+		Twitter: []MetaTag{
+			{Name: "twitter:card", Content: "summary"},
+			{Name: "twitter:title", Content: title},
+			{Name: "twitter:description", Content: description},
+		},
+	}
+	if image != "" {
+		resp.OpenGraph = append(resp.OpenGraph, MetaTag{Property: "og:image", Content: image})
+		resp.Twitter[0].Content = "summary_large_image"
+		resp.Twitter = append(resp.Twitter, MetaTag{Name: "twitter:image", Content: image})
+	}
+	resp.OpenGraph = append(resp.OpenGraph,
+		MetaTag{Property: "article:published_time", Content: published.UTC().Format(time.RFC3339)},
+		MetaTag{Property: "article:modified_time", Content: p.UpdatedAt.UTC().Format(time.RFC3339)},
+		MetaTag{Property: "article:author", Content: authorURL},
+	)
+	if p.Category.Name != "" {
+		resp.OpenGraph = append(resp.OpenGraph, MetaTag{Property: "article:section", Content: p.Category.Name})
+	}
+
+	resp.JSONLD = map[string]interface{}{
+		"@context":         "https://schema.org",
+		"@type":            "BlogPosting",
+		"headline":         title,
+		"description":      description,
+		"url":              canonical,
+		"mainEntityOfPage": map[string]string{"@type": "WebPage", "@id": canonical},
+		"datePublished":    published.UTC().Format(time.RFC3339),
+		"dateModified":     p.UpdatedAt.UTC().Format(time.RFC3339),
+		"author":           map[string]string{"@type": "Person", "name": authorName, "url": authorURL},
+		"publisher":        map[string]string{"@type": "Organization", "name": siteTitle},
+	}
+	if image != "" {
+		resp.JSONLD["image"] = image
+	}
+	if p.Category.Name != "" {
+		resp.JSONLD["articleSection"] = p.Category.Name
+	}
+
+	var err error
+	resp.HTML, err = resp.renderHTML()
+	return resp, err
+}
+
+func (m *PostMetaResponse) renderHTML() (string, error) {
+	// json.Marshal escapes <, > and &, so the JSON-LD cannot close its script tag early
+	ld, err := json.Marshal(m.JSONLD)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("<title>" + html.EscapeString(m.Title) + "</title>\n")
+	b.WriteString(`<meta name="description" content="` + html.EscapeString(m.Description) + "\">\n")
+	b.WriteString(`<meta name="robots" content="` + m.Robots + "\">\n")
+	b.WriteString(`<link rel="canonical" href="` + html.EscapeString(m.CanonicalURL) + "\">\n")
+	for _, tag := range m.OpenGraph {
+		b.WriteString(`<meta property="` + tag.Property + `" content="` + html.EscapeString(tag.Content) + "\">\n")
+	}
+	for _, tag := range m.Twitter {
+		b.WriteString(`<meta name="` + tag.Name + `" content="` + html.EscapeString(tag.Content) + "\">\n")
+	}
+	b.WriteString(`<script type="application/ld+json">` + string(ld) + "</script>")
+	return b.String(), nil
+}
@@ -0,0 +1,97 @@
+package dto
+
+import (
+	"blog-api/internal/entities"
+	"strings"
+	"testing"
+	"time"
+)
+
+func metaContent(tags []MetaTag, key string) string {
+	for _, tag := range tags {
+		if tag.Property == key || tag.Name == key {
+			return tag.Content
+		}
+	}
+	return ""
+}
+
+func TestPostMetaDefaults(t *testing.T) {
+	created := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	post := &entities.Post{
+		Title:     "Hello <world>",
+		Slug:      "hello",
+		Content:   "# Hello\n\nA **first** post with [a link](https://example.com).",
+		Thumbnail: "/uploads/hello.jpg",
+		CreatedAt: created,
+		UpdatedAt: created.Add(time.Hour),
+		Author:    entities.User{Username: "alice"},
+	}
+	meta, err := NewPostMetaResponse(post, "https://blog.example.com", "Blog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Title != "Hello <world>" || meta.Description != "Hello A first post with a link." ||
+		meta.CanonicalURL != "https://blog.example.com/posts/hello" || meta.Robots != "index, follow" {
+		t.Errorf("meta = %+v", meta)
+	}
+	// relative images are made absolute, and an image makes a large card
+	if image := metaContent(meta.OpenGraph, "og:image"); image != "https://blog.example.com/uploads/hello.jpg" {
+		t.Errorf("og:image = %q", image)
+	}
+	if card := metaContent(meta.Twitter, "twitter:card"); card != "summary_large_image" {
+		t.Errorf("twitter:card = %q", card)
+	}
+	// a draft never published falls back to its creation time
+	if published := metaContent(meta.OpenGraph, "article:published_time"); published != "2024-05-01T08:00:00Z" {
+		t.Errorf("published time = %q", published)
+	}
+	if meta.JSONLD["author"].(map[string]string)["url"] != "https://blog.example.com/users/alice" {
+		t.Errorf("JSON-LD author = %v", meta.JSONLD["author"])
+	}
+	if !strings.Contains(meta.HTML, "<title>Hello &lt;world&gt;</title>") {
+		t.Errorf("title not escaped in the HTML:\n%s", meta.HTML)
+	}
+}
+
+func TestPostMetaOverrides(t *testing.T) {
+	published := time.Date(2024, 5, 2, 8, 0, 0, 0, time.UTC)
+	post := &entities.Post{
+		Title:           "Hello",
+		Slug:            "hello",
+		Content:         "Body",
+		Summary:         "The summary",
+		MetaTitle:       "SEO title",
+		MetaDescription: `Say "hi" </script><script>alert(1)</script>`,
+		CanonicalURL:    "https://elsewhere.example.com/hello",
+		OGImage:         "https://cdn.example.com/card.png",
+		Noindex:         true,
+		PublishedAt:     &published,
+		Author:          entities.User{Username: "alice", DisplayName: "Alice Nguyen"},
+		Category:        entities.Category{Name: "News"},
+	}
+	meta, err := NewPostMetaResponse(post, "https://blog.example.com", "Blog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Title != "SEO title" || meta.CanonicalURL != "https://elsewhere.example.com/hello" || meta.Robots != "noindex, nofollow" {
+		t.Errorf("meta = %+v", meta)
+	}
+	if metaContent(meta.OpenGraph, "og:image") != "https://cdn.example.com/card.png" || metaContent(meta.OpenGraph, "article:section") != "News" ||
+		metaContent(meta.OpenGraph, "article:published_time") != "2024-05-02T08:00:00Z" {
+		t.Errorf("open graph = %+v", meta.OpenGraph)
+	}
+	if meta.JSONLD["author"].(map[string]string)["name"] != "Alice Nguyen" {
+		t.Errorf("JSON-LD author = %v", meta.JSONLD["author"])
+	}
+	// the description cannot break out of its attribute or of the JSON-LD script
+	if strings.Count(meta.HTML, "<script") != 1 || strings.Count(meta.HTML, "</script>") != 1 {
+		t.Errorf("description escaped its context:\n%s", meta.HTML)
+	}
+
+	// without a description of its own, the summary is used
+	post.MetaDescription = ""
+	if meta, _ := NewPostMetaResponse(post, "https://blog.example.com", "Blog"); meta.Description != "The summary" {
+		t.Errorf("description = %q; want the summary", meta.Description)
+	}
+}
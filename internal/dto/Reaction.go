@@ -0,0 +1,23 @@
+package dto
+
+import "blog-api/internal/entities"
+
+type ReactRequest struct {
+	Type string `json:"type" binding:"required"`
+}
+
+type ReactionResponse struct {
+	MyReaction *string          `json:"my_reaction"`
+	Reactions  map[string]int64 `json:"reactions"`
+}
+
+// NewReactionCounts flattens reaction counters into a type => count map, leaving out empty ones.
+func NewReactionCounts(counts []entities.ReactionCount) map[string]int64 {
+	result := make(map[string]int64)
+	for _, c := range counts {
+		if c.Count > 0 {
+			result[c.Type] = c.Count
+		}
+	}
+	return result
+}
@@ -1,5 +1,10 @@
 package dto
 
+import (
+	"blog-api/internal/entities"
+	"blog-api/pkg/utils"
+)
+
 type UserRegisterRequest struct {
 	Username string `json:"username" binding:"required,min=3,max=20,username"`
 	Email    string `json:"email" binding:"required,email"`
@@ -18,11 +23,54 @@ type UserResponse struct {
     Role     string `json:"role"`
 }
 
+// UpdateProfileRequest changes the public profile fields; an empty string clears a field.
+type UpdateProfileRequest struct {
+	DisplayName *string `json:"display_name,omitempty" binding:"omitempty,max=100"`
+	Bio         *string `json:"bio,omitempty" binding:"omitempty,max=1000"`
+	AvatarURL   *string `json:"avatar_url,omitempty" binding:"omitempty,url"`
+	Website     *string `json:"website,omitempty" binding:"omitempty,url,max=255"`
+}
+
+// PublicProfileResponse is what anyone can see about a user. It must never carry the email or role.
+type PublicProfileResponse struct {
+	Username       string `json:"username"`
+	DisplayName    string `json:"display_name"`
+	Bio            string `json:"bio"`
+	AvatarURL      string `json:"avatar_url"`
+	Website        string `json:"website"`
+	JoinedAt       string `json:"joined_at"`
+	PostCount      int64  `json:"post_count"`
+	FollowersCount int64  `json:"followers_count"`
+	FollowingCount int64  `json:"following_count"`
+}
+
+// AuthorSummary is the short author card embedded in post responses.
+type AuthorSummary struct {
+	Username    string `json:"username"`
+	DisplayName string `json:"display_name"`
+	AvatarURL   string `json:"avatar_url"`
+	ProfileURL  string `json:"profile_url"`
+}
+
+func NewAuthorSummary(u *entities.User) AuthorSummary {
+	return AuthorSummary{
+		Username:    u.Username,
+		DisplayName: u.DisplayName,
+		AvatarURL:   u.AvatarURL,
+		ProfileURL:  utils.ProfilePath(u.Username),
+	}
+}
+
 type UserUpdateRequest struct {
 	Username string `json:"username" binding:"required,min=3,max=20,username"` 
 	Email    string `json:"email" binding:"required,email"`           
 	Password string `json:"password" binding:"required,min=6"`        
-	Role     string `json:"role" binding:"required,oneof=admin client"` 
+}
+
+// UpdateMeRequest is what users may change about their own account. It deliberately has no role.
+type UpdateMeRequest struct {
+	Username *string `json:"username,omitempty" binding:"omitempty,min=3,max=20,username"`
+	Email    *string `json:"email,omitempty" binding:"omitempty,email"`
 }
 
 type AdminUpdateUserRequest struct {
@@ -0,0 +1,22 @@
+package entities
+
+import "time"
+
+// BookmarkCollection is a named group in a user's reading list.
+type BookmarkCollection struct {
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;uniqueIndex:idx_bookmark_collections_user_name"`
+	Name      string `gorm:"type:varchar(100);not null;uniqueIndex:idx_bookmark_collections_user_name"`
+	CreatedAt time.Time
+}
+
+type Bookmark struct {
+	ID           uint  `gorm:"primaryKey"`
+	UserID       uint  `gorm:"not null;uniqueIndex:idx_bookmarks_user_post"`
+	PostID       uint  `gorm:"not null;uniqueIndex:idx_bookmarks_user_post"`
+	CollectionID *uint `gorm:"index"`
+	CreatedAt    time.Time
+
+	Post       Post
+	Collection *BookmarkCollection
+}
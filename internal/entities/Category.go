@@ -7,6 +7,10 @@ type Category struct {
 	Name string `gorm:"type:varchar(100);not null"`
 	Slug string `gorm:"type:varchar(100);unique;not null"`
 
+	// Defaults applied to new posts in this category
+	CommentsEnabled       *bool `gorm:"not null;default:true"`
+	CommentsAutoCloseDays int   `gorm:"not null;default:0"`
+
 	Posts []Post `gorm:"foreignKey:CategoryID"`
 
 	DeletedAt gorm.DeletedAt `gorm:"index"`
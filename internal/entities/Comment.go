@@ -9,11 +9,15 @@ type Comment struct {
 	ID        uint      `gorm:"primaryKey"`
 	PostID    uint
 	UserID    uint
+	ParentID  *uint     `gorm:"index"`
 	Content   string    `gorm:"type:text;not null"`
 	CreatedAt time.Time
+	UpdatedAt time.Time
 
-	Post Post
-	User User	
+	Post           Post
+	User           User
+	Mentions       []Mention       `gorm:"polymorphic:Source;polymorphicValue:comments"`
+	ReactionCounts []ReactionCount `gorm:"polymorphic:Target;polymorphicValue:comments"`
 
 	DeletedAt   gorm.DeletedAt `gorm:"index"`
 }
\ No newline at end of file
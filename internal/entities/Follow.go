@@ -0,0 +1,18 @@
+package entities
+
+import "time"
+
+const (
+	FollowTargetUser     = "users"
+	FollowTargetCategory = "categories"
+)
+
+// Follow subscribes a user to an author or a category. The home feed is built
+// from these rows at read time.
+type Follow struct {
+	ID         uint   `gorm:"primaryKey"`
+	FollowerID uint   `gorm:"not null;uniqueIndex:idx_follow_unique,priority:1"`
+	TargetType string `gorm:"type:varchar(20);not null;uniqueIndex:idx_follow_unique,priority:2;index:idx_follow_target,priority:1"`
+	TargetID   uint   `gorm:"not null;uniqueIndex:idx_follow_unique,priority:3;index:idx_follow_target,priority:2"`
+	CreatedAt  time.Time
+}
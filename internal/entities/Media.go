@@ -0,0 +1,54 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	MediaStatusPending    = "pending"
+	MediaStatusProcessing = "processing"
+	MediaStatusReady      = "ready"
+	MediaStatusFailed     = "failed"
+)
+
+// Media is an uploaded image in a user's media library. The file itself lives in
+// the configured storage backend under StorageKey.
+type Media struct {
+	ID           uint   `gorm:"primaryKey"`
+	OwnerID      uint   `gorm:"not null;index"`
+	StorageKey   string `gorm:"type:varchar(255);unique;not null"`
+	URL          string `gorm:"type:text;not null"`
+	ContentType  string `gorm:"type:varchar(50);not null"`
+	Size         int64  `gorm:"not null"`
+	Width        int    `gorm:"not null"`
+	Height       int    `gorm:"not null"`
+	OriginalName string `gorm:"type:varchar(255);not null;default:''"`
+	CreatedAt    time.Time
+
+	// Variant processing: pending -> processing -> ready or failed
+	Status              string `gorm:"type:varchar(20);not null;default:'pending';index"`
+	ProcessingError     string `gorm:"type:text;not null;default:''"`
+	ProcessingStartedAt *time.Time
+	ProcessedAt         *time.Time
+
+	Owner    User
+	Variants []MediaVariant `gorm:"foreignKey:MediaID;constraint:OnDelete:CASCADE"`
+
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+// MediaVariant is a resized copy of a media item in one encoding, for responsive images.
+type MediaVariant struct {
+	ID          uint   `gorm:"primaryKey"`
+	MediaID     uint   `gorm:"not null;uniqueIndex:idx_media_variant,priority:1"`
+	Width       int    `gorm:"not null;uniqueIndex:idx_media_variant,priority:2"`
+	Format      string `gorm:"type:varchar(10);not null;uniqueIndex:idx_media_variant,priority:3"`
+	Height      int    `gorm:"not null"`
+	ContentType string `gorm:"type:varchar(50);not null"`
+	StorageKey  string `gorm:"type:varchar(255);not null"`
+	URL         string `gorm:"type:text;not null"`
+	Size        int64  `gorm:"not null"`
+	CreatedAt   time.Time
+}
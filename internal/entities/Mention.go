@@ -0,0 +1,27 @@
+package entities
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Mention links an @username in a post or comment to the mentioned user.
+// SourceType is "posts" or "comments", matching the polymorphic values on Post and Comment.
+type Mention struct {
+	ID              uint   `gorm:"primaryKey"`
+	SourceType      string `gorm:"type:varchar(20);not null;uniqueIndex:idx_mentions_source_user"`
+	SourceID        uint   `gorm:"not null;uniqueIndex:idx_mentions_source_user"`
+	MentionedUserID uint   `gorm:"not null;index;uniqueIndex:idx_mentions_source_user"`
+	AuthorID        uint   `gorm:"not null"`
+	CreatedAt       time.Time
+
+	MentionedUser User `gorm:"foreignKey:MentionedUserID"`
+
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+const (
+	MentionSourcePost    = "posts"
+	MentionSourceComment = "comments"
+)
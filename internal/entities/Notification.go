@@ -0,0 +1,44 @@
+package entities
+
+import "time"
+
+const (
+	NotificationCommentOnPost            = "comment_on_your_post"
+	NotificationReplyToComment           = "reply_to_your_comment"
+	NotificationMentioned                = "mentioned"
+	NotificationPostApproved             = "post_approved"
+	NotificationRoleChanged              = "role_changed"
+	NotificationPostingPermissionChanged = "posting_permission_changed"
+)
+
+// NotificationTypes lists every event users can receive, in the order shown in preferences.
+var NotificationTypes = []string{
+	NotificationCommentOnPost,
+	NotificationReplyToComment,
+	NotificationMentioned,
+	NotificationPostApproved,
+	NotificationRoleChanged,
+	NotificationPostingPermissionChanged,
+}
+
+type Notification struct {
+	ID        uint `gorm:"primaryKey"`
+	UserID    uint `gorm:"not null;index"`
+	ActorID   *uint
+	Type      string `gorm:"type:varchar(50);not null"`
+	PostID    *uint
+	CommentID *uint
+	Message   string `gorm:"type:text;not null"`
+	ReadAt    *time.Time
+	CreatedAt time.Time
+
+	Actor *User `gorm:"foreignKey:ActorID"`
+}
+
+// NotificationPreference stores an opt-out (or opt back in) for one notification type.
+// Types without a row are enabled.
+type NotificationPreference struct {
+	UserID  uint   `gorm:"primaryKey"`
+	Type    string `gorm:"primaryKey;type:varchar(50)"`
+	Enabled bool   `gorm:"not null"`
+}
@@ -1,26 +1,63 @@
 package entities
 
 import (
+	"blog-api/pkg/utils"
 	"time"
 	"gorm.io/gorm"
 )
 
+// ExcerptLength is how many characters of content make up a generated excerpt.
+const ExcerptLength = 200
+
 type Post struct {
 	ID          uint      `gorm:"primaryKey"`
 	Title       string    `gorm:"type:varchar(200);not null"`
 	Slug        string    `gorm:"type:varchar(200);unique;not null"`
 	Content     string    `gorm:"type:text;not null"`
 	Thumbnail   string    `gorm:"type:text;not null"`
-	CategoryID  uint
-	AuthorID    uint
+	ThumbnailMediaID *uint `gorm:"index"`
+	CategoryID  uint      `gorm:"index:idx_posts_category_feed,priority:1"`
+	AuthorID    uint      `gorm:"index:idx_posts_author_feed,priority:1"`
 	Status      string    `gorm:"type:post_status;default:'draft'"` // ENUM
+	PublishedAt *time.Time `gorm:"index:idx_posts_author_feed,priority:2;index:idx_posts_category_feed,priority:2"`
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 
+	// Comment settings: comments are closed when disabled or once CommentsLockedAt has passed
+	CommentsEnabled  *bool `gorm:"not null;default:true"`
+	CommentsLockedAt *time.Time
+
+	// SEO and social metadata, all optional: the title, excerpt and thumbnail stand in when empty
+	Summary         string `gorm:"type:varchar(500);not null;default:''"`
+	MetaTitle       string `gorm:"type:varchar(200);not null;default:''"`
+	MetaDescription string `gorm:"type:varchar(300);not null;default:''"`
+	CanonicalURL    string `gorm:"type:text;not null;default:''"`
+	OGImage         string `gorm:"type:text;not null;default:''"`
+	Noindex         bool   `gorm:"not null;default:false"`
+
 	// Relationships
-	Author   User
-	Category Category
-	Comments []Comment `gorm:"foreignKey:PostID"`
+	Author         User
+	Category       Category
+	ThumbnailMedia *Media          `gorm:"foreignKey:ThumbnailMediaID;constraint:OnDelete:SET NULL"`
+	Comments       []Comment       `gorm:"foreignKey:PostID"`
+	Mentions       []Mention       `gorm:"polymorphic:Source;polymorphicValue:posts"`
+	ReactionCounts []ReactionCount `gorm:"polymorphic:Target;polymorphicValue:posts"`
 
 	DeletedAt   gorm.DeletedAt `gorm:"index"`
-}
\ No newline at end of file
+}
+
+func (p *Post) CommentsOpen(now time.Time) bool {
+	if p.CommentsEnabled != nil && !*p.CommentsEnabled {
+		return false
+	}
+	return p.CommentsLockedAt == nil || now.Before(*p.CommentsLockedAt)
+}
+
+// Excerpt is the post summary, or the beginning of its content with markup stripped when
+// no summary was written.
+func (p *Post) Excerpt() string {
+	if p.Summary != "" {
+		return p.Summary
+	}
+	return utils.Excerpt(p.Content, ExcerptLength)
+}
@@ -0,0 +1,13 @@
+package entities
+
+import "time"
+
+// PostDailyView is how many views a post got on one day (UTC) from one referring host;
+// Referrer is empty for direct visits. Views are buffered in memory and added in batches,
+// so page views never write to the database themselves.
+type PostDailyView struct {
+	PostID   uint      `gorm:"primaryKey"`
+	Day      time.Time `gorm:"primaryKey;type:date;index"`
+	Referrer string    `gorm:"primaryKey;type:varchar(255)"`
+	Views    int64     `gorm:"not null;default:0"`
+}
@@ -0,0 +1,27 @@
+package entities
+
+import "time"
+
+const (
+	ReactionTargetPost    = "posts"
+	ReactionTargetComment = "comments"
+)
+
+// Reaction is a user's single reaction to a post or a comment.
+type Reaction struct {
+	ID         uint   `gorm:"primaryKey"`
+	TargetType string `gorm:"type:varchar(20);not null;uniqueIndex:idx_reactions_target_user"`
+	TargetID   uint   `gorm:"not null;uniqueIndex:idx_reactions_target_user"`
+	UserID     uint   `gorm:"not null;uniqueIndex:idx_reactions_target_user"`
+	Type       string `gorm:"type:varchar(30);not null"`
+	CreatedAt  time.Time
+}
+
+// ReactionCount is the number of reactions of one type on a target. It is updated in the
+// same transaction as the reactions themselves so reading counts never scans reactions.
+type ReactionCount struct {
+	TargetType string `gorm:"primaryKey;type:varchar(20)"`
+	TargetID   uint   `gorm:"primaryKey"`
+	Type       string `gorm:"primaryKey;type:varchar(30)"`
+	Count      int64  `gorm:"not null;default:0"`
+}
@@ -14,6 +14,12 @@ type User struct {
 	Role      string `gorm:"type:user_role;default:'client';not null"`
 	CreatedAt time.Time
 
+	// Public profile
+	DisplayName string `gorm:"type:varchar(100);not null;default:''"`
+	Bio         string `gorm:"type:text;not null;default:''"`
+	AvatarURL   string `gorm:"type:text;not null;default:''"`
+	Website     string `gorm:"type:varchar(255);not null;default:''"`
+
 	// relationships
 	Posts    []Post    `gorm:"foreignKey:AuthorID"`
 	Comments []Comment `gorm:"foreignKey:UserID"`
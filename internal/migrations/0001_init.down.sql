@@ -0,0 +1,17 @@
+DROP TABLE IF EXISTS post_daily_views;
+DROP TABLE IF EXISTS follows;
+DROP TABLE IF EXISTS bookmarks;
+DROP TABLE IF EXISTS bookmark_collections;
+DROP TABLE IF EXISTS reaction_counts;
+DROP TABLE IF EXISTS reactions;
+DROP TABLE IF EXISTS notification_preferences;
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS mentions;
+DROP TABLE IF EXISTS comments;
+DROP TABLE IF EXISTS posts;
+DROP TABLE IF EXISTS media_variants;
+DROP TABLE IF EXISTS media;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS users;
+DROP TYPE IF EXISTS post_status;
+DROP TYPE IF EXISTS user_role;
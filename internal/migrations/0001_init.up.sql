@@ -0,0 +1,246 @@
+-- Initial schema. Every statement is idempotent so that databases created by the
+-- AutoMigrate call this replaces are adopted as they are: the tables it made are kept,
+-- and the ALTER TABLE statements add the columns of the releases they predate.
+
+DO $$ BEGIN
+    CREATE TYPE user_role AS ENUM ('admin', 'client');
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+DO $$ BEGIN
+    CREATE TYPE post_status AS ENUM ('draft', 'published');
+EXCEPTION WHEN duplicate_object THEN NULL;
+END $$;
+
+CREATE TABLE IF NOT EXISTS users (
+    id           bigserial PRIMARY KEY,
+    username     varchar(50) NOT NULL CONSTRAINT uni_users_username UNIQUE,
+    email        varchar(100) NOT NULL CONSTRAINT uni_users_email UNIQUE,
+    password     varchar(255) NOT NULL,
+    role         user_role NOT NULL DEFAULT 'client',
+    created_at   timestamptz,
+    display_name varchar(100) NOT NULL DEFAULT '',
+    bio          text NOT NULL DEFAULT '',
+    avatar_url   text NOT NULL DEFAULT '',
+    website      varchar(255) NOT NULL DEFAULT '',
+    deleted_at   timestamptz,
+    can_post     boolean DEFAULT true
+);
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS display_name varchar(100) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS bio text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS avatar_url text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS website varchar(255) NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS categories (
+    id                       bigserial PRIMARY KEY,
+    name                     varchar(100) NOT NULL,
+    slug                     varchar(100) NOT NULL CONSTRAINT uni_categories_slug UNIQUE,
+    comments_enabled         boolean NOT NULL DEFAULT true,
+    comments_auto_close_days bigint NOT NULL DEFAULT 0,
+    deleted_at               timestamptz
+);
+ALTER TABLE categories
+    ADD COLUMN IF NOT EXISTS comments_enabled boolean NOT NULL DEFAULT true,
+    ADD COLUMN IF NOT EXISTS comments_auto_close_days bigint NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_categories_deleted_at ON categories (deleted_at);
+
+CREATE TABLE IF NOT EXISTS media (
+    id                    bigserial PRIMARY KEY,
+    owner_id              bigint NOT NULL CONSTRAINT fk_media_owner REFERENCES users (id),
+    storage_key           varchar(255) NOT NULL CONSTRAINT uni_media_storage_key UNIQUE,
+    url                   text NOT NULL,
+    content_type          varchar(50) NOT NULL,
+    size                  bigint NOT NULL,
+    width                 bigint NOT NULL,
+    height                bigint NOT NULL,
+    original_name         varchar(255) NOT NULL DEFAULT '',
+    created_at            timestamptz,
+    status                varchar(20) NOT NULL DEFAULT 'pending',
+    processing_error      text NOT NULL DEFAULT '',
+    processing_started_at timestamptz,
+    processed_at          timestamptz,
+    deleted_at            timestamptz
+);
+ALTER TABLE media
+    ADD COLUMN IF NOT EXISTS status varchar(20) NOT NULL DEFAULT 'pending',
+    ADD COLUMN IF NOT EXISTS processing_error text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS processing_started_at timestamptz,
+    ADD COLUMN IF NOT EXISTS processed_at timestamptz;
+CREATE INDEX IF NOT EXISTS idx_media_owner_id ON media (owner_id);
+CREATE INDEX IF NOT EXISTS idx_media_status ON media (status);
+CREATE INDEX IF NOT EXISTS idx_media_deleted_at ON media (deleted_at);
+
+CREATE TABLE IF NOT EXISTS media_variants (
+    id           bigserial PRIMARY KEY,
+    media_id     bigint NOT NULL CONSTRAINT fk_media_variants REFERENCES media (id) ON DELETE CASCADE,
+    width        bigint NOT NULL,
+    format       varchar(10) NOT NULL,
+    height       bigint NOT NULL,
+    content_type varchar(50) NOT NULL,
+    storage_key  varchar(255) NOT NULL,
+    url          text NOT NULL,
+    size         bigint NOT NULL,
+    created_at   timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_media_variant ON media_variants (media_id, width, format);
+
+CREATE TABLE IF NOT EXISTS posts (
+    id                 bigserial PRIMARY KEY,
+    title              varchar(200) NOT NULL,
+    slug               varchar(200) NOT NULL CONSTRAINT uni_posts_slug UNIQUE,
+    content            text NOT NULL,
+    thumbnail          text NOT NULL,
+    thumbnail_media_id bigint CONSTRAINT fk_posts_thumbnail_media REFERENCES media (id) ON DELETE SET NULL,
+    category_id        bigint CONSTRAINT fk_categories_posts REFERENCES categories (id),
+    author_id          bigint CONSTRAINT fk_users_posts REFERENCES users (id),
+    status             post_status DEFAULT 'draft',
+    published_at       timestamptz,
+    created_at         timestamptz,
+    updated_at         timestamptz,
+    comments_enabled   boolean NOT NULL DEFAULT true,
+    comments_locked_at timestamptz,
+    summary            varchar(500) NOT NULL DEFAULT '',
+    meta_title         varchar(200) NOT NULL DEFAULT '',
+    meta_description   varchar(300) NOT NULL DEFAULT '',
+    canonical_url      text NOT NULL DEFAULT '',
+    og_image           text NOT NULL DEFAULT '',
+    noindex            boolean NOT NULL DEFAULT false,
+    deleted_at         timestamptz
+);
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS thumbnail_media_id bigint CONSTRAINT fk_posts_thumbnail_media REFERENCES media (id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS published_at timestamptz,
+    ADD COLUMN IF NOT EXISTS comments_enabled boolean NOT NULL DEFAULT true,
+    ADD COLUMN IF NOT EXISTS comments_locked_at timestamptz,
+    ADD COLUMN IF NOT EXISTS summary varchar(500) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS meta_title varchar(200) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS meta_description varchar(300) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS canonical_url text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS og_image text NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS noindex boolean NOT NULL DEFAULT false;
+-- AutoMigrate created the SEO columns nullable and without a default.
+UPDATE posts SET
+    summary = COALESCE(summary, ''),
+    meta_title = COALESCE(meta_title, ''),
+    meta_description = COALESCE(meta_description, ''),
+    canonical_url = COALESCE(canonical_url, ''),
+    og_image = COALESCE(og_image, '')
+WHERE summary IS NULL OR meta_title IS NULL OR meta_description IS NULL OR canonical_url IS NULL OR og_image IS NULL;
+ALTER TABLE posts
+    ALTER COLUMN summary SET DEFAULT '',
+    ALTER COLUMN summary SET NOT NULL,
+    ALTER COLUMN meta_title SET DEFAULT '',
+    ALTER COLUMN meta_title SET NOT NULL,
+    ALTER COLUMN meta_description SET DEFAULT '',
+    ALTER COLUMN meta_description SET NOT NULL,
+    ALTER COLUMN canonical_url SET DEFAULT '',
+    ALTER COLUMN canonical_url SET NOT NULL,
+    ALTER COLUMN og_image SET DEFAULT '',
+    ALTER COLUMN og_image SET NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_posts_thumbnail_media_id ON posts (thumbnail_media_id);
+CREATE INDEX IF NOT EXISTS idx_posts_category_feed ON posts (category_id, published_at);
+CREATE INDEX IF NOT EXISTS idx_posts_author_feed ON posts (author_id, published_at);
+CREATE INDEX IF NOT EXISTS idx_posts_deleted_at ON posts (deleted_at);
+
+CREATE TABLE IF NOT EXISTS comments (
+    id         bigserial PRIMARY KEY,
+    post_id    bigint CONSTRAINT fk_posts_comments REFERENCES posts (id),
+    user_id    bigint CONSTRAINT fk_users_comments REFERENCES users (id),
+    parent_id  bigint,
+    content    text NOT NULL,
+    created_at timestamptz,
+    deleted_at timestamptz
+);
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS parent_id bigint;
+CREATE INDEX IF NOT EXISTS idx_comments_parent_id ON comments (parent_id);
+CREATE INDEX IF NOT EXISTS idx_comments_deleted_at ON comments (deleted_at);
+
+CREATE TABLE IF NOT EXISTS mentions (
+    id                bigserial PRIMARY KEY,
+    source_type       varchar(20) NOT NULL,
+    source_id         bigint NOT NULL,
+    mentioned_user_id bigint NOT NULL CONSTRAINT fk_mentions_mentioned_user REFERENCES users (id),
+    author_id         bigint NOT NULL,
+    created_at        timestamptz,
+    deleted_at        timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_mentions_source_user ON mentions (source_type, source_id, mentioned_user_id);
+CREATE INDEX IF NOT EXISTS idx_mentions_mentioned_user_id ON mentions (mentioned_user_id);
+CREATE INDEX IF NOT EXISTS idx_mentions_deleted_at ON mentions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id         bigserial PRIMARY KEY,
+    user_id    bigint NOT NULL,
+    actor_id   bigint CONSTRAINT fk_notifications_actor REFERENCES users (id),
+    type       varchar(50) NOT NULL,
+    post_id    bigint,
+    comment_id bigint,
+    message    text NOT NULL,
+    read_at    timestamptz,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications (user_id);
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id bigint,
+    type    varchar(50),
+    enabled boolean NOT NULL,
+    PRIMARY KEY (user_id, type)
+);
+
+CREATE TABLE IF NOT EXISTS reactions (
+    id          bigserial PRIMARY KEY,
+    target_type varchar(20) NOT NULL,
+    target_id   bigint NOT NULL,
+    user_id     bigint NOT NULL,
+    type        varchar(30) NOT NULL,
+    created_at  timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reactions_target_user ON reactions (target_type, target_id, user_id);
+
+CREATE TABLE IF NOT EXISTS reaction_counts (
+    target_type varchar(20),
+    target_id   bigint,
+    type        varchar(30),
+    count       bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (target_type, target_id, type)
+);
+
+CREATE TABLE IF NOT EXISTS bookmark_collections (
+    id         bigserial PRIMARY KEY,
+    user_id    bigint NOT NULL,
+    name       varchar(100) NOT NULL,
+    created_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bookmark_collections_user_name ON bookmark_collections (user_id, name);
+
+CREATE TABLE IF NOT EXISTS bookmarks (
+    id            bigserial PRIMARY KEY,
+    user_id       bigint NOT NULL,
+    post_id       bigint NOT NULL CONSTRAINT fk_bookmarks_post REFERENCES posts (id),
+    collection_id bigint CONSTRAINT fk_bookmarks_collection REFERENCES bookmark_collections (id),
+    created_at    timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bookmarks_user_post ON bookmarks (user_id, post_id);
+CREATE INDEX IF NOT EXISTS idx_bookmarks_collection_id ON bookmarks (collection_id);
+
+CREATE TABLE IF NOT EXISTS follows (
+    id          bigserial PRIMARY KEY,
+    follower_id bigint NOT NULL,
+    target_type varchar(20) NOT NULL,
+    target_id   bigint NOT NULL,
+    created_at  timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_follow_unique ON follows (follower_id, target_type, target_id);
+CREATE INDEX IF NOT EXISTS idx_follow_target ON follows (target_type, target_id);
+
+CREATE TABLE IF NOT EXISTS post_daily_views (
+    post_id  bigint,
+    day      date,
+    referrer varchar(255),
+    views    bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (post_id, day, referrer)
+);
+CREATE INDEX IF NOT EXISTS idx_post_daily_views_day ON post_daily_views (day);
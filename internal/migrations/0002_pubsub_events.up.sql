@@ -0,0 +1,12 @@
+-- Events of the postgres event bus, which created the table itself before. It keeps them
+-- for a day so that subscribers on any replica can resume from Last-Event-ID.
+
+CREATE TABLE IF NOT EXISTS pubsub_events (
+    id         bigserial PRIMARY KEY,
+    topic      varchar(100) NOT NULL,
+    type       varchar(50) NOT NULL,
+    data       jsonb NOT NULL,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_pubsub_events_topic ON pubsub_events (topic);
+CREATE INDEX IF NOT EXISTS idx_pubsub_events_created_at ON pubsub_events (created_at);
@@ -0,0 +1,4 @@
+-- Irreversible, so rolling back does nothing: the backfilled publication dates cannot be
+-- told apart from the recorded ones, and clearing them all would lose real data.
+
+SELECT 1;
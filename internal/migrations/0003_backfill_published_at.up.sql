@@ -0,0 +1,4 @@
+-- Posts published before published_at existed have it unset, which hides them from the
+-- feeds ordered by it. Their creation date is the best estimate left.
+
+UPDATE posts SET published_at = created_at WHERE status = 'published' AND published_at IS NULL;
@@ -0,0 +1,5 @@
+-- Comments record when they were last edited. Those written before keep their creation
+-- date, the last time they are known to have changed.
+
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS updated_at timestamptz;
+UPDATE comments SET updated_at = created_at WHERE updated_at IS NULL;
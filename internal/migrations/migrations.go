@@ -0,0 +1,9 @@
+// Package migrations holds the database schema as numbered SQL migrations, applied by
+// pkg/migrate. Add a change as the next NNNN_description.up.sql and .down.sql pair; never
+// edit a migration that has been released.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,137 @@
+package migrations_test
+
+import (
+	"blog-api/internal/migrations"
+	"blog-api/internal/repositories"
+	"blog-api/internal/testdb"
+	"blog-api/pkg/migrate"
+	"context"
+	"math"
+	"os"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestMain(m *testing.M) { os.Exit(testdb.Run(m)) }
+
+// baseline is the schema AutoMigrate created before the SQL migrations, with the data of
+// a site running it.
+const baseline = `
+CREATE TYPE user_role AS ENUM ('admin', 'client');
+CREATE TYPE post_status AS ENUM ('draft', 'published');
+CREATE TABLE users (
+    id bigserial PRIMARY KEY,
+    username varchar(50) NOT NULL CONSTRAINT uni_users_username UNIQUE,
+    email varchar(100) NOT NULL CONSTRAINT uni_users_email UNIQUE,
+    password varchar(255) NOT NULL,
+    role user_role NOT NULL DEFAULT 'client',
+    created_at timestamptz,
+    deleted_at timestamptz,
+    can_post boolean DEFAULT true
+);
+CREATE INDEX idx_users_deleted_at ON users (deleted_at);
+CREATE TABLE categories (
+    id bigserial PRIMARY KEY,
+    name varchar(100) NOT NULL,
+    slug varchar(100) NOT NULL CONSTRAINT uni_categories_slug UNIQUE,
+    deleted_at timestamptz
+);
+CREATE INDEX idx_categories_deleted_at ON categories (deleted_at);
+CREATE TABLE posts (
+    id bigserial PRIMARY KEY,
+    title varchar(200) NOT NULL,
+    slug varchar(200) NOT NULL CONSTRAINT uni_posts_slug UNIQUE,
+    content text NOT NULL,
+    thumbnail text NOT NULL,
+    category_id bigint CONSTRAINT fk_categories_posts REFERENCES categories (id),
+    author_id bigint CONSTRAINT fk_users_posts REFERENCES users (id),
+    status post_status DEFAULT 'draft',
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+CREATE INDEX idx_posts_deleted_at ON posts (deleted_at);
+CREATE TABLE comments (
+    id bigserial PRIMARY KEY,
+    post_id bigint CONSTRAINT fk_posts_comments REFERENCES posts (id),
+    user_id bigint CONSTRAINT fk_users_comments REFERENCES users (id),
+    content text NOT NULL,
+    created_at timestamptz,
+    deleted_at timestamptz
+);
+CREATE INDEX idx_comments_deleted_at ON comments (deleted_at);
+
+INSERT INTO users (username, email, password, role, created_at) VALUES ('alice', 'alice@example.com', 'x', 'client', now());
+INSERT INTO categories (name, slug) VALUES ('News', 'news');
+INSERT INTO posts (title, slug, content, thumbnail, category_id, author_id, status, created_at, updated_at)
+VALUES ('Hello', 'hello', 'Hello world', '', 1, 1, 'published', now(), now());
+INSERT INTO comments (post_id, user_id, content, created_at) VALUES (1, 1, 'First', now());
+`
+
+func TestUpgradeBaseline(t *testing.T) {
+	db := testdb.Empty(t, "migrations_test")
+	ctx := context.Background()
+	if _, err := db.ExecContext(ctx, baseline); err != nil {
+		t.Fatal(err)
+	}
+	migrator, err := migrate.New(db, migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatalf("migrating a baseline database: %v", err)
+	}
+
+	// the existing rows get the defaults of the new columns, and the new columns work
+	var displayName, summary string
+	var commentsEnabled bool
+	err = db.QueryRowContext(ctx, `SELECT u.display_name, c.comments_enabled, p.summary
+		FROM posts p JOIN users u ON u.id = p.author_id JOIN categories c ON c.id = p.category_id`).
+		Scan(&displayName, &commentsEnabled, &summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if displayName != "" || !commentsEnabled || summary != "" {
+		t.Errorf("defaults = %q, %v, %q", displayName, commentsEnabled, summary)
+	}
+	// the post published before publication dates were recorded is in the feeds
+	if _, err := db.ExecContext(ctx, `INSERT INTO users (username, email, password, created_at) VALUES ('reader', 'reader@example.com', 'x', now());
+		INSERT INTO follows (follower_id, target_type, target_id, created_at) VALUES (2, 'users', 1, now())`); err != nil {
+		t.Fatal(err)
+	}
+	gormDB, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	feed, err := repositories.NewPostRepository(gormDB).ListFeed(ctx, 2, nil, 0, 10)
+	if err != nil || len(feed) != 1 || feed[0].PublishedAt == nil || !feed[0].PublishedAt.Equal(feed[0].CreatedAt) {
+		t.Errorf("feed = %+v, %v; want the post, published when it was created", feed, err)
+	}
+	// the existing comment was last changed when it was written
+	var edited bool
+	if err := db.QueryRowContext(ctx, `SELECT updated_at IS DISTINCT FROM created_at FROM comments WHERE id = 1`).Scan(&edited); err != nil || edited {
+		t.Errorf("comment updated_at backfilled = %v, %v; want its creation date", !edited, err)
+	}
+	if _, err := db.ExecContext(ctx, `INSERT INTO comments (post_id, user_id, parent_id, content, created_at) VALUES (1, 1, 1, 'Reply', now())`); err != nil {
+		t.Errorf("replying after the upgrade: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `INSERT INTO media (owner_id, storage_key, url, content_type, size, width, height, created_at)
+		VALUES (1, 'a.png', '/a.png', 'image/png', 1, 1, 1, now())`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, `UPDATE posts SET thumbnail_media_id = 1, meta_title = 'Hello' WHERE id = 1`); err != nil {
+		t.Errorf("setting the new post columns: %v", err)
+	}
+
+	if _, err := db.ExecContext(ctx, `INSERT INTO pubsub_events (topic, type, data, created_at) VALUES ('post:1', 'comment', '{}', now())`); err != nil {
+		t.Errorf("publishing an event: %v", err)
+	}
+
+	// and the migrations can be rolled back from there
+	if _, err := migrator.Down(ctx, math.MaxInt); err != nil {
+		t.Fatalf("rolling back: %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"blog-api/internal/entities"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BookmarkRepository interface {
+	FindOrCreateCollection(userID uint, name string) (*entities.BookmarkCollection, error)
+	// Save bookmarks a post, or moves an existing bookmark to the given collection.
+	Save(bookmark *entities.Bookmark) error
+	Delete(userID, postID uint) error
+	// ListByUser returns the user's bookmarks of published, non-deleted posts, newest first,
+	// optionally restricted to one collection.
+	ListByUser(userID uint, collection string, page, pageSize int) ([]entities.Bookmark, int64, error)
+	// ListCollections returns the user's collections by name.
+	ListCollections(userID uint) ([]entities.BookmarkCollection, error)
+	// BookmarkedPostIDs reports which of the given posts the user has bookmarked.
+	BookmarkedPostIDs(userID uint, postIDs []uint) (map[uint]bool, error)
+}
+
+type bookmarkRepository struct {
+	db *gorm.DB
+}
+
+func NewBookmarkRepository(db *gorm.DB) BookmarkRepository {
+	return &bookmarkRepository{db: db}
+}
+
+func (r *bookmarkRepository) FindOrCreateCollection(userID uint, name string) (*entities.BookmarkCollection, error) {
+	collection := entities.BookmarkCollection{UserID: userID, Name: name}
+	err := r.db.Where("user_id = ? AND name = ?", userID, name).FirstOrCreate(&collection).Error
+	if err != nil {
+		return nil, err
+	}
+	return &collection, nil
+}
+
+// Save bookmarks a post, or moves an existing bookmark to the given collection.
+func (r *bookmarkRepository) Save(bookmark *entities.Bookmark) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "post_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"collection_id"}),
+	}).Create(bookmark).Error
+}
+
+func (r *bookmarkRepository) Delete(userID, postID uint) error {
+	result := r.db.Where("user_id = ? AND post_id = ?", userID, postID).Delete(&entities.Bookmark{})
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return result.Error
+}
+
+// ListByUser returns the user's bookmarks of published, non-deleted posts, newest first,
+// optionally restricted to one collection.
+func (r *bookmarkRepository) ListByUser(userID uint, collection string, page, pageSize int) ([]entities.Bookmark, int64, error) {
+	var bookmarks []entities.Bookmark
+	var total int64
+
+	query := r.db.Model(&entities.Bookmark{}).
+		Joins("JOIN posts ON posts.id = bookmarks.post_id AND posts.deleted_at IS NULL AND posts.status = ?", "published").
+		Where("bookmarks.user_id = ?", userID)
+	if collection != "" {
+		query = query.Joins("JOIN bookmark_collections ON bookmark_collections.id = bookmarks.collection_id").
+			Where("bookmark_collections.name = ?", collection)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	offset := (page - 1) * pageSize
+
+	err := query.Preload("Collection").
+		Preload("Post.Author").Preload("Post.Category").Preload("Post.Mentions.MentionedUser").Preload("Post.ReactionCounts").Preload("Post.ThumbnailMedia.Variants", orderVariants).
+		Order("bookmarks.created_at desc").Limit(pageSize).Offset(offset).Find(&bookmarks).Error
+	return bookmarks, total, err
+}
+
+func (r *bookmarkRepository) ListCollections(userID uint) ([]entities.BookmarkCollection, error) {
+	var collections []entities.BookmarkCollection
+	err := r.db.Where("user_id = ?", userID).Order("name asc").Find(&collections).Error
+	return collections, err
+}
+
+// BookmarkedPostIDs reports which of the given posts the user has bookmarked.
+func (r *bookmarkRepository) BookmarkedPostIDs(userID uint, postIDs []uint) (map[uint]bool, error) {
+	result := make(map[uint]bool)
+	if len(postIDs) == 0 {
+		return result, nil
+	}
+	var ids []uint
+	err := r.db.Model(&entities.Bookmark{}).Where("user_id = ? AND post_id IN ?", userID, postIDs).Pluck("post_id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		result[id] = true
+	}
+	return result, nil
+}
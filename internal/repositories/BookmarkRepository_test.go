@@ -0,0 +1,89 @@
+package repositories_test
+
+import (
+	"blog-api/internal/entities"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestBookmarkRepository(t *testing.T) {
+	run(t, func(t *testing.T, r *repos) {
+		alice, bob := r.user(t, "alice"), r.user(t, "bob")
+		news := r.category(t, "News", "news")
+		first := r.post(t, bob, news, "first", "published", at(0))
+		second := r.post(t, bob, news, "second", "published", at(1))
+		draft := r.post(t, bob, news, "draft", "published", at(2))
+		deleted := r.post(t, bob, news, "deleted", "published", at(3))
+
+		later, err := r.bookmarks.FindOrCreateCollection(uint(alice.ID), "Read later")
+		if err != nil {
+			t.Fatal(err)
+		}
+		again, err := r.bookmarks.FindOrCreateCollection(uint(alice.ID), "Read later")
+		if err != nil || again.ID != later.ID {
+			t.Errorf("FindOrCreateCollection created a second collection: %v, %v", again, err)
+		}
+		favorites, err := r.bookmarks.FindOrCreateCollection(uint(alice.ID), "Favorites")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		save := func(post *entities.Post, collectionID *uint, created int) {
+			t.Helper()
+			if err := r.bookmarks.Save(&entities.Bookmark{UserID: uint(alice.ID), PostID: post.ID, CollectionID: collectionID, CreatedAt: at(created)}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		save(first, &later.ID, 10)
+		save(second, nil, 11)
+		save(draft, nil, 12)
+		save(deleted, nil, 13)
+		// saving again moves the bookmark
+		save(second, &favorites.ID, 14)
+
+		if err := r.posts.Update(draft.ID, map[string]interface{}{"status": "draft"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.posts.Delete(deleted.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		bookmarks, total, err := r.bookmarks.ListByUser(uint(alice.ID), "", 1, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 2 || len(bookmarks) != 2 || bookmarks[0].PostID != second.ID || bookmarks[1].PostID != first.ID {
+			t.Fatalf("ListByUser = %+v (total %d); want second, first", bookmarks, total)
+		}
+		if bookmarks[0].Collection == nil || bookmarks[0].Collection.Name != "Favorites" || bookmarks[0].Post.Author.Username != "bob" {
+			t.Errorf("bookmark associations not loaded: %+v", bookmarks[0])
+		}
+
+		bookmarks, total, _ = r.bookmarks.ListByUser(uint(alice.ID), "Read later", 1, 10)
+		if total != 1 || len(bookmarks) != 1 || bookmarks[0].PostID != first.ID {
+			t.Errorf("ListByUser in a collection = %+v (total %d); want first", bookmarks, total)
+		}
+
+		collections, err := r.bookmarks.ListCollections(uint(alice.ID))
+		if err != nil || len(collections) != 2 || collections[0].Name != "Favorites" || collections[1].Name != "Read later" {
+			t.Errorf("ListCollections = %+v, %v; want Favorites, Read later", collections, err)
+		}
+
+		marked, err := r.bookmarks.BookmarkedPostIDs(uint(alice.ID), []uint{first.ID, deleted.ID, 9999})
+		if err != nil || len(marked) != 2 || !marked[first.ID] || !marked[deleted.ID] {
+			t.Errorf("BookmarkedPostIDs = %v, %v", marked, err)
+		}
+		if marked, err := r.bookmarks.BookmarkedPostIDs(uint(alice.ID), nil); err != nil || len(marked) != 0 {
+			t.Errorf("BookmarkedPostIDs of no posts = %v, %v", marked, err)
+		}
+
+		if err := r.bookmarks.Delete(uint(alice.ID), first.ID); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.bookmarks.Delete(uint(alice.ID), first.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("second Delete = %v; want ErrRecordNotFound", err)
+		}
+	})
+}
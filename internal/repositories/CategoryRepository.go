@@ -5,27 +5,42 @@ import (
 	"gorm.io/gorm"
 )
 
-type CategoryRepository struct {
+type CategoryRepository interface {
+	Create(category *entities.Category) error
+	// Update sets the given columns; it returns gorm.ErrRecordNotFound when the category does not exist.
+	Update(id uint, updates map[string]interface{}) error
+	Delete(id uint) error
+	// ListAll returns every category with its posts.
+	ListAll() ([]entities.Category, error)
+	FindByID(id uint) (*entities.Category, error)
+	FindBySlug(slug string) (*entities.Category, error)
+	Exists(id uint) (bool, error)
+	// Merge moves every post and follower of the category fromID into intoID and deletes
+	// fromID. Users following both keep their existing follow of intoID.
+	Merge(fromID, intoID uint) (movedPosts int64, err error)
+}
+
+type categoryRepository struct {
 	db *gorm.DB
 }
 
-func NewCategoryRepository(db *gorm.DB) *CategoryRepository {
-    return &CategoryRepository{db: db}
+func NewCategoryRepository(db *gorm.DB) CategoryRepository {
+    return &categoryRepository{db: db}
 }
 
-func (r *CategoryRepository) Create(category *entities.Category) error {
+func (r *categoryRepository) Create(category *entities.Category) error {
     return r.db.Create(category).Error
 }
 
-func (r *CategoryRepository) Update(id uint, updated *entities.Category) error {
-    result := r.db.Model(&entities.Category{}).Where("id = ?", id).Updates(updated)
+func (r *categoryRepository) Update(id uint, updates map[string]interface{}) error {
+    result := r.db.Model(&entities.Category{}).Where("id = ?", id).Updates(updates)
     if result.RowsAffected == 0 {
         return gorm.ErrRecordNotFound
     }
     return result.Error
 }
 
-func (r *CategoryRepository) Delete(id uint) error {
+func (r *categoryRepository) Delete(id uint) error {
     result := r.db.Delete((&entities.Category{}), id)
     if result.RowsAffected == 0 {
         return gorm.ErrRecordNotFound
@@ -33,14 +48,53 @@ func (r *CategoryRepository) Delete(id uint) error {
     return result.Error
 }
 
-func (r *CategoryRepository) ListAll() ([]entities.Category, error) {
+func (r *categoryRepository) ListAll() ([]entities.Category, error) {
     var categories []entities.Category
     err := r.db.Preload("Posts").Find(&categories).Error
     return categories, err
 }
 
-func (r *CategoryRepository) Exists(id uint) (bool, error) {
+func (r *categoryRepository) FindByID(id uint) (*entities.Category, error) {
+    var category entities.Category
+    if err := r.db.First(&category, id).Error; err != nil {
+        return nil, err
+    }
+    return &category, nil
+}
+
+func (r *categoryRepository) FindBySlug(slug string) (*entities.Category, error) {
+    var category entities.Category
+    if err := r.db.Where("slug = ?", slug).First(&category).Error; err != nil {
+        return nil, err
+    }
+    return &category, nil
+}
+
+func (r *categoryRepository) Exists(id uint) (bool, error) {
     var count int64
     err := r.db.Model(&entities.Category{}).Where("id = ?", id).Count(&count).Error
     return count > 0, err
-}
\ No newline at end of file
+}
+// Merge moves every post and follower of the category fromID into intoID and deletes fromID,
+// in one transaction. Users following both keep their existing follow of intoID.
+func (r *categoryRepository) Merge(fromID, intoID uint) (movedPosts int64, err error) {
+    err = r.db.Transaction(func(tx *gorm.DB) error {
+        result := tx.Unscoped().Model(&entities.Post{}).Where("category_id = ?", fromID).Update("category_id", intoID)
+        if result.Error != nil {
+            return result.Error
+        }
+        movedPosts = result.RowsAffected
+
+        if err := tx.Where("target_type = ? AND target_id = ? AND follower_id IN (?)", entities.FollowTargetCategory, fromID,
+            tx.Model(&entities.Follow{}).Select("follower_id").Where("target_type = ? AND target_id = ?", entities.FollowTargetCategory, intoID),
+        ).Delete(&entities.Follow{}).Error; err != nil {
+            return err
+        }
+        if err := tx.Model(&entities.Follow{}).Where("target_type = ? AND target_id = ?", entities.FollowTargetCategory, fromID).
+            Update("target_id", intoID).Error; err != nil {
+            return err
+        }
+        return tx.Delete(&entities.Category{}, fromID).Error
+    })
+    return movedPosts, err
+}
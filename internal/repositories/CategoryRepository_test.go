@@ -0,0 +1,124 @@
+package repositories_test
+
+import (
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+	"context"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestCategoryRepositoryCRUD(t *testing.T) {
+	run(t, func(t *testing.T, r *repos) {
+		news := r.category(t, "News", "news")
+		if news.ID == 0 || news.CommentsEnabled == nil || !*news.CommentsEnabled {
+			t.Fatalf("create did not apply defaults: %+v", news)
+		}
+		if err := r.categories.Create(&entities.Category{Name: "Other news", Slug: "news"}); err == nil {
+			t.Error("duplicate slug accepted")
+		}
+
+		if err := r.categories.Update(news.ID, map[string]interface{}{"name": "Headlines", "comments_auto_close_days": 7}); err != nil {
+			t.Fatal(err)
+		}
+		found, err := r.categories.FindBySlug("news")
+		if err != nil || found.Name != "Headlines" || found.CommentsAutoCloseDays != 7 {
+			t.Errorf("FindBySlug after update = %+v, %v", found, err)
+		}
+		if err := r.categories.Update(9999, map[string]interface{}{"name": "Nothing"}); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("Update of a missing category = %v; want ErrRecordNotFound", err)
+		}
+
+		if ok, err := r.categories.Exists(news.ID); !ok || err != nil {
+			t.Errorf("Exists = %v, %v", ok, err)
+		}
+		if err := r.categories.Delete(news.ID); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.categories.Delete(news.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("second Delete = %v; want ErrRecordNotFound", err)
+		}
+		if ok, _ := r.categories.Exists(news.ID); ok {
+			t.Error("deleted category exists")
+		}
+		if _, err := r.categories.FindByID(news.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("FindByID of a deleted category = %v; want ErrRecordNotFound", err)
+		}
+		if _, err := r.categories.FindBySlug("news"); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("FindBySlug of a deleted category = %v; want ErrRecordNotFound", err)
+		}
+	})
+}
+
+func TestCategoryRepositoryListAllLoadsPosts(t *testing.T) {
+	run(t, func(t *testing.T, r *repos) {
+		alice := r.user(t, "alice")
+		news := r.category(t, "News", "news")
+		r.category(t, "Empty", "empty")
+		r.post(t, alice, news, "one", "published", at(0))
+		r.post(t, alice, news, "two", "draft", at(1))
+		deleted := r.post(t, alice, news, "three", "published", at(2))
+		if err := r.posts.Delete(deleted.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		categories, err := r.categories.ListAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		posts := make(map[string]int)
+		for _, c := range categories {
+			posts[c.Slug] = len(c.Posts)
+		}
+		if len(categories) != 2 || posts["news"] != 2 || posts["empty"] != 0 {
+			t.Errorf("posts per category = %v; want news: 2, empty: 0", posts)
+		}
+	})
+}
+
+func TestCategoryRepositoryMerge(t *testing.T) {
+	run(t, func(t *testing.T, r *repos) {
+		alice, bob := r.user(t, "alice"), r.user(t, "bob")
+		from := r.category(t, "Old", "old")
+		into := r.category(t, "New", "new")
+		r.post(t, alice, from, "one", "published", at(0))
+		deleted := r.post(t, alice, from, "two", "draft", at(1))
+		if err := r.posts.Delete(deleted.ID); err != nil {
+			t.Fatal(err)
+		}
+		r.post(t, alice, into, "three", "published", at(2))
+
+		for _, f := range []entities.Follow{
+			{FollowerID: uint(alice.ID), TargetType: entities.FollowTargetCategory, TargetID: from.ID},
+			{FollowerID: uint(alice.ID), TargetType: entities.FollowTargetCategory, TargetID: into.ID},
+			{FollowerID: uint(bob.ID), TargetType: entities.FollowTargetCategory, TargetID: from.ID},
+		} {
+			if err := r.follows.Create(&f); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		moved, err := r.categories.Merge(from.ID, into.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// deleted posts move too, so restoring them cannot point at a deleted category
+		if moved != 2 {
+			t.Errorf("moved %d posts; want 2", moved)
+		}
+		if _, total, _ := r.posts.ListPosts(context.Background(), repositories.PostFilter{CategoryID: into.ID}, 1, 10); total != 2 {
+			t.Errorf("category has %d posts after merge; want 2", total)
+		}
+		if ok, _ := r.categories.Exists(from.ID); ok {
+			t.Error("merged category still exists")
+		}
+		if n, _ := r.follows.CountFollowers(entities.FollowTargetCategory, into.ID); n != 2 {
+			t.Errorf("followers after merge = %d; want 2", n)
+		}
+		if n, _ := r.follows.CountFollowers(entities.FollowTargetCategory, from.ID); n != 0 {
+			t.Errorf("followers left on the merged category = %d; want 0", n)
+		}
+	})
+}
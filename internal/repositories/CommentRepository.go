@@ -5,23 +5,42 @@ import (
 	"gorm.io/gorm"
 )
 
-type CommentRepository struct {
+type CommentRepository interface {
+	Create(comment *entities.Comment) error
+	// FindByID loads the comment with its post, mentions and reaction counts.
+	FindByID(id uint) (*entities.Comment, error)
+	Update(id uint, content string) error
+	Delete(id uint) error
+	// ListByPostID returns a page of the post's comments, oldest first, and the total count.
+	ListByPostID(postID uint, page, pageSize int) ([]entities.Comment, int64, error)
+	CountByPostID(postID uint) (int64, error)
+}
+
+type commentRepository struct {
 	db *gorm.DB
 }
 
-func NewCommentRepository(db *gorm.DB) *CommentRepository {
-	return &CommentRepository{db: db}
+func NewCommentRepository(db *gorm.DB) CommentRepository {
+	return &commentRepository{db: db}
 }
 
-func (r *CommentRepository) Create(comment *entities.Comment) error {
+func (r *commentRepository) Create(comment *entities.Comment) error {
 	return r.db.Create(comment).Error
 }
 
-func (r *CommentRepository) Update(id uint, content string) error {
+func (r *commentRepository) FindByID(id uint) (*entities.Comment, error) {
+    var comment entities.Comment
+    if err := r.db.Preload("Post").Preload("Mentions.MentionedUser").Preload("ReactionCounts").First(&comment, id).Error; err != nil {
+        return nil, err
+    }
+    return &comment, nil
+}
+
+func (r *commentRepository) Update(id uint, content string) error {
     return r.db.Model(&entities.Comment{}).Where("id = ?", id).Update("content", content).Error
 }
 
-func (r *CommentRepository) Delete(id uint) error {
+func (r *commentRepository) Delete(id uint) error {
     result := r.db.Delete(&entities.Comment{}, id)
     if result.RowsAffected == 0 {
         return gorm.ErrRecordNotFound
@@ -29,7 +48,7 @@ func (r *CommentRepository) Delete(id uint) error {
     return result.Error
 }
 
-func (r *CommentRepository) ListByPostID(postID uint, page, pageSize int) ([]entities.Comment, int64, error) {
+func (r *commentRepository) ListByPostID(postID uint, page, pageSize int) ([]entities.Comment, int64, error) {
     var comments []entities.Comment
     var total int64
 
@@ -45,6 +64,11 @@ func (r *CommentRepository) ListByPostID(postID uint, page, pageSize int) ([]ent
         pageSize = 10
     }
     offset := (page - 1) * pageSize
-    err := query.Order("created_at asc").Limit(pageSize).Offset(offset).Find(&comments).Error
+    err := query.Preload("Mentions.MentionedUser").Preload("ReactionCounts").Order("created_at asc").Limit(pageSize).Offset(offset).Find(&comments).Error
     return comments, total, err
-}
\ No newline at end of file
+}
+func (r *commentRepository) CountByPostID(postID uint) (int64, error) {
+    var count int64
+    err := r.db.Model(&entities.Comment{}).Where("post_id = ?", postID).Count(&count).Error
+    return count, err
+}
@@ -0,0 +1,63 @@
+package repositories_test
+
+import (
+	"blog-api/internal/entities"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestCommentRepository(t *testing.T) {
+	run(t, func(t *testing.T, r *repos) {
+		alice, bob := r.user(t, "alice"), r.user(t, "bob")
+		news := r.category(t, "News", "news")
+		post := r.post(t, alice, news, "hello", "published", at(0))
+		other := r.post(t, alice, news, "other", "published", at(0))
+
+		var ids []uint
+		for i, content := range []string{"first", "second", "third"} {
+			comment := &entities.Comment{PostID: post.ID, UserID: uint(bob.ID), Content: content, CreatedAt: at(i + 1)}
+			if i == 2 {
+				comment.ParentID = &ids[0]
+			}
+			if err := r.comments.Create(comment); err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, comment.ID)
+		}
+		if err := r.comments.Create(&entities.Comment{PostID: other.ID, UserID: uint(bob.ID), Content: "elsewhere"}); err != nil {
+			t.Fatal(err)
+		}
+
+		comments, total, err := r.comments.ListByPostID(post.ID, 1, 2)
+		if err != nil || total != 3 || len(comments) != 2 || comments[0].Content != "first" || comments[1].Content != "second" {
+			t.Fatalf("first page = %+v (total %d), %v; want first, second of 3", comments, total, err)
+		}
+		comments, _, _ = r.comments.ListByPostID(post.ID, 2, 2)
+		if len(comments) != 1 || comments[0].ParentID == nil || *comments[0].ParentID != ids[0] {
+			t.Errorf("second page = %+v; want the reply to the first comment", comments)
+		}
+
+		if err := r.comments.Update(ids[1], "edited"); err != nil {
+			t.Fatal(err)
+		}
+		found, err := r.comments.FindByID(ids[1])
+		if err != nil || found.Content != "edited" || found.Post.Slug != "hello" {
+			t.Errorf("FindByID = %+v, %v; want edited content and the post loaded", found, err)
+		}
+
+		if err := r.comments.Delete(ids[1]); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.comments.Delete(ids[1]); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("second Delete = %v; want ErrRecordNotFound", err)
+		}
+		if _, err := r.comments.FindByID(ids[1]); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("FindByID of a deleted comment = %v; want ErrRecordNotFound", err)
+		}
+		if n, err := r.comments.CountByPostID(post.ID); n != 2 || err != nil {
+			t.Errorf("CountByPostID = %d, %v; want 2", n, err)
+		}
+	})
+}
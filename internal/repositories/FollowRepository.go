@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"blog-api/internal/entities"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FollowRepository interface {
+	// Create stores the follow, doing nothing if the user already follows the target.
+	Create(follow *entities.Follow) error
+	// Delete returns gorm.ErrRecordNotFound when the user does not follow the target.
+	Delete(followerID uint, targetType string, targetID uint) error
+	CountFollowers(targetType string, targetID uint) (int64, error)
+	CountFollowing(followerID uint, targetType string) (int64, error)
+}
+
+type followRepository struct {
+	db *gorm.DB
+}
+
+func NewFollowRepository(db *gorm.DB) FollowRepository {
+	return &followRepository{db: db}
+}
+
+// Create stores the follow, doing nothing if the user already follows the target.
+func (r *followRepository) Create(follow *entities.Follow) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(follow).Error
+}
+
+func (r *followRepository) Delete(followerID uint, targetType string, targetID uint) error {
+	result := r.db.Where("follower_id = ? AND target_type = ? AND target_id = ?", followerID, targetType, targetID).
+		Delete(&entities.Follow{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *followRepository) CountFollowers(targetType string, targetID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&entities.Follow{}).Where("target_type = ? AND target_id = ?", targetType, targetID).Count(&count).Error
+	return count, err
+}
+
+func (r *followRepository) CountFollowing(followerID uint, targetType string) (int64, error) {
+	var count int64
+	err := r.db.Model(&entities.Follow{}).Where("follower_id = ? AND target_type = ?", followerID, targetType).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,48 @@
+package repositories_test
+
+import (
+	"blog-api/internal/entities"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestFollowRepository(t *testing.T) {
+	run(t, func(t *testing.T, r *repos) {
+		alice, bob, carol := r.user(t, "alice"), r.user(t, "bob"), r.user(t, "carol")
+		news := r.category(t, "News", "news")
+
+		follow := func(follower *entities.User, targetType string, targetID uint) {
+			t.Helper()
+			if err := r.follows.Create(&entities.Follow{FollowerID: uint(follower.ID), TargetType: targetType, TargetID: targetID}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		follow(alice, entities.FollowTargetUser, uint(carol.ID))
+		follow(bob, entities.FollowTargetUser, uint(carol.ID))
+		follow(alice, entities.FollowTargetCategory, news.ID)
+		// following twice is not an error and does not count twice
+		follow(alice, entities.FollowTargetUser, uint(carol.ID))
+
+		if n, err := r.follows.CountFollowers(entities.FollowTargetUser, uint(carol.ID)); n != 2 || err != nil {
+			t.Errorf("CountFollowers = %d, %v; want 2", n, err)
+		}
+		if n, err := r.follows.CountFollowing(uint(alice.ID), entities.FollowTargetUser); n != 1 || err != nil {
+			t.Errorf("CountFollowing users = %d, %v; want 1", n, err)
+		}
+		if n, _ := r.follows.CountFollowing(uint(alice.ID), entities.FollowTargetCategory); n != 1 {
+			t.Errorf("CountFollowing categories = %d; want 1", n)
+		}
+
+		if err := r.follows.Delete(uint(alice.ID), entities.FollowTargetUser, uint(carol.ID)); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.follows.Delete(uint(alice.ID), entities.FollowTargetUser, uint(carol.ID)); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("second Delete = %v; want ErrRecordNotFound", err)
+		}
+		if n, _ := r.follows.CountFollowers(entities.FollowTargetUser, uint(carol.ID)); n != 1 {
+			t.Errorf("CountFollowers after unfollow = %d; want 1", n)
+		}
+	})
+}
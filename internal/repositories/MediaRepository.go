@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"blog-api/internal/entities"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MediaRepository stores media items and their variants, which are loaded ordered by format
+// and width.
+type MediaRepository interface {
+	Create(media *entities.Media) error
+	FindByID(id uint) (*entities.Media, error)
+	// ListByOwner returns a page of the owner's media, newest first, and the total count.
+	ListByOwner(ownerID uint, page, pageSize int) ([]entities.Media, int64, error)
+	// Claim marks a media item as being processed, unless another worker already holds it.
+	// Claims older than staleAfter are considered abandoned and can be taken over.
+	Claim(id uint, staleAfter time.Duration) (bool, error)
+	// ListUnprocessedIDs returns media waiting for processing, including abandoned claims.
+	ListUnprocessedIDs(staleAfter time.Duration, limit int) ([]uint, error)
+	// CompleteProcessing replaces the variants of a media item and marks it ready.
+	CompleteProcessing(id uint, variants []entities.MediaVariant) error
+	FailProcessing(id uint, reason string) error
+}
+
+type mediaRepository struct {
+	db *gorm.DB
+}
+
+func NewMediaRepository(db *gorm.DB) MediaRepository {
+	return &mediaRepository{db: db}
+}
+
+func (r *mediaRepository) Create(media *entities.Media) error {
+	return r.db.Create(media).Error
+}
+
+func (r *mediaRepository) FindByID(id uint) (*entities.Media, error) {
+	var media entities.Media
+	if err := r.db.Preload("Variants", orderVariants).First(&media, id).Error; err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+func (r *mediaRepository) ListByOwner(ownerID uint, page, pageSize int) ([]entities.Media, int64, error) {
+	var media []entities.Media
+	var total int64
+
+	query := r.db.Model(&entities.Media{}).Where("owner_id = ?", ownerID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	offset := (page - 1) * pageSize
+
+	err := query.Preload("Variants", orderVariants).Order("id desc").Limit(pageSize).Offset(offset).Find(&media).Error
+	return media, total, err
+}
+
+func orderVariants(db *gorm.DB) *gorm.DB {
+	return db.Order("format, width")
+}
+
+// Claim marks a media item as being processed, unless another worker already holds it.
+// Claims older than staleAfter are considered abandoned and can be taken over.
+func (r *mediaRepository) Claim(id uint, staleAfter time.Duration) (bool, error) {
+	now := time.Now()
+	result := r.db.Model(&entities.Media{}).
+		Where("id = ?", id).
+		Where("status = ? OR (status = ? AND processing_started_at < ?)",
+			entities.MediaStatusPending, entities.MediaStatusProcessing, now.Add(-staleAfter)).
+		Updates(map[string]interface{}{"status": entities.MediaStatusProcessing, "processing_started_at": now})
+	return result.RowsAffected == 1, result.Error
+}
+
+// ListUnprocessedIDs returns media waiting for processing, including abandoned claims.
+func (r *mediaRepository) ListUnprocessedIDs(staleAfter time.Duration, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&entities.Media{}).
+		Where("status = ? OR (status = ? AND processing_started_at < ?)",
+			entities.MediaStatusPending, entities.MediaStatusProcessing, time.Now().Add(-staleAfter)).
+		Order("id").Limit(limit).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// CompleteProcessing replaces the variants of a media item and marks it ready.
+func (r *mediaRepository) CompleteProcessing(id uint, variants []entities.MediaVariant) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("media_id = ?", id).Delete(&entities.MediaVariant{}).Error; err != nil {
+			return err
+		}
+		if len(variants) > 0 {
+			if err := tx.Create(&variants).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&entities.Media{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"status":           entities.MediaStatusReady,
+			"processing_error": "",
+			"processed_at":     time.Now(),
+		}).Error
+	})
+}
+
+func (r *mediaRepository) FailProcessing(id uint, reason string) error {
+	return r.db.Model(&entities.Media{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":           entities.MediaStatusFailed,
+		"processing_error": reason,
+		"processed_at":     time.Now(),
+	}).Error
+}
@@ -0,0 +1,87 @@
+package repositories_test
+
+import (
+	"blog-api/internal/entities"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestMediaRepository(t *testing.T) {
+	run(t, func(t *testing.T, r *repos) {
+		alice, bob := r.user(t, "alice"), r.user(t, "bob")
+
+		upload := func(owner *entities.User, key string) *entities.Media {
+			t.Helper()
+			media := &entities.Media{OwnerID: uint(owner.ID), StorageKey: key, URL: "/uploads/" + key, ContentType: "image/png", Size: 100, Width: 800, Height: 600}
+			if err := r.media.Create(media); err != nil {
+				t.Fatal(err)
+			}
+			return media
+		}
+		first, second, third := upload(alice, "a.png"), upload(alice, "b.png"), upload(alice, "c.png")
+		fourth := upload(bob, "d.png")
+		if first.Status != entities.MediaStatusPending {
+			t.Errorf("new media status = %q; want pending", first.Status)
+		}
+
+		page, total, err := r.media.ListByOwner(uint(alice.ID), 1, 2)
+		if err != nil || total != 3 || len(page) != 2 || page[0].ID != third.ID || page[1].ID != second.ID {
+			t.Errorf("ListByOwner = %+v (total %d), %v; want the two newest of 3", page, total, err)
+		}
+
+		if ok, err := r.media.Claim(first.ID, time.Minute); !ok || err != nil {
+			t.Fatalf("Claim = %v, %v; want the claim", ok, err)
+		}
+		if ok, _ := r.media.Claim(first.ID, time.Minute); ok {
+			t.Error("a held claim was taken over")
+		}
+		// with no grace period the claim is already stale
+		if ok, _ := r.media.Claim(first.ID, -time.Second); !ok {
+			t.Error("a stale claim was not taken over")
+		}
+
+		ids, err := r.media.ListUnprocessedIDs(time.Minute, 10)
+		if err != nil || !slices.Equal(ids, []uint{second.ID, third.ID, fourth.ID}) {
+			t.Errorf("ListUnprocessedIDs = %v, %v; want everything but the claimed item", ids, err)
+		}
+		if ids, _ := r.media.ListUnprocessedIDs(-time.Second, 2); !slices.Equal(ids, []uint{first.ID, second.ID}) {
+			t.Errorf("ListUnprocessedIDs with stale claims = %v; want the first two", ids)
+		}
+
+		variant := func(width int, format string) entities.MediaVariant {
+			key := fmt.Sprintf("a-%d.%s", width, format)
+			return entities.MediaVariant{MediaID: first.ID, Width: width, Height: width * 3 / 4, Format: format, ContentType: "image/" + format, StorageKey: key, URL: "/uploads/" + key, Size: 10}
+		}
+		if err := r.media.CompleteProcessing(first.ID, []entities.MediaVariant{variant(320, "png")}); err != nil {
+			t.Fatal(err)
+		}
+		// processing again replaces the variants
+		if err := r.media.CompleteProcessing(first.ID, []entities.MediaVariant{variant(640, "webp"), variant(320, "webp"), variant(640, "png")}); err != nil {
+			t.Fatal(err)
+		}
+		found, err := r.media.FindByID(first.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var variants []string
+		for _, v := range found.Variants {
+			variants = append(variants, fmt.Sprintf("%s/%d", v.Format, v.Width))
+		}
+		if found.Status != entities.MediaStatusReady || found.ProcessedAt == nil || !slices.Equal(variants, []string{"png/640", "webp/320", "webp/640"}) {
+			t.Errorf("after processing: status %q, variants %v; want ready with png/640, webp/320, webp/640", found.Status, variants)
+		}
+
+		if err := r.media.FailProcessing(second.ID, "corrupt image"); err != nil {
+			t.Fatal(err)
+		}
+		found, _ = r.media.FindByID(second.ID)
+		if found.Status != entities.MediaStatusFailed || found.ProcessingError != "corrupt image" {
+			t.Errorf("after failing: status %q, error %q", found.Status, found.ProcessingError)
+		}
+		if ok, _ := r.media.Claim(second.ID, -time.Second); ok {
+			t.Error("failed media was claimed")
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"blog-api/internal/entities"
+	"gorm.io/gorm"
+)
+
+type MentionRepository interface {
+	// ListBySource returns every mention of a post or comment, including soft-deleted ones.
+	ListBySource(sourceType string, sourceID uint) ([]entities.Mention, error)
+	Create(mention *entities.Mention) error
+	Restore(id uint) error
+	Delete(ids []uint) error
+	DeleteBySource(sourceType string, sourceID uint) error
+}
+
+type mentionRepository struct {
+	db *gorm.DB
+}
+
+func NewMentionRepository(db *gorm.DB) MentionRepository {
+	return &mentionRepository{db: db}
+}
+
+// ListBySource returns every mention of a post or comment, including soft-deleted ones,
+// so callers can tell a re-added mention from a brand new one.
+func (r *mentionRepository) ListBySource(sourceType string, sourceID uint) ([]entities.Mention, error) {
+	var mentions []entities.Mention
+	err := r.db.Unscoped().Where("source_type = ? AND source_id = ?", sourceType, sourceID).Find(&mentions).Error
+	return mentions, err
+}
+
+func (r *mentionRepository) Create(mention *entities.Mention) error {
+	return r.db.Create(mention).Error
+}
+
+func (r *mentionRepository) Restore(id uint) error {
+	return r.db.Unscoped().Model(&entities.Mention{}).Where("id = ?", id).Update("deleted_at", nil).Error
+}
+
+func (r *mentionRepository) Delete(ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Delete(&entities.Mention{}, ids).Error
+}
+
+func (r *mentionRepository) DeleteBySource(sourceType string, sourceID uint) error {
+	return r.db.Where("source_type = ? AND source_id = ?", sourceType, sourceID).Delete(&entities.Mention{}).Error
+}
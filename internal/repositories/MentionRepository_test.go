@@ -0,0 +1,72 @@
+package repositories_test
+
+import (
+	"blog-api/internal/entities"
+	"testing"
+)
+
+func TestMentionRepository(t *testing.T) {
+	run(t, func(t *testing.T, r *repos) {
+		alice, bob, carol := r.user(t, "alice"), r.user(t, "bob"), r.user(t, "carol")
+		news := r.category(t, "News", "news")
+		post := r.post(t, alice, news, "hello", "published", at(0))
+
+		mention := func(user *entities.User) *entities.Mention {
+			t.Helper()
+			m := &entities.Mention{SourceType: entities.MentionSourcePost, SourceID: post.ID, MentionedUserID: uint(user.ID), AuthorID: uint(alice.ID)}
+			if err := r.mentions.Create(m); err != nil {
+				t.Fatal(err)
+			}
+			return m
+		}
+		toBob, toCarol := mention(bob), mention(carol)
+
+		mentioned := func() []string {
+			t.Helper()
+			found, err := r.posts.FindByID(post.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, m := range found.Mentions {
+				names = append(names, m.MentionedUser.Username)
+			}
+			return names
+		}
+		if names := mentioned(); len(names) != 2 {
+			t.Errorf("post mentions %v; want bob and carol", names)
+		}
+
+		if err := r.mentions.Delete([]uint{toBob.ID}); err != nil {
+			t.Fatal(err)
+		}
+		if names := mentioned(); len(names) != 1 || names[0] != "carol" {
+			t.Errorf("post mentions %v after deleting bob's mention; want carol", names)
+		}
+
+		// deleted mentions are still listed so editing a post back can restore them
+		all, err := r.mentions.ListBySource(entities.MentionSourcePost, post.ID)
+		if err != nil || len(all) != 2 {
+			t.Fatalf("ListBySource = %+v, %v; want both mentions", all, err)
+		}
+		for _, m := range all {
+			if m.ID == toBob.ID && !m.DeletedAt.Valid || m.ID == toCarol.ID && m.DeletedAt.Valid {
+				t.Errorf("mention %d deleted = %v", m.ID, m.DeletedAt.Valid)
+			}
+		}
+
+		if err := r.mentions.Restore(toBob.ID); err != nil {
+			t.Fatal(err)
+		}
+		if names := mentioned(); len(names) != 2 {
+			t.Errorf("post mentions %v after restoring; want bob and carol", names)
+		}
+
+		if err := r.mentions.DeleteBySource(entities.MentionSourcePost, post.ID); err != nil {
+			t.Fatal(err)
+		}
+		if names := mentioned(); len(names) != 0 {
+			t.Errorf("post mentions %v after deleting all; want none", names)
+		}
+	})
+}
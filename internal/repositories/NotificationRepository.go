@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"blog-api/internal/entities"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository interface {
+	Create(notification *entities.Notification) error
+	// ListByUser returns up to limit notifications older than cursor (a notification ID),
+	// newest first. A zero cursor starts from the most recent notification.
+	ListByUser(userID, cursor uint, limit int, unreadOnly bool) ([]entities.Notification, error)
+	CountUnread(userID uint) (int64, error)
+	// MarkRead returns gorm.ErrRecordNotFound when the user has no such notification.
+	MarkRead(userID, id uint) error
+	MarkAllRead(userID uint) error
+	ListPreferences(userID uint) ([]entities.NotificationPreference, error)
+	// SavePreferences creates or updates the given preferences.
+	SavePreferences(prefs []entities.NotificationPreference) error
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) Create(notification *entities.Notification) error {
+	return r.db.Create(notification).Error
+}
+
+// ListByUser returns up to limit notifications older than cursor (a notification ID), newest first.
+// A zero cursor starts from the most recent notification.
+func (r *notificationRepository) ListByUser(userID, cursor uint, limit int, unreadOnly bool) ([]entities.Notification, error) {
+	var notifications []entities.Notification
+
+	query := r.db.Preload("Actor").Where("user_id = ?", userID)
+	if cursor > 0 {
+		query = query.Where("id < ?", cursor)
+	}
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	err := query.Order("id desc").Limit(limit).Find(&notifications).Error
+	return notifications, err
+}
+
+func (r *notificationRepository) CountUnread(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&entities.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *notificationRepository) MarkRead(userID, id uint) error {
+	result := r.db.Model(&entities.Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", time.Now()))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *notificationRepository) MarkAllRead(userID uint) error {
+	return r.db.Model(&entities.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", time.Now()).Error
+}
+
+func (r *notificationRepository) ListPreferences(userID uint) ([]entities.NotificationPreference, error) {
+	var prefs []entities.NotificationPreference
+	err := r.db.Where("user_id = ?", userID).Find(&prefs).Error
+	return prefs, err
+}
+
+func (r *notificationRepository) SavePreferences(prefs []entities.NotificationPreference) error {
+	if len(prefs) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled"}),
+	}).Create(&prefs).Error
+}
@@ -0,0 +1,104 @@
+package repositories_test
+
+import (
+	"blog-api/internal/entities"
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestNotificationRepository(t *testing.T) {
+	run(t, func(t *testing.T, r *repos) {
+		alice, bob := r.user(t, "alice"), r.user(t, "bob")
+		actorID := uint(bob.ID)
+
+		var ids []uint
+		for _, message := range []string{"one", "two", "three", "four"} {
+			n := &entities.Notification{UserID: uint(alice.ID), ActorID: &actorID, Type: entities.NotificationMentioned, Message: message}
+			if err := r.notifications.Create(n); err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, n.ID)
+		}
+		if err := r.notifications.Create(&entities.Notification{UserID: uint(bob.ID), Type: entities.NotificationRoleChanged, Message: "other"}); err != nil {
+			t.Fatal(err)
+		}
+
+		messages := func(cursor uint, limit int, unreadOnly bool) []string {
+			t.Helper()
+			list, err := r.notifications.ListByUser(uint(alice.ID), cursor, limit, unreadOnly)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var out []string
+			for _, n := range list {
+				out = append(out, n.Message)
+			}
+			return out
+		}
+		if got := messages(0, 2, false); len(got) != 2 || got[0] != "four" || got[1] != "three" {
+			t.Errorf("first page = %v; want four, three", got)
+		}
+		if got := messages(ids[2], 10, false); len(got) != 2 || got[0] != "two" || got[1] != "one" {
+			t.Errorf("page after three = %v; want two, one", got)
+		}
+		list, _ := r.notifications.ListByUser(uint(alice.ID), 0, 1, false)
+		if len(list) != 1 || list[0].Actor == nil || list[0].Actor.Username != "bob" {
+			t.Errorf("actor not loaded: %+v", list)
+		}
+
+		if err := r.notifications.MarkRead(uint(alice.ID), ids[3]); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.notifications.MarkRead(uint(bob.ID), ids[2]); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("MarkRead of someone else's notification = %v; want ErrRecordNotFound", err)
+		}
+		if got := messages(0, 10, true); len(got) != 3 || got[0] != "three" {
+			t.Errorf("unread = %v; want three, two, one", got)
+		}
+		if n, err := r.notifications.CountUnread(uint(alice.ID)); n != 3 || err != nil {
+			t.Errorf("CountUnread = %d, %v; want 3", n, err)
+		}
+		if err := r.notifications.MarkAllRead(uint(alice.ID)); err != nil {
+			t.Fatal(err)
+		}
+		if n, _ := r.notifications.CountUnread(uint(alice.ID)); n != 0 {
+			t.Errorf("CountUnread after MarkAllRead = %d; want 0", n)
+		}
+		if n, _ := r.notifications.CountUnread(uint(bob.ID)); n != 1 {
+			t.Errorf("MarkAllRead touched another user: bob has %d unread; want 1", n)
+		}
+	})
+}
+
+func TestNotificationRepositoryPreferences(t *testing.T) {
+	run(t, func(t *testing.T, r *repos) {
+		alice := r.user(t, "alice")
+		userID := uint(alice.ID)
+
+		if err := r.notifications.SavePreferences([]entities.NotificationPreference{
+			{UserID: userID, Type: entities.NotificationMentioned, Enabled: false},
+			{UserID: userID, Type: entities.NotificationRoleChanged, Enabled: false},
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.notifications.SavePreferences([]entities.NotificationPreference{
+			{UserID: userID, Type: entities.NotificationMentioned, Enabled: true},
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		prefs, err := r.notifications.ListPreferences(userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		enabled := make(map[string]bool)
+		for _, p := range prefs {
+			enabled[p.Type] = p.Enabled
+		}
+		if len(prefs) != 2 || !enabled[entities.NotificationMentioned] || enabled[entities.NotificationRoleChanged] {
+			t.Errorf("preferences = %v; want mentions back on and role changes off", enabled)
+		}
+	})
+}
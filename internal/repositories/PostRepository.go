@@ -2,32 +2,67 @@ package repositories
 
 import (
 	"blog-api/internal/entities"
+	"context"
+	"time"
+
 	"gorm.io/gorm"
 )
 
-type PostRepository struct {
+// PostRepository stores posts. Lists of posts come with their author, category, mentions,
+// reaction counts and thumbnail variants loaded.
+type PostRepository interface {
+	// IsSlugExists reports whether any post, deleted ones included, uses the slug.
+	IsSlugExists(slug string) (bool, error)
+	Create(post *entities.Post) error
+	// Update sets the given columns, e.g. "title" or "published_at".
+	Update(id uint, updates map[string]interface{}) error
+	Delete(id uint) error
+	FindByID(id uint) (*entities.Post, error)
+	// ListPosts returns a page of the posts matching the filter, with their comments, and
+	// the total count. The list methods run their queries with ctx, so that they are traced
+	// as part of the request.
+	ListPosts(ctx context.Context, filter PostFilter, page, pageSize int) ([]entities.Post, int64, error)
+	// ListFeed returns up to limit published posts by the authors or in the categories the
+	// user follows, newest first, older than the (before, beforeID) position when before is set.
+	ListFeed(ctx context.Context, userID uint, before *time.Time, beforeID uint, limit int) ([]entities.Post, error)
+	// ListPublishedByAuthor returns a page of the author's published posts, newest first.
+	ListPublishedByAuthor(ctx context.Context, authorID uint, page, pageSize int) ([]entities.Post, int64, error)
+	CountPublishedByAuthor(authorID uint) (int64, error)
+
+	// CountSitemapPosts counts the published posts that may be indexed.
+	CountSitemapPosts() (int64, error)
+	// SitemapPosts returns a page of the published posts that may be indexed, in a stable order.
+	SitemapPosts(offset, limit int) ([]SitemapEntry, error)
+	// SitemapCategories returns the categories that have published posts.
+	SitemapCategories() ([]SitemapEntry, error)
+	CountPublishingAuthors() (int64, error)
+	// SitemapAuthors returns a page of the authors that have published posts.
+	SitemapAuthors(offset, limit int) ([]SitemapEntry, error)
+}
+
+type postRepository struct {
 	db *gorm.DB
 }
 
-func NewPostRepository(db *gorm.DB) *PostRepository {
-	return &PostRepository{db: db}
+func NewPostRepository(db *gorm.DB) PostRepository {
+	return &postRepository{db: db}
 }
 
-func (r *PostRepository) IsSlugExists(slug string) (bool, error) {
+func (r *postRepository) IsSlugExists(slug string) (bool, error) {
 	var count int64
 	err := r.db.Model(&entities.Post{}).Unscoped().Where("slug = ?", slug).Count(&count).Error
 	return count > 0, err
 }
 
-func (r *PostRepository) Create(post *entities.Post) error {
+func (r *postRepository) Create(post *entities.Post) error {
 	return r.db.Create(post).Error
 }
 
-func (r *PostRepository) Update(id uint, updates map[string]interface{}) error {
+func (r *postRepository) Update(id uint, updates map[string]interface{}) error {
     return r.db.Model(&entities.Post{}).Where("id = ?", id).Updates(updates).Error
 }
 
-func (r *PostRepository) Delete(id uint) error {
+func (r *postRepository) Delete(id uint) error {
     result := r.db.Delete(&entities.Post{}, id)
     if result.RowsAffected == 0 {
         return gorm.ErrRecordNotFound
@@ -35,34 +70,59 @@ func (r *PostRepository) Delete(id uint) error {
     return result.Error
 }
 
-func (r *PostRepository) FindByID(id uint) (*entities.Post, error) {
+func (r *postRepository) FindByID(id uint) (*entities.Post, error) {
     var post entities.Post
-    err := r.db.Preload("Author").Preload("Category").First(&post, id).Error
+    err := r.db.Preload("Author").Preload("Category").Preload("Mentions.MentionedUser").Preload("ReactionCounts").Preload("ThumbnailMedia.Variants", orderVariants).First(&post, id).Error
     if err != nil {
         return nil, err
     }
     return &post, nil
 }
 
-func (r *PostRepository) ListPosts(title, content, category, author, status string, page, pageSize int) ([]entities.Post, int64, error) {
+// PostFilter narrows ListPosts. Title, Content, Category (name) and Author (username) are
+// case-insensitive substring matches; CategoryID and AuthorID match exactly. Zero values are ignored.
+type PostFilter struct {
+    Title      string
+    Content    string
+    Category   string
+    Author     string
+    Status     string
+    CategoryID uint
+    AuthorID   uint
+    // ByPublication orders by publication time instead of creation time, unpublished posts last.
+    ByPublication bool
+    // WithoutComments skips loading the comments, for listings that do not show them.
+    WithoutComments bool
+}
+
+func (r *postRepository) ListPosts(ctx context.Context, filter PostFilter, page, pageSize int) ([]entities.Post, int64, error) {
     var posts []entities.Post
     var total int64
 
-    query := r.db.Model(&entities.Post{}).Preload("Author").Preload("Category").Preload("Comments")
-    if title != "" {
-        query = query.Where("title ILIKE ?", "%"+title+"%")
+    query := r.db.WithContext(ctx).Model(&entities.Post{}).Preload("Author").Preload("Category").Preload("Mentions.MentionedUser").Preload("ReactionCounts").Preload("ThumbnailMedia.Variants", orderVariants)
+    if !filter.WithoutComments {
+        query = query.Preload("Comments")
+    }
+    if filter.Title != "" {
+        query = query.Where("title ILIKE ?", "%"+filter.Title+"%")
     }
-    if content != "" {
-        query = query.Where("content ILIKE ?", "%"+content+"%")
+    if filter.Content != "" {
+        query = query.Where("content ILIKE ?", "%"+filter.Content+"%")
     }
-    if category != "" {
-        query = query.Joins("JOIN categories ON categories.id = posts.category_id")  .Where("categories.name ILIKE ?", "%"+category+"%")
+    if filter.Category != "" {
+        query = query.Joins("JOIN categories ON categories.id = posts.category_id")  .Where("categories.name ILIKE ?", "%"+filter.Category+"%")
     }
-    if author != "" {
-        query = query.Joins("JOIN users ON users.id = posts.author_id").Where("users.username ILIKE ?", "%"+author+"%")
+    if filter.Author != "" {
+        query = query.Joins("JOIN users ON users.id = posts.author_id").Where("users.username ILIKE ?", "%"+filter.Author+"%")
     }
-    if status != "" {
-        query = query.Where("status = ?", status)
+    if filter.Status != "" {
+        query = query.Where("status = ?", filter.Status)
+    }
+    if filter.CategoryID != 0 {
+        query = query.Where("posts.category_id = ?", filter.CategoryID)
+    }
+    if filter.AuthorID != 0 {
+        query = query.Where("posts.author_id = ?", filter.AuthorID)
     }
 
     if err := query.Count(&total).Error; err != nil {
@@ -77,6 +137,124 @@ func (r *PostRepository) ListPosts(title, content, category, author, status stri
     }
     offset := (page - 1) * pageSize
 
-    err := query.Limit(pageSize).Offset(offset).Order("created_at desc").Find(&posts).Error
+    order := "posts.created_at desc"
+    if filter.ByPublication {
+        order = "posts.published_at desc nulls last, posts.id desc"
+    }
+    err := query.Limit(pageSize).Offset(offset).Order(order).Find(&posts).Error
     return posts, total, err
 }
+
+// ListFeed returns up to limit published posts by the authors or in the categories the user
+// follows, ordered by publication time and older than the (before, beforeID) position when
+// before is set. The follow lists are resolved as subqueries so the database can walk the
+// (author_id, published_at) and (category_id, published_at) indexes however many follows there are.
+// Every published post has a publication time to page on: the posts published before it was
+// recorded got their creation time by migration 0003.
+func (r *postRepository) ListFeed(ctx context.Context, userID uint, before *time.Time, beforeID uint, limit int) ([]entities.Post, error) {
+    var posts []entities.Post
+    db := r.db.WithContext(ctx)
+
+    followed := func(targetType string) *gorm.DB {
+        return db.Model(&entities.Follow{}).Select("target_id").Where("follower_id = ? AND target_type = ?", userID, targetType)
+    }
+
+    query := db.Preload("Author").Preload("Category").Preload("Mentions.MentionedUser").Preload("ReactionCounts").Preload("ThumbnailMedia.Variants", orderVariants).
+        Where("status = ? AND published_at IS NOT NULL", "published").
+        Where(db.Where("author_id IN (?)", followed(entities.FollowTargetUser)).
+            Or("category_id IN (?)", followed(entities.FollowTargetCategory)))
+    if before != nil {
+        query = query.Where("(published_at, id) < (?, ?)", *before, beforeID)
+    }
+    err := query.Order("published_at desc, id desc").Limit(limit).Find(&posts).Error
+    return posts, err
+}
+
+// ListPublishedByAuthor returns a page of the author's published posts, newest first.
+func (r *postRepository) ListPublishedByAuthor(ctx context.Context, authorID uint, page, pageSize int) ([]entities.Post, int64, error) {
+    var posts []entities.Post
+    var total int64
+
+    query := r.db.WithContext(ctx).Model(&entities.Post{}).Where("author_id = ? AND status = ?", authorID, "published")
+    if err := query.Count(&total).Error; err != nil {
+        return nil, 0, err
+    }
+
+    if page < 1 {
+        page = 1
+    }
+    if pageSize < 1 {
+        pageSize = 10
+    }
+    offset := (page - 1) * pageSize
+
+    err := query.Preload("Author").Preload("Category").Preload("Mentions.MentionedUser").Preload("ReactionCounts").Preload("ThumbnailMedia.Variants", orderVariants).
+        Limit(pageSize).Offset(offset).Order("published_at desc nulls last, id desc").Find(&posts).Error
+    return posts, total, err
+}
+
+func (r *postRepository) CountPublishedByAuthor(authorID uint) (int64, error) {
+    var count int64
+    err := r.db.Model(&entities.Post{}).Where("author_id = ? AND status = ?", authorID, "published").Count(&count).Error
+    return count, err
+}
+
+// SitemapEntry is a page to list in the sitemap: the slug or username identifying it and
+// when its content last changed.
+type SitemapEntry struct {
+    Key     string
+    LastMod time.Time
+}
+
+// CountSitemapPosts counts the published posts that may be indexed.
+func (r *postRepository) CountSitemapPosts() (int64, error) {
+    var count int64
+    err := r.db.Model(&entities.Post{}).Where("status = ? AND noindex = ?", "published", false).Count(&count).Error
+    return count, err
+}
+
+// SitemapPosts returns a page of the published posts that may be indexed, in a stable order.
+func (r *postRepository) SitemapPosts(offset, limit int) ([]SitemapEntry, error) {
+    var entries []SitemapEntry
+    err := r.db.Model(&entities.Post{}).
+        Select("slug AS key, updated_at AS last_mod").
+        Where("status = ? AND noindex = ?", "published", false).
+        Order("id").Offset(offset).Limit(limit).
+        Scan(&entries).Error
+    return entries, err
+}
+
+// SitemapCategories returns the categories that have published posts, with the time their
+// newest change happened.
+func (r *postRepository) SitemapCategories() ([]SitemapEntry, error) {
+    var entries []SitemapEntry
+    err := r.db.Model(&entities.Post{}).
+        Select("categories.slug AS key, MAX(posts.updated_at) AS last_mod").
+        Joins("JOIN categories ON categories.id = posts.category_id AND categories.deleted_at IS NULL").
+        Where("posts.status = ?", "published").
+        Group("categories.id, categories.slug").Order("categories.id").
+        Scan(&entries).Error
+    return entries, err
+}
+
+func (r *postRepository) CountPublishingAuthors() (int64, error) {
+    var count int64
+    err := r.db.Model(&entities.Post{}).
+        Joins("JOIN users ON users.id = posts.author_id AND users.deleted_at IS NULL").
+        Where("posts.status = ?", "published").
+        Distinct("posts.author_id").Count(&count).Error
+    return count, err
+}
+
+// SitemapAuthors returns a page of the authors that have published posts.
+func (r *postRepository) SitemapAuthors(offset, limit int) ([]SitemapEntry, error) {
+    var entries []SitemapEntry
+    err := r.db.Model(&entities.Post{}).
+        Select("users.username AS key, MAX(posts.updated_at) AS last_mod").
+        Joins("JOIN users ON users.id = posts.author_id AND users.deleted_at IS NULL").
+        Where("posts.status = ?", "published").
+        Group("users.id, users.username").Order("users.id").
+        Offset(offset).Limit(limit).
+        Scan(&entries).Error
+    return entries, err
+}
@@ -0,0 +1,298 @@
+package repositories_test
+
+import (
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestPostRepositoryCRUD(t *testing.T) {
+	run(t, func(t *testing.T, r *repos) {
+		alice := r.user(t, "alice")
+		news := r.category(t, "News", "news")
+
+		post := &entities.Post{Title: "Hello", Slug: "hello", Content: "Hi", Thumbnail: "https://example.com/a.png", CategoryID: news.ID, AuthorID: uint(alice.ID)}
+		if err := r.posts.Create(post); err != nil {
+			t.Fatal(err)
+		}
+		if post.ID == 0 || post.Status != "draft" || post.CommentsEnabled == nil || !*post.CommentsEnabled || post.CreatedAt.IsZero() {
+			t.Fatalf("create did not apply defaults: %+v", post)
+		}
+		if err := r.posts.Create(&entities.Post{Title: "Again", Slug: "hello", Content: "Hi", CategoryID: news.ID, AuthorID: uint(alice.ID)}); err == nil {
+			t.Error("duplicate slug accepted")
+		}
+
+		found, err := r.posts.FindByID(post.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found.Author.Username != "alice" || found.Category.Slug != "news" {
+			t.Errorf("associations not loaded: author %q, category %q", found.Author.Username, found.Category.Slug)
+		}
+
+		published := at(5)
+		if err := r.posts.Update(post.ID, map[string]interface{}{
+			"title":              "Hello, world",
+			"status":             "published",
+			"published_at":       published,
+			"comments_enabled":   false,
+			"comments_locked_at": nil,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		found, err = r.posts.FindByID(post.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found.Title != "Hello, world" || found.Status != "published" || found.PublishedAt == nil || !found.PublishedAt.Equal(published) ||
+			*found.CommentsEnabled || found.CommentsLockedAt != nil {
+			t.Errorf("update not applied: %+v", found)
+		}
+		if !found.UpdatedAt.After(post.UpdatedAt) && !found.UpdatedAt.Equal(post.UpdatedAt) {
+			t.Errorf("UpdatedAt went back from %v to %v", post.UpdatedAt, found.UpdatedAt)
+		}
+
+		if err := r.posts.Delete(post.ID); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.posts.Delete(post.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("second Delete = %v; want ErrRecordNotFound", err)
+		}
+		if _, err := r.posts.FindByID(post.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("FindByID of a deleted post = %v; want ErrRecordNotFound", err)
+		}
+		// deleted posts keep their slug
+		if exists, err := r.posts.IsSlugExists("hello"); !exists || err != nil {
+			t.Errorf("IsSlugExists of a deleted post's slug = %v, %v; want true", exists, err)
+		}
+		if exists, _ := r.posts.IsSlugExists("missing"); exists {
+			t.Error("IsSlugExists of an unused slug = true")
+		}
+	})
+}
+
+func TestPostRepositoryListPosts(t *testing.T) {
+	run(t, func(t *testing.T, r *repos) {
+		alice, bob := r.user(t, "alice"), r.user(t, "bob")
+		news, travel := r.category(t, "News", "news"), r.category(t, "Travel Notes", "travel")
+		r.post(t, alice, news, "a", "published", at(0))
+		r.post(t, bob, news, "b", "draft", at(1))
+		r.post(t, alice, travel, "c", "published", at(2))
+		d := r.post(t, bob, travel, "d", "published", at(3))
+		deleted := r.post(t, alice, news, "e", "published", at(4))
+		if err := r.posts.Delete(deleted.ID); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.posts.Update(d.ID, map[string]interface{}{"title": "Something Special"}); err != nil {
+			t.Fatal(err)
+		}
+
+		tests := []struct {
+			name   string
+			filter repositories.PostFilter
+			want   []string
+		}{
+			{"all, newest first", repositories.PostFilter{}, []string{"d", "c", "b", "a"}},
+			{"title", repositories.PostFilter{Title: "SPECIAL"}, []string{"d"}},
+			{"content", repositories.PostFilter{Content: "content of c"}, []string{"c"}},
+			{"category name", repositories.PostFilter{Category: "notes"}, []string{"d", "c"}},
+			{"author username", repositories.PostFilter{Author: "BO"}, []string{"d", "b"}},
+			{"status", repositories.PostFilter{Status: "draft"}, []string{"b"}},
+			{"category ID", repositories.PostFilter{CategoryID: news.ID}, []string{"b", "a"}},
+			{"author ID", repositories.PostFilter{AuthorID: uint(alice.ID)}, []string{"c", "a"}},
+			{"combined", repositories.PostFilter{AuthorID: uint(bob.ID), Status: "published"}, []string{"d"}},
+		}
+		for _, tt := range tests {
+			posts, total, err := r.posts.ListPosts(context.Background(), tt.filter, 1, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(slugs(posts), tt.want) || total != int64(len(tt.want)) {
+				t.Errorf("%s: got %v (total %d); want %v", tt.name, slugs(posts), total, tt.want)
+			}
+		}
+
+		page, total, err := r.posts.ListPosts(context.Background(), repositories.PostFilter{}, 2, 3)
+		if err != nil || total != 4 || !slices.Equal(slugs(page), []string{"a"}) {
+			t.Errorf("page 2 of 3 = %v (total %d), %v; want [a] of 4", slugs(page), total, err)
+		}
+		page, _, _ = r.posts.ListPosts(context.Background(), repositories.PostFilter{}, 3, 3)
+		if len(page) != 0 {
+			t.Errorf("page past the end = %v; want none", slugs(page))
+		}
+	})
+}
+
+func TestPostRepositoryListPostsLoadsAssociations(t *testing.T) {
+	run(t, func(t *testing.T, r *repos) {
+		alice := r.user(t, "alice")
+		news := r.category(t, "News", "news")
+		post := r.post(t, alice, news, "a", "published", at(0))
+		for _, content := range []string{"one", "two"} {
+			if err := r.comments.Create(&entities.Comment{PostID: post.ID, UserID: uint(alice.ID), Content: content}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := r.mentions.Create(&entities.Mention{SourceType: entities.MentionSourcePost, SourceID: post.ID, MentionedUserID: uint(alice.ID), AuthorID: uint(alice.ID)}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.reactions.Toggle(entities.ReactionTargetPost, post.ID, uint(alice.ID), "like"); err != nil {
+			t.Fatal(err)
+		}
+
+		posts, _, err := r.posts.ListPosts(context.Background(), repositories.PostFilter{}, 1, 10)
+		if err != nil || len(posts) != 1 {
+			t.Fatalf("ListPosts = %d posts, %v", len(posts), err)
+		}
+		p := posts[0]
+		if p.Author.Username != "alice" || p.Category.Slug != "news" || len(p.Comments) != 2 ||
+			len(p.Mentions) != 1 || p.Mentions[0].MentionedUser.Username != "alice" ||
+			len(p.ReactionCounts) != 1 || p.ReactionCounts[0].Count != 1 {
+			t.Errorf("associations not loaded: %+v", p)
+		}
+
+		posts, _, err = r.posts.ListPosts(context.Background(), repositories.PostFilter{WithoutComments: true}, 1, 10)
+		if err != nil || len(posts) != 1 || len(posts[0].Comments) != 0 || posts[0].Author.Username != "alice" {
+			t.Errorf("ListPosts without comments = %+v, %v", posts, err)
+		}
+	})
+}
+
+func TestPostRepositoryListByPublication(t *testing.T) {
+	run(t, func(t *testing.T, r *repos) {
+		alice := r.user(t, "alice")
+		news := r.category(t, "News", "news")
+		r.post(t, alice, news, "old", "published", at(0))
+		r.post(t, alice, news, "new", "published", at(5))
+		r.post(t, alice, news, "draft", "draft", at(9))
+		late := r.post(t, alice, news, "late", "published", at(1))
+		if err := r.posts.Update(late.ID, map[string]interface{}{"published_at": at(3)}); err != nil {
+			t.Fatal(err)
+		}
+
+		posts, _, err := r.posts.ListPosts(context.Background(), repositories.PostFilter{ByPublication: true}, 1, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// unpublished posts, with a null publication time, come last
+		if want := []string{"new", "late", "old", "draft"}; !slices.Equal(slugs(posts), want) {
+			t.Errorf("by publication = %v; want %v", slugs(posts), want)
+		}
+	})
+}
+
+func TestPostRepositoryPublishedByAuthor(t *testing.T) {
+	run(t, func(t *testing.T, r *repos) {
+		alice, bob := r.user(t, "alice"), r.user(t, "bob")
+		news := r.category(t, "News", "news")
+		r.post(t, alice, news, "a1", "published", at(0))
+		r.post(t, alice, news, "a2", "draft", at(1))
+		r.post(t, alice, news, "a3", "published", at(2))
+		r.post(t, bob, news, "b1", "published", at(3))
+
+		posts, total, err := r.posts.ListPublishedByAuthor(context.Background(), uint(alice.ID), 1, 10)
+		if err != nil || total != 2 || !slices.Equal(slugs(posts), []string{"a3", "a1"}) {
+			t.Errorf("ListPublishedByAuthor = %v (total %d), %v; want [a3 a1]", slugs(posts), total, err)
+		}
+		if n, err := r.posts.CountPublishedByAuthor(uint(alice.ID)); n != 2 || err != nil {
+			t.Errorf("CountPublishedByAuthor = %d, %v; want 2", n, err)
+		}
+	})
+}
+
+func TestPostRepositoryListFeed(t *testing.T) {
+	run(t, func(t *testing.T, r *repos) {
+		reader, alice, bob, carol := r.user(t, "reader"), r.user(t, "alice"), r.user(t, "bob"), r.user(t, "carol")
+		news, travel := r.category(t, "News", "news"), r.category(t, "Travel", "travel")
+		r.post(t, alice, news, "alice-1", "published", at(0))
+		r.post(t, bob, travel, "bob-travel", "published", at(1))
+		r.post(t, carol, news, "carol-news", "published", at(2))
+		r.post(t, alice, news, "alice-draft", "draft", at(3))
+		r.post(t, alice, travel, "alice-2", "published", at(4))
+		r.post(t, carol, travel, "carol-travel", "published", at(4))
+
+		for _, f := range []entities.Follow{
+			{FollowerID: uint(reader.ID), TargetType: entities.FollowTargetUser, TargetID: uint(alice.ID)},
+			{FollowerID: uint(reader.ID), TargetType: entities.FollowTargetCategory, TargetID: travel.ID},
+			{FollowerID: uint(bob.ID), TargetType: entities.FollowTargetCategory, TargetID: news.ID},
+		} {
+			if err := r.follows.Create(&f); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		feed, err := r.posts.ListFeed(context.Background(), uint(reader.ID), nil, 0, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// posts published at the same time are ordered by ID
+		if want := []string{"carol-travel", "alice-2"}; !slices.Equal(slugs(feed), want) {
+			t.Fatalf("first page = %v; want %v", slugs(feed), want)
+		}
+		last := feed[len(feed)-1]
+		feed, err = r.posts.ListFeed(context.Background(), uint(reader.ID), last.PublishedAt, last.ID, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"bob-travel", "alice-1"}; !slices.Equal(slugs(feed), want) {
+			t.Errorf("second page = %v; want %v", slugs(feed), want)
+		}
+		if feed, _ := r.posts.ListFeed(context.Background(), uint(carol.ID), nil, 0, 10); len(feed) != 0 {
+			t.Errorf("feed without follows = %v; want none", slugs(feed))
+		}
+	})
+}
+
+func TestPostRepositorySitemap(t *testing.T) {
+	run(t, func(t *testing.T, r *repos) {
+		alice, bob, gone := r.user(t, "alice"), r.user(t, "bob"), r.user(t, "gone")
+		news, travel, empty := r.category(t, "News", "news"), r.category(t, "Travel", "travel"), r.category(t, "Empty", "empty")
+		r.post(t, alice, news, "a", "published", at(0))
+		r.post(t, alice, travel, "b", "published", at(2))
+		r.post(t, bob, empty, "draft", "draft", at(3))
+		hidden := r.post(t, bob, news, "hidden", "published", at(1))
+		if err := r.posts.Update(hidden.ID, map[string]interface{}{"noindex": true, "updated_at": at(4)}); err != nil {
+			t.Fatal(err)
+		}
+		r.post(t, gone, travel, "orphan", "published", at(5))
+		if err := r.users.Delete(uint(gone.ID)); err != nil {
+			t.Fatal(err)
+		}
+
+		if n, err := r.posts.CountSitemapPosts(); n != 3 || err != nil {
+			t.Errorf("CountSitemapPosts = %d, %v; want 3", n, err)
+		}
+		entries, err := r.posts.SitemapPosts(1, 5)
+		if err != nil || len(entries) != 2 || entries[0].Key != "b" || entries[1].Key != "orphan" {
+			t.Errorf("SitemapPosts(1, 5) = %v, %v; want b, orphan", entries, err)
+		}
+
+		entries, err = r.posts.SitemapCategories()
+		if err != nil || len(entries) != 2 || entries[0].Key != "news" || entries[1].Key != "travel" {
+			t.Fatalf("SitemapCategories = %v, %v; want news, travel", entries, err)
+		}
+		// noindex posts still count towards their category's last change
+		if !entries[0].LastMod.Equal(at(4)) || !entries[1].LastMod.Equal(at(5)) {
+			t.Errorf("category last changes = %v, %v; want %v, %v", entries[0].LastMod, entries[1].LastMod, at(4), at(5))
+		}
+
+		if n, err := r.posts.CountPublishingAuthors(); n != 2 || err != nil {
+			t.Errorf("CountPublishingAuthors = %d, %v; want 2", n, err)
+		}
+		entries, err = r.posts.SitemapAuthors(0, 10)
+		if err != nil || len(entries) != 2 || entries[0].Key != "alice" || entries[1].Key != "bob" {
+			t.Fatalf("SitemapAuthors = %v, %v; want alice, bob", entries, err)
+		}
+		if !entries[0].LastMod.Equal(at(2)) {
+			t.Errorf("alice last change = %v; want %v", entries[0].LastMod, at(2))
+		}
+		if entries, _ := r.posts.SitemapAuthors(1, 10); len(entries) != 1 || entries[0].Key != "bob" {
+			t.Errorf("SitemapAuthors(1, 10) = %v; want bob", entries)
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"blog-api/internal/entities"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const viewBatchSize = 500
+
+// PostViewRepository stores the daily view counts of posts.
+type PostViewRepository interface {
+	// AddViews adds view counts to the daily rows, creating missing ones.
+	AddViews(rows []entities.PostDailyView) error
+	DailyViews(postID uint, since time.Time) ([]DailyViews, error)
+	TopReferrers(postID uint, since time.Time, limit int) ([]ReferrerViews, error)
+	TotalViews(postID uint) (int64, error)
+	TopPosts(since time.Time, limit int) ([]TopPost, error)
+}
+
+type postViewRepository struct {
+	db *gorm.DB
+}
+
+func NewPostViewRepository(db *gorm.DB) PostViewRepository {
+	return &postViewRepository{db: db}
+}
+
+// AddViews adds buffered view counts to the daily rows, creating missing ones. Rows are
+// written in key order so concurrent flushes from several replicas cannot deadlock.
+func (r *postViewRepository) AddViews(rows []entities.PostDailyView) error {
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.PostID != b.PostID {
+			return a.PostID < b.PostID
+		}
+		if !a.Day.Equal(b.Day) {
+			return a.Day.Before(b.Day)
+		}
+		return a.Referrer < b.Referrer
+	})
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "post_id"}, {Name: "day"}, {Name: "referrer"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"views": gorm.Expr("post_daily_views.views + excluded.views")}),
+	}).CreateInBatches(rows, viewBatchSize).Error
+}
+
+type DailyViews struct {
+	Day   time.Time
+	Views int64
+}
+
+// DailyViews returns the views of a post per day since the given day, oldest first.
+// Days without views are missing.
+func (r *postViewRepository) DailyViews(postID uint, since time.Time) ([]DailyViews, error) {
+	var days []DailyViews
+	err := r.db.Model(&entities.PostDailyView{}).
+		Select("day, SUM(views) AS views").
+		Where("post_id = ? AND day >= ?", postID, since).
+		Group("day").Order("day").
+		Scan(&days).Error
+	return days, err
+}
+
+type ReferrerViews struct {
+	Referrer string
+	Views    int64
+}
+
+// TopReferrers returns the hosts that sent the most views to a post since the given day.
+func (r *postViewRepository) TopReferrers(postID uint, since time.Time, limit int) ([]ReferrerViews, error) {
+	var referrers []ReferrerViews
+	err := r.db.Model(&entities.PostDailyView{}).
+		Select("referrer, SUM(views) AS views").
+		Where("post_id = ? AND day >= ?", postID, since).
+		Group("referrer").Order("views DESC, referrer").Limit(limit).
+		Scan(&referrers).Error
+	return referrers, err
+}
+
+func (r *postViewRepository) TotalViews(postID uint) (int64, error) {
+	var total int64
+	err := r.db.Model(&entities.PostDailyView{}).
+		Select("COALESCE(SUM(views), 0)").
+		Where("post_id = ?", postID).
+		Scan(&total).Error
+	return total, err
+}
+
+type TopPost struct {
+	PostID   uint
+	Title    string
+	Slug     string
+	Username string
+	Views    int64
+}
+
+// TopPosts returns the most viewed posts since the given day across the whole site.
+func (r *postViewRepository) TopPosts(since time.Time, limit int) ([]TopPost, error) {
+	var posts []TopPost
+	err := r.db.Model(&entities.PostDailyView{}).
+		Select("post_daily_views.post_id, posts.title, posts.slug, users.username, SUM(post_daily_views.views) AS views").
+		Joins("JOIN posts ON posts.id = post_daily_views.post_id AND posts.deleted_at IS NULL").
+		Joins("JOIN users ON users.id = posts.author_id").
+		Where("post_daily_views.day >= ?", since).
+		Group("post_daily_views.post_id, posts.title, posts.slug, users.username").
+		Order("views DESC, post_daily_views.post_id").Limit(limit).
+		Scan(&posts).Error
+	return posts, err
+}
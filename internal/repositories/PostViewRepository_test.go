@@ -0,0 +1,73 @@
+package repositories_test
+
+import (
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestPostViewRepository(t *testing.T) {
+	run(t, func(t *testing.T, r *repos) {
+		alice := r.user(t, "alice")
+		news := r.category(t, "News", "news")
+		post := r.post(t, alice, news, "hello", "published", at(0))
+		other := r.post(t, alice, news, "other", "published", at(0))
+		deleted := r.post(t, alice, news, "deleted", "published", at(0))
+
+		day := func(n int) time.Time { return time.Date(2024, 3, n, 0, 0, 0, 0, time.UTC) }
+		view := func(post *entities.Post, d int, referrer string, views int64) entities.PostDailyView {
+			return entities.PostDailyView{PostID: post.ID, Day: day(d), Referrer: referrer, Views: views}
+		}
+		if err := r.views.AddViews([]entities.PostDailyView{
+			view(post, 1, "", 3),
+			view(post, 1, "example.com", 2),
+			view(post, 2, "news.ycombinator.com", 5),
+			view(other, 2, "", 4),
+			view(deleted, 2, "", 100),
+		}); err != nil {
+			t.Fatal(err)
+		}
+		// adding to existing rows sums the views
+		if err := r.views.AddViews([]entities.PostDailyView{view(post, 1, "example.com", 4), view(post, 3, "", 1)}); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.posts.Delete(deleted.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		if total, err := r.views.TotalViews(post.ID); total != 15 || err != nil {
+			t.Errorf("TotalViews = %d, %v; want 15", total, err)
+		}
+		if total, err := r.views.TotalViews(9999); total != 0 || err != nil {
+			t.Errorf("TotalViews of an unviewed post = %d, %v; want 0", total, err)
+		}
+
+		days, err := r.views.DailyViews(post.ID, day(2))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, d := range days {
+			got = append(got, fmt.Sprintf("%s=%d", d.Day.Format("2006-01-02"), d.Views))
+		}
+		if len(got) != 2 || got[0] != "2024-03-02=5" || got[1] != "2024-03-03=1" {
+			t.Errorf("DailyViews = %v; want 2024-03-02=5, 2024-03-03=1", got)
+		}
+
+		referrers, err := r.views.TopReferrers(post.ID, day(1), 2)
+		want := []repositories.ReferrerViews{{Referrer: "example.com", Views: 6}, {Referrer: "news.ycombinator.com", Views: 5}}
+		if err != nil || len(referrers) != 2 || referrers[0] != want[0] || referrers[1] != want[1] {
+			t.Errorf("TopReferrers = %+v, %v; want %+v", referrers, err, want)
+		}
+
+		top, err := r.views.TopPosts(day(2), 10)
+		if err != nil || len(top) != 2 {
+			t.Fatalf("TopPosts = %+v, %v; want two posts", top, err)
+		}
+		if top[0] != (repositories.TopPost{PostID: post.ID, Title: post.Title, Slug: "hello", Username: "alice", Views: 6}) || top[1].PostID != other.ID || top[1].Views != 4 {
+			t.Errorf("TopPosts = %+v; want hello with 6 views, then other with 4", top)
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package repositories
+
+import (
+	"blog-api/internal/entities"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReactionRepository interface {
+	// Toggle adds the user's reaction, switches it to reactionType, or removes it when the
+	// user already reacted with reactionType, keeping the counts in step. It returns the
+	// user's reaction afterwards, or "" when none is left.
+	Toggle(targetType string, targetID, userID uint, reactionType string) (string, error)
+	Counts(targetType string, targetID uint) ([]entities.ReactionCount, error)
+	// UserReactions returns the user's reaction to each of the given targets that they reacted to.
+	UserReactions(targetType string, targetIDs []uint, userID uint) (map[uint]string, error)
+}
+
+type reactionRepository struct {
+	db *gorm.DB
+}
+
+func NewReactionRepository(db *gorm.DB) ReactionRepository {
+	return &reactionRepository{db: db}
+}
+
+// Toggle adds the user's reaction, switches it to reactionType, or removes it when the
+// user already reacted with reactionType. Counters are updated in the same transaction.
+// It returns the user's reaction afterwards, or "" when none is left.
+func (r *reactionRepository) Toggle(targetType string, targetID, userID uint, reactionType string) (string, error) {
+	var current string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for {
+			var existing entities.Reaction
+			err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("target_type = ? AND target_id = ? AND user_id = ?", targetType, targetID, userID).
+				First(&existing).Error
+
+			switch {
+			case errors.Is(err, gorm.ErrRecordNotFound):
+				// A missing row cannot be locked: when a concurrent toggle inserts it first, the
+				// insert waits for that transaction, does nothing, and the row is looked up again.
+				reaction := &entities.Reaction{TargetType: targetType, TargetID: targetID, UserID: userID, Type: reactionType}
+				result := tx.Clauses(clause.OnConflict{
+					Columns:   []clause.Column{{Name: "target_type"}, {Name: "target_id"}, {Name: "user_id"}},
+					DoNothing: true,
+				}).Create(reaction)
+				if result.Error != nil {
+					return result.Error
+				}
+				if result.RowsAffected == 0 {
+					continue
+				}
+				current = reactionType
+				return incrementCount(tx, targetType, targetID, reactionType, 1)
+			case err != nil:
+				return err
+			case existing.Type == reactionType:
+				if err := tx.Delete(&existing).Error; err != nil {
+					return err
+				}
+				return incrementCount(tx, targetType, targetID, reactionType, -1)
+			default:
+				if err := tx.Model(&existing).Update("type", reactionType).Error; err != nil {
+					return err
+				}
+				current = reactionType
+				if err := incrementCount(tx, targetType, targetID, existing.Type, -1); err != nil {
+					return err
+				}
+				return incrementCount(tx, targetType, targetID, reactionType, 1)
+			}
+		}
+	})
+	return current, err
+}
+
+func incrementCount(tx *gorm.DB, targetType string, targetID uint, reactionType string, delta int64) error {
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "target_type"}, {Name: "target_id"}, {Name: "type"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"count": gorm.Expr("reaction_counts.count + ?", delta)}),
+	}).Create(&entities.ReactionCount{TargetType: targetType, TargetID: targetID, Type: reactionType, Count: delta}).Error
+}
+
+func (r *reactionRepository) Counts(targetType string, targetID uint) ([]entities.ReactionCount, error) {
+	var counts []entities.ReactionCount
+	err := r.db.Where("target_type = ? AND target_id = ?", targetType, targetID).Find(&counts).Error
+	return counts, err
+}
+
+// UserReactions returns the user's reaction to each of the given targets that they reacted to.
+func (r *reactionRepository) UserReactions(targetType string, targetIDs []uint, userID uint) (map[uint]string, error) {
+	result := make(map[uint]string)
+	if len(targetIDs) == 0 {
+		return result, nil
+	}
+	var reactions []entities.Reaction
+	err := r.db.Where("target_type = ? AND target_id IN ? AND user_id = ?", targetType, targetIDs, userID).Find(&reactions).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, reaction := range reactions {
+		result[reaction.TargetID] = reaction.Type
+	}
+	return result, nil
+}
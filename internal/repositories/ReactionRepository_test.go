@@ -0,0 +1,101 @@
+package repositories_test
+
+import (
+	"blog-api/internal/entities"
+	"sync"
+	"testing"
+)
+
+func TestReactionRepository(t *testing.T) {
+	run(t, func(t *testing.T, r *repos) {
+		alice, bob := r.user(t, "alice"), r.user(t, "bob")
+		news := r.category(t, "News", "news")
+		post := r.post(t, alice, news, "hello", "published", at(0))
+		other := r.post(t, alice, news, "other", "published", at(0))
+
+		toggle := func(user *entities.User, postID uint, reaction, want string) {
+			t.Helper()
+			got, err := r.reactions.Toggle(entities.ReactionTargetPost, postID, uint(user.ID), reaction)
+			if err != nil || got != want {
+				t.Fatalf("Toggle(%s, %q) = %q, %v; want %q", user.Username, reaction, got, err, want)
+			}
+		}
+		counts := func(postID uint) map[string]int64 {
+			t.Helper()
+			list, err := r.reactions.Counts(entities.ReactionTargetPost, postID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out := make(map[string]int64)
+			for _, c := range list {
+				out[c.Type] = c.Count
+			}
+			return out
+		}
+
+		toggle(alice, post.ID, "like", "like")
+		toggle(bob, post.ID, "like", "like")
+		toggle(bob, other.ID, "love", "love")
+		if c := counts(post.ID); len(c) != 1 || c["like"] != 2 {
+			t.Errorf("counts = %v; want like: 2", c)
+		}
+
+		// reacting with another type switches the reaction
+		toggle(bob, post.ID, "love", "love")
+		if c := counts(post.ID); c["like"] != 1 || c["love"] != 1 {
+			t.Errorf("counts after switching = %v; want like: 1, love: 1", c)
+		}
+
+		// reacting with the same type removes it; the emptied count stays at zero
+		toggle(alice, post.ID, "like", "")
+		if c := counts(post.ID); len(c) != 2 || c["like"] != 0 || c["love"] != 1 {
+			t.Errorf("counts after removing = %v; want like: 0, love: 1", c)
+		}
+
+		mine, err := r.reactions.UserReactions(entities.ReactionTargetPost, []uint{post.ID, other.ID}, uint(bob.ID))
+		if err != nil || len(mine) != 2 || mine[post.ID] != "love" || mine[other.ID] != "love" {
+			t.Errorf("bob's reactions = %v, %v", mine, err)
+		}
+		if mine, _ := r.reactions.UserReactions(entities.ReactionTargetPost, []uint{post.ID, other.ID}, uint(alice.ID)); len(mine) != 0 {
+			t.Errorf("alice's reactions = %v; want none", mine)
+		}
+		if mine, err := r.reactions.UserReactions(entities.ReactionTargetComment, []uint{post.ID}, uint(bob.ID)); len(mine) != 0 || err != nil {
+			t.Errorf("reactions on comments = %v, %v; want none", mine, err)
+		}
+	})
+}
+
+func TestReactionRepositoryConcurrentToggles(t *testing.T) {
+	run(t, func(t *testing.T, r *repos) {
+		alice := r.user(t, "alice")
+		post := r.post(t, alice, r.category(t, "News", "news"), "hello", "published", at(0))
+
+		// the first reaction races with the others: each toggle still applies once
+		const toggles = 8
+		var wg sync.WaitGroup
+		errs := make(chan error, toggles)
+		for i := 0; i < toggles; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := r.reactions.Toggle(entities.ReactionTargetPost, post.ID, uint(alice.ID), "like"); err != nil {
+					errs <- err
+				}
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			t.Errorf("Toggle: %v", err)
+		}
+
+		mine, err := r.reactions.UserReactions(entities.ReactionTargetPost, []uint{post.ID}, uint(alice.ID))
+		if err != nil || len(mine) != 0 {
+			t.Errorf("reactions after %d toggles = %v, %v; want none", toggles, mine, err)
+		}
+		counts, err := r.reactions.Counts(entities.ReactionTargetPost, post.ID)
+		if err != nil || len(counts) != 1 || counts[0].Count != 0 {
+			t.Errorf("counts = %+v, %v; want like: 0", counts, err)
+		}
+	})
+}
@@ -6,15 +6,35 @@ import (
 	"gorm.io/gorm"
 )
 
-type UserRepository struct {
+// UserRepository stores user accounts. Lookups by email or username return nil, nil when
+// no user matches.
+type UserRepository interface {
+	FindEmail(email string) (*entities.User, error)
+	FindByUsername(username string) (*entities.User, error)
+	Create(user *entities.User) error
+	// FindByID loads the user with their posts and comments.
+	FindByID(id uint) (*entities.User, error)
+	// ListAll returns a page of users, newest first, and the total count.
+	ListAll(page, pageSize int) ([]entities.User, int64, error)
+	Delete(id uint) error
+	// Update saves the username, email, password and role of the user.
+	Update(user *entities.User) error
+	UpdateCanPost(userID uint, canPost bool) error
+	// UpdateProfile sets the given columns, e.g. "display_name" or "avatar_url".
+	UpdateProfile(userID uint, updates map[string]interface{}) error
+	UpdateUsername(userID uint, username string) error
+	UpdateEmail(userID uint, email string) error
+}
+
+type userRepository struct {
 	db *gorm.DB
 }
 
-func NewUserRepository(db *gorm.DB) *UserRepository {
-	return &UserRepository{db: db}
+func NewUserRepository(db *gorm.DB) UserRepository {
+	return &userRepository{db: db}
 }
 
-func (r *UserRepository) FindEmail(email string) (*entities.User, error) {
+func (r *userRepository) FindEmail(email string) (*entities.User, error) {
 	var user entities.User
 	err := r.db.Where("email = ?", email).First(&user).Error
 	if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -23,7 +43,7 @@ func (r *UserRepository) FindEmail(email string) (*entities.User, error) {
 	return &user, err
 }
 
-func (r *UserRepository) FindByUsername(username string) (*entities.User, error) {
+func (r *userRepository) FindByUsername(username string) (*entities.User, error) {
     var user entities.User
     err := r.db.Where("username = ?", username).First(&user).Error
     if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -32,11 +52,11 @@ func (r *UserRepository) FindByUsername(username string) (*entities.User, error)
     return &user, err
 }
 
-func (r *UserRepository) Create(user *entities.User) error {
+func (r *userRepository) Create(user *entities.User) error {
 	return r.db.Create(user).Error
 }
 
-func (r *UserRepository) FindByID(id uint) (*entities.User, error) {
+func (r *userRepository) FindByID(id uint) (*entities.User, error) {
 	var user entities.User
     err := r.db.Preload("Posts").Preload("Comments").First(&user, id).Error
     if errors.Is(err, gorm.ErrRecordNotFound){
@@ -45,7 +65,7 @@ func (r *UserRepository) FindByID(id uint) (*entities.User, error) {
     return &user, err
 }
 
-func (r *UserRepository) ListAll(page, pageSize int) ([]entities.User, int64, error) {
+func (r *userRepository) ListAll(page, pageSize int) ([]entities.User, int64, error) {
 	var users []entities.User
     var total int64
 
@@ -65,7 +85,7 @@ func (r *UserRepository) ListAll(page, pageSize int) ([]entities.User, int64, er
     return users, total, err
 }
 
-func (r *UserRepository) Delete(id uint) error {
+func (r *userRepository) Delete(id uint) error {
 	result := r.db.Delete(&entities.User{}, id)
 	if result.RowsAffected == 0{
 		return errors.New("user not found")
@@ -74,7 +94,7 @@ func (r *UserRepository) Delete(id uint) error {
 	return result.Error
 }
 
-func (r *UserRepository) Update(user  *entities.User) error {
+func (r *userRepository) Update(user  *entities.User) error {
 	return r.db.Model(&entities.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
         "username":  user.Username,
         "email":     user.Email,
@@ -83,6 +103,18 @@ func (r *UserRepository) Update(user  *entities.User) error {
     }).Error
 }
 
-func (r *UserRepository) UpdateCanPost(userID uint, canPost bool) error {
+func (r *userRepository) UpdateCanPost(userID uint, canPost bool) error {
     return r.db.Model(&entities.User{}).Where("id = ?", userID).Update("can_post", canPost).Error
-}
\ No newline at end of file
+}
+
+func (r *userRepository) UpdateProfile(userID uint, updates map[string]interface{}) error {
+    return r.db.Model(&entities.User{}).Where("id = ?", userID).Updates(updates).Error
+}
+
+func (r *userRepository) UpdateUsername(userID uint, username string) error {
+    return r.db.Model(&entities.User{}).Where("id = ?", userID).Update("username", username).Error
+}
+
+func (r *userRepository) UpdateEmail(userID uint, email string) error {
+    return r.db.Model(&entities.User{}).Where("id = ?", userID).Update("email", email).Error
+}
@@ -0,0 +1,154 @@
+package repositories_test
+
+import (
+	"blog-api/internal/entities"
+	"testing"
+)
+
+func TestUserRepositoryCreateAndFind(t *testing.T) {
+	run(t, func(t *testing.T, r *repos) {
+		alice := &entities.User{Username: "alice", Email: "alice@example.com", Password: "hash"}
+		if err := r.users.Create(alice); err != nil {
+			t.Fatal(err)
+		}
+		if alice.ID == 0 || alice.CreatedAt.IsZero() {
+			t.Fatalf("ID and CreatedAt not set: %+v", alice)
+		}
+		if alice.Role != "client" || !alice.CanPost {
+			t.Errorf("defaults: role %q, can post %v; want client, true", alice.Role, alice.CanPost)
+		}
+
+		found, err := r.users.FindByUsername("alice")
+		if err != nil || found == nil || found.ID != alice.ID {
+			t.Fatalf("FindByUsername = %v, %v", found, err)
+		}
+		found, err = r.users.FindEmail("alice@example.com")
+		if err != nil || found == nil || found.ID != alice.ID {
+			t.Fatalf("FindEmail = %v, %v", found, err)
+		}
+		if found, err := r.users.FindByUsername("nobody"); found != nil || err != nil {
+			t.Errorf("FindByUsername of a missing user = %v, %v; want nil, nil", found, err)
+		}
+		if found, err := r.users.FindEmail("nobody@example.com"); found != nil || err != nil {
+			t.Errorf("FindEmail of a missing user = %v, %v; want nil, nil", found, err)
+		}
+
+		if err := r.users.Create(&entities.User{Username: "alice", Email: "other@example.com", Password: "hash"}); err == nil {
+			t.Error("duplicate username accepted")
+		}
+		if err := r.users.Create(&entities.User{Username: "other", Email: "alice@example.com", Password: "hash"}); err == nil {
+			t.Error("duplicate email accepted")
+		}
+	})
+}
+
+func TestUserRepositoryFindByIDLoadsPostsAndComments(t *testing.T) {
+	run(t, func(t *testing.T, r *repos) {
+		alice := r.user(t, "alice")
+		general := r.category(t, "General", "general")
+		post := r.post(t, alice, general, "hello", "published", at(0))
+		if err := r.comments.Create(&entities.Comment{PostID: post.ID, UserID: uint(alice.ID), Content: "first"}); err != nil {
+			t.Fatal(err)
+		}
+
+		found, err := r.users.FindByID(uint(alice.ID))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(found.Posts) != 1 || len(found.Comments) != 1 {
+			t.Errorf("loaded %d posts and %d comments; want 1 and 1", len(found.Posts), len(found.Comments))
+		}
+		if _, err := r.users.FindByID(9999); err == nil {
+			t.Error("FindByID of a missing user succeeded")
+		}
+	})
+}
+
+func TestUserRepositoryUpdates(t *testing.T) {
+	run(t, func(t *testing.T, r *repos) {
+		alice := r.user(t, "alice")
+		r.user(t, "bob")
+		id := uint(alice.ID)
+
+		alice.Role = "admin"
+		alice.Password = "new-hash"
+		if err := r.users.Update(alice); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.users.UpdateCanPost(id, false); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.users.UpdateProfile(id, map[string]interface{}{"display_name": "Alice A.", "bio": "Writes things"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.users.UpdateUsername(id, "alicia"); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.users.UpdateEmail(id, "alicia@example.com"); err != nil {
+			t.Fatal(err)
+		}
+
+		found, err := r.users.FindByID(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found.Role != "admin" || found.Password != "new-hash" || found.CanPost || found.DisplayName != "Alice A." ||
+			found.Bio != "Writes things" || found.Username != "alicia" || found.Email != "alicia@example.com" {
+			t.Errorf("updates not applied: %+v", found)
+		}
+
+		if err := r.users.UpdateUsername(id, "bob"); err == nil {
+			t.Error("renaming to a taken username succeeded")
+		}
+	})
+}
+
+func TestUserRepositoryListAndDelete(t *testing.T) {
+	run(t, func(t *testing.T, r *repos) {
+		for i, name := range []string{"ann", "ben", "cat", "dan", "eve"} {
+			user := &entities.User{Username: name, Email: name + "@example.com", Password: "hash", CreatedAt: at(i)}
+			if err := r.users.Create(user); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		page, total, err := r.users.ListAll(2, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 5 || len(page) != 2 || page[0].Username != "cat" || page[1].Username != "ben" {
+			t.Errorf("page 2 = %v (total %d); want cat, ben of 5", usernames(page), total)
+		}
+		// invalid paging falls back to the first page of 10
+		page, _, err = r.users.ListAll(0, 0)
+		if err != nil || len(page) != 5 || page[0].Username != "eve" {
+			t.Errorf("default page = %v, %v", usernames(page), err)
+		}
+
+		dan, _ := r.users.FindByUsername("dan")
+		if err := r.users.Delete(uint(dan.ID)); err != nil {
+			t.Fatal(err)
+		}
+		if err := r.users.Delete(uint(dan.ID)); err == nil {
+			t.Error("deleting a deleted user succeeded")
+		}
+		if found, err := r.users.FindByUsername("dan"); found != nil || err != nil {
+			t.Errorf("deleted user found: %v, %v", found, err)
+		}
+		if _, total, _ := r.users.ListAll(1, 10); total != 4 {
+			t.Errorf("total after delete = %d; want 4", total)
+		}
+		// deleted users keep their username
+		if err := r.users.Create(&entities.User{Username: "dan", Email: "dan2@example.com", Password: "hash"}); err == nil {
+			t.Error("reusing a deleted user's username succeeded")
+		}
+	})
+}
+
+func usernames(users []entities.User) []string {
+	list := make([]string, 0, len(users))
+	for _, u := range users {
+		list = append(list, u.Username)
+	}
+	return list
+}
@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type bookmarkRepository struct {
+	s *Store
+}
+
+func NewBookmarkRepository(s *Store) repositories.BookmarkRepository {
+	return &bookmarkRepository{s: s}
+}
+
+func (r *bookmarkRepository) FindOrCreateCollection(userID uint, name string) (*entities.BookmarkCollection, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, c := range r.s.collections {
+		if c.UserID == userID && c.Name == name {
+			return &c, nil
+		}
+	}
+	collection := entities.BookmarkCollection{UserID: userID, Name: name}
+	prepareCreate(&collection, time.Now())
+	collection.ID = r.s.nextID("bookmark_collections")
+	r.s.collections = append(r.s.collections, collection)
+	return &collection, nil
+}
+
+func (r *bookmarkRepository) Save(bookmark *entities.Bookmark) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	prepareCreate(bookmark, time.Now())
+	for i, b := range r.s.bookmarks {
+		if b.UserID == bookmark.UserID && b.PostID == bookmark.PostID {
+			r.s.bookmarks[i].CollectionID = bookmark.CollectionID
+			bookmark.ID = b.ID
+			return nil
+		}
+	}
+	bookmark.ID = r.s.nextID("bookmarks")
+	r.s.bookmarks = append(r.s.bookmarks, stored(bookmark))
+	return nil
+}
+
+func (r *bookmarkRepository) Delete(userID, postID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for i, b := range r.s.bookmarks {
+		if b.UserID == userID && b.PostID == postID {
+			r.s.bookmarks = append(r.s.bookmarks[:i], r.s.bookmarks[i+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *bookmarkRepository) ListByUser(userID uint, collection string, page, pageSize int) ([]entities.Bookmark, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var bookmarks []entities.Bookmark
+	for _, b := range r.s.bookmarks {
+		if b.UserID != userID {
+			continue
+		}
+		i, ok := r.s.findPost(b.PostID)
+		if !ok || r.s.posts[i].Status != "published" {
+			continue
+		}
+		b.Post = r.s.loadPost(r.s.posts[i], false)
+		b.Collection = r.collection(b.CollectionID)
+		if collection != "" && (b.Collection == nil || b.Collection.Name != collection) {
+			continue
+		}
+		bookmarks = append(bookmarks, b)
+	}
+	sort.SliceStable(bookmarks, func(i, j int) bool { return bookmarks[i].CreatedAt.After(bookmarks[j].CreatedAt) })
+	return paginate(bookmarks, page, pageSize), int64(len(bookmarks)), nil
+}
+
+func (r *bookmarkRepository) collection(id *uint) *entities.BookmarkCollection {
+	if id == nil {
+		return nil
+	}
+	for _, c := range r.s.collections {
+		if c.ID == *id {
+			return &c
+		}
+	}
+	return nil
+}
+
+func (r *bookmarkRepository) ListCollections(userID uint) ([]entities.BookmarkCollection, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	collections := []entities.BookmarkCollection{}
+	for _, c := range r.s.collections {
+		if c.UserID == userID {
+			collections = append(collections, c)
+		}
+	}
+	sort.SliceStable(collections, func(i, j int) bool { return collections[i].Name < collections[j].Name })
+	return collections, nil
+}
+
+func (r *bookmarkRepository) BookmarkedPostIDs(userID uint, postIDs []uint) (map[uint]bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	result := make(map[uint]bool)
+	wanted := make(map[uint]bool, len(postIDs))
+	for _, id := range postIDs {
+		wanted[id] = true
+	}
+	for _, b := range r.s.bookmarks {
+		if b.UserID == userID && wanted[b.PostID] {
+			result[b.PostID] = true
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type categoryRepository struct {
+	s *Store
+}
+
+func NewCategoryRepository(s *Store) repositories.CategoryRepository {
+	return &categoryRepository{s: s}
+}
+
+func (r *categoryRepository) Create(category *entities.Category) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if err := r.checkUnique(0, category.Slug); err != nil {
+		return err
+	}
+	prepareCreate(category, time.Now())
+	category.ID = r.s.nextID("categories")
+	r.s.categories = append(r.s.categories, stored(category))
+	return nil
+}
+
+func (r *categoryRepository) checkUnique(id uint, slug string) error {
+	for _, c := range r.s.categories {
+		if c.ID != id && c.Slug == slug {
+			return duplicate("categories_slug_key")
+		}
+	}
+	return nil
+}
+
+func (r *categoryRepository) Update(id uint, updates map[string]interface{}) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i, ok := r.s.findCategory(id)
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	category := r.s.categories[i]
+	if err := applyUpdates(&category, updates, time.Now()); err != nil {
+		return err
+	}
+	if err := r.checkUnique(id, category.Slug); err != nil {
+		return err
+	}
+	r.s.categories[i] = category
+	return nil
+}
+
+func (r *categoryRepository) Delete(id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i, ok := r.s.findCategory(id)
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	r.s.categories[i].DeletedAt = softDelete(time.Now())
+	return nil
+}
+
+func (r *categoryRepository) ListAll() ([]entities.Category, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	categories := []entities.Category{}
+	for _, c := range r.s.categories {
+		if !alive(c.DeletedAt) {
+			continue
+		}
+		for _, p := range r.s.posts {
+			if p.CategoryID == c.ID && alive(p.DeletedAt) {
+				c.Posts = append(c.Posts, p)
+			}
+		}
+		categories = append(categories, c)
+	}
+	return categories, nil
+}
+
+func (r *categoryRepository) FindByID(id uint) (*entities.Category, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i, ok := r.s.findCategory(id)
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	category := r.s.categories[i]
+	return &category, nil
+}
+
+func (r *categoryRepository) FindBySlug(slug string) (*entities.Category, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, c := range r.s.categories {
+		if c.Slug == slug && alive(c.DeletedAt) {
+			return &c, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *categoryRepository) Exists(id uint) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	_, ok := r.s.findCategory(id)
+	return ok, nil
+}
+
+func (r *categoryRepository) Merge(fromID, intoID uint) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var moved int64
+	for i := range r.s.posts {
+		if r.s.posts[i].CategoryID == fromID {
+			r.s.posts[i].CategoryID = intoID
+			r.s.posts[i].UpdatedAt = time.Now()
+			moved++
+		}
+	}
+
+	following := make(map[uint]bool)
+	for _, f := range r.s.follows {
+		if f.TargetType == entities.FollowTargetCategory && f.TargetID == intoID {
+			following[f.FollowerID] = true
+		}
+	}
+	follows := r.s.follows[:0]
+	for _, f := range r.s.follows {
+		if f.TargetType == entities.FollowTargetCategory && f.TargetID == fromID {
+			if following[f.FollowerID] {
+				continue
+			}
+			f.TargetID = intoID
+		}
+		follows = append(follows, f)
+	}
+	r.s.follows = follows
+
+	// deleting a category that does not exist is not an error, as with GORM
+	if i, ok := r.s.findCategory(fromID); ok {
+		r.s.categories[i].DeletedAt = softDelete(time.Now())
+	}
+	return moved, nil
+}
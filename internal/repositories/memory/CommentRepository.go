@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type commentRepository struct {
+	s *Store
+}
+
+func NewCommentRepository(s *Store) repositories.CommentRepository {
+	return &commentRepository{s: s}
+}
+
+func (r *commentRepository) Create(comment *entities.Comment) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	prepareCreate(comment, time.Now())
+	comment.ID = r.s.nextID("comments")
+	r.s.comments = append(r.s.comments, stored(comment))
+	return nil
+}
+
+func (r *commentRepository) FindByID(id uint) (*entities.Comment, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i, ok := r.s.findComment(id)
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	comment := r.load(r.s.comments[i])
+	if i, ok := r.s.findPost(comment.PostID); ok {
+		comment.Post = r.s.posts[i]
+	}
+	return &comment, nil
+}
+
+func (r *commentRepository) load(c entities.Comment) entities.Comment {
+	c.Mentions = r.s.loadMentions(entities.MentionSourceComment, c.ID)
+	c.ReactionCounts = r.s.loadReactionCounts(entities.ReactionTargetComment, c.ID)
+	return c
+}
+
+func (r *commentRepository) Update(id uint, content string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if i, ok := r.s.findComment(id); ok {
+		return applyUpdates(&r.s.comments[i], map[string]interface{}{"content": content}, time.Now())
+	}
+	return nil
+}
+
+func (r *commentRepository) Delete(id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i, ok := r.s.findComment(id)
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	r.s.comments[i].DeletedAt = softDelete(time.Now())
+	return nil
+}
+
+func (r *commentRepository) ListByPostID(postID uint, page, pageSize int) ([]entities.Comment, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var comments []entities.Comment
+	for _, c := range r.s.comments {
+		if c.PostID == postID && alive(c.DeletedAt) {
+			comments = append(comments, c)
+		}
+	}
+	sort.SliceStable(comments, func(i, j int) bool { return comments[i].CreatedAt.Before(comments[j].CreatedAt) })
+
+	result := paginate(comments, page, pageSize)
+	for i := range result {
+		result[i] = r.load(result[i])
+	}
+	return result, int64(len(comments)), nil
+}
+
+func (r *commentRepository) CountByPostID(postID uint) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var count int64
+	for _, c := range r.s.comments {
+		if c.PostID == postID && alive(c.DeletedAt) {
+			count++
+		}
+	}
+	return count, nil
+}
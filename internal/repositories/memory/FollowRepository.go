@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type followRepository struct {
+	s *Store
+}
+
+func NewFollowRepository(s *Store) repositories.FollowRepository {
+	return &followRepository{s: s}
+}
+
+func (r *followRepository) Create(follow *entities.Follow) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	prepareCreate(follow, time.Now())
+	for _, f := range r.s.follows {
+		if f.FollowerID == follow.FollowerID && f.TargetType == follow.TargetType && f.TargetID == follow.TargetID {
+			return nil
+		}
+	}
+	follow.ID = r.s.nextID("follows")
+	r.s.follows = append(r.s.follows, *follow)
+	return nil
+}
+
+func (r *followRepository) Delete(followerID uint, targetType string, targetID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for i, f := range r.s.follows {
+		if f.FollowerID == followerID && f.TargetType == targetType && f.TargetID == targetID {
+			r.s.follows = append(r.s.follows[:i], r.s.follows[i+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *followRepository) CountFollowers(targetType string, targetID uint) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var count int64
+	for _, f := range r.s.follows {
+		if f.TargetType == targetType && f.TargetID == targetID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *followRepository) CountFollowing(followerID uint, targetType string) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var count int64
+	for _, f := range r.s.follows {
+		if f.FollowerID == followerID && f.TargetType == targetType {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,144 @@
+package memory
+
+import (
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type mediaRepository struct {
+	s *Store
+}
+
+func NewMediaRepository(s *Store) repositories.MediaRepository {
+	return &mediaRepository{s: s}
+}
+
+func (r *mediaRepository) Create(media *entities.Media) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, m := range r.s.media {
+		if m.StorageKey == media.StorageKey {
+			return duplicate("media_storage_key_key")
+		}
+	}
+	prepareCreate(media, time.Now())
+	media.ID = r.s.nextID("media")
+	r.s.media = append(r.s.media, stored(media))
+	return nil
+}
+
+func (r *mediaRepository) FindByID(id uint) (*entities.Media, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i, ok := r.s.findMedia(id)
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	media := r.s.media[i]
+	media.Variants = r.s.loadVariants(id)
+	return &media, nil
+}
+
+func (r *mediaRepository) ListByOwner(ownerID uint, page, pageSize int) ([]entities.Media, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var media []entities.Media
+	for _, m := range r.s.media {
+		if m.OwnerID == ownerID && alive(m.DeletedAt) {
+			media = append(media, m)
+		}
+	}
+	sort.SliceStable(media, func(i, j int) bool { return media[i].ID > media[j].ID })
+
+	result := paginate(media, page, pageSize)
+	for i := range result {
+		result[i].Variants = r.s.loadVariants(result[i].ID)
+	}
+	return result, int64(len(media)), nil
+}
+
+// unprocessed reports whether a media item waits for processing or its claim was abandoned.
+func unprocessed(m entities.Media, staleBefore time.Time) bool {
+	switch m.Status {
+	case entities.MediaStatusPending:
+		return true
+	case entities.MediaStatusProcessing:
+		return m.ProcessingStartedAt != nil && m.ProcessingStartedAt.Before(staleBefore)
+	}
+	return false
+}
+
+func (r *mediaRepository) Claim(id uint, staleAfter time.Duration) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	now := time.Now()
+	i, ok := r.s.findMedia(id)
+	if !ok || !unprocessed(r.s.media[i], now.Add(-staleAfter)) {
+		return false, nil
+	}
+	r.s.media[i].Status = entities.MediaStatusProcessing
+	r.s.media[i].ProcessingStartedAt = &now
+	return true, nil
+}
+
+func (r *mediaRepository) ListUnprocessedIDs(staleAfter time.Duration, limit int) ([]uint, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	staleBefore := time.Now().Add(-staleAfter)
+	ids := []uint{}
+	for _, m := range r.s.media {
+		if alive(m.DeletedAt) && unprocessed(m, staleBefore) {
+			ids = append(ids, m.ID)
+		}
+	}
+	return window(ids, 0, limit), nil
+}
+
+func (r *mediaRepository) CompleteProcessing(id uint, variants []entities.MediaVariant) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	now := time.Now()
+
+	kept := make([]entities.MediaVariant, 0, len(r.s.variants))
+	for _, v := range r.s.variants {
+		if v.MediaID != id {
+			kept = append(kept, v)
+		}
+	}
+	for i := range variants {
+		for _, v := range variants[:i] {
+			if v.MediaID == variants[i].MediaID && v.Width == variants[i].Width && v.Format == variants[i].Format {
+				return duplicate("idx_media_variant")
+			}
+		}
+	}
+	for i := range variants {
+		prepareCreate(&variants[i], now)
+		variants[i].ID = r.s.nextID("media_variants")
+		kept = append(kept, variants[i])
+	}
+	r.s.variants = kept
+
+	if i, ok := r.s.findMedia(id); ok {
+		r.s.media[i].Status = entities.MediaStatusReady
+		r.s.media[i].ProcessingError = ""
+		r.s.media[i].ProcessedAt = &now
+	}
+	return nil
+}
+
+func (r *mediaRepository) FailProcessing(id uint, reason string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if i, ok := r.s.findMedia(id); ok {
+		now := time.Now()
+		r.s.media[i].Status = entities.MediaStatusFailed
+		r.s.media[i].ProcessingError = reason
+		r.s.media[i].ProcessedAt = &now
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type mentionRepository struct {
+	s *Store
+}
+
+func NewMentionRepository(s *Store) repositories.MentionRepository {
+	return &mentionRepository{s: s}
+}
+
+func (r *mentionRepository) ListBySource(sourceType string, sourceID uint) ([]entities.Mention, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	mentions := []entities.Mention{}
+	for _, m := range r.s.mentions {
+		if m.SourceType == sourceType && m.SourceID == sourceID {
+			mentions = append(mentions, m)
+		}
+	}
+	return mentions, nil
+}
+
+func (r *mentionRepository) Create(mention *entities.Mention) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, m := range r.s.mentions {
+		if m.SourceType == mention.SourceType && m.SourceID == mention.SourceID && m.MentionedUserID == mention.MentionedUserID {
+			return duplicate("idx_mentions_source_user")
+		}
+	}
+	prepareCreate(mention, time.Now())
+	mention.ID = r.s.nextID("mentions")
+	r.s.mentions = append(r.s.mentions, stored(mention))
+	return nil
+}
+
+func (r *mentionRepository) Restore(id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for i := range r.s.mentions {
+		if r.s.mentions[i].ID == id {
+			r.s.mentions[i].DeletedAt = gorm.DeletedAt{}
+		}
+	}
+	return nil
+}
+
+func (r *mentionRepository) Delete(ids []uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	now := time.Now()
+	for _, id := range ids {
+		for i := range r.s.mentions {
+			if r.s.mentions[i].ID == id && alive(r.s.mentions[i].DeletedAt) {
+				r.s.mentions[i].DeletedAt = softDelete(now)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *mentionRepository) DeleteBySource(sourceType string, sourceID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	now := time.Now()
+	for i, m := range r.s.mentions {
+		if m.SourceType == sourceType && m.SourceID == sourceID && alive(m.DeletedAt) {
+			r.s.mentions[i].DeletedAt = softDelete(now)
+		}
+	}
+	return nil
+}
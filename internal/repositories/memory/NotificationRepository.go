@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type notificationRepository struct {
+	s *Store
+}
+
+func NewNotificationRepository(s *Store) repositories.NotificationRepository {
+	return &notificationRepository{s: s}
+}
+
+func (r *notificationRepository) Create(notification *entities.Notification) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	prepareCreate(notification, time.Now())
+	notification.ID = r.s.nextID("notifications")
+	r.s.notifications = append(r.s.notifications, stored(notification))
+	return nil
+}
+
+func (r *notificationRepository) ListByUser(userID, cursor uint, limit int, unreadOnly bool) ([]entities.Notification, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	notifications := []entities.Notification{}
+	// rows are kept in ID order, so walking backwards lists the newest first
+	for i := len(r.s.notifications) - 1; i >= 0; i-- {
+		n := r.s.notifications[i]
+		if n.UserID != userID || (cursor > 0 && n.ID >= cursor) || (unreadOnly && n.ReadAt != nil) {
+			continue
+		}
+		if n.ActorID != nil {
+			if j, ok := r.s.findUser(*n.ActorID); ok {
+				actor := r.s.users[j]
+				n.Actor = &actor
+			}
+		}
+		notifications = append(notifications, n)
+	}
+	return window(notifications, 0, limit), nil
+}
+
+func (r *notificationRepository) CountUnread(userID uint) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var count int64
+	for _, n := range r.s.notifications {
+		if n.UserID == userID && n.ReadAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *notificationRepository) MarkRead(userID, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for i, n := range r.s.notifications {
+		if n.ID == id && n.UserID == userID {
+			if n.ReadAt == nil {
+				now := time.Now()
+				r.s.notifications[i].ReadAt = &now
+			}
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *notificationRepository) MarkAllRead(userID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	now := time.Now()
+	for i, n := range r.s.notifications {
+		if n.UserID == userID && n.ReadAt == nil {
+			r.s.notifications[i].ReadAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *notificationRepository) ListPreferences(userID uint) ([]entities.NotificationPreference, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	prefs := []entities.NotificationPreference{}
+	for _, p := range r.s.preferences {
+		if p.UserID == userID {
+			prefs = append(prefs, p)
+		}
+	}
+	return prefs, nil
+}
+
+func (r *notificationRepository) SavePreferences(prefs []entities.NotificationPreference) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+next:
+	for _, pref := range prefs {
+		for i, p := range r.s.preferences {
+			if p.UserID == pref.UserID && p.Type == pref.Type {
+				r.s.preferences[i].Enabled = pref.Enabled
+				continue next
+			}
+		}
+		r.s.preferences = append(r.s.preferences, pref)
+	}
+	return nil
+}
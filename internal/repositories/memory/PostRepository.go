@@ -0,0 +1,312 @@
+package memory
+
+import (
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+	"context"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type postRepository struct {
+	s *Store
+}
+
+func NewPostRepository(s *Store) repositories.PostRepository {
+	return &postRepository{s: s}
+}
+
+func (r *postRepository) IsSlugExists(slug string) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, p := range r.s.posts {
+		if p.Slug == slug {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *postRepository) Create(post *entities.Post) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if err := r.checkUnique(0, post.Slug); err != nil {
+		return err
+	}
+	prepareCreate(post, time.Now())
+	post.ID = r.s.nextID("posts")
+	r.s.posts = append(r.s.posts, stored(post))
+	return nil
+}
+
+func (r *postRepository) checkUnique(id uint, slug string) error {
+	for _, p := range r.s.posts {
+		if p.ID != id && p.Slug == slug {
+			return duplicate("posts_slug_key")
+		}
+	}
+	return nil
+}
+
+func (r *postRepository) Update(id uint, updates map[string]interface{}) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i, ok := r.s.findPost(id)
+	if !ok {
+		return nil
+	}
+	post := r.s.posts[i]
+	if err := applyUpdates(&post, updates, time.Now()); err != nil {
+		return err
+	}
+	if err := r.checkUnique(id, post.Slug); err != nil {
+		return err
+	}
+	r.s.posts[i] = post
+	return nil
+}
+
+func (r *postRepository) Delete(id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i, ok := r.s.findPost(id)
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	r.s.posts[i].DeletedAt = softDelete(time.Now())
+	return nil
+}
+
+func (r *postRepository) FindByID(id uint) (*entities.Post, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i, ok := r.s.findPost(id)
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	post := r.s.loadPost(r.s.posts[i], false)
+	return &post, nil
+}
+
+func (r *postRepository) ListPosts(_ context.Context, filter repositories.PostFilter, page, pageSize int) ([]entities.Post, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var posts []entities.Post
+	for _, p := range r.s.posts {
+		if alive(p.DeletedAt) && r.matches(p, filter) {
+			posts = append(posts, p)
+		}
+	}
+	if filter.ByPublication {
+		byPublication(posts)
+	} else {
+		sort.SliceStable(posts, func(i, j int) bool { return posts[i].CreatedAt.After(posts[j].CreatedAt) })
+	}
+
+	result := paginate(posts, page, pageSize)
+	for i := range result {
+		result[i] = r.s.loadPost(result[i], !filter.WithoutComments)
+	}
+	return result, int64(len(posts)), nil
+}
+
+func (r *postRepository) matches(p entities.Post, filter repositories.PostFilter) bool {
+	if filter.Title != "" && !ilike(p.Title, filter.Title) {
+		return false
+	}
+	if filter.Content != "" && !ilike(p.Content, filter.Content) {
+		return false
+	}
+	// the GORM query inner joins categories and users without their default scope
+	if filter.Category != "" {
+		matched := false
+		for _, c := range r.s.categories {
+			if c.ID == p.CategoryID && ilike(c.Name, filter.Category) {
+				matched = true
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if filter.Author != "" {
+		matched := false
+		for _, u := range r.s.users {
+			if uint(u.ID) == p.AuthorID && ilike(u.Username, filter.Author) {
+				matched = true
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if filter.Status != "" && p.Status != filter.Status {
+		return false
+	}
+	if filter.CategoryID != 0 && p.CategoryID != filter.CategoryID {
+		return false
+	}
+	if filter.AuthorID != 0 && p.AuthorID != filter.AuthorID {
+		return false
+	}
+	return true
+}
+
+// byPublication orders posts by "published_at desc nulls last, id desc".
+func byPublication(posts []entities.Post) {
+	sort.SliceStable(posts, func(i, j int) bool {
+		a, b := posts[i].PublishedAt, posts[j].PublishedAt
+		switch {
+		case a == nil && b == nil:
+		case a == nil:
+			return false
+		case b == nil:
+			return true
+		case !a.Equal(*b):
+			return a.After(*b)
+		}
+		return posts[i].ID > posts[j].ID
+	})
+}
+
+func (r *postRepository) ListFeed(_ context.Context, userID uint, before *time.Time, beforeID uint, limit int) ([]entities.Post, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	authors := make(map[uint]bool)
+	categories := make(map[uint]bool)
+	for _, f := range r.s.follows {
+		switch {
+		case f.FollowerID != userID:
+		case f.TargetType == entities.FollowTargetUser:
+			authors[f.TargetID] = true
+		case f.TargetType == entities.FollowTargetCategory:
+			categories[f.TargetID] = true
+		}
+	}
+
+	var posts []entities.Post
+	for _, p := range r.s.posts {
+		if !alive(p.DeletedAt) || p.Status != "published" || p.PublishedAt == nil {
+			continue
+		}
+		if !authors[p.AuthorID] && !categories[p.CategoryID] {
+			continue
+		}
+		if before != nil && !p.PublishedAt.Before(*before) && !(p.PublishedAt.Equal(*before) && p.ID < beforeID) {
+			continue
+		}
+		posts = append(posts, p)
+	}
+	byPublication(posts)
+
+	posts = window(posts, 0, limit)
+	for i := range posts {
+		posts[i] = r.s.loadPost(posts[i], false)
+	}
+	return posts, nil
+}
+
+func (r *postRepository) ListPublishedByAuthor(_ context.Context, authorID uint, page, pageSize int) ([]entities.Post, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	posts := r.published(func(p entities.Post) bool { return p.AuthorID == authorID })
+	byPublication(posts)
+
+	result := paginate(posts, page, pageSize)
+	for i := range result {
+		result[i] = r.s.loadPost(result[i], false)
+	}
+	return result, int64(len(posts)), nil
+}
+
+func (r *postRepository) CountPublishedByAuthor(authorID uint) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return int64(len(r.published(func(p entities.Post) bool { return p.AuthorID == authorID }))), nil
+}
+
+// published returns the published, non-deleted posts that match, by ID.
+func (r *postRepository) published(match func(p entities.Post) bool) []entities.Post {
+	var posts []entities.Post
+	for _, p := range r.s.posts {
+		if alive(p.DeletedAt) && p.Status == "published" && match(p) {
+			posts = append(posts, p)
+		}
+	}
+	return posts
+}
+
+func indexable(p entities.Post) bool {
+	return !p.Noindex
+}
+
+func (r *postRepository) CountSitemapPosts() (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return int64(len(r.published(indexable))), nil
+}
+
+func (r *postRepository) SitemapPosts(offset, limit int) ([]repositories.SitemapEntry, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	entries := []repositories.SitemapEntry{}
+	for _, p := range window(r.published(indexable), offset, limit) {
+		entries = append(entries, repositories.SitemapEntry{Key: p.Slug, LastMod: p.UpdatedAt})
+	}
+	return entries, nil
+}
+
+func (r *postRepository) SitemapCategories() ([]repositories.SitemapEntry, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	entries := []repositories.SitemapEntry{}
+	for _, c := range r.s.categories {
+		if !alive(c.DeletedAt) {
+			continue
+		}
+		if lastMod, ok := latestUpdate(r.published(func(p entities.Post) bool { return p.CategoryID == c.ID })); ok {
+			entries = append(entries, repositories.SitemapEntry{Key: c.Slug, LastMod: lastMod})
+		}
+	}
+	return entries, nil
+}
+
+func (r *postRepository) CountPublishingAuthors() (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return int64(len(r.publishingAuthors())), nil
+}
+
+func (r *postRepository) SitemapAuthors(offset, limit int) ([]repositories.SitemapEntry, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return window(r.publishingAuthors(), offset, limit), nil
+}
+
+// publishingAuthors returns the non-deleted users with published posts, by ID.
+func (r *postRepository) publishingAuthors() []repositories.SitemapEntry {
+	var entries []repositories.SitemapEntry
+	for _, u := range r.s.users {
+		if !alive(u.DeletedAt) {
+			continue
+		}
+		if lastMod, ok := latestUpdate(r.published(func(p entities.Post) bool { return p.AuthorID == uint(u.ID) })); ok {
+			entries = append(entries, repositories.SitemapEntry{Key: u.Username, LastMod: lastMod})
+		}
+	}
+	return entries
+}
+
+func latestUpdate(posts []entities.Post) (time.Time, bool) {
+	var t time.Time
+	for _, p := range posts {
+		if p.UpdatedAt.After(t) {
+			t = p.UpdatedAt
+		}
+	}
+	return t, len(posts) > 0
+}
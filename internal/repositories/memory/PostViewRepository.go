@@ -0,0 +1,126 @@
+package memory
+
+import (
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+	"sort"
+	"time"
+)
+
+type postViewRepository struct {
+	s *Store
+}
+
+func NewPostViewRepository(s *Store) repositories.PostViewRepository {
+	return &postViewRepository{s: s}
+}
+
+func (r *postViewRepository) AddViews(rows []entities.PostDailyView) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+next:
+	for _, row := range rows {
+		for i, v := range r.s.views {
+			if v.PostID == row.PostID && v.Day.Equal(row.Day) && v.Referrer == row.Referrer {
+				r.s.views[i].Views += row.Views
+				continue next
+			}
+		}
+		r.s.views = append(r.s.views, row)
+	}
+	return nil
+}
+
+// since returns the view rows of a post on or after the given day.
+func (r *postViewRepository) since(postID uint, since time.Time) []entities.PostDailyView {
+	var rows []entities.PostDailyView
+	for _, v := range r.s.views {
+		if v.PostID == postID && !v.Day.Before(since) {
+			rows = append(rows, v)
+		}
+	}
+	return rows
+}
+
+func (r *postViewRepository) DailyViews(postID uint, since time.Time) ([]repositories.DailyViews, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	days := []repositories.DailyViews{}
+next:
+	for _, v := range r.since(postID, since) {
+		for i := range days {
+			if days[i].Day.Equal(v.Day) {
+				days[i].Views += v.Views
+				continue next
+			}
+		}
+		days = append(days, repositories.DailyViews{Day: v.Day, Views: v.Views})
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Day.Before(days[j].Day) })
+	return days, nil
+}
+
+func (r *postViewRepository) TopReferrers(postID uint, since time.Time, limit int) ([]repositories.ReferrerViews, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	totals := make(map[string]int64)
+	for _, v := range r.since(postID, since) {
+		totals[v.Referrer] += v.Views
+	}
+	referrers := []repositories.ReferrerViews{}
+	for referrer, views := range totals {
+		referrers = append(referrers, repositories.ReferrerViews{Referrer: referrer, Views: views})
+	}
+	sort.Slice(referrers, func(i, j int) bool {
+		if referrers[i].Views != referrers[j].Views {
+			return referrers[i].Views > referrers[j].Views
+		}
+		return referrers[i].Referrer < referrers[j].Referrer
+	})
+	return window(referrers, 0, limit), nil
+}
+
+func (r *postViewRepository) TotalViews(postID uint) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var total int64
+	for _, v := range r.s.views {
+		if v.PostID == postID {
+			total += v.Views
+		}
+	}
+	return total, nil
+}
+
+func (r *postViewRepository) TopPosts(since time.Time, limit int) ([]repositories.TopPost, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	totals := make(map[uint]int64)
+	for _, v := range r.s.views {
+		if !v.Day.Before(since) {
+			totals[v.PostID] += v.Views
+		}
+	}
+
+	posts := []repositories.TopPost{}
+	for postID, views := range totals {
+		i, ok := r.s.findPost(postID)
+		if !ok {
+			continue
+		}
+		post := r.s.posts[i]
+		// the author is joined without the default scope, so deleted authors still count
+		for _, u := range r.s.users {
+			if uint(u.ID) == post.AuthorID {
+				posts = append(posts, repositories.TopPost{PostID: postID, Title: post.Title, Slug: post.Slug, Username: u.Username, Views: views})
+			}
+		}
+	}
+	sort.Slice(posts, func(i, j int) bool {
+		if posts[i].Views != posts[j].Views {
+			return posts[i].Views > posts[j].Views
+		}
+		return posts[i].PostID < posts[j].PostID
+	})
+	return window(posts, 0, limit), nil
+}
@@ -0,0 +1,78 @@
+package memory
+
+import (
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+	"time"
+)
+
+type reactionRepository struct {
+	s *Store
+}
+
+func NewReactionRepository(s *Store) repositories.ReactionRepository {
+	return &reactionRepository{s: s}
+}
+
+func (r *reactionRepository) Toggle(targetType string, targetID, userID uint, reactionType string) (string, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for i, existing := range r.s.reactions {
+		if existing.TargetType != targetType || existing.TargetID != targetID || existing.UserID != userID {
+			continue
+		}
+		r.incrementCount(targetType, targetID, existing.Type, -1)
+		if existing.Type == reactionType {
+			r.s.reactions = append(r.s.reactions[:i], r.s.reactions[i+1:]...)
+			return "", nil
+		}
+		r.s.reactions[i].Type = reactionType
+		r.incrementCount(targetType, targetID, reactionType, 1)
+		return reactionType, nil
+	}
+
+	reaction := entities.Reaction{TargetType: targetType, TargetID: targetID, UserID: userID, Type: reactionType}
+	prepareCreate(&reaction, time.Now())
+	reaction.ID = r.s.nextID("reactions")
+	r.s.reactions = append(r.s.reactions, reaction)
+	r.incrementCount(targetType, targetID, reactionType, 1)
+	return reactionType, nil
+}
+
+// incrementCount changes a counter, creating it at delta; counters that drop to zero are
+// kept, as in the database.
+func (r *reactionRepository) incrementCount(targetType string, targetID uint, reactionType string, delta int64) {
+	for i, c := range r.s.reactionCounts {
+		if c.TargetType == targetType && c.TargetID == targetID && c.Type == reactionType {
+			r.s.reactionCounts[i].Count += delta
+			return
+		}
+	}
+	r.s.reactionCounts = append(r.s.reactionCounts, entities.ReactionCount{TargetType: targetType, TargetID: targetID, Type: reactionType, Count: delta})
+}
+
+func (r *reactionRepository) Counts(targetType string, targetID uint) ([]entities.ReactionCount, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	counts := r.s.loadReactionCounts(targetType, targetID)
+	if counts == nil {
+		counts = []entities.ReactionCount{}
+	}
+	return counts, nil
+}
+
+func (r *reactionRepository) UserReactions(targetType string, targetIDs []uint, userID uint) (map[uint]string, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	result := make(map[uint]string)
+	wanted := make(map[uint]bool, len(targetIDs))
+	for _, id := range targetIDs {
+		wanted[id] = true
+	}
+	for _, reaction := range r.s.reactions {
+		if reaction.TargetType == targetType && wanted[reaction.TargetID] && reaction.UserID == userID {
+			result[reaction.TargetID] = reaction.Type
+		}
+	}
+	return result, nil
+}
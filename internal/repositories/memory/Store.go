@@ -0,0 +1,282 @@
+// Package memory implements the repositories in memory, for unit tests that should not need
+// Postgres. The implementations follow the GORM ones: IDs are assigned in sequence, column
+// defaults and timestamps are applied on create, soft-deleted rows are hidden unless the GORM
+// query is unscoped, unique columns are enforced and associations are loaded where the GORM
+// query preloads them. Foreign keys are not enforced.
+package memory
+
+import (
+	"blog-api/internal/entities"
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Store holds the tables shared by the repositories created from it, so that queries joining
+// several tables, such as the feed, see the rows written through the other repositories.
+type Store struct {
+	mu sync.Mutex
+
+	users          []entities.User
+	categories     []entities.Category
+	posts          []entities.Post
+	comments       []entities.Comment
+	follows        []entities.Follow
+	collections    []entities.BookmarkCollection
+	bookmarks      []entities.Bookmark
+	media          []entities.Media
+	variants       []entities.MediaVariant
+	mentions       []entities.Mention
+	notifications  []entities.Notification
+	preferences    []entities.NotificationPreference
+	reactions      []entities.Reaction
+	reactionCounts []entities.ReactionCount
+	views          []entities.PostDailyView
+
+	lastID map[string]uint
+}
+
+func NewStore() *Store {
+	return &Store{lastID: make(map[string]uint)}
+}
+
+func (s *Store) nextID(table string) uint {
+	s.lastID[table]++
+	return s.lastID[table]
+}
+
+var schemas sync.Map
+
+func schemaOf(model interface{}) *schema.Schema {
+	sch, err := schema.Parse(model, &schemas, schema.NamingStrategy{})
+	if err != nil {
+		panic(err)
+	}
+	return sch
+}
+
+// prepareCreate does to a new row what GORM does on insert: zero columns that have a database
+// default get it and the creation and update times are set when zero.
+func prepareCreate(row interface{}, now time.Time) {
+	ctx := context.Background()
+	rv := reflect.ValueOf(row).Elem()
+	for _, field := range schemaOf(row).Fields {
+		_, zero := field.ValueOf(ctx, rv)
+		switch {
+		case !zero:
+		case field.AutoCreateTime != 0 || field.AutoUpdateTime != 0:
+			field.Set(ctx, rv, now)
+		case field.HasDefaultValue && field.DefaultValueInterface != nil:
+			field.Set(ctx, rv, field.DefaultValueInterface)
+		}
+	}
+}
+
+// applyUpdates sets columns by name, like Updates with a map, and refreshes the update time.
+func applyUpdates(row interface{}, updates map[string]interface{}, now time.Time) error {
+	ctx := context.Background()
+	rv := reflect.ValueOf(row).Elem()
+	sch := schemaOf(row)
+	for column, value := range updates {
+		field := sch.LookUpField(column)
+		if field == nil || field.DBName == "" {
+			return fmt.Errorf("column %q of relation %q does not exist", column, sch.Table)
+		}
+		if err := field.Set(ctx, rv, value); err != nil {
+			return err
+		}
+	}
+	for _, field := range sch.Fields {
+		if _, ok := updates[field.DBName]; !ok && field.AutoUpdateTime != 0 {
+			field.Set(ctx, rv, now)
+		}
+	}
+	return nil
+}
+
+// stored returns a copy of a row without its associations, which are not stored but loaded
+// on read.
+func stored[T any](row *T) T {
+	copied := *row
+	rv := reflect.ValueOf(&copied).Elem()
+	sch := schemaOf(row)
+	for _, rel := range sch.Relationships.Relations {
+		// the parsed schema also lists the relations of other models that point back to it
+		if rel.Field.Schema != sch {
+			continue
+		}
+		f := rv.FieldByIndex(rel.Field.StructField.Index)
+		f.Set(reflect.Zero(f.Type()))
+	}
+	return copied
+}
+
+func duplicate(constraint string) error {
+	return fmt.Errorf("%w: %s", gorm.ErrDuplicatedKey, constraint)
+}
+
+// paginate returns the page of rows the GORM repositories return for page and pageSize,
+// with the same defaults.
+func paginate[T any](rows []T, page, pageSize int) []T {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	return window(rows, (page-1)*pageSize, pageSize)
+}
+
+// window applies an offset and a limit; a negative limit means none, as with GORM.
+func window[T any](rows []T, offset, limit int) []T {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset >= len(rows) {
+		return []T{}
+	}
+	rows = rows[offset:]
+	if limit >= 0 && limit < len(rows) {
+		rows = rows[:limit]
+	}
+	return rows
+}
+
+// ilike matches like ILIKE '%substr%'.
+func ilike(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+func alive(deletedAt gorm.DeletedAt) bool {
+	return !deletedAt.Valid
+}
+
+func softDelete(now time.Time) gorm.DeletedAt {
+	return gorm.DeletedAt{Time: now, Valid: true}
+}
+
+func (s *Store) findUser(id uint) (int, bool) {
+	for i := range s.users {
+		if uint(s.users[i].ID) == id && alive(s.users[i].DeletedAt) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func (s *Store) findCategory(id uint) (int, bool) {
+	for i := range s.categories {
+		if s.categories[i].ID == id && alive(s.categories[i].DeletedAt) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func (s *Store) findPost(id uint) (int, bool) {
+	for i := range s.posts {
+		if s.posts[i].ID == id && alive(s.posts[i].DeletedAt) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func (s *Store) findComment(id uint) (int, bool) {
+	for i := range s.comments {
+		if s.comments[i].ID == id && alive(s.comments[i].DeletedAt) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func (s *Store) findMedia(id uint) (int, bool) {
+	for i := range s.media {
+		if s.media[i].ID == id && alive(s.media[i].DeletedAt) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// The load functions fill in the associations the GORM queries preload. Soft-deleted
+// associations are left out, as Preload applies the default scope.
+
+func (s *Store) loadUser(id uint) entities.User {
+	if i, ok := s.findUser(id); ok {
+		return s.users[i]
+	}
+	return entities.User{}
+}
+
+func (s *Store) loadMentions(sourceType string, sourceID uint) []entities.Mention {
+	var mentions []entities.Mention
+	for _, m := range s.mentions {
+		if m.SourceType == sourceType && m.SourceID == sourceID && alive(m.DeletedAt) {
+			m.MentionedUser = s.loadUser(m.MentionedUserID)
+			mentions = append(mentions, m)
+		}
+	}
+	return mentions
+}
+
+func (s *Store) loadReactionCounts(targetType string, targetID uint) []entities.ReactionCount {
+	var counts []entities.ReactionCount
+	for _, c := range s.reactionCounts {
+		if c.TargetType == targetType && c.TargetID == targetID {
+			counts = append(counts, c)
+		}
+	}
+	return counts
+}
+
+// loadVariants returns the variants of a media item ordered by format and width.
+func (s *Store) loadVariants(mediaID uint) []entities.MediaVariant {
+	var variants []entities.MediaVariant
+	for _, v := range s.variants {
+		if v.MediaID == mediaID {
+			variants = append(variants, v)
+		}
+	}
+	sort.SliceStable(variants, func(i, j int) bool {
+		if variants[i].Format != variants[j].Format {
+			return variants[i].Format < variants[j].Format
+		}
+		return variants[i].Width < variants[j].Width
+	})
+	return variants
+}
+
+// loadPost fills in the author, category, mentions, reaction counts and thumbnail of a post,
+// and its comments when withComments is set.
+func (s *Store) loadPost(p entities.Post, withComments bool) entities.Post {
+	p.Author = s.loadUser(p.AuthorID)
+	if i, ok := s.findCategory(p.CategoryID); ok {
+		p.Category = s.categories[i]
+	}
+	p.Mentions = s.loadMentions(entities.MentionSourcePost, p.ID)
+	p.ReactionCounts = s.loadReactionCounts(entities.ReactionTargetPost, p.ID)
+	if p.ThumbnailMediaID != nil {
+		if i, ok := s.findMedia(*p.ThumbnailMediaID); ok {
+			media := s.media[i]
+			media.Variants = s.loadVariants(media.ID)
+			p.ThumbnailMedia = &media
+		}
+	}
+	if withComments {
+		for _, c := range s.comments {
+			if c.PostID == p.ID && alive(c.DeletedAt) {
+				p.Comments = append(p.Comments, c)
+			}
+		}
+	}
+	return p
+}
@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+	"errors"
+	"sort"
+	"time"
+)
+
+type userRepository struct {
+	s *Store
+}
+
+func NewUserRepository(s *Store) repositories.UserRepository {
+	return &userRepository{s: s}
+}
+
+func (r *userRepository) FindEmail(email string) (*entities.User, error) {
+	return r.findBy(func(u *entities.User) bool { return u.Email == email })
+}
+
+func (r *userRepository) FindByUsername(username string) (*entities.User, error) {
+	return r.findBy(func(u *entities.User) bool { return u.Username == username })
+}
+
+func (r *userRepository) findBy(match func(u *entities.User) bool) (*entities.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for i := range r.s.users {
+		if u := r.s.users[i]; alive(u.DeletedAt) && match(&u) {
+			return &u, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *userRepository) Create(user *entities.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if err := r.checkUnique(0, user.Username, user.Email); err != nil {
+		return err
+	}
+	prepareCreate(user, time.Now())
+	user.ID = int(r.s.nextID("users"))
+	r.s.users = append(r.s.users, stored(user))
+	return nil
+}
+
+// checkUnique enforces the unique username and email, which soft-deleted users keep.
+func (r *userRepository) checkUnique(id uint, username, email string) error {
+	for _, u := range r.s.users {
+		if uint(u.ID) == id {
+			continue
+		}
+		if u.Username == username {
+			return duplicate("users_username_key")
+		}
+		if u.Email == email {
+			return duplicate("users_email_key")
+		}
+	}
+	return nil
+}
+
+func (r *userRepository) FindByID(id uint) (*entities.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i, ok := r.s.findUser(id)
+	if !ok {
+		return nil, errors.New("user not found")
+	}
+	user := r.s.users[i]
+	for _, p := range r.s.posts {
+		if p.AuthorID == id && alive(p.DeletedAt) {
+			user.Posts = append(user.Posts, p)
+		}
+	}
+	for _, c := range r.s.comments {
+		if c.UserID == id && alive(c.DeletedAt) {
+			user.Comments = append(user.Comments, c)
+		}
+	}
+	return &user, nil
+}
+
+func (r *userRepository) ListAll(page, pageSize int) ([]entities.User, int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var users []entities.User
+	for _, u := range r.s.users {
+		if alive(u.DeletedAt) {
+			users = append(users, u)
+		}
+	}
+	sort.SliceStable(users, func(i, j int) bool { return users[i].CreatedAt.After(users[j].CreatedAt) })
+	return paginate(users, page, pageSize), int64(len(users)), nil
+}
+
+func (r *userRepository) Delete(id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i, ok := r.s.findUser(id)
+	if !ok {
+		return errors.New("user not found")
+	}
+	r.s.users[i].DeletedAt = softDelete(time.Now())
+	return nil
+}
+
+func (r *userRepository) Update(user *entities.User) error {
+	return r.update(uint(user.ID), map[string]interface{}{
+		"username": user.Username,
+		"email":    user.Email,
+		"password": user.Password,
+		"role":     user.Role,
+	})
+}
+
+func (r *userRepository) UpdateCanPost(userID uint, canPost bool) error {
+	return r.update(userID, map[string]interface{}{"can_post": canPost})
+}
+
+func (r *userRepository) UpdateProfile(userID uint, updates map[string]interface{}) error {
+	return r.update(userID, updates)
+}
+
+func (r *userRepository) UpdateUsername(userID uint, username string) error {
+	return r.update(userID, map[string]interface{}{"username": username})
+}
+
+func (r *userRepository) UpdateEmail(userID uint, email string) error {
+	return r.update(userID, map[string]interface{}{"email": email})
+}
+
+// update changes the columns of a user; updating a missing user is not an error.
+func (r *userRepository) update(id uint, updates map[string]interface{}) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	i, ok := r.s.findUser(id)
+	if !ok {
+		return nil
+	}
+	user := r.s.users[i]
+	if err := applyUpdates(&user, updates, time.Now()); err != nil {
+		return err
+	}
+	if err := r.checkUnique(id, user.Username, user.Email); err != nil {
+		return err
+	}
+	r.s.users[i] = user
+	return nil
+}
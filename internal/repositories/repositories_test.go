@@ -0,0 +1,122 @@
+package repositories_test
+
+import (
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+	"blog-api/internal/repositories/memory"
+	"blog-api/internal/testdb"
+	"os"
+	"testing"
+	"time"
+)
+
+// The tests in this package form a conformance suite: each one runs against the in-memory
+// repositories and against the GORM ones on a test database, so both stay interchangeable.
+
+func TestMain(m *testing.M) { os.Exit(testdb.Run(m)) }
+
+type repos struct {
+	users         repositories.UserRepository
+	categories    repositories.CategoryRepository
+	posts         repositories.PostRepository
+	comments      repositories.CommentRepository
+	follows       repositories.FollowRepository
+	bookmarks     repositories.BookmarkRepository
+	media         repositories.MediaRepository
+	mentions      repositories.MentionRepository
+	notifications repositories.NotificationRepository
+	reactions     repositories.ReactionRepository
+	views         repositories.PostViewRepository
+}
+
+func run(t *testing.T, test func(t *testing.T, r *repos)) {
+	t.Run("memory", func(t *testing.T) {
+		s := memory.NewStore()
+		test(t, &repos{
+			users:         memory.NewUserRepository(s),
+			categories:    memory.NewCategoryRepository(s),
+			posts:         memory.NewPostRepository(s),
+			comments:      memory.NewCommentRepository(s),
+			follows:       memory.NewFollowRepository(s),
+			bookmarks:     memory.NewBookmarkRepository(s),
+			media:         memory.NewMediaRepository(s),
+			mentions:      memory.NewMentionRepository(s),
+			notifications: memory.NewNotificationRepository(s),
+			reactions:     memory.NewReactionRepository(s),
+			views:         memory.NewPostViewRepository(s),
+		})
+	})
+	t.Run("gorm", func(t *testing.T) {
+		db := testdb.Open(t, "repositories_test")
+		test(t, &repos{
+			users:         repositories.NewUserRepository(db),
+			categories:    repositories.NewCategoryRepository(db),
+			posts:         repositories.NewPostRepository(db),
+			comments:      repositories.NewCommentRepository(db),
+			follows:       repositories.NewFollowRepository(db),
+			bookmarks:     repositories.NewBookmarkRepository(db),
+			media:         repositories.NewMediaRepository(db),
+			mentions:      repositories.NewMentionRepository(db),
+			notifications: repositories.NewNotificationRepository(db),
+			reactions:     repositories.NewReactionRepository(db),
+			views:         repositories.NewPostViewRepository(db),
+		})
+	})
+}
+
+// base is a fixed point in time for rows whose order matters, whole seconds apart so that
+// the database precision does not matter.
+var base = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func at(hours int) time.Time {
+	return base.Add(time.Duration(hours) * time.Hour)
+}
+
+func (r *repos) user(t *testing.T, username string) *entities.User {
+	t.Helper()
+	user := &entities.User{Username: username, Email: username + "@example.com", Password: "hash"}
+	if err := r.users.Create(user); err != nil {
+		t.Fatalf("create user %s: %v", username, err)
+	}
+	return user
+}
+
+func (r *repos) category(t *testing.T, name, slug string) *entities.Category {
+	t.Helper()
+	category := &entities.Category{Name: name, Slug: slug}
+	if err := r.categories.Create(category); err != nil {
+		t.Fatalf("create category %s: %v", slug, err)
+	}
+	return category
+}
+
+// post creates a post created at the given time; a published post is published then.
+func (r *repos) post(t *testing.T, author *entities.User, category *entities.Category, slug, status string, created time.Time) *entities.Post {
+	t.Helper()
+	post := &entities.Post{
+		Title:      "Title of " + slug,
+		Slug:       slug,
+		Content:    "Content of " + slug,
+		Thumbnail:  "https://example.com/" + slug + ".png",
+		CategoryID: category.ID,
+		AuthorID:   uint(author.ID),
+		Status:     status,
+		CreatedAt:  created,
+		UpdatedAt:  created,
+	}
+	if status == "published" {
+		post.PublishedAt = &created
+	}
+	if err := r.posts.Create(post); err != nil {
+		t.Fatalf("create post %s: %v", slug, err)
+	}
+	return post
+}
+
+func slugs(posts []entities.Post) []string {
+	list := make([]string, 0, len(posts))
+	for _, p := range posts {
+		list = append(list, p.Slug)
+	}
+	return list
+}
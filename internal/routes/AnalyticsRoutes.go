@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"blog-api/internal/config"
+	"blog-api/internal/controllers"
+	"blog-api/internal/repositories"
+	"blog-api/internal/services"
+	"blog-api/pkg/middlewares"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func SetupAnalyticsRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) {
+	secret := []byte(cfg.JWTSecret)
+	service := services.NewAnalyticsService(repositories.NewPostViewRepository(db), repositories.NewPostRepository(db), repositories.NewCommentRepository(db), repositories.NewReactionRepository(db))
+	controller := controllers.NewAnalyticsController(service)
+
+	r.GET("/posts/:post_id/stats", middlewares.AuthMiddleware(secret), middlewares.OwnerOrAdminMiddleware(db), controller.GetPostStats)
+	r.GET("/admin/posts/top", middlewares.AuthMiddleware(secret), middlewares.AdminMiddleware(), controller.GetTopPosts)
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"blog-api/internal/config"
+	"blog-api/internal/controllers"
+	"blog-api/internal/repositories"
+	"blog-api/internal/services"
+	"blog-api/pkg/middlewares"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func SetupBookmarkRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) {
+	secret := []byte(cfg.JWTSecret)
+	service := services.NewBookmarkService(repositories.NewBookmarkRepository(db), repositories.NewPostRepository(db))
+	controller := controllers.NewBookmarkController(service)
+
+	r.POST("/posts/:post_id/bookmark", middlewares.AuthMiddleware(secret), controller.AddBookmark)
+	r.DELETE("/posts/:id/bookmark", middlewares.AuthMiddleware(secret), controller.RemoveBookmark)
+
+	authGroup := r.Group("/users/me").Use(middlewares.AuthMiddleware(secret))
+	{
+		authGroup.GET("/bookmarks", controller.ListBookmarks)
+		authGroup.GET("/bookmark-collections", controller.ListCollections)
+	}
+}
@@ -1,6 +1,7 @@
 package routes
 
 import (
+	"blog-api/internal/config"
 	"blog-api/internal/controllers"
 	"blog-api/internal/repositories"
 	"blog-api/internal/services"
@@ -10,12 +11,13 @@ import (
 	"gorm.io/gorm"
 )
 
-func SetupCategoryRoutes(r *gin.Engine, db *gorm.DB) {
+func SetupCategoryRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) {
+	secret := []byte(cfg.JWTSecret)
 	repo := repositories.NewCategoryRepository(db)
 	service := services.NewCategoryService(repo)
 	controller := controllers.NewCategoryController(service)
 
-	adminGroup := r.Group("admin/categories").Use(middlewares.AuthMiddleware(), middlewares.AdminMiddleware())
+	adminGroup := r.Group("admin/categories").Use(middlewares.AuthMiddleware(secret), middlewares.AdminMiddleware())
 	{
 		adminGroup.GET("", controller.AdminListCategories)
 		adminGroup.POST("", controller.CreateCategory)
@@ -1,21 +1,30 @@
 package routes
 
 import (
+	"blog-api/internal/config"
 	"blog-api/internal/controllers"
 	"blog-api/internal/repositories"
 	"blog-api/internal/services"
 	"blog-api/pkg/middlewares"
+	"blog-api/pkg/pubsub"
+	"context"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-func SetupCommentRoutes(r *gin.Engine, db *gorm.DB) {
+func SetupCommentRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config, bus pubsub.Bus, shutdown context.Context) {
+	secret := []byte(cfg.JWTSecret)
 	repo := repositories.NewCommentRepository(db)
-	service := services.NewCommentService(repo)
+	postRepo := repositories.NewPostRepository(db)
+	userRepo := repositories.NewUserRepository(db)
+	notificationService := services.NewNotificationService(repositories.NewNotificationRepository(db))
+	mentionService := services.NewMentionService(repositories.NewMentionRepository(db), userRepo)
+	service := services.NewCommentService(repo, postRepo, mentionService, notificationService, repositories.NewReactionRepository(db), bus)
 	controller := controllers.NewCommentController(service)
 
-	r.POST("/posts/:post_id/comments", middlewares.AuthMiddleware(), controller.CreateComment)
-    r.PUT("/comments/:comment_id", middlewares.AuthMiddleware(), controller.UpdateComment)
-    r.DELETE("/comments/:comment_id", middlewares.AuthMiddleware(), middlewares.CommentOwnerOrPostOwnerMiddleware(db), controller.DeleteComment)
-    r.GET("/posts/:post_id/comments", controller.GetCommentsByPost)
+	r.POST("/posts/:post_id/comments", middlewares.AuthMiddleware(secret), controller.CreateComment)
+    r.PUT("/comments/:comment_id", middlewares.AuthMiddleware(secret), controller.UpdateComment)
+    r.DELETE("/comments/:comment_id", middlewares.AuthMiddleware(secret), middlewares.CommentOwnerOrPostOwnerMiddleware(db), controller.DeleteComment)
+    r.GET("/posts/:post_id/comments", middlewares.OptionalAuthMiddleware(secret), controller.GetCommentsByPost)
+    r.GET("/posts/:post_id/comments/stream", middlewares.CancelOnShutdown(shutdown), controller.StreamComments)
 }
\ No newline at end of file
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"blog-api/internal/config"
+	"blog-api/internal/controllers"
+	"blog-api/internal/repositories"
+	"blog-api/internal/services"
+	"blog-api/pkg/feed"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func SetupFeedRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) {
+	service := services.NewFeedService(repositories.NewPostRepository(db), repositories.NewCategoryRepository(db), repositories.NewUserRepository(db), cfg.Site.URL, cfg.Site.Title)
+	controller := controllers.NewFeedController(service)
+
+	for format := range feed.Formats {
+		r.GET("/feed."+format, controller.SiteFeed)
+		r.GET("/categories/:slug/feed."+format, controller.CategoryFeed)
+		r.GET("/users/:username/feed."+format, controller.AuthorFeed)
+	}
+}
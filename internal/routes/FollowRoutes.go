@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"blog-api/internal/config"
+	"blog-api/internal/controllers"
+	"blog-api/internal/repositories"
+	"blog-api/internal/services"
+	"blog-api/pkg/middlewares"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func SetupFollowRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) {
+	secret := []byte(cfg.JWTSecret)
+	service := services.NewFollowService(repositories.NewFollowRepository(db), repositories.NewUserRepository(db), repositories.NewCategoryRepository(db))
+	controller := controllers.NewFollowController(service)
+
+	authGroup := r.Group("").Use(middlewares.AuthMiddleware(secret))
+	{
+		authGroup.POST("/users/:username/follow", controller.FollowUser)
+		authGroup.DELETE("/users/:username/follow", controller.UnfollowUser)
+		authGroup.POST("/categories/:slug/follow", controller.FollowCategory)
+		authGroup.DELETE("/categories/:slug/follow", controller.UnfollowCategory)
+	}
+}
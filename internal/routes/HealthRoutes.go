@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"blog-api/internal/controllers"
+	"blog-api/pkg/health"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupHealthRoutes(r *gin.Engine, checker *health.Checker) {
+	controller := controllers.NewHealthController(checker)
+
+	r.GET("/healthz", controller.Healthz)
+	r.GET("/readyz", controller.Readyz)
+	r.GET("/version", controller.Version)
+}
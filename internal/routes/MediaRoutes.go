@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"blog-api/internal/config"
+	"blog-api/internal/controllers"
+	"blog-api/internal/repositories"
+	"blog-api/internal/services"
+	"blog-api/pkg/middlewares"
+	"blog-api/pkg/storage"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func SetupMediaRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config, store storage.Storage, processor *services.MediaProcessor) {
+	secret := []byte(cfg.JWTSecret)
+	service := services.NewMediaService(repositories.NewMediaRepository(db), repositories.NewUserRepository(db), store, cfg.Storage.UploadMaxBytes, processor)
+	controller := controllers.NewMediaController(service)
+
+	// Files on local disk are served by the API itself; other backends serve their own URLs.
+	if local, ok := store.(*storage.Local); ok {
+		r.Static("/uploads", local.Dir())
+	}
+
+	r.POST("/media", middlewares.AuthMiddleware(secret), controller.UploadMedia)
+	r.GET("/media/:id", middlewares.AuthMiddleware(secret), controller.GetMedia)
+
+	authGroup := r.Group("/users/me").Use(middlewares.AuthMiddleware(secret))
+	{
+		authGroup.GET("/media", controller.ListMedia)
+		authGroup.POST("/avatar", controller.UploadAvatar)
+	}
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"blog-api/internal/config"
+	"blog-api/pkg/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// SetupMetricsRoutes serves the Prometheus metrics, behind basic authentication when
+// credentials are configured.
+func SetupMetricsRoutes(r *gin.Engine, cfg *config.Config, m *metrics.Metrics) {
+	var handlers []gin.HandlerFunc
+	if cfg.Metrics.Username != "" {
+		handlers = append(handlers, gin.BasicAuth(gin.Accounts{cfg.Metrics.Username: string(cfg.Metrics.Password)}))
+	}
+	r.GET("/metrics", append(handlers, gin.WrapH(m.Handler()))...)
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"blog-api/internal/config"
+	"blog-api/internal/controllers"
+	"blog-api/internal/repositories"
+	"blog-api/internal/services"
+	"blog-api/pkg/middlewares"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func SetupNotificationRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) {
+	secret := []byte(cfg.JWTSecret)
+	service := services.NewNotificationService(repositories.NewNotificationRepository(db))
+	controller := controllers.NewNotificationController(service)
+
+	authGroup := r.Group("/users/me").Use(middlewares.AuthMiddleware(secret))
+	{
+		authGroup.GET("/notifications", controller.ListNotifications)
+		authGroup.PUT("/notifications/read-all", controller.MarkAllRead)
+		authGroup.PUT("/notifications/:id/read", controller.MarkRead)
+		authGroup.GET("/notification-preferences", controller.GetPreferences)
+		authGroup.PUT("/notification-preferences", controller.UpdatePreferences)
+	}
+}
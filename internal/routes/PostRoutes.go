@@ -1,38 +1,49 @@
 package routes
 
 import (
+	"blog-api/internal/config"
 	"blog-api/internal/controllers"
 	"blog-api/internal/repositories"
 	"blog-api/internal/services"
 	"blog-api/pkg/middlewares"
+	"blog-api/pkg/pubsub"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-func SetupPostRoutes(r *gin.Engine, db *gorm.DB) {
+func SetupPostRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config, bus pubsub.Bus, viewCounter *services.ViewCounter) {
+	secret := []byte(cfg.JWTSecret)
     repo := repositories.NewPostRepository(db)
 	categoryRepo := repositories.NewCategoryRepository(db)
 	userRepo := repositories.NewUserRepository(db)
-	service := services.NewPostService(repo, categoryRepo, userRepo)
-    controller := controllers.NewPostController(service)
+	notificationService := services.NewNotificationService(repositories.NewNotificationRepository(db))
+	mentionService := services.NewMentionService(repositories.NewMentionRepository(db), userRepo)
+	service := services.NewPostService(repo, categoryRepo, userRepo, mentionService, notificationService, repositories.NewReactionRepository(db), repositories.NewBookmarkRepository(db), repositories.NewMediaRepository(db), bus)
+    controller := controllers.NewPostController(service, viewCounter)
+	metaController := controllers.NewPostMetaController(services.NewPostMetaService(repo, cfg.Site.URL, cfg.Site.Title))
 
-    userGroup := r.Group("/posts").Use(middlewares.AuthMiddleware())
+    userGroup := r.Group("/posts").Use(middlewares.AuthMiddleware(secret))
     {
         userGroup.POST("", controller.CreatePost)
         userGroup.PUT("/:id", middlewares.OwnerOrAdminMiddleware(db), controller.UpdatePost)
         userGroup.PATCH("/:id", middlewares.OwnerOrAdminMiddleware(db), controller.UpdatePost)
+        userGroup.PUT("/:id/comments/lock", middlewares.OwnerOrAdminMiddleware(db), controller.LockComments)
         userGroup.DELETE("/:id", middlewares.OwnerOrAdminMiddleware(db), controller.DeletePost) 
     }
 
-    adminGroup := r.Group("/admin/posts").Use(middlewares.AuthMiddleware(), middlewares.AdminMiddleware())
+    r.GET("/users/me/feed", middlewares.AuthMiddleware(secret), controller.GetFeed)
+    r.GET("/users/:username/posts", middlewares.OptionalAuthMiddleware(secret), controller.GetPostsByAuthor)
+
+    adminGroup := r.Group("/admin/posts").Use(middlewares.AuthMiddleware(secret), middlewares.AdminMiddleware())
     {
 		adminGroup.GET("", controller.GetAllPosts)
         adminGroup.DELETE("/:id", controller.DeletePost) 
     }
 
-    publicGroup := r.Group("/posts")
+    publicGroup := r.Group("/posts").Use(middlewares.OptionalAuthMiddleware(secret))
     {
         publicGroup.GET("", controller.GetAllPosts)
         publicGroup.GET("/:post_id", controller.GetPostDetail)
+        publicGroup.GET("/:post_id/meta", metaController.GetPostMeta)
     }
 }
\ No newline at end of file
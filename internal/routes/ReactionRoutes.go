@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"blog-api/internal/config"
+	"blog-api/internal/controllers"
+	"blog-api/internal/repositories"
+	"blog-api/internal/services"
+	"blog-api/pkg/middlewares"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func SetupReactionRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config) {
+	secret := []byte(cfg.JWTSecret)
+	repo := repositories.NewReactionRepository(db)
+	service := services.NewReactionService(repo, repositories.NewPostRepository(db), repositories.NewCommentRepository(db), cfg.ReactionTypes)
+	controller := controllers.NewReactionController(service)
+
+	r.POST("/posts/:post_id/reactions", middlewares.AuthMiddleware(secret), controller.ReactToPost)
+	r.POST("/comments/:comment_id/reactions", middlewares.AuthMiddleware(secret), controller.ReactToComment)
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"blog-api/internal/controllers"
+	"blog-api/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func SetupSitemapRoutes(r *gin.Engine, service *services.SitemapService) {
+	controller := controllers.NewSitemapController(service)
+
+	r.GET("/sitemap.xml", controller.GetIndex)
+	r.GET("/sitemaps/:file", controller.GetSitemap)
+}
@@ -1,9 +0,0 @@
-package routes
-
-import "github.com/gin-gonic/gin"
-
-func SetupRoutes(r *gin.Engine){
-	r.GET("/ping", func(c *gin.Context){
-		c.JSON(200, gin.H{"message": "pong"})
-	})
-}
\ No newline at end of file
@@ -1,36 +1,44 @@
 package routes
 
 import (
+	"blog-api/internal/config"
 	"blog-api/internal/controllers"
 	"blog-api/internal/repositories"
 	"blog-api/internal/services"
+	"blog-api/pkg/metrics"
 	"blog-api/pkg/middlewares"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-func SetupUserRoutes(r *gin.Engine, db *gorm.DB) {
+func SetupUserRoutes(r *gin.Engine, db *gorm.DB, cfg *config.Config, m *metrics.Metrics) {
+	secret := []byte(cfg.JWTSecret)
 	userRepo := repositories.NewUserRepository(db)
-	authService := services.NewAuthService(userRepo)
-	userService := services.NewUserService(userRepo)
+	authService := services.NewAuthService(userRepo, secret, m)
+	notificationService := services.NewNotificationService(repositories.NewNotificationRepository(db))
+	userService := services.NewUserService(userRepo, notificationService, repositories.NewFollowRepository(db), repositories.NewPostRepository(db), cfg.Site.URL, secret, config.NewMailer(cfg.SMTP))
 	UserController := controllers.NewUserController(authService, userService)
 
 	public := r.Group("/users")
 	{
 		public.POST("/register", UserController.Register)
 		public.POST("/login", UserController.Login) 
+		public.GET("/confirm-email", UserController.ConfirmEmailChange)
+		public.GET("/:username", UserController.GetPublicProfile)
 		// public.POST("/forgot-password", UserController.ForgotPassword)
         // public.POST("/reset-password", UserController.ResetPassword)
 	}
 
-	authGroup := r.Group("/users").Use(middlewares.AuthMiddleware())
+	authGroup := r.Group("/users").Use(middlewares.AuthMiddleware(secret))
 	{
 		authGroup.GET("/me", UserController.GetMe)
+		authGroup.PATCH("/me", UserController.UpdateMe)
+		authGroup.PUT("/me/profile", UserController.UpdateProfile)
 		authGroup.PUT("/change-password", UserController.ChangePassword)
 		authGroup.DELETE("/me", UserController.DeleteMe)
 	}
 
-	adminGroup := r.Group("/admin/users").Use(middlewares.AuthMiddleware(), middlewares.AdminMiddleware())
+	adminGroup := r.Group("/admin/users").Use(middlewares.AuthMiddleware(secret), middlewares.AdminMiddleware())
 	{
 		adminGroup.GET("", UserController.ListUsers)
 		adminGroup.GET("/:id", UserController.GetUserDetail)
@@ -0,0 +1,271 @@
+// Package seed fills a database with realistic development data: users, categories, posts
+// of varied lengths and statuses, and threaded comments. Generation is deterministic, each
+// row being derived from a fixed seed and its index, so every run produces the same data
+// and re-running only creates what is missing: users, categories and posts are matched by
+// username or slug, and comments are only added to posts created by the same run. A larger
+// preset extends the data of a smaller one.
+package seed
+
+import (
+	"blog-api/internal/dto"
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+	"blog-api/pkg/helper"
+	"blog-api/pkg/utils"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Password is the password of every seeded account.
+const Password = "Seed-pass1"
+
+// AdminUsername is the seeded admin account; the other accounts are clients.
+const AdminUsername = "seed_admin"
+
+// randomSeed is the fixed seed all generated data derives from.
+const randomSeed = 20240101
+
+// epoch anchors generated timestamps, so they do not depend on when the seeder runs.
+var epoch = time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+
+// Preset is how much data to generate.
+type Preset struct {
+	Users              int
+	Categories         int
+	Posts              int
+	MaxCommentsPerPost int
+}
+
+var Presets = map[string]Preset{
+	"small":  {Users: 10, Categories: 5, Posts: 50, MaxCommentsPerPost: 5},
+	"medium": {Users: 50, Categories: 10, Posts: 500, MaxCommentsPerPost: 10},
+	"large":  {Users: 200, Categories: 20, Posts: 5000, MaxCommentsPerPost: 20},
+}
+
+// Result counts the rows created by a run.
+type Result struct {
+	Users, Categories, Posts, Comments int
+}
+
+type Seeder struct {
+	userRepo     repositories.UserRepository
+	categoryRepo repositories.CategoryRepository
+	postRepo     repositories.PostRepository
+	commentRepo  repositories.CommentRepository
+	out          io.Writer
+}
+
+func NewSeeder(db *gorm.DB, out io.Writer) *Seeder {
+	return &Seeder{
+		userRepo:     repositories.NewUserRepository(db),
+		categoryRepo: repositories.NewCategoryRepository(db),
+		postRepo:     repositories.NewPostRepository(db),
+		commentRepo:  repositories.NewCommentRepository(db),
+		out:          out,
+	}
+}
+
+// Run generates the data of the preset that does not exist yet.
+func (s *Seeder) Run(preset Preset) (*Result, error) {
+	if preset.Categories > len(categoryNames) {
+		return nil, fmt.Errorf("at most %d categories can be seeded", len(categoryNames))
+	}
+	result := &Result{}
+
+	users, err := s.seedUsers(preset.Users, result)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.seedCategories(preset.Categories, result)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < preset.Posts; i++ {
+		if err := s.seedPost(i, users, categories, preset.MaxCommentsPerPost, result); err != nil {
+			return nil, err
+		}
+		if (i+1)%500 == 0 {
+			fmt.Fprintf(s.out, "  %d/%d posts\n", i+1, preset.Posts)
+		}
+	}
+	return result, nil
+}
+
+// rng returns the random source of one generated row, so a row comes out the same no
+// matter how many rows were generated before it.
+func rng(kind int64, index int) *rand.Rand {
+	return rand.New(rand.NewSource(randomSeed + kind*1_000_000 + int64(index)))
+}
+
+const (
+	kindUser int64 = iota + 1
+	kindPost
+)
+
+func (s *Seeder) seedUsers(n int, result *Result) ([]*entities.User, error) {
+	hashed, err := helper.HashPassword(Password)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]*entities.User, 0, n)
+	for i := 0; i < n; i++ {
+		r := rng(kindUser, i)
+		first := firstNames[r.Intn(len(firstNames))]
+		last := lastNames[r.Intn(len(lastNames))]
+		username := fmt.Sprintf("%s_%s%d", strings.ToLower(first), strings.ToLower(last[:1]), i)
+		role := "client"
+		if i == 0 {
+			username, role = AdminUsername, "admin"
+		}
+
+		existing, err := s.userRepo.FindByUsername(username)
+		if err != nil {
+			return nil, err
+		}
+		if existing != nil {
+			users = append(users, existing)
+			continue
+		}
+
+		req := dto.UserRegisterRequest{Username: username, Email: username + "@example.com", Password: Password}
+		if errs := utils.ValidateStruct(&req); errs != nil {
+			return nil, fmt.Errorf("user %s: %v", username, errs)
+		}
+		user := &entities.User{
+			Username:    req.Username,
+			Email:       req.Email,
+			Password:    hashed,
+			Role:        role,
+			CreatedAt:   epoch.Add(time.Duration(i) * time.Hour),
+			DisplayName: first + " " + last,
+			Bio:         sentence(r, 8, 20),
+			CanPost:     true,
+		}
+		if err := s.userRepo.Create(user); err != nil {
+			return nil, fmt.Errorf("user %s: %w", username, err)
+		}
+		users = append(users, user)
+		result.Users++
+	}
+	return users, nil
+}
+
+func (s *Seeder) seedCategories(n int, result *Result) ([]*entities.Category, error) {
+	categories := make([]*entities.Category, 0, n)
+	for _, name := range categoryNames[:n] {
+		req := dto.CreateCategoryRequest{Name: name, Slug: slugify(name)}
+		existing, err := s.categoryRepo.FindBySlug(req.Slug)
+		if err == nil {
+			categories = append(categories, existing)
+			continue
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		if errs := utils.ValidateStruct(&req); errs != nil {
+			return nil, fmt.Errorf("category %s: %v", req.Slug, errs)
+		}
+		category := &entities.Category{Name: req.Name, Slug: req.Slug}
+		if err := s.categoryRepo.Create(category); err != nil {
+			return nil, fmt.Errorf("category %s: %w", req.Slug, err)
+		}
+		categories = append(categories, category)
+		result.Categories++
+	}
+	return categories, nil
+}
+
+func (s *Seeder) seedPost(i int, users []*entities.User, categories []*entities.Category, maxComments int, result *Result) error {
+	r := rng(kindPost, i)
+	// the title is drawn first so the slug, the row's identity, is stable across presets
+	title := headline(r)
+	slug := fmt.Sprintf("%s-%d", slugify(title), i)
+	exists, err := s.postRepo.IsSlugExists(slug)
+	if err != nil || exists {
+		return err
+	}
+	if len(users) == 0 || len(categories) == 0 {
+		return errors.New("posts need at least one user and one category")
+	}
+
+	author := users[r.Intn(len(users))]
+	category := categories[r.Intn(len(categories))]
+	status := "published"
+	if r.Intn(5) == 0 {
+		status = "draft"
+	}
+	req := dto.CreatePostRequest{
+		Title:      title,
+		Slug:       slug,
+		Content:    article(r),
+		Thumbnail:  fmt.Sprintf("https://picsum.photos/seed/%s/1200/630", slug),
+		CategoryID: category.ID,
+		Status:     status,
+	}
+	if errs := utils.ValidateStruct(&req); errs != nil {
+		return fmt.Errorf("post %s: %v", slug, errs)
+	}
+
+	created := epoch.Add(time.Duration(i)*3*time.Hour + time.Duration(r.Intn(180))*time.Minute)
+	post := &entities.Post{
+		Title:      req.Title,
+		Slug:       req.Slug,
+		Content:    req.Content,
+		Thumbnail:  req.Thumbnail,
+		CategoryID: req.CategoryID,
+		AuthorID:   uint(author.ID),
+		Status:     req.Status,
+		CreatedAt:  created,
+		UpdatedAt:  created,
+	}
+	if status == "published" {
+		published := created.Add(time.Duration(r.Intn(48)) * time.Hour)
+		post.PublishedAt = &published
+		post.UpdatedAt = published
+	}
+	if err := s.postRepo.Create(post); err != nil {
+		return fmt.Errorf("post %s: %w", slug, err)
+	}
+	result.Posts++
+
+	if status != "published" || maxComments == 0 {
+		return nil
+	}
+	return s.seedComments(r, post, users, r.Intn(maxComments+1), result)
+}
+
+// seedComments adds n comments to a post; about a third reply to an earlier comment.
+func (s *Seeder) seedComments(r *rand.Rand, post *entities.Post, users []*entities.User, n int, result *Result) error {
+	ids := make([]uint, 0, n)
+	at := *post.PublishedAt
+	for j := 0; j < n; j++ {
+		at = at.Add(time.Duration(1+r.Intn(600)) * time.Minute)
+		req := dto.CreateCommentRequest{PostID: post.ID, Content: sentence(r, 5, 40)}
+		if errs := utils.ValidateStruct(&req); errs != nil {
+			return fmt.Errorf("comment on %s: %v", post.Slug, errs)
+		}
+		comment := &entities.Comment{
+			PostID:    post.ID,
+			UserID:    uint(users[r.Intn(len(users))].ID),
+			Content:   req.Content,
+			CreatedAt: at,
+		}
+		if len(ids) > 0 && r.Intn(3) == 0 {
+			parent := ids[r.Intn(len(ids))]
+			comment.ParentID = &parent
+		}
+		if err := s.commentRepo.Create(comment); err != nil {
+			return fmt.Errorf("comment on %s: %w", post.Slug, err)
+		}
+		ids = append(ids, comment.ID)
+		result.Comments++
+	}
+	return nil
+}
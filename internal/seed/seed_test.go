@@ -0,0 +1,168 @@
+package seed
+
+import (
+	"blog-api/internal/repositories"
+	"blog-api/internal/repositories/memory"
+	"blog-api/pkg/utils"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// the seeder validates its users like the API does
+	if err := utils.RegisterValidators(); err != nil {
+		panic(err)
+	}
+	os.Exit(m.Run())
+}
+
+var testPreset = Preset{Users: 6, Categories: 3, Posts: 30, MaxCommentsPerPost: 4}
+
+func newTestSeeder() (*Seeder, *memory.Store) {
+	s := memory.NewStore()
+	return &Seeder{
+		userRepo:     memory.NewUserRepository(s),
+		categoryRepo: memory.NewCategoryRepository(s),
+		postRepo:     memory.NewPostRepository(s),
+		commentRepo:  memory.NewCommentRepository(s),
+		out:          io.Discard,
+	}, s
+}
+
+// snapshot describes the seeded rows by their content, without IDs.
+func snapshot(t *testing.T, s *Seeder) []string {
+	t.Helper()
+	users, _, err := s.userRepo.ListAll(1, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	posts, _, err := s.postRepo.ListPosts(context.Background(), repositories.PostFilter{}, 1, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rows []string
+	for _, u := range users {
+		rows = append(rows, fmt.Sprintf("user %s %s %s %q %q %v", u.Username, u.Email, u.Role, u.DisplayName, u.Bio, u.CreatedAt))
+	}
+	for _, p := range posts {
+		published := "-"
+		if p.PublishedAt != nil {
+			published = p.PublishedAt.String()
+		}
+		rows = append(rows, fmt.Sprintf("post %s %q %s %s %s %v %s %x", p.Slug, p.Title, p.Status, p.Author.Username,
+			p.Category.Slug, p.CreatedAt, published, p.Content))
+		for _, c := range p.Comments {
+			rows = append(rows, fmt.Sprintf("comment %s %d %v %q %v", p.Slug, c.UserID, c.CreatedAt, c.Content, c.ParentID != nil))
+		}
+	}
+	slices.Sort(rows)
+	return rows
+}
+
+func TestGeneratedTextIsDeterministic(t *testing.T) {
+	a, b := rng(kindPost, 7), rng(kindPost, 7)
+	if headline(a) != headline(b) || article(a) != article(b) {
+		t.Error("the same row generated different text")
+	}
+	// rows do not share a random source
+	if article(rng(kindPost, 7)) == article(rng(kindPost, 8)) || article(rng(kindUser, 7)) == article(rng(kindPost, 7)) {
+		t.Error("two rows generated the same text")
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	for in, want := range map[string]string{
+		"What remote work taught me about cooking": "what-remote-work-taught-me-about-cooking",
+		"Travel: honest notes":                     "travel-honest-notes",
+		"  Go 1.24 -- release  ":                   "go-1-24-release",
+	} {
+		if got := slugify(in); got != want {
+			t.Errorf("slugify(%q) = %q; want %q", in, got, want)
+		}
+	}
+}
+
+func TestSeedIsDeterministic(t *testing.T) {
+	first, _ := newTestSeeder()
+	result, err := first.Run(testPreset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Users != testPreset.Users || result.Categories != testPreset.Categories || result.Posts != testPreset.Posts || result.Comments == 0 {
+		t.Fatalf("result = %+v", result)
+	}
+	second, _ := newTestSeeder()
+	if _, err := second.Run(testPreset); err != nil {
+		t.Fatal(err)
+	}
+	a, b := snapshot(t, first), snapshot(t, second)
+	if !slices.Equal(a, b) {
+		t.Fatalf("two runs differ:\n%s\n---\n%s", strings.Join(a, "\n"), strings.Join(b, "\n"))
+	}
+
+	var drafts, replies int
+	for _, row := range a {
+		if strings.Contains(row, " draft ") {
+			drafts++
+		}
+		if strings.HasPrefix(row, "comment ") && strings.HasSuffix(row, "true") {
+			replies++
+		}
+	}
+	if drafts == 0 || drafts == testPreset.Posts || replies == 0 {
+		t.Errorf("%d drafts and %d replies; want a mix", drafts, replies)
+	}
+}
+
+func TestSeedRerunCreatesNothing(t *testing.T) {
+	s, _ := newTestSeeder()
+	if _, err := s.Run(testPreset); err != nil {
+		t.Fatal(err)
+	}
+	before := snapshot(t, s)
+	result, err := s.Run(testPreset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *result != (Result{}) {
+		t.Errorf("second run created %+v; want nothing", result)
+	}
+	if !slices.Equal(snapshot(t, s), before) {
+		t.Error("second run changed the data")
+	}
+}
+
+func TestSeedLargerPresetExtends(t *testing.T) {
+	s, _ := newTestSeeder()
+	if _, err := s.Run(testPreset); err != nil {
+		t.Fatal(err)
+	}
+	before := snapshot(t, s)
+
+	larger := Preset{Users: 8, Categories: 4, Posts: 40, MaxCommentsPerPost: 4}
+	result, err := s.Run(larger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Users != 2 || result.Categories != 1 || result.Posts != 10 {
+		t.Errorf("extension created %+v; want only the missing rows", result)
+	}
+	after := snapshot(t, s)
+	for _, row := range before {
+		if _, found := slices.BinarySearch(after, row); !found {
+			t.Errorf("row lost or changed by the larger preset: %s", row)
+		}
+	}
+}
+
+func TestSeedRejectsTooManyCategories(t *testing.T) {
+	s, _ := newTestSeeder()
+	if _, err := s.Run(Preset{Categories: len(categoryNames) + 1}); err == nil {
+		t.Error("seeded more categories than there are names")
+	}
+}
@@ -0,0 +1,125 @@
+package seed
+
+import (
+	"math/rand"
+	"strings"
+	"unicode"
+)
+
+var firstNames = []string{
+	"Anna", "Bao", "Carlos", "Dung", "Elena", "Farah", "Giang", "Hana", "Ivan", "Julia",
+	"Khanh", "Linh", "Marco", "Nam", "Olivia", "Phuong", "Quang", "Rosa", "Sam", "Thao",
+	"Uma", "Viet", "Wen", "Xuan", "Yusuf", "Zoe",
+}
+
+var lastNames = []string{
+	"Nguyen", "Tran", "Le", "Pham", "Hoang", "Vu", "Smith", "Garcia", "Kim", "Muller",
+	"Rossi", "Silva", "Tanaka", "Ivanova", "Khan", "Dubois",
+}
+
+// categoryNames are the categories in creation order; the first ones are the starter
+// categories an empty site gets.
+var categoryNames = []string{
+	"General", "Technology", "Lifestyle", "Travel", "Food", "Programming", "Design",
+	"Science", "Health", "Books", "Music", "Photography", "Business", "Education",
+	"Sports", "Gaming", "Movies", "Personal Finance", "Productivity", "Open Source",
+}
+
+var (
+	adjectives = []string{
+		"practical", "quiet", "simple", "honest", "modern", "slow", "small", "better",
+		"hidden", "forgotten", "curious", "everyday", "unexpected", "gentle", "lazy",
+	}
+	nouns = []string{
+		"guide", "notes", "lessons", "habits", "mistakes", "ideas", "tools", "questions",
+		"stories", "patterns", "recipes", "experiments", "routines", "maps", "drafts",
+	}
+	topics = []string{
+		"remote work", "coffee", "databases", "city walks", "side projects", "morning runs",
+		"code review", "street food", "old cameras", "reading lists", "caching", "budgeting",
+		"gardening", "open source", "writing", "testing", "train journeys", "home cooking",
+	}
+	words = []string{
+		"the", "a", "we", "it", "this", "that", "every", "small", "change", "makes", "time",
+		"work", "day", "people", "idea", "better", "try", "often", "never", "always", "when",
+		"after", "before", "learn", "write", "build", "read", "simple", "question", "answer",
+		"data", "city", "morning", "team", "notes", "problem", "slowly", "quickly", "again",
+		"together", "enough", "really", "should", "could", "first", "last", "good", "hard",
+	}
+)
+
+// headline returns a post title such as "Practical lessons on remote work".
+func headline(r *rand.Rand) string {
+	switch r.Intn(3) {
+	case 0:
+		return capitalize(adjectives[r.Intn(len(adjectives))]) + " " + nouns[r.Intn(len(nouns))] + " on " + topics[r.Intn(len(topics))]
+	case 1:
+		i := r.Intn(len(topics))
+		j := (i + 1 + r.Intn(len(topics)-1)) % len(topics)
+		return "What " + topics[i] + " taught me about " + topics[j]
+	default:
+		return capitalize(topics[r.Intn(len(topics))]) + ": " + adjectives[r.Intn(len(adjectives))] + " " + nouns[r.Intn(len(nouns))]
+	}
+}
+
+// sentence returns a sentence of min to max words.
+func sentence(r *rand.Rand, min, max int) string {
+	n := min + r.Intn(max-min+1)
+	parts := make([]string, n)
+	for i := range parts {
+		parts[i] = words[r.Intn(len(words))]
+	}
+	return capitalize(strings.Join(parts, " ")) + "."
+}
+
+func paragraph(r *rand.Rand) string {
+	sentences := make([]string, 2+r.Intn(5))
+	for i := range sentences {
+		sentences[i] = sentence(r, 6, 18)
+	}
+	return strings.Join(sentences, " ")
+}
+
+// article returns markdown content that is short, medium or long: from one paragraph to
+// a dozen sections.
+func article(r *rand.Rand) string {
+	var sections int
+	switch n := r.Intn(10); {
+	case n < 3:
+		sections = 0
+	case n < 8:
+		sections = 1 + r.Intn(3)
+	default:
+		sections = 6 + r.Intn(7)
+	}
+
+	var b strings.Builder
+	b.WriteString(paragraph(r))
+	for i := 0; i < sections; i++ {
+		b.WriteString("\n\n## " + capitalize(nouns[r.Intn(len(nouns))]) + " about " + topics[r.Intn(len(topics))] + "\n\n")
+		b.WriteString(paragraph(r))
+		switch r.Intn(4) {
+		case 0:
+			b.WriteString("\n\n- " + sentence(r, 3, 8) + "\n- " + sentence(r, 3, 8) + "\n- " + sentence(r, 3, 8))
+		case 1:
+			b.WriteString("\n\n> " + sentence(r, 8, 16))
+		}
+		b.WriteString("\n\n" + paragraph(r))
+	}
+	return b.String()
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// slugify lowercases s and joins its words with dashes.
+func slugify(s string) string {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(c rune) bool {
+		return !unicode.IsLetter(c) && !unicode.IsDigit(c)
+	})
+	return strings.Join(fields, "-")
+}
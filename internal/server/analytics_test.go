@@ -0,0 +1,55 @@
+package server_test
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestPostAnalytics(t *testing.T) {
+	w := newWorld(t)
+	post := pathf("/posts/%d", w.postID)
+
+	w.bob.request(http.MethodGet, post, nil, http.Header{"Referer": {"https://www.google.com/search?q=hello"}}).expect(http.StatusOK)
+	w.bob.get(post).expect(http.StatusOK)   // the same reader again
+	w.alice.get(post).expect(http.StatusOK) // the author
+	w.anonymous().get(post).expect(http.StatusOK)
+	w.anonymous().request(http.MethodGet, post, nil, http.Header{"User-Agent": {"Googlebot/2.1"}}).expect(http.StatusOK)
+	// stopping the counter writes the buffered views
+	w.srv.ViewCounter.Stop()
+
+	var stats struct {
+		PostID      uint  `json:"post_id"`
+		Days        int   `json:"days"`
+		TotalViews  int64 `json:"total_views"`
+		PeriodViews int64 `json:"period_views"`
+		Referrers   []struct {
+			Referrer string `json:"referrer"`
+			Views    int64  `json:"views"`
+		} `json:"referrers"`
+	}
+	w.alice.get(post + "/stats?days=7").expect(http.StatusOK).decode(&stats)
+	if stats.PostID != w.postID || stats.Days != 7 || stats.TotalViews != 2 || stats.PeriodViews != 2 {
+		t.Errorf("stats = %+v; want 2 views", stats)
+	}
+	found := false
+	for _, r := range stats.Referrers {
+		found = found || r.Referrer == "google.com" && r.Views == 1
+	}
+	if !found {
+		t.Errorf("referrers = %+v; want one view from google.com", stats.Referrers)
+	}
+	w.admin.get(post + "/stats").expect(http.StatusOK)
+	w.alice.get(post + "/stats?days=0").expect(http.StatusBadRequest)
+
+	var top []struct {
+		PostID uint   `json:"post_id"`
+		Author string `json:"author"`
+		Views  int64  `json:"views"`
+	}
+	w.admin.get("/admin/posts/top?days=7&limit=5").expect(http.StatusOK).decode(&top)
+	if len(top) != 1 || top[0].PostID != w.postID || top[0].Author != "alice" || top[0].Views != 2 {
+		t.Errorf("top posts = %+v", top)
+	}
+	w.admin.get("/admin/posts/top?limit=x").expect(http.StatusBadRequest)
+	w.alice.get("/admin/posts/top").expect(http.StatusForbidden)
+}
@@ -0,0 +1,59 @@
+package server_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type bookmarkList struct {
+	Bookmarks []struct {
+		Collection *string  `json:"collection"`
+		Post       postInfo `json:"post"`
+	} `json:"bookmarks"`
+	Meta struct {
+		Total int `json:"total"`
+	} `json:"meta"`
+}
+
+func TestBookmarks(t *testing.T) {
+	w := newWorld(t)
+	otherID := w.post(w.alice, w.categoryID, "other", "published")
+	draftID := w.post(w.alice, w.categoryID, "draft-post", "draft")
+
+	w.bob.post(pathf("/posts/%d/bookmark", w.postID), nil).expect(http.StatusOK)
+	w.bob.post(pathf("/posts/%d/bookmark", otherID), gin.H{"collection": "Later"}).expect(http.StatusOK)
+	w.bob.post(pathf("/posts/%d/bookmark", draftID), nil).expect(http.StatusNotFound)
+	w.bob.post("/posts/999/bookmark", nil).expect(http.StatusNotFound)
+
+	if !w.bob.postDetail(w.postID).Bookmarked {
+		t.Error("bookmarked post not marked as bookmarked")
+	}
+
+	var list bookmarkList
+	w.bob.get("/users/me/bookmarks").expect(http.StatusOK).decode(&list)
+	if list.Meta.Total != 2 {
+		t.Errorf("bookmarks = %+v; want 2", list)
+	}
+	list = bookmarkList{}
+	w.bob.get("/users/me/bookmarks?collection=Later").expect(http.StatusOK).decode(&list)
+	if len(list.Bookmarks) != 1 || list.Bookmarks[0].Post.ID != otherID {
+		t.Errorf("bookmarks in Later = %+v", list.Bookmarks)
+	}
+
+	var collections struct {
+		Collections []struct {
+			ID   uint   `json:"id"`
+			Name string `json:"name"`
+		} `json:"collections"`
+	}
+	w.bob.get("/users/me/bookmark-collections").expect(http.StatusOK).decode(&collections)
+	if len(collections.Collections) != 1 || collections.Collections[0].Name != "Later" {
+		t.Errorf("collections = %+v", collections.Collections)
+	}
+
+	w.bob.delete(pathf("/posts/%d/bookmark", w.postID)).expect(http.StatusOK)
+	w.bob.delete(pathf("/posts/%d/bookmark", w.postID)).expect(http.StatusNotFound)
+	w.alice.delete(pathf("/posts/%d/bookmark", otherID)).expect(http.StatusNotFound)
+}
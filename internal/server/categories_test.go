@@ -0,0 +1,60 @@
+package server_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCategories(t *testing.T) {
+	w := newWorld(t)
+
+	w.alice.post("/admin/categories", gin.H{"name": "Mine", "slug": "mine"}).expect(http.StatusForbidden)
+	w.admin.post("/admin/categories", gin.H{"name": "X", "slug": "no"}).expect(http.StatusBadRequest)
+	w.admin.post("/admin/categories", gin.H{"name": "Tech", "slug": "tech", "comments_auto_close_days": 3}).
+		expect(http.StatusCreated)
+
+	var public struct {
+		Categories []struct {
+			ID                    uint   `json:"id"`
+			Slug                  string `json:"slug"`
+			CommentsAutoCloseDays int    `json:"comments_auto_close_days"`
+		} `json:"categories"`
+	}
+	w.anonymous().get("/categories").expect(http.StatusOK).decode(&public)
+	var techID uint
+	for _, c := range public.Categories {
+		if c.Slug == "tech" {
+			techID = c.ID
+			if c.CommentsAutoCloseDays != 3 {
+				t.Errorf("comments_auto_close_days = %d; want 3", c.CommentsAutoCloseDays)
+			}
+		}
+	}
+	if len(public.Categories) != 2 || techID == 0 {
+		t.Fatalf("categories = %+v", public.Categories)
+	}
+
+	w.admin.put(pathf("/admin/categories/%d", techID), gin.H{"name": "Technology", "slug": "technology"}).expect(http.StatusOK)
+	w.admin.put("/admin/categories/999", gin.H{"name": "Nothing", "slug": "nothing"}).expect(http.StatusNotFound)
+
+	var admin struct {
+		Categories []struct {
+			Slug      string `json:"slug"`
+			PostCount int    `json:"post_count"`
+			Posts     []struct {
+				ID uint `json:"id"`
+			} `json:"posts"`
+		} `json:"categories"`
+	}
+	w.admin.get("/admin/categories").expect(http.StatusOK).decode(&admin)
+	for _, c := range admin.Categories {
+		if c.Slug == "news" && (c.PostCount != 1 || c.Posts[0].ID != w.postID) {
+			t.Errorf("news in the admin list = %+v; want the published post", c)
+		}
+	}
+
+	w.admin.delete(pathf("/admin/categories/%d", techID)).expect(http.StatusOK)
+	w.admin.delete(pathf("/admin/categories/%d", techID)).expect(http.StatusNotFound)
+}
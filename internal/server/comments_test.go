@@ -0,0 +1,190 @@
+package server_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type commentList struct {
+	Comments []struct {
+		ID         uint    `json:"id"`
+		ParentID   *uint   `json:"parent_id"`
+		Content    string  `json:"content"`
+		MyReaction *string `json:"my_reaction"`
+	} `json:"comments"`
+	Meta struct {
+		Total int `json:"total"`
+	} `json:"meta"`
+}
+
+func TestComments(t *testing.T) {
+	w := newWorld(t)
+	comments := pathf("/posts/%d/comments", w.postID)
+
+	w.anonymous().post(comments, gin.H{"post_id": w.postID, "content": "Anonymous"}).expect(http.StatusUnauthorized)
+	w.bob.post(comments, gin.H{"post_id": w.postID}).expect(http.StatusBadRequest)
+	w.bob.post("/posts/999/comments", gin.H{"post_id": 999, "content": "Nowhere"}).expect(http.StatusNotFound)
+
+	first := w.comment(w.bob, w.postID, "First")
+	w.bob.post(comments, gin.H{"post_id": w.postID, "parent_id": first, "content": "Reply"}).expect(http.StatusCreated)
+
+	otherPost := w.post(w.alice, w.categoryID, "other", "published")
+	w.bob.post(pathf("/posts/%d/comments", otherPost), gin.H{"post_id": otherPost, "parent_id": first, "content": "Wrong thread"}).
+		expect(http.StatusBadRequest)
+
+	var list commentList
+	w.anonymous().get(comments).expectCode(http.StatusOK, "COMMENTS_FETCHED").decode(&list)
+	if list.Meta.Total != 2 || list.Comments[0].Content != "First" || list.Comments[1].ParentID == nil || *list.Comments[1].ParentID != first {
+		t.Errorf("comments = %+v", list)
+	}
+
+	w.bob.put(pathf("/comments/%d", first), gin.H{"content": "First, edited"}).expect(http.StatusOK)
+	w.bob.put("/comments/999", gin.H{"content": "Nothing"}).expect(http.StatusNotFound)
+	w.bob.put(pathf("/comments/%d", first), gin.H{}).expect(http.StatusBadRequest)
+
+	// commenting notifies the post's author
+	var inbox notificationList
+	w.alice.get("/users/me/notifications").expect(http.StatusOK).decode(&inbox)
+	if len(inbox.Notifications) != 2 || inbox.Notifications[0].Type != "comment_on_your_post" {
+		t.Errorf("alice's notifications = %+v; want the two comments", inbox.Notifications)
+	}
+}
+
+func TestLockComments(t *testing.T) {
+	w := newWorld(t)
+	first := w.comment(w.bob, w.postID, "Before the lock")
+	lock := pathf("/posts/%d/comments/lock", w.postID)
+
+	w.alice.put(lock, gin.H{}).expect(http.StatusBadRequest)
+	w.alice.put(lock, gin.H{"locked": true}).expect(http.StatusOK)
+	if w.anonymous().postDetail(w.postID).CommentsOpen {
+		t.Error("comments open after locking")
+	}
+	w.bob.post(pathf("/posts/%d/comments", w.postID), gin.H{"post_id": w.postID, "content": "Too late"}).
+		expectCode(http.StatusForbidden, "COMMENTS_LOCKED")
+	w.bob.put(pathf("/comments/%d", first), gin.H{"content": "Edited"}).expectCode(http.StatusForbidden, "COMMENTS_LOCKED")
+
+	w.admin.put(lock, gin.H{"locked": false}).expect(http.StatusOK)
+	w.comment(w.bob, w.postID, "After the lock")
+}
+
+// sseEvent is an event read from a comment stream.
+type sseEvent struct {
+	ID, Type string
+	Data     json.RawMessage
+}
+
+// stream opens the comment stream of a post and returns its events.
+func (h *harness) stream(postID uint, lastEventID string) <-chan sseEvent {
+	h.t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.url+pathf("/posts/%d/comments/stream", postID), nil)
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	h.t.Cleanup(func() {
+		cancel()
+		res.Body.Close()
+	})
+	if res.StatusCode != http.StatusOK || res.Header.Get("Content-Type") != "text/event-stream" {
+		h.t.Fatalf("stream: status %d, content type %q", res.StatusCode, res.Header.Get("Content-Type"))
+	}
+
+	reader := bufio.NewReader(res.Body)
+	// the retry hint is written once the stream is subscribed
+	if line, err := reader.ReadString('\n'); err != nil || line != "retry: 3000\n" {
+		h.t.Fatalf("stream starts with %q, %v", line, err)
+	}
+
+	events := make(chan sseEvent)
+	go func() {
+		defer close(events)
+		var ev sseEvent
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimSuffix(line, "\n")
+			switch {
+			case strings.HasPrefix(line, "id: "):
+				ev.ID = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				ev.Type = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				ev.Data = json.RawMessage(strings.TrimPrefix(line, "data: "))
+			case line == "" && ev.Type != "":
+				select {
+				case events <- ev:
+				case <-ctx.Done():
+					return
+				}
+				ev = sseEvent{}
+			}
+		}
+	}()
+	return events
+}
+
+func next(t *testing.T, events <-chan sseEvent) sseEvent {
+	t.Helper()
+	select {
+	case ev, ok := <-events:
+		if !ok {
+			t.Fatal("stream closed")
+		}
+		return ev
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event received")
+		return sseEvent{}
+	}
+}
+
+func TestCommentStream(t *testing.T) {
+	w := newWorld(t)
+	w.anonymous().get(pathf("/posts/%d/comments/stream?last_event_id=x", w.postID)).expect(http.StatusBadRequest)
+	w.anonymous().get("/posts/999/comments/stream").expect(http.StatusNotFound)
+
+	events := w.stream(w.postID, "")
+	id := w.comment(w.bob, w.postID, "Live")
+	created := next(t, events)
+	var comment struct {
+		ID      uint   `json:"id"`
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(created.Data, &comment); err != nil || created.Type != "comment.created" || comment.ID != id {
+		t.Errorf("event = %s %s, %v; want comment.created of comment %d", created.Type, created.Data, err, id)
+	}
+
+	w.bob.put(pathf("/comments/%d", id), gin.H{"content": "Live, edited"}).expect(http.StatusOK)
+	if ev := next(t, events); ev.Type != "comment.updated" {
+		t.Errorf("event = %s; want comment.updated", ev.Type)
+	}
+	w.bob.delete(pathf("/comments/%d", id)).expect(http.StatusOK)
+	if ev := next(t, events); ev.Type != "comment.deleted" {
+		t.Errorf("event = %s; want comment.deleted", ev.Type)
+	}
+
+	// a reconnecting client is sent what it missed
+	resumed := w.stream(w.postID, created.ID)
+	if ev := next(t, resumed); ev.Type != "comment.updated" {
+		t.Errorf("first resumed event = %s; want comment.updated", ev.Type)
+	}
+	if ev := next(t, resumed); ev.Type != "comment.deleted" {
+		t.Errorf("second resumed event = %s; want comment.deleted", ev.Type)
+	}
+}
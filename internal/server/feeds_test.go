@@ -0,0 +1,75 @@
+package server_test
+
+import (
+	"blog-api/pkg/feed"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFeeds(t *testing.T) {
+	w := newWorld(t)
+	w.post(w.alice, w.categoryID, "draft-post", "draft")
+	anon := w.anonymous()
+
+	for format, contentType := range feed.Formats {
+		for _, path := range []string{"/feed.", "/categories/news/feed.", "/users/alice/feed."} {
+			res := anon.get(path + format).expect(http.StatusOK)
+			if got := res.Header.Get("Content-Type"); got != contentType {
+				t.Errorf("%s%s: content type %q; want %q", path, format, got, contentType)
+			}
+			body := string(res.Body)
+			if !strings.Contains(body, "hello-world") || strings.Contains(body, "draft-post") {
+				t.Errorf("%s%s lists the wrong posts:\n%s", path, format, body)
+			}
+
+			etag := res.Header.Get("ETag")
+			if etag == "" {
+				t.Errorf("%s%s: no ETag", path, format)
+				continue
+			}
+			anon.request(http.MethodGet, path+format, nil, http.Header{"If-None-Match": {etag}}).expect(http.StatusNotModified)
+		}
+		anon.get("/categories/nothing/feed." + format).expect(http.StatusNotFound)
+		anon.get("/users/nobody/feed." + format).expect(http.StatusNotFound)
+	}
+}
+
+func TestSitemaps(t *testing.T) {
+	w := newWorld(t)
+	anon := w.anonymous()
+
+	index := anon.get("/sitemap.xml").expect(http.StatusOK)
+	for _, file := range []string{"categories.xml", "posts-1.xml", "authors-1.xml"} {
+		if !strings.Contains(string(index.Body), "/sitemaps/"+file) {
+			t.Errorf("index does not link %s:\n%s", file, index.Body)
+		}
+	}
+	anon.request(http.MethodGet, "/sitemap.xml", nil, http.Header{"If-None-Match": {index.Header.Get("ETag")}}).
+		expect(http.StatusNotModified)
+
+	if body := anon.get("/sitemaps/categories.xml").expect(http.StatusOK).Body; !strings.Contains(string(body), "/categories/news") {
+		t.Errorf("categories sitemap:\n%s", body)
+	}
+	if body := anon.get("/sitemaps/authors-1.xml").expect(http.StatusOK).Body; !strings.Contains(string(body), "alice") {
+		t.Errorf("authors sitemap:\n%s", body)
+	}
+	anon.get("/sitemaps/unknown.xml").expect(http.StatusNotFound)
+	anon.get("/sitemaps/posts-9.xml").expect(http.StatusNotFound)
+
+	// publishing a post invalidates the cached sitemaps, through the event bus
+	anon.get("/sitemaps/posts-1.xml").expect(http.StatusOK)
+	w.post(w.bob, w.categoryID, "fresh-post", "published")
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		body := anon.get("/sitemaps/posts-1.xml").expect(http.StatusOK).Body
+		if strings.Contains(string(body), "/posts/fresh-post") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("new post missing from the posts sitemap:\n%s", body)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -0,0 +1,81 @@
+package server_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+type feedPage struct {
+	Posts []postInfo `json:"posts"`
+	Meta  struct {
+		NextCursor *string `json:"next_cursor"`
+	} `json:"meta"`
+}
+
+func TestFollowUsers(t *testing.T) {
+	w := newWorld(t)
+
+	w.bob.post("/users/alice/follow", nil).expect(http.StatusOK)
+	w.bob.post("/users/bob/follow", nil).expect(http.StatusBadRequest)
+	w.bob.post("/users/nobody/follow", nil).expect(http.StatusNotFound)
+
+	var me userInfo
+	w.bob.get("/users/me").expect(http.StatusOK).decode(&me)
+	if me.FollowingCount != 1 {
+		t.Errorf("bob follows %d users; want 1", me.FollowingCount)
+	}
+	var profile struct {
+		FollowersCount int64 `json:"followers_count"`
+	}
+	w.anonymous().get("/users/alice").expect(http.StatusOK).decode(&profile)
+	if profile.FollowersCount != 1 {
+		t.Errorf("alice has %d followers; want 1", profile.FollowersCount)
+	}
+
+	w.bob.delete("/users/alice/follow").expect(http.StatusOK)
+	w.bob.delete("/users/alice/follow").expect(http.StatusNotFound)
+}
+
+func TestHomeFeed(t *testing.T) {
+	w := newWorld(t)
+	techID := w.category(w.admin, "tech")
+	carol := w.register("carol")
+	w.post(carol, techID, "carol-on-tech", "published")
+	w.post(carol, w.categoryID, "carol-on-news", "published")
+	w.post(w.alice, techID, "alice-draft", "draft")
+
+	var page feedPage
+	w.bob.get("/users/me/feed").expect(http.StatusOK).decode(&page)
+	if len(page.Posts) != 0 {
+		t.Errorf("feed without follows = %+v; want it empty", page.Posts)
+	}
+
+	w.bob.post("/users/alice/follow", nil).expect(http.StatusOK)
+	w.bob.post("/categories/tech/follow", nil).expect(http.StatusOK)
+	w.bob.post("/categories/nothing/follow", nil).expect(http.StatusNotFound)
+
+	// alice's published post and carol's post on tech, newest first, one per page
+	var slugs []string
+	cursor := ""
+	for range 3 {
+		page = feedPage{}
+		w.bob.get("/users/me/feed?limit=1&cursor=" + url.QueryEscape(cursor)).expect(http.StatusOK).decode(&page)
+		for _, p := range page.Posts {
+			slugs = append(slugs, p.Slug)
+		}
+		if page.Meta.NextCursor == nil {
+			break
+		}
+		cursor = *page.Meta.NextCursor
+	}
+	if len(slugs) != 2 || slugs[0] != "carol-on-tech" || slugs[1] != "hello-world" {
+		t.Errorf("feed = %v; want carol-on-tech, hello-world", slugs)
+	}
+
+	w.bob.get("/users/me/feed?limit=0").expect(http.StatusBadRequest)
+	w.bob.get("/users/me/feed?cursor=garbage").expect(http.StatusBadRequest)
+
+	w.bob.delete("/categories/tech/follow").expect(http.StatusOK)
+	w.bob.delete("/categories/tech/follow").expect(http.StatusNotFound)
+}
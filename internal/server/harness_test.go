@@ -0,0 +1,439 @@
+package server_test
+
+import (
+	"blog-api/internal/config"
+	"blog-api/internal/server"
+	"blog-api/internal/testdb"
+	"blog-api/pkg/metrics"
+	"blog-api/pkg/pubsub"
+	"blog-api/pkg/storage"
+	"blog-api/pkg/tracing"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/gorm"
+)
+
+// The tests in this package drive the whole API over HTTP, built by server.NewServer as
+// main builds it, against a test database. Every route must be requested by at least one
+// test: a full run fails listing the routes nobody called.
+
+const password = "Passw0rd!"
+
+// browser is the User-Agent of test requests; Go's default one is counted as a bot.
+const browser = "Mozilla/5.0 (X11; Linux x86_64) e2e"
+
+// jwtSecret signs the tokens of the test servers.
+var jwtSecret = []byte("test-secret")
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	gin.DefaultWriter = io.Discard
+
+	code := testdb.Run(m)
+	if code == 0 {
+		code = coverage.check()
+	}
+	os.Exit(code)
+}
+
+// coverage records which route templates were requested.
+var coverage = &routeCoverage{hit: make(map[string]bool)}
+
+type routeCoverage struct {
+	mu     sync.Mutex
+	routes []routePattern
+	hit    map[string]bool
+}
+
+type routePattern struct {
+	name   string // "METHOD /path/:param"
+	method string
+	path   *regexp.Regexp
+	params int
+}
+
+var routeParam = regexp.MustCompile(`[:*][^/]*`)
+
+// register learns the routes of an engine, once: every harness builds the same routes.
+func (c *routeCoverage) register(engine *gin.Engine) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.routes != nil {
+		return
+	}
+	for _, route := range engine.Routes() {
+		if strings.HasPrefix(route.Path, "/swagger/") {
+			continue
+		}
+		pattern, last := "^", 0
+		for _, param := range routeParam.FindAllStringIndex(route.Path, -1) {
+			pattern += regexp.QuoteMeta(route.Path[last:param[0]])
+			if route.Path[param[0]] == '*' {
+				pattern += ".*"
+			} else {
+				pattern += "[^/]+"
+			}
+			last = param[1]
+		}
+		pattern += regexp.QuoteMeta(route.Path[last:]) + "$"
+		c.routes = append(c.routes, routePattern{
+			name:   route.Method + " " + route.Path,
+			method: route.Method,
+			path:   regexp.MustCompile(pattern),
+			params: strings.Count(route.Path, ":") + 10*strings.Count(route.Path, "*"),
+		})
+	}
+}
+
+// record marks the route a request was routed to. Like the router, it prefers static
+// segments over parameters.
+func (c *routeCoverage) record(r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var best *routePattern
+	for i, route := range c.routes {
+		if route.method == r.Method && route.path.MatchString(r.URL.Path) && (best == nil || route.params < best.params) {
+			best = &c.routes[i]
+		}
+	}
+	if best != nil {
+		c.hit[best.name] = true
+	}
+}
+
+// check reports the routes no test requested. It only applies to full runs that reached
+// the database.
+func (c *routeCoverage) check() int {
+	if c.routes == nil || flag.Lookup("test.run").Value.String() != "" {
+		return 0
+	}
+	var missed []string
+	for _, route := range c.routes {
+		if !c.hit[route.name] {
+			missed = append(missed, route.name)
+		}
+	}
+	if len(missed) == 0 {
+		return 0
+	}
+	sort.Strings(missed)
+	fmt.Fprintf(os.Stderr, "FAIL: %d routes are not covered by any test:\n\t%s\n", len(missed), strings.Join(missed, "\n\t"))
+	return 1
+}
+
+// testMetrics is shared by the servers of the package, like the database it is collected
+// from: a database can only be instrumented once, for metrics and for tracing.
+var (
+	testMetrics     = metrics.New()
+	instrumentDB    sync.Once
+	instrumentDBErr error
+)
+
+// harness is a running API server on an emptied test database. It is shut down when the
+// test ends.
+type harness struct {
+	t   *testing.T
+	db  *gorm.DB
+	srv *server.Server
+	url string
+	// spans holds the spans of the server's requests.
+	spans *tracetest.InMemoryExporter
+}
+
+// newHarness starts a server with the default configuration, changed by the options.
+func newHarness(t *testing.T, options ...func(*config.Config)) *harness {
+	t.Helper()
+	db := testdb.Open(t, "server_test")
+	instrumentDB.Do(func() {
+		instrumentDBErr = errors.Join(testMetrics.InstrumentDB(db), tracing.InstrumentDB(db))
+	})
+	if instrumentDBErr != nil {
+		t.Fatal(instrumentDBErr)
+	}
+
+	bus := pubsub.NewMemoryBus(64)
+	local, err := storage.NewLocal(t.TempDir(), "/uploads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Default()
+	cfg.JWTSecret = config.Secret(jwtSecret)
+	for _, option := range options {
+		option(cfg)
+	}
+	spans := tracetest.NewInMemoryExporter()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans))
+	srv, err := server.NewServer(server.Config{App: cfg, Bus: bus, Storage: local, Metrics: testMetrics, Tracer: tracer}, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coverage.register(srv.Engine)
+	srv.HTTP.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		coverage.record(r)
+		srv.Engine.ServeHTTP(w, r)
+	})
+	srv.OnShutdown("event bus", func(context.Context) error { return bus.Close() })
+	srv.OnShutdown("tracer provider", tracer.Shutdown)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.Start()
+	go srv.Serve(listener)
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			t.Error(err)
+		}
+	})
+	return &harness{t: t, db: db, srv: srv, url: "http://" + listener.Addr().String(), spans: spans}
+}
+
+// client sends requests as one user, or anonymously when it has no token.
+type client struct {
+	h        *harness
+	token    string
+	ID       uint
+	Username string
+	Email    string
+}
+
+func (h *harness) anonymous() *client {
+	return &client{h: h}
+}
+
+// register signs up a user and logs them in.
+func (h *harness) register(username string) *client {
+	h.t.Helper()
+	c := &client{h: h, Username: username, Email: username + "@example.com"}
+	h.anonymous().post("/users/register", gin.H{"username": username, "email": c.Email, "password": password}).
+		expect(http.StatusCreated)
+	c.login()
+	return c
+}
+
+// registerAdmin signs up a user with the admin role. Admins only come from the database, so the
+// role is set there and the user logs in again for a token carrying it.
+func (h *harness) registerAdmin(username string) *client {
+	h.t.Helper()
+	c := h.register(username)
+	h.exec("UPDATE users SET role = 'admin' WHERE id = ?", c.ID)
+	c.login()
+	return c
+}
+
+func (c *client) login() {
+	c.h.t.Helper()
+	var data struct {
+		Token string `json:"token"`
+		User  struct {
+			ID uint `json:"id"`
+		} `json:"user"`
+	}
+	c.h.anonymous().post("/users/login", gin.H{"email": c.Email, "password": password}).
+		expect(http.StatusOK).decode(&data)
+	c.token, c.ID = data.Token, data.User.ID
+}
+
+// request sends a request with the client's token. A non-nil body is sent as JSON unless
+// it is already an io.Reader.
+func (c *client) request(method, path string, body any, header http.Header) *response {
+	c.h.t.Helper()
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case io.Reader:
+		reader = b
+	default:
+		encoded, err := json.Marshal(b)
+		if err != nil {
+			c.h.t.Fatal(err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	req, err := http.NewRequest(method, c.h.url+path, reader)
+	if err != nil {
+		c.h.t.Fatal(err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if req.Header.Get("Content-Type") == "" && body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", browser)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.h.t.Fatal(err)
+	}
+	defer res.Body.Close()
+	raw, err := io.ReadAll(res.Body)
+	if err != nil {
+		c.h.t.Fatal(err)
+	}
+	r := &response{t: c.h.t, req: method + " " + path, Status: res.StatusCode, Header: res.Header, Body: raw}
+	if strings.HasPrefix(res.Header.Get("Content-Type"), "application/json") {
+		json.Unmarshal(raw, &r.envelope)
+	}
+	return r
+}
+
+func (c *client) get(path string) *response {
+	c.h.t.Helper()
+	return c.request(http.MethodGet, path, nil, nil)
+}
+
+func (c *client) post(path string, body any) *response {
+	c.h.t.Helper()
+	return c.request(http.MethodPost, path, body, nil)
+}
+
+func (c *client) put(path string, body any) *response {
+	c.h.t.Helper()
+	return c.request(http.MethodPut, path, body, nil)
+}
+
+func (c *client) patch(path string, body any) *response {
+	c.h.t.Helper()
+	return c.request(http.MethodPatch, path, body, nil)
+}
+
+func (c *client) delete(path string) *response {
+	c.h.t.Helper()
+	return c.request(http.MethodDelete, path, nil, nil)
+}
+
+// response is a received response. JSON bodies are decoded into the API envelope.
+type response struct {
+	t        *testing.T
+	req      string
+	Status   int
+	Header   http.Header
+	Body     []byte
+	envelope struct {
+		Status  string          `json:"status"`
+		Code    string          `json:"code"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	}
+}
+
+// expect fails the test unless the response has the given status.
+func (r *response) expect(status int) *response {
+	r.t.Helper()
+	if r.Status != status {
+		r.t.Fatalf("%s: status %d; want %d\n%s", r.req, r.Status, status, r.Body)
+	}
+	return r
+}
+
+// expectCode is expect that also checks the code of the envelope.
+func (r *response) expectCode(status int, code string) *response {
+	r.t.Helper()
+	r.expect(status)
+	if r.envelope.Code != code {
+		r.t.Fatalf("%s: code %q; want %q\n%s", r.req, r.envelope.Code, code, r.Body)
+	}
+	return r
+}
+
+// decode decodes the data of the envelope.
+func (r *response) decode(v any) {
+	r.t.Helper()
+	if err := json.Unmarshal(r.envelope.Data, v); err != nil {
+		r.t.Fatalf("%s: decode data: %v\n%s", r.req, err, r.Body)
+	}
+}
+
+// message is the message of the envelope.
+func (r *response) message() string {
+	return r.envelope.Message
+}
+
+// fixtures
+
+func (h *harness) exec(sql string, args ...any) {
+	h.t.Helper()
+	if err := h.db.Exec(sql, args...).Error; err != nil {
+		h.t.Fatal(err)
+	}
+}
+
+// id looks up the ID of a row the API created without returning it.
+func (h *harness) id(table, column string, value any) uint {
+	h.t.Helper()
+	var id uint
+	if err := h.db.Table(table).Where(column+" = ?", value).Pluck("id", &id).Error; err != nil || id == 0 {
+		h.t.Fatalf("no %s with %s %v: %v", table, column, value, err)
+	}
+	return id
+}
+
+func (h *harness) category(admin *client, slug string) uint {
+	h.t.Helper()
+	admin.post("/admin/categories", gin.H{"name": "Category " + slug, "slug": slug}).expect(http.StatusCreated)
+	return h.id("categories", "slug", slug)
+}
+
+func (h *harness) post(author *client, categoryID uint, slug, status string) uint {
+	h.t.Helper()
+	author.post("/posts", gin.H{
+		"title": "Post " + slug, "slug": slug, "content": "Content of " + slug,
+		"thumbnail": "https://example.com/" + slug + ".png", "category_id": categoryID, "status": status,
+	}).expect(http.StatusCreated)
+	return h.id("posts", "slug", slug)
+}
+
+func (h *harness) comment(author *client, postID uint, content string) uint {
+	h.t.Helper()
+	author.post(fmt.Sprintf("/posts/%d/comments", postID), gin.H{"post_id": postID, "content": content}).
+		expect(http.StatusCreated)
+	return h.id("comments", "content", content)
+}
+
+// world is the fixture most tests start from: an admin, two users and a published post
+// by alice.
+type world struct {
+	*harness
+	admin, alice, bob *client
+	categoryID        uint
+	postID            uint
+}
+
+func newWorld(t *testing.T) *world {
+	t.Helper()
+	h := newHarness(t)
+	w := &world{harness: h, admin: h.registerAdmin("root"), alice: h.register("alice"), bob: h.register("bob")}
+	w.categoryID = h.category(w.admin, "news")
+	w.postID = h.post(w.alice, w.categoryID, "hello-world", "published")
+	return w
+}
+
+func pathf(format string, args ...any) string {
+	return fmt.Sprintf(format, args...)
+}
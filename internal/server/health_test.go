@@ -0,0 +1,93 @@
+package server_test
+
+import (
+	"blog-api/internal/config"
+	"context"
+	"net/http"
+	"runtime"
+	"testing"
+	"time"
+)
+
+type readiness struct {
+	Ready  bool `json:"ready"`
+	Checks []struct {
+		Name  string `json:"name"`
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	} `json:"checks"`
+}
+
+func TestHealth(t *testing.T) {
+	h := newHarness(t)
+	anon := h.anonymous()
+
+	anon.get("/healthz").expect(http.StatusOK)
+
+	var build struct {
+		Commit    string `json:"commit"`
+		BuildTime string `json:"build_time"`
+		GoVersion string `json:"go_version"`
+	}
+	anon.get("/version").expect(http.StatusOK).decode(&build)
+	if build.GoVersion != runtime.Version() || build.Commit == "" || build.BuildTime == "" {
+		t.Errorf("version = %+v", build)
+	}
+
+	var ready readiness
+	anon.get("/readyz").expect(http.StatusOK).decode(&ready)
+	names := map[string]bool{}
+	for _, check := range ready.Checks {
+		names[check.Name] = check.OK
+	}
+	for _, name := range []string{"database", "migrations", "media processor", "view counter"} {
+		if ok, found := names[name]; !found || !ok {
+			t.Errorf("check %q: found %v, ok %v", name, found, ok)
+		}
+	}
+
+	// a stopped worker makes the server unready
+	h.srv.ViewCounter.Stop()
+	anon.get("/readyz").expectCode(http.StatusServiceUnavailable, "503").decode(&ready)
+	for _, check := range ready.Checks {
+		if check.Name == "view counter" && (check.OK || check.Error == "") {
+			t.Errorf("view counter check = %+v", check)
+		}
+	}
+}
+
+func TestNotReadyDuringShutdown(t *testing.T) {
+	h := newHarness(t, func(cfg *config.Config) { cfg.HTTP.ShutdownDelay = 500 * time.Millisecond })
+	anon := h.anonymous()
+	anon.get("/readyz").expect(http.StatusOK)
+
+	shutdown := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		shutdown <- h.srv.Shutdown(ctx)
+	}()
+
+	// requests are still served during the delay, with readiness failing
+	deadline := time.Now().Add(400 * time.Millisecond)
+	for {
+		res := anon.get("/readyz")
+		if res.Status == http.StatusServiceUnavailable {
+			var ready readiness
+			res.decode(&ready)
+			if ready.Ready || len(ready.Checks) != 1 || ready.Checks[0].Name != "shutdown" {
+				t.Errorf("readiness while shutting down = %+v", ready)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("readyz still %d during shutdown", res.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	anon.get("/healthz").expect(http.StatusOK)
+
+	if err := <-shutdown; err != nil {
+		t.Fatal(err)
+	}
+}
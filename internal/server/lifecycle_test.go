@@ -0,0 +1,77 @@
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestGracefulShutdown(t *testing.T) {
+	w := newWorld(t)
+	events := w.stream(w.postID, "")
+	w.bob.get(pathf("/posts/%d", w.postID)).expect(http.StatusOK) // a buffered view
+
+	// a comment whose body is still being sent when the shutdown begins
+	body, send := io.Pipe()
+	status := make(chan int, 1)
+	go func() {
+		req, _ := http.NewRequest(http.MethodPost, w.url+pathf("/posts/%d/comments", w.postID), body)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+w.bob.token)
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			status <- 0
+			return
+		}
+		res.Body.Close()
+		status <- res.StatusCode
+	}()
+	fmt.Fprintf(send, `{"post_id": %d, `, w.postID)
+	time.Sleep(100 * time.Millisecond) // for the server to read the headers
+
+	var closed []string
+	w.srv.OnShutdown("database", func(context.Context) error {
+		closed = append(closed, "database")
+		return nil
+	})
+	shutdown := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		shutdown <- w.srv.Shutdown(ctx)
+	}()
+
+	// the event stream ends, so that it does not hold up the shutdown
+	for range events {
+	}
+	select {
+	case err := <-shutdown:
+		t.Fatalf("shutdown did not wait for the request in flight: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	fmt.Fprint(send, `"content": "Sent during shutdown"}`)
+	send.Close()
+	if got := <-status; got != http.StatusCreated {
+		t.Errorf("request in flight: status %d; want %d", got, http.StatusCreated)
+	}
+	if err := <-shutdown; err != nil {
+		t.Fatal(err)
+	}
+	if len(closed) != 1 {
+		t.Errorf("OnShutdown steps run %d times", len(closed))
+	}
+
+	// the view counter wrote its buffer before the database step
+	var views int64
+	w.db.Table("post_daily_views").Where("post_id = ?", w.postID).Select("COALESCE(SUM(views), 0)").Scan(&views)
+	if views != 1 {
+		t.Errorf("views = %d; want the buffered view written", views)
+	}
+	if _, err := http.Get(w.url + "/categories"); err == nil {
+		t.Error("the server still accepts requests")
+	}
+}
@@ -0,0 +1,206 @@
+package server_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"blog-api/pkg/imaging"
+
+	"github.com/gin-gonic/gin"
+)
+
+type mediaInfo struct {
+	ID       uint   `json:"id"`
+	URL      string `json:"url"`
+	Status   string `json:"status"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+	Variants []struct {
+		Width  int    `json:"width"`
+		Format string `json:"format"`
+		URL    string `json:"url"`
+	} `json:"variants"`
+}
+
+// upload sends a file as the multipart form the media endpoints take.
+func (c *client) upload(path, filename string, content []byte) *response {
+	c.h.t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", filename)
+	if err != nil {
+		c.h.t.Fatal(err)
+	}
+	part.Write(content)
+	form.Close()
+	return c.request(http.MethodPost, path, &body, http.Header{"Content-Type": {form.FormDataContentType()}})
+}
+
+func testPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := range width {
+		for y := range height {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 128, 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// sidewaysJPEG returns a portrait photo as phones store it: the pixels turned a quarter
+// counter-clockwise, red on the left, blue on the right, and an EXIF orientation of 6
+// next to a GPS position.
+func sidewaysJPEG(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 32, 16))
+	for x := range 32 {
+		for y := range 16 {
+			if x < 16 {
+				img.Set(x, y, color.RGBA{255, 0, 0, 255})
+			} else {
+				img.Set(x, y, color.RGBA{0, 0, 255, 255})
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+	// a big-endian TIFF header, then one IFD: the orientation and the GPS IFD pointer
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08\x00\x02" +
+		"\x01\x12\x00\x03\x00\x00\x00\x01\x00\x06\x00\x00" +
+		"\x88\x25\x00\x04\x00\x00\x00\x01\x00\x00\x00\x26" +
+		"\x00\x00\x00\x00GPS 48.8584 N 2.2945 E")
+	app1 := append([]byte("\xff\xe1\x00\x00Exif\x00\x00"), tiff...)
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(app1)-2))
+	encoded := buf.Bytes()
+	return append(append(bytes.Clone(encoded[:2]), app1...), encoded[2:]...)
+}
+
+// upright tells whether img is the portrait of sidewaysJPEG: red at the top, blue below.
+func upright(img image.Image) bool {
+	b := img.Bounds()
+	top, _, _, _ := img.At(b.Dx()/2, b.Dy()/4).RGBA()
+	_, _, bottom, _ := img.At(b.Dx()/2, 3*b.Dy()/4).RGBA()
+	return b.Dx() < b.Dy() && top > 0xc000 && bottom > 0xc000
+}
+
+func TestMediaOrientation(t *testing.T) {
+	w := newWorld(t)
+
+	var media mediaInfo
+	w.alice.upload("/media", "portrait.jpg", sidewaysJPEG(t)).expect(http.StatusCreated).decode(&media)
+	if media.Width != 16 || media.Height != 32 {
+		t.Errorf("uploaded media is %dx%d; want the upright 16x32", media.Width, media.Height)
+	}
+	stored := w.anonymous().get(media.URL).expect(http.StatusOK).Body
+	if bytes.Contains(stored, []byte("GPS")) {
+		t.Error("stored photo keeps its GPS position")
+	}
+	img, err := imaging.Decode(stored)
+	if err != nil || !upright(img) {
+		t.Errorf("stored photo does not show upright: %v", err)
+	}
+
+	deadline := time.Now().Add(10 * time.Second)
+	for media.Status != "ready" {
+		if media.Status == "failed" || time.Now().After(deadline) {
+			t.Fatalf("media not processed: %+v", media)
+		}
+		time.Sleep(50 * time.Millisecond)
+		w.alice.get(pathf("/media/%d", media.ID)).expect(http.StatusOK).decode(&media)
+	}
+	// variants carry no orientation: their pixels are upright
+	for _, variant := range media.Variants {
+		if variant.Format != "jpeg" {
+			continue
+		}
+		img, err := jpeg.Decode(bytes.NewReader(w.anonymous().get(variant.URL).expect(http.StatusOK).Body))
+		if err != nil || !upright(img) {
+			t.Errorf("variant %dpx is not upright: %v", variant.Width, err)
+		}
+	}
+}
+
+func TestMedia(t *testing.T) {
+	w := newWorld(t)
+	picture := testPNG(t, 800, 600)
+
+	w.anonymous().upload("/media", "a.png", picture).expect(http.StatusUnauthorized)
+	w.alice.upload("/media", "notes.txt", []byte("just text")).expect(http.StatusBadRequest)
+	// a small file declaring a huge image is refused before it is decoded
+	bomb := bytes.Clone(picture)
+	binary.BigEndian.PutUint32(bomb[16:], 50000)
+	binary.BigEndian.PutUint32(bomb[20:], 50000)
+	binary.BigEndian.PutUint32(bomb[29:], crc32.ChecksumIEEE(bomb[12:29]))
+	w.alice.upload("/media", "bomb.png", bomb).expect(http.StatusBadRequest)
+	w.alice.post("/media", gin.H{}).expect(http.StatusBadRequest)
+
+	var media mediaInfo
+	w.alice.upload("/media", "a.png", picture).expect(http.StatusCreated).decode(&media)
+	if media.ID == 0 || media.Width != 800 || !strings.HasPrefix(media.URL, "/uploads/") {
+		t.Fatalf("uploaded media = %+v", media)
+	}
+
+	// the worker adds the variants in the background
+	deadline := time.Now().Add(10 * time.Second)
+	for media.Status != "ready" {
+		if media.Status == "failed" || time.Now().After(deadline) {
+			t.Fatalf("media not processed: %+v", media)
+		}
+		time.Sleep(50 * time.Millisecond)
+		w.alice.get(pathf("/media/%d", media.ID)).expect(http.StatusOK).decode(&media)
+	}
+	if len(media.Variants) == 0 {
+		t.Errorf("processed media has no variants: %+v", media)
+	}
+	w.bob.get(pathf("/media/%d", media.ID)).expect(http.StatusNotFound)
+
+	res := w.anonymous().get(media.URL).expect(http.StatusOK)
+	if got := res.Header.Get("Content-Type"); got != "image/png" || !bytes.HasPrefix(res.Body, []byte("\x89PNG")) {
+		t.Errorf("GET %s: content type %q", media.URL, got)
+	}
+	w.anonymous().request(http.MethodHead, media.URL, nil, nil).expect(http.StatusOK)
+	w.anonymous().get("/uploads/missing.png").expect(http.StatusNotFound)
+
+	var list struct {
+		Media []mediaInfo `json:"media"`
+	}
+	w.alice.get("/users/me/media").expect(http.StatusOK).decode(&list)
+	if len(list.Media) != 1 || list.Media[0].ID != media.ID {
+		t.Errorf("alice's media = %+v", list.Media)
+	}
+
+	// uploads can be used as thumbnails, by their owner only
+	post := gin.H{"title": "Pictured", "slug": "pictured", "content": "Text", "thumbnail_media_id": media.ID, "category_id": w.categoryID, "status": "published"}
+	w.bob.post("/posts", post).expect(http.StatusBadRequest)
+	w.alice.post("/posts", post).expect(http.StatusCreated)
+}
+
+func TestAvatar(t *testing.T) {
+	w := newWorld(t)
+
+	var media mediaInfo
+	w.bob.upload("/users/me/avatar", "me.png", testPNG(t, 64, 64)).expect(http.StatusCreated).decode(&media)
+	var profile struct {
+		AvatarURL string `json:"avatar_url"`
+	}
+	w.anonymous().get("/users/bob").expect(http.StatusOK).decode(&profile)
+	if profile.AvatarURL == "" || profile.AvatarURL != media.URL {
+		t.Errorf("avatar url = %q; want %q", profile.AvatarURL, media.URL)
+	}
+}
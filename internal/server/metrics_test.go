@@ -0,0 +1,103 @@
+package server_test
+
+import (
+	"blog-api/internal/config"
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func basicAuth(username, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}
+
+// scrape parses the metrics of a text exposition into their values by series, e.g.
+// `blog_user_logins_total{result="failure"}`.
+func scrape(t *testing.T, body []byte) map[string]float64 {
+	t.Helper()
+	series := make(map[string]float64)
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.LastIndexByte(line, ' ')
+		value, err := strconv.ParseFloat(line[i+1:], 64)
+		if err != nil {
+			t.Fatalf("metrics line %q: %v", line, err)
+		}
+		series[line[:i]] = value
+	}
+	return series
+}
+
+func TestMetrics(t *testing.T) {
+	h := newHarness(t, func(cfg *config.Config) {
+		cfg.Metrics.Username, cfg.Metrics.Password = "prometheus", "scrape"
+	})
+	admin := h.registerAdmin("root")
+	alice := h.register("alice")
+	h.anonymous().post("/users/login", gin.H{"email": alice.Email, "password": "Wr0ng-pass"}).expect(http.StatusUnauthorized)
+	postID := h.post(alice, h.category(admin, "news"), "measured", "published")
+	h.comment(alice, postID, "Measured comment")
+	h.anonymous().get(pathf("/posts/%d", postID)).expect(http.StatusOK)
+	h.anonymous().get("/no/such/path").expect(http.StatusNotFound)
+
+	h.anonymous().get("/metrics").expect(http.StatusUnauthorized)
+	res := h.anonymous().request(http.MethodGet, "/metrics", nil, http.Header{"Authorization": {basicAuth("prometheus", "scrape")}}).
+		expect(http.StatusOK)
+	metrics := scrape(t, res.Body)
+
+	for _, series := range []string{
+		`blog_http_requests_total{method="GET",route="/posts/:post_id",status="200"}`,
+		`blog_http_requests_total{method="POST",route="/users/register",status="201"}`,
+		`blog_http_requests_total{method="GET",route="unmatched",status="404"}`,
+		`blog_http_request_duration_seconds_count{method="GET",route="/posts/:post_id"}`,
+		`blog_db_query_duration_seconds_count{operation="create",table="users"}`,
+		`blog_db_query_duration_seconds_count{operation="query",table="posts"}`,
+		`go_sql_max_open_connections{db_name="blog"}`,
+		`blog_user_registrations_total`,
+		`blog_user_logins_total{result="success"}`,
+		`blog_user_logins_total{result="failure"}`,
+		`blog_events_published_total{type="post.published"}`,
+		`blog_events_published_total{type="comment.created"}`,
+	} {
+		if metrics[series] <= 0 {
+			t.Errorf("%s = %v; want a positive value", series, metrics[series])
+		}
+	}
+	for series := range metrics {
+		if strings.Contains(series, pathf(`route="/posts/%d"`, postID)) {
+			t.Errorf("series labelled with a raw path: %s", series)
+		}
+	}
+}
+
+func TestMetricsListener(t *testing.T) {
+	h := newHarness(t, func(cfg *config.Config) { cfg.Metrics.Listen = "127.0.0.1:0" })
+	h.anonymous().get("/metrics").expect(http.StatusNotFound)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go h.srv.ServeMetrics(listener)
+	res, err := http.Get("http://" + listener.Addr().String() + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, _ := io.ReadAll(res.Body)
+	if res.StatusCode != http.StatusOK || !strings.Contains(string(body), "blog_http_requests_total") {
+		t.Errorf("internal listener: status %d\n%s", res.StatusCode, body)
+	}
+}
@@ -0,0 +1,87 @@
+package server_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type notificationList struct {
+	Notifications []struct {
+		ID   uint   `json:"id"`
+		Type string `json:"type"`
+		Read bool   `json:"read"`
+	} `json:"notifications"`
+	Meta struct {
+		UnreadCount int64 `json:"unread_count"`
+		NextCursor  *uint `json:"next_cursor"`
+	} `json:"meta"`
+}
+
+func TestNotifications(t *testing.T) {
+	w := newWorld(t)
+	for _, content := range []string{"One", "Two", "Three"} {
+		w.comment(w.bob, w.postID, content)
+	}
+	// commenting on your own post notifies nobody
+	w.comment(w.alice, w.postID, "Mine")
+
+	var page notificationList
+	w.alice.get("/users/me/notifications?limit=2").expect(http.StatusOK).decode(&page)
+	if len(page.Notifications) != 2 || page.Meta.UnreadCount != 3 || page.Meta.NextCursor == nil {
+		t.Fatalf("first page = %+v", page)
+	}
+	var rest notificationList
+	w.alice.get(pathf("/users/me/notifications?cursor=%d", *page.Meta.NextCursor)).expect(http.StatusOK).decode(&rest)
+	if len(rest.Notifications) != 1 || rest.Meta.NextCursor != nil {
+		t.Errorf("second page = %+v", rest)
+	}
+	w.alice.get("/users/me/notifications?cursor=x").expect(http.StatusBadRequest)
+	w.alice.get("/users/me/notifications?limit=0").expect(http.StatusBadRequest)
+
+	first := page.Notifications[0].ID
+	w.bob.put(pathf("/users/me/notifications/%d/read", first), nil).expect(http.StatusNotFound)
+	w.alice.put("/users/me/notifications/x/read", nil).expect(http.StatusBadRequest)
+	w.alice.put(pathf("/users/me/notifications/%d/read", first), nil).expect(http.StatusOK)
+
+	page = notificationList{}
+	w.alice.get("/users/me/notifications?unread_only=true").expect(http.StatusOK).decode(&page)
+	if len(page.Notifications) != 2 || page.Meta.UnreadCount != 2 {
+		t.Errorf("unread after reading one = %+v", page)
+	}
+
+	w.alice.put("/users/me/notifications/read-all", nil).expect(http.StatusOK)
+	page = notificationList{}
+	w.alice.get("/users/me/notifications").expect(http.StatusOK).decode(&page)
+	if page.Meta.UnreadCount != 0 {
+		t.Errorf("unread after reading all = %d", page.Meta.UnreadCount)
+	}
+}
+
+func TestNotificationPreferences(t *testing.T) {
+	w := newWorld(t)
+
+	var prefs struct {
+		Preferences map[string]bool `json:"preferences"`
+	}
+	w.alice.get("/users/me/notification-preferences").expect(http.StatusOK).decode(&prefs)
+	if len(prefs.Preferences) == 0 || !prefs.Preferences["comment_on_your_post"] {
+		t.Errorf("default preferences = %v; want everything on", prefs.Preferences)
+	}
+
+	w.alice.put("/users/me/notification-preferences", gin.H{"preferences": gin.H{"unknown": false}}).
+		expect(http.StatusBadRequest)
+	w.alice.put("/users/me/notification-preferences", gin.H{"preferences": gin.H{"comment_on_your_post": false}}).
+		expect(http.StatusOK).decode(&prefs)
+	if prefs.Preferences["comment_on_your_post"] || !prefs.Preferences["mentioned"] {
+		t.Errorf("preferences after update = %v", prefs.Preferences)
+	}
+
+	w.comment(w.bob, w.postID, "Unheard")
+	var page notificationList
+	w.alice.get("/users/me/notifications").expect(http.StatusOK).decode(&page)
+	if len(page.Notifications) != 0 {
+		t.Errorf("notified despite the preference: %+v", page.Notifications)
+	}
+}
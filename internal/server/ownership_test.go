@@ -0,0 +1,44 @@
+package server_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPostOwnership(t *testing.T) {
+	w := newWorld(t)
+	post := pathf("/posts/%d", w.postID)
+
+	w.bob.put(post, gin.H{"title": "Taken over"}).expect(http.StatusForbidden)
+	w.bob.patch(post, gin.H{"title": "Taken over"}).expect(http.StatusForbidden)
+	w.bob.put(post+"/comments/lock", gin.H{"locked": true}).expect(http.StatusForbidden)
+	w.bob.delete(post).expect(http.StatusForbidden)
+	w.bob.get(post + "/stats").expect(http.StatusForbidden)
+
+	w.bob.put("/posts/abc", gin.H{"title": "Nothing"}).expect(http.StatusBadRequest)
+	w.bob.put("/posts/999", gin.H{"title": "Nothing"}).expect(http.StatusNotFound)
+
+	// admins may change any post
+	w.admin.patch(post, gin.H{"title": "Edited by an admin"}).expect(http.StatusOK)
+	if got := w.anonymous().postDetail(w.postID).Title; got != "Edited by an admin" {
+		t.Errorf("title = %q", got)
+	}
+}
+
+func TestCommentOwnership(t *testing.T) {
+	w := newWorld(t)
+	carol := w.register("carol")
+	byBob := w.comment(w.bob, w.postID, "Comment by bob")
+	byCarol := w.comment(carol, w.postID, "Comment by carol")
+
+	// only the comment's author and the post's author may delete it, admins included
+	w.admin.delete(pathf("/comments/%d", byBob)).expect(http.StatusForbidden)
+	carol.delete(pathf("/comments/%d", byBob)).expect(http.StatusForbidden)
+	w.bob.delete(pathf("/comments/%d", byBob)).expect(http.StatusOK)
+	w.alice.delete(pathf("/comments/%d", byCarol)).expect(http.StatusOK)
+
+	w.bob.delete("/comments/abc").expect(http.StatusBadRequest)
+	w.bob.delete(pathf("/comments/%d", byBob)).expect(http.StatusNotFound)
+}
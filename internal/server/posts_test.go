@@ -0,0 +1,173 @@
+package server_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type postInfo struct {
+	ID           uint             `json:"id"`
+	Title        string           `json:"title"`
+	Slug         string           `json:"slug"`
+	Status       string           `json:"status"`
+	Author       string           `json:"author"`
+	CommentsOpen bool             `json:"comments_open"`
+	Bookmarked   bool             `json:"bookmarked"`
+	MyReaction   *string          `json:"my_reaction"`
+	Reactions    map[string]int64 `json:"reactions"`
+}
+
+type postList struct {
+	Posts []postInfo `json:"posts"`
+	Meta  struct {
+		Total int `json:"total"`
+	} `json:"meta"`
+}
+
+func (c *client) postDetail(id uint) postInfo {
+	c.h.t.Helper()
+	var data struct {
+		Post postInfo `json:"post"`
+	}
+	c.get(pathf("/posts/%d", id)).expect(http.StatusOK).decode(&data)
+	return data.Post
+}
+
+func TestCreatePost(t *testing.T) {
+	w := newWorld(t)
+
+	valid := gin.H{"title": "Another", "slug": "another", "content": "Text", "thumbnail": "https://example.com/a.png", "category_id": w.categoryID, "status": "published"}
+	with := func(key string, value any) gin.H {
+		body := gin.H{}
+		for k, v := range valid {
+			body[k] = v
+		}
+		if value == nil {
+			delete(body, key)
+		} else {
+			body[key] = value
+		}
+		return body
+	}
+
+	w.anonymous().post("/posts", valid).expect(http.StatusUnauthorized)
+	var errs map[string]string
+	w.alice.post("/posts", with("slug", "hello-world")).expect(http.StatusBadRequest).decode(&errs)
+	if errs["slug"] == "" {
+		t.Errorf("duplicate slug errors = %v", errs)
+	}
+	errs = nil
+	w.alice.post("/posts", with("category_id", 999)).expect(http.StatusBadRequest).decode(&errs)
+	if errs["category_id"] == "" {
+		t.Errorf("unknown category errors = %v", errs)
+	}
+	w.alice.post("/posts", with("thumbnail", nil)).expect(http.StatusBadRequest)
+	w.alice.post("/posts", with("thumbnail", "not a url")).expect(http.StatusBadRequest)
+
+	h := w.harness
+	h.exec("UPDATE users SET can_post = false WHERE id = ?", w.bob.ID)
+	if res := w.bob.post("/posts", with("slug", "by-bob")); res.Status == http.StatusCreated {
+		t.Error("an author banned from posting created a post")
+	}
+
+	w.alice.post("/posts", valid).expect(http.StatusCreated)
+	post := w.anonymous().postDetail(h.id("posts", "slug", "another"))
+	if post.Title != "Another" || post.Author != "alice" || post.Status != "published" || !post.CommentsOpen {
+		t.Errorf("created post = %+v", post)
+	}
+}
+
+func TestListPosts(t *testing.T) {
+	w := newWorld(t)
+	draftID := w.post(w.alice, w.categoryID, "draft-post", "draft")
+	w.post(w.bob, w.categoryID, "by-bob", "published")
+
+	var list postList
+	w.anonymous().get("/posts").expect(http.StatusOK).decode(&list)
+	if list.Meta.Total != 2 {
+		t.Errorf("GET /posts total = %d; want the 2 published posts", list.Meta.Total)
+	}
+	list = postList{}
+	w.anonymous().get("/posts?author=bob").expect(http.StatusOK).decode(&list)
+	if len(list.Posts) != 1 || list.Posts[0].Slug != "by-bob" {
+		t.Errorf("posts by bob = %+v", list.Posts)
+	}
+	w.anonymous().get("/posts?page_size=x").expect(http.StatusNotFound)
+
+	list = postList{}
+	w.admin.get("/admin/posts").expect(http.StatusOK).decode(&list)
+	if list.Meta.Total != 2 {
+		t.Errorf("GET /admin/posts total = %d; want 2", list.Meta.Total)
+	}
+	w.alice.get("/admin/posts").expect(http.StatusForbidden)
+
+	list = postList{}
+	w.anonymous().get("/users/alice/posts").expect(http.StatusOK).decode(&list)
+	if len(list.Posts) != 1 || list.Posts[0].ID != w.postID {
+		t.Errorf("alice's posts = %+v; want the published one", list.Posts)
+	}
+	w.anonymous().get("/users/nobody/posts").expect(http.StatusNotFound)
+
+	if post := w.anonymous().postDetail(draftID); post.Status != "draft" {
+		t.Errorf("draft detail = %+v", post)
+	}
+	w.anonymous().get("/posts/999").expect(http.StatusNotFound)
+	w.anonymous().get("/posts/abc").expect(http.StatusBadRequest)
+}
+
+func TestUpdatePost(t *testing.T) {
+	w := newWorld(t)
+	draftID := w.post(w.alice, w.categoryID, "draft-post", "draft")
+
+	w.alice.put(pathf("/posts/%d", w.postID), gin.H{"title": "Hello again"}).expect(http.StatusOK)
+	if post := w.anonymous().postDetail(w.postID); post.Title != "Hello again" {
+		t.Errorf("title after PUT = %q", post.Title)
+	}
+	w.alice.put(pathf("/posts/%d", w.postID), gin.H{"thumbnail": "not a url"}).expect(http.StatusBadRequest)
+	w.alice.patch(pathf("/posts/%d", w.postID), gin.H{"category_id": 999}).expect(http.StatusBadRequest)
+
+	// an admin publishing a draft approves it
+	w.admin.patch(pathf("/posts/%d", draftID), gin.H{"status": "published"}).expect(http.StatusOK)
+	if post := w.anonymous().postDetail(draftID); post.Status != "published" {
+		t.Errorf("status after approval = %q", post.Status)
+	}
+	var inbox notificationList
+	w.alice.get("/users/me/notifications").expect(http.StatusOK).decode(&inbox)
+	if len(inbox.Notifications) != 1 || inbox.Notifications[0].Type != "post_approved" {
+		t.Errorf("alice's notifications = %+v; want the approval", inbox.Notifications)
+	}
+}
+
+func TestDeletePost(t *testing.T) {
+	w := newWorld(t)
+	otherID := w.post(w.alice, w.categoryID, "other", "published")
+
+	w.alice.delete(pathf("/posts/%d", w.postID)).expect(http.StatusOK)
+	w.anonymous().get(pathf("/posts/%d", w.postID)).expect(http.StatusNotFound)
+	// the ownership check no longer finds the post
+	w.alice.delete(pathf("/posts/%d", w.postID)).expect(http.StatusNotFound)
+
+	w.bob.delete(pathf("/admin/posts/%d", otherID)).expect(http.StatusForbidden)
+	w.admin.delete(pathf("/admin/posts/%d", otherID)).expect(http.StatusOK)
+	w.admin.delete(pathf("/admin/posts/%d", otherID)).expect(http.StatusNotFound)
+}
+
+func TestPostMeta(t *testing.T) {
+	w := newWorld(t)
+
+	var meta struct {
+		Title     string `json:"title"`
+		OpenGraph []struct {
+			Property string `json:"property"`
+			Content  string `json:"content"`
+		} `json:"open_graph"`
+		JSONLD map[string]any `json:"json_ld"`
+	}
+	w.anonymous().get(pathf("/posts/%d/meta", w.postID)).expect(http.StatusOK).decode(&meta)
+	if meta.Title == "" || len(meta.OpenGraph) == 0 || meta.JSONLD == nil {
+		t.Errorf("post meta = %+v", meta)
+	}
+	w.anonymous().get("/posts/999/meta").expect(http.StatusNotFound)
+}
@@ -0,0 +1,66 @@
+package server_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type reactionState struct {
+	MyReaction *string          `json:"my_reaction"`
+	Reactions  map[string]int64 `json:"reactions"`
+}
+
+func TestPostReactions(t *testing.T) {
+	w := newWorld(t)
+	reactions := pathf("/posts/%d/reactions", w.postID)
+
+	var state reactionState
+	w.bob.post(reactions, gin.H{"type": "like"}).expect(http.StatusOK).decode(&state)
+	if state.MyReaction == nil || *state.MyReaction != "like" || state.Reactions["like"] != 1 {
+		t.Errorf("after liking: %+v", state)
+	}
+	// one reaction per user: a different one replaces it
+	state = reactionState{}
+	w.bob.post(reactions, gin.H{"type": "love"}).expect(http.StatusOK).decode(&state)
+	if state.Reactions["like"] != 0 || state.Reactions["love"] != 1 {
+		t.Errorf("after switching to love: %+v", state)
+	}
+	w.alice.post(reactions, gin.H{"type": "love"}).expect(http.StatusOK)
+
+	post := w.bob.postDetail(w.postID)
+	if post.MyReaction == nil || *post.MyReaction != "love" || post.Reactions["love"] != 2 {
+		t.Errorf("post seen by bob: %+v", post)
+	}
+
+	// the same reaction again removes it
+	state = reactionState{}
+	w.bob.post(reactions, gin.H{"type": "love"}).expect(http.StatusOK).decode(&state)
+	if state.MyReaction != nil || state.Reactions["love"] != 1 {
+		t.Errorf("after toggling off: %+v", state)
+	}
+
+	w.bob.post(reactions, gin.H{"type": "angry"}).expectCode(http.StatusBadRequest, "INVALID_REACTION")
+	w.bob.post(reactions, gin.H{}).expect(http.StatusBadRequest)
+	w.bob.post("/posts/999/reactions", gin.H{"type": "like"}).expect(http.StatusNotFound)
+	w.anonymous().post(reactions, gin.H{"type": "like"}).expect(http.StatusUnauthorized)
+}
+
+func TestCommentReactions(t *testing.T) {
+	w := newWorld(t)
+	id := w.comment(w.bob, w.postID, "React to me")
+
+	var state reactionState
+	w.alice.post(pathf("/comments/%d/reactions", id), gin.H{"type": "insightful"}).expect(http.StatusOK).decode(&state)
+	if state.Reactions["insightful"] != 1 {
+		t.Errorf("after reacting: %+v", state)
+	}
+
+	var list commentList
+	w.alice.get(pathf("/posts/%d/comments", w.postID)).expect(http.StatusOK).decode(&list)
+	if len(list.Comments) != 1 || list.Comments[0].MyReaction == nil || *list.Comments[0].MyReaction != "insightful" {
+		t.Errorf("comments seen by alice: %+v", list.Comments)
+	}
+	w.alice.post("/comments/999/reactions", gin.H{"type": "like"}).expect(http.StatusNotFound)
+}
@@ -0,0 +1,299 @@
+// Package server assembles the API: the router with its middleware and every route, and
+// the background workers the routes hand work to. main serves it; the end-to-end tests
+// build the same server against a test database.
+package server
+
+import (
+	_ "blog-api/docs"
+	"blog-api/internal/config"
+	"blog-api/internal/migrations"
+	"blog-api/internal/repositories"
+	"blog-api/internal/routes"
+	"blog-api/internal/services"
+	"blog-api/pkg/health"
+	"blog-api/pkg/metrics"
+	"blog-api/pkg/migrate"
+	"blog-api/pkg/pubsub"
+	"blog-api/pkg/storage"
+	"blog-api/pkg/tracing"
+	"blog-api/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"gorm.io/gorm"
+)
+
+// Config is what the server is built from besides the database: the application
+// configuration and the connections made from it.
+type Config struct {
+	App     *config.Config
+	Bus     pubsub.Bus
+	Storage storage.Storage
+	// Metrics collects the server's metrics. The database is instrumented by the caller,
+	// with Metrics.InstrumentDB.
+	Metrics *metrics.Metrics
+	// Tracer records the spans of requests; without it they are not traced. The
+	// database is instrumented by the caller, with tracing.InstrumentDB.
+	Tracer trace.TracerProvider
+}
+
+// Server is the API router, the HTTP server serving it and the workers behind it.
+type Server struct {
+	Engine *gin.Engine
+	// HTTP serves Engine with the timeouts of the configuration. Its handler may be
+	// wrapped before serving.
+	HTTP *http.Server
+	// MetricsHTTP serves /metrics on the internal address of the configuration; it is nil
+	// when the metrics are served by Engine.
+	MetricsHTTP    *http.Server
+	ViewCounter    *services.ViewCounter
+	MediaProcessor *services.MediaProcessor
+	Sitemaps       *services.SitemapService
+	// Health holds the readiness checks served on /readyz.
+	Health *health.Checker
+
+	bus           pubsub.Bus
+	shutdownDelay time.Duration
+	// workers is cancelled to stop the goroutines started by Start.
+	workers     context.Context
+	stopWorkers context.CancelFunc
+	running     sync.WaitGroup
+	// closing is cancelled when shutdown begins, ending the event streams.
+	closing      context.Context
+	closeStreams context.CancelFunc
+	closers      []shutdownStep
+	shutdownOnce sync.Once
+	shutdownErr  error
+}
+
+// NewServer builds the router with every route registered. The workers are not running
+// until Start is called.
+func NewServer(cfg Config, db *gorm.DB) (*Server, error) {
+	if err := utils.RegisterValidators(); err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		Engine:         gin.Default(),
+		ViewCounter:    services.NewViewCounter(repositories.NewPostViewRepository(db)),
+		MediaProcessor: services.NewMediaProcessor(repositories.NewMediaRepository(db), cfg.Storage, cfg.App.Storage.VariantWidths),
+		Sitemaps:       services.NewSitemapService(repositories.NewPostRepository(db), cfg.App.Site.URL, cfg.App.Site.SitemapGzip),
+		Health:         health.NewChecker(),
+		bus:            cfg.Metrics.InstrumentBus(cfg.Bus),
+		shutdownDelay:  cfg.App.HTTP.ShutdownDelay,
+	}
+	if err := s.addHealthChecks(db); err != nil {
+		return nil, err
+	}
+	s.workers, s.stopWorkers = context.WithCancel(context.Background())
+	s.closing, s.closeStreams = context.WithCancel(context.Background())
+
+	tracer := cfg.Tracer
+	if tracer == nil {
+		tracer = noop.NewTracerProvider()
+	}
+	r := s.Engine
+	r.Use(tracing.Middleware(cfg.App.Tracing.ServiceName, tracer))
+	r.Use(cfg.Metrics.Middleware())
+	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	routes.SetupHealthRoutes(r, s.Health)
+	if cfg.App.Metrics.Listen == "" {
+		routes.SetupMetricsRoutes(r, cfg.App, cfg.Metrics)
+	} else {
+		internal := gin.New()
+		internal.Use(gin.Recovery())
+		routes.SetupMetricsRoutes(internal, cfg.App, cfg.Metrics)
+		s.MetricsHTTP = &http.Server{
+			Addr:              cfg.App.Metrics.Listen,
+			Handler:           internal,
+			ReadHeaderTimeout: cfg.App.HTTP.ReadHeaderTimeout,
+			ReadTimeout:       cfg.App.HTTP.ReadTimeout,
+			WriteTimeout:      cfg.App.HTTP.WriteTimeout,
+			IdleTimeout:       cfg.App.HTTP.IdleTimeout,
+			MaxHeaderBytes:    cfg.App.HTTP.MaxHeaderBytes,
+		}
+	}
+	r.Use(cors.New(cors.Config{
+		AllowOrigins:     cfg.App.AllowOrigins,
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
+		ExposeHeaders:    []string{"Content-Length"},
+		AllowCredentials: true,
+	}))
+
+	routes.SetupUserRoutes(r, db, cfg.App, cfg.Metrics)
+	routes.SetupCategoryRoutes(r, db, cfg.App)
+	routes.SetupPostRoutes(r, db, cfg.App, s.bus, s.ViewCounter)
+	routes.SetupCommentRoutes(r, db, cfg.App, s.bus, s.closing)
+	routes.SetupNotificationRoutes(r, db, cfg.App)
+	routes.SetupReactionRoutes(r, db, cfg.App)
+	routes.SetupBookmarkRoutes(r, db, cfg.App)
+	routes.SetupFollowRoutes(r, db, cfg.App)
+	routes.SetupFeedRoutes(r, db, cfg.App)
+	routes.SetupSitemapRoutes(r, s.Sitemaps)
+	routes.SetupAnalyticsRoutes(r, db, cfg.App)
+	routes.SetupMediaRoutes(r, db, cfg.App, cfg.Storage, s.MediaProcessor)
+
+	s.HTTP = &http.Server{
+		Addr:              ":" + cfg.App.Port,
+		Handler:           r,
+		ReadHeaderTimeout: cfg.App.HTTP.ReadHeaderTimeout,
+		ReadTimeout:       cfg.App.HTTP.ReadTimeout,
+		WriteTimeout:      cfg.App.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.App.HTTP.IdleTimeout,
+		MaxHeaderBytes:    cfg.App.HTTP.MaxHeaderBytes,
+	}
+	s.HTTP.RegisterOnShutdown(s.closeStreams)
+	return s, nil
+}
+
+// addHealthChecks registers what the server needs to serve requests: the database with an
+// up-to-date schema and the workers requests hand work to.
+func (s *Server) addHealthChecks(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	migrator, err := migrate.New(sqlDB, migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	s.Health.Add(health.Check{Name: "database", Run: sqlDB.PingContext})
+	s.Health.Add(health.Check{Name: "migrations", Run: func(ctx context.Context) error {
+		pending, err := migrator.Pending(ctx)
+		if err != nil || len(pending) == 0 {
+			return err
+		}
+		names := make([]string, len(pending))
+		for i, m := range pending {
+			names[i] = fmt.Sprintf("%04d_%s", m.Version, m.Name)
+		}
+		return fmt.Errorf("%d pending: %s", len(pending), strings.Join(names, ", "))
+	}})
+	s.Health.Add(health.Check{Name: "media processor", Run: workerRunning(s.MediaProcessor.Running)})
+	s.Health.Add(health.Check{Name: "view counter", Run: workerRunning(s.ViewCounter.Running)})
+	return nil
+}
+
+var errWorkerStopped = errors.New("not running")
+
+func workerRunning(running func() bool) func(context.Context) error {
+	return func(context.Context) error {
+		if !running() {
+			return errWorkerStopped
+		}
+		return nil
+	}
+}
+
+// Start runs the background workers.
+func (s *Server) Start() {
+	s.MediaProcessor.Start()
+	s.ViewCounter.Start()
+	s.running.Add(1)
+	go func() {
+		defer s.running.Done()
+		s.Sitemaps.WatchPosts(s.workers, s.bus)
+	}()
+}
+
+// ListenAndServe serves the API on the configured port until Shutdown.
+func (s *Server) ListenAndServe() error {
+	return ignoreClosed(s.HTTP.ListenAndServe())
+}
+
+// Serve serves the API on l until Shutdown.
+func (s *Server) Serve(l net.Listener) error {
+	return ignoreClosed(s.HTTP.Serve(l))
+}
+
+// ListenAndServeMetrics serves the metrics on their internal address until Shutdown. It
+// returns at once when the metrics are served with the API.
+func (s *Server) ListenAndServeMetrics() error {
+	if s.MetricsHTTP == nil {
+		return nil
+	}
+	return ignoreClosed(s.MetricsHTTP.ListenAndServe())
+}
+
+// ServeMetrics serves the metrics on l until Shutdown. It must only be used when
+// MetricsHTTP is set.
+func (s *Server) ServeMetrics(l net.Listener) error {
+	return ignoreClosed(s.MetricsHTTP.Serve(l))
+}
+
+func ignoreClosed(err error) error {
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// OnShutdown adds a step run after the server and its workers have stopped, for the
+// connections they used. Steps run in the order they were added.
+func (s *Server) OnShutdown(name string, stop func(ctx context.Context) error) {
+	s.closers = append(s.closers, shutdownStep{name: name, stop: stop})
+}
+
+// Shutdown stops the server within the deadline of ctx, in order:
+//
+//  1. /readyz starts failing, and requests are still served for the configured delay;
+//  2. the HTTP server stops accepting connections, ends the event streams and waits for
+//     the requests in flight, closing the connections left at the deadline;
+//  3. the workers stop: the sitemap watcher, the media processor, which finishes the item
+//     in hand, and the view counter, which writes the buffered views;
+//  4. the metrics listener, if any, stops;
+//  5. the steps added with OnShutdown run.
+//
+// It must follow Start. Later calls return the result of the first.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.shutdownOnce.Do(func() { s.shutdownErr = s.shutdown(ctx) })
+	return s.shutdownErr
+}
+
+func (s *Server) shutdown(ctx context.Context) error {
+	steps := []shutdownStep{
+		{"readiness", func(ctx context.Context) error {
+			s.Health.ShutDown()
+			select {
+			case <-time.After(s.shutdownDelay):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}},
+		{"http server", func(ctx context.Context) error {
+			if err := s.HTTP.Shutdown(ctx); err != nil {
+				s.HTTP.Close()
+				return err
+			}
+			return nil
+		}},
+		{"sitemap watcher", interruptible(func() {
+			s.stopWorkers()
+			s.running.Wait()
+		})},
+		{"media processor", interruptible(s.MediaProcessor.Stop)},
+		{"view counter", interruptible(s.ViewCounter.Stop)},
+		{"metrics server", func(ctx context.Context) error {
+			if s.MetricsHTTP == nil {
+				return nil
+			}
+			return s.MetricsHTTP.Shutdown(ctx)
+		}},
+	}
+	return runShutdown(ctx, append(steps, s.closers...))
+}
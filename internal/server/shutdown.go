@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// shutdownStep is one stage of stopping the server.
+type shutdownStep struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+// runShutdown runs the steps in order. A failing step does not prevent the next ones, so
+// that, say, a timed-out drain still lets the workers flush and the database close. The
+// errors are returned together, each prefixed by the name of its step.
+func runShutdown(ctx context.Context, steps []shutdownStep) error {
+	var errs []error
+	for _, step := range steps {
+		log.Println("Shutdown:", step.name)
+		if err := step.stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", step.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// interruptible adapts a blocking stop function to the shutdown deadline: it stops waiting
+// for it once ctx is done and reports the deadline, leaving it to finish in the background.
+func interruptible(stop func()) func(context.Context) error {
+	return func(ctx context.Context) error {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			stop()
+		}()
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
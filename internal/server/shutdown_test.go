@@ -0,0 +1,61 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunShutdownRunsEveryStepInOrder(t *testing.T) {
+	var ran []string
+	step := func(name string, err error) shutdownStep {
+		return shutdownStep{name: name, stop: func(context.Context) error {
+			ran = append(ran, name)
+			return err
+		}}
+	}
+	boom := errors.New("boom")
+
+	err := runShutdown(context.Background(), []shutdownStep{
+		step("http server", nil),
+		step("worker", boom),
+		step("database", nil),
+	})
+	if want := []string{"http server", "worker", "database"}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("ran %v; want %v", ran, want)
+	}
+	if !errors.Is(err, boom) || !strings.Contains(err.Error(), "worker: boom") {
+		t.Errorf("err = %v; want the failure of the worker step", err)
+	}
+}
+
+func TestInterruptibleGivesUpAtTheDeadline(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	stuck := interruptible(func() { <-release })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	closed := false
+	start := time.Now()
+	err := runShutdown(ctx, []shutdownStep{
+		{"stuck worker", stuck},
+		{"database", func(context.Context) error { closed = true; return nil }},
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v; want the deadline", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("shutdown took %v", elapsed)
+	}
+	if !closed {
+		t.Error("the steps after the stuck one did not run")
+	}
+
+	if err := interruptible(func() {})(context.Background()); err != nil {
+		t.Errorf("a worker stopping in time: %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package server_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// trace returns the spans of the trace with the given ID once its request span, named
+// root, has ended: it ends just after the response is sent.
+func (h *harness) trace(traceID, root string) map[string][]tracetest.SpanStub {
+	h.t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		byName := make(map[string][]tracetest.SpanStub)
+		for _, span := range h.spans.GetSpans() {
+			if span.SpanContext.TraceID().String() == traceID {
+				byName[span.Name] = append(byName[span.Name], span)
+			}
+		}
+		if len(byName[root]) > 0 {
+			return byName
+		}
+		if time.Now().After(deadline) {
+			h.t.Fatalf("no %s span in trace %s: %v", root, traceID, byName)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestTracing(t *testing.T) {
+	h := newHarness(t)
+	admin := h.registerAdmin("root")
+	alice := h.register("alice")
+	h.post(alice, h.category(admin, "news"), "traced-secret", "published")
+
+	const traceID, callerSpan = "4bf92f3577b34da6a3ce929d0e0e4736", "00f067aa0ba902b7"
+	h.anonymous().request(http.MethodGet, "/posts?title=secret", nil,
+		http.Header{"Traceparent": {"00-" + traceID + "-" + callerSpan + "-01"}}).expect(http.StatusOK)
+	spans := h.trace(traceID, "GET /posts")
+
+	request := spans["GET /posts"][0]
+	if request.Parent.SpanID().String() != callerSpan {
+		t.Errorf("request span parent = %s; want the caller's span", request.Parent.SpanID())
+	}
+	service := spans["PostService.ListPosts"]
+	if len(service) != 1 || service[0].Parent.SpanID() != request.SpanContext.SpanID() {
+		t.Fatalf("ListPosts spans = %v; want one under the request span", service)
+	}
+	// the count, the page and the preloaded associations
+	for _, name := range []string{"SELECT posts", "SELECT users", "SELECT categories", "SELECT comments"} {
+		if len(spans[name]) == 0 {
+			t.Errorf("no %s span", name)
+		}
+		for _, query := range spans[name] {
+			if query.Parent.SpanID() != service[0].SpanContext.SpanID() {
+				t.Errorf("%s span is not a child of ListPosts", name)
+			}
+			for _, kv := range query.Attributes {
+				if kv.Key == "db.query.text" && strings.Contains(kv.Value.AsString(), "secret") {
+					t.Errorf("%s span records a bound value: %s", name, kv.Value.AsString())
+				}
+			}
+		}
+	}
+
+	// probes are not traced
+	h.anonymous().get("/healthz").expect(http.StatusOK)
+	for _, span := range h.spans.GetSpans() {
+		if span.Name == "GET /healthz" {
+			t.Error("health probe traced")
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package server_test
+
+import (
+	"blog-api/pkg/utils"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type userInfo struct {
+	ID             uint   `json:"id"`
+	Email          string `json:"email"`
+	Username       string `json:"username"`
+	Role           string `json:"role"`
+	FollowersCount int64  `json:"followers_count"`
+	FollowingCount int64  `json:"following_count"`
+}
+
+func TestRegisterAndLogin(t *testing.T) {
+	h := newHarness(t)
+	anon := h.anonymous()
+
+	var registered userInfo
+	anon.post("/users/register", gin.H{"username": "alice", "email": "alice@example.com", "password": password}).
+		expect(http.StatusCreated).decode(&registered)
+	if registered.ID == 0 || registered.Username != "alice" {
+		t.Errorf("registered %+v", registered)
+	}
+	anon.post("/users/register", gin.H{"username": "alice2", "email": "alice@example.com", "password": password}).
+		expect(http.StatusConflict)
+	anon.post("/users/register", gin.H{"username": "al", "email": "al@example.com", "password": "weak"}).
+		expectCode(http.StatusBadRequest, "400")
+	anon.post("/users/register", "{").expect(http.StatusBadRequest)
+
+	anon.post("/users/login", gin.H{"email": "alice@example.com", "password": "Wr0ng-pass"}).expect(http.StatusUnauthorized)
+
+	alice := &client{h: h, Email: "alice@example.com"}
+	alice.login()
+	var me userInfo
+	alice.get("/users/me").expect(http.StatusOK).decode(&me)
+	if me.ID != registered.ID || me.Email != "alice@example.com" || me.Role != "client" {
+		t.Errorf("GET /users/me = %+v", me)
+	}
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	h := newHarness(t)
+	alice := h.register("alice")
+
+	h.anonymous().get("/users/me").expect(http.StatusUnauthorized)
+	(&client{h: h, token: "not-a-token"}).get("/users/me").expect(http.StatusUnauthorized)
+
+	// the Bearer prefix is optional
+	h.anonymous().request(http.MethodGet, "/users/me", nil, http.Header{"Authorization": {alice.token}}).
+		expect(http.StatusOK)
+
+	expired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": alice.ID, "role": "client", "exp": time.Now().Add(-time.Hour).Unix(),
+	}).SignedString(jwtSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := (&client{h: h, token: expired}).get("/users/me").expect(http.StatusUnauthorized); res.message() != "token expired" {
+		t.Errorf("expired token message = %q", res.message())
+	}
+
+	alice.get("/admin/users").expect(http.StatusForbidden)
+}
+
+func TestProfile(t *testing.T) {
+	h := newHarness(t)
+	alice := h.register("alice")
+
+	alice.put("/users/me/profile", gin.H{}).expect(http.StatusBadRequest)
+	alice.put("/users/me/profile", gin.H{"display_name": "Alice", "bio": "Writes things", "website": "https://alice.example.com"}).
+		expect(http.StatusOK)
+
+	var profile struct {
+		Username    string  `json:"username"`
+		DisplayName string  `json:"display_name"`
+		Website     string  `json:"website"`
+		Email       *string `json:"email"`
+	}
+	h.anonymous().get("/users/alice").expect(http.StatusOK).decode(&profile)
+	if profile.DisplayName != "Alice" || profile.Website != "https://alice.example.com" || profile.Email != nil {
+		t.Errorf("public profile = %+v", profile)
+	}
+	h.anonymous().get("/users/nobody").expect(http.StatusNotFound)
+}
+
+func TestUpdateMe(t *testing.T) {
+	h := newHarness(t)
+	alice, bob := h.register("alice"), h.register("bob")
+
+	alice.patch("/users/me", gin.H{"username": "bob"}).expect(http.StatusConflict)
+	alice.patch("/users/me", gin.H{"username": "alicia"}).expect(http.StatusOK)
+	h.anonymous().get("/users/alicia").expect(http.StatusOK)
+
+	// changes of address are confirmed from the new one
+	token, err := utils.GenerateEmailChangeToken(jwtSecret, alice.ID, alice.Email, "alicia@example.com", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.anonymous().get("/users/confirm-email?token=" + url.QueryEscape(token)).expect(http.StatusOK)
+	h.anonymous().get("/users/confirm-email?token=invalid").expect(http.StatusBadRequest)
+	var me userInfo
+	alice.get("/users/me").expect(http.StatusOK).decode(&me)
+	if me.Email != "alicia@example.com" {
+		t.Errorf("email after confirming = %q", me.Email)
+	}
+
+	alice.put("/users/change-password", gin.H{"old_password": "Wr0ng-pass!", "new_password": "N3w-passw0rd"}).
+		expect(http.StatusBadRequest)
+	alice.put("/users/change-password", gin.H{"old_password": password, "new_password": "N3w-passw0rd"}).
+		expect(http.StatusOK)
+	h.anonymous().post("/users/login", gin.H{"email": "alicia@example.com", "password": "N3w-passw0rd"}).
+		expect(http.StatusOK)
+
+	bob.delete("/users/me").expect(http.StatusOK)
+	bob.get("/users/me").expect(http.StatusNotFound)
+}
+
+func TestAdminUsers(t *testing.T) {
+	h := newHarness(t)
+	admin, alice := h.registerAdmin("root"), h.register("alice")
+
+	var list struct {
+		Users []userInfo `json:"users"`
+		Meta  utils.Meta `json:"meta"`
+	}
+	admin.get("/admin/users").expect(http.StatusOK).decode(&list)
+	if list.Meta.Total != 2 || len(list.Users) != 2 {
+		t.Errorf("admin user list = %+v", list)
+	}
+	admin.get("/admin/users?page=0").expect(http.StatusNotFound)
+
+	var detail userInfo
+	admin.get(pathf("/admin/users/%d", alice.ID)).expect(http.StatusOK).decode(&detail)
+	if detail.Username != "alice" {
+		t.Errorf("user detail = %+v", detail)
+	}
+	admin.get("/admin/users/999").expect(http.StatusNotFound)
+
+	admin.put(pathf("/admin/users/%d/role", alice.ID), gin.H{"role": "owner"}).expect(http.StatusBadRequest)
+	admin.put(pathf("/admin/users/%d/role", alice.ID), gin.H{"role": "admin"}).expect(http.StatusOK)
+	h.exec("UPDATE users SET can_post = false WHERE id = ?", alice.ID)
+	admin.put(pathf("/admin/users/%d/ban-post", alice.ID), gin.H{"can_post": true}).expect(http.StatusOK)
+
+	var notifications struct {
+		Notifications []struct {
+			Type string `json:"type"`
+		} `json:"notifications"`
+	}
+	alice.get("/users/me/notifications").expect(http.StatusOK).decode(&notifications)
+	types := map[string]bool{}
+	for _, n := range notifications.Notifications {
+		types[n.Type] = true
+	}
+	if !types["role_changed"] || !types["posting_permission_changed"] {
+		t.Errorf("notifications after admin changes = %v", types)
+	}
+
+	admin.delete("/admin/users/999").expect(http.StatusNotFound)
+	admin.delete(pathf("/admin/users/%d", alice.ID)).expect(http.StatusOK)
+	admin.get(pathf("/admin/users/%d", alice.ID)).expect(http.StatusNotFound)
+}
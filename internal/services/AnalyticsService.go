@@ -0,0 +1,113 @@
+package services
+
+import (
+	"blog-api/internal/dto"
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+	"time"
+)
+
+const (
+	defaultStatsDays = 30
+	maxStatsDays     = 365
+	statsReferrers   = 10
+	defaultTopPosts  = 10
+	maxTopPosts      = 100
+)
+
+// AnalyticsService reports post views together with engagement counts. Views still
+// buffered in the ViewCounter show up after its next flush.
+type AnalyticsService struct {
+	viewRepo     repositories.PostViewRepository
+	postRepo     repositories.PostRepository
+	commentRepo  repositories.CommentRepository
+	reactionRepo repositories.ReactionRepository
+}
+
+func NewAnalyticsService(viewRepo repositories.PostViewRepository, postRepo repositories.PostRepository, commentRepo repositories.CommentRepository, reactionRepo repositories.ReactionRepository) *AnalyticsService {
+	return &AnalyticsService{viewRepo: viewRepo, postRepo: postRepo, commentRepo: commentRepo, reactionRepo: reactionRepo}
+}
+
+// PostStats returns the views of a post per day over the last days days, including days
+// without views, with its top referrers and engagement counts.
+func (s *AnalyticsService) PostStats(postID uint, days int) (*dto.PostStatsResponse, error) {
+	if _, err := s.postRepo.FindByID(postID); err != nil {
+		return nil, err
+	}
+	days = clamp(days, defaultStatsDays, maxStatsDays)
+	since := statsSince(days)
+
+	daily, err := s.viewRepo.DailyViews(postID, since)
+	if err != nil {
+		return nil, err
+	}
+	referrers, err := s.viewRepo.TopReferrers(postID, since, statsReferrers)
+	if err != nil {
+		return nil, err
+	}
+	total, err := s.viewRepo.TotalViews(postID)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := s.commentRepo.CountByPostID(postID)
+	if err != nil {
+		return nil, err
+	}
+	reactions, err := s.reactionRepo.Counts(entities.ReactionTargetPost, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.PostStatsResponse{
+		PostID:        postID,
+		Days:          days,
+		TotalViews:    total,
+		Views:         make([]dto.DailyViewsResponse, 0, days),
+		Referrers:     make([]dto.ReferrerViewsResponse, 0, len(referrers)),
+		CommentsCount: comments,
+		Reactions:     dto.NewReactionCounts(reactions),
+	}
+	byDay := make(map[string]int64, len(daily))
+	for _, d := range daily {
+		byDay[d.Day.Format("2006-01-02")] = d.Views
+	}
+	for day := since; len(resp.Views) < days; day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		resp.Views = append(resp.Views, dto.DailyViewsResponse{Date: date, Views: byDay[date]})
+		resp.PeriodViews += byDay[date]
+	}
+	for _, r := range referrers {
+		resp.Referrers = append(resp.Referrers, dto.ReferrerViewsResponse{Referrer: r.Referrer, Views: r.Views})
+	}
+	return resp, nil
+}
+
+// TopPosts returns the most viewed posts of the site over the last days days.
+func (s *AnalyticsService) TopPosts(days, limit int) ([]dto.TopPostResponse, error) {
+	days = clamp(days, defaultStatsDays, maxStatsDays)
+	limit = clamp(limit, defaultTopPosts, maxTopPosts)
+	posts, err := s.viewRepo.TopPosts(statsSince(days), limit)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]dto.TopPostResponse, 0, len(posts))
+	for _, p := range posts {
+		resp = append(resp, dto.TopPostResponse{PostID: p.PostID, Title: p.Title, Slug: p.Slug, Author: p.Username, Views: p.Views})
+	}
+	return resp, nil
+}
+
+// statsSince is the first day (UTC) of a period of days days ending today.
+func statsSince(days int) time.Time {
+	return time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1-days)
+}
+
+func clamp(v, def, max int) int {
+	if v < 1 {
+		return def
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
@@ -4,6 +4,7 @@ import (
 	"blog-api/internal/entities"
 	"blog-api/internal/repositories"
 	"blog-api/pkg/helper"
+	"blog-api/pkg/metrics"
 	"blog-api/pkg/utils"
 
 	// "blog-api/pkg/helper"
@@ -15,14 +16,26 @@ import (
 )
 
 type AuthService struct {
-	userRepo *repositories.UserRepository
+	userRepo  repositories.UserRepository
+	jwtSecret []byte
+	metrics   *metrics.Metrics
 }
 
-func NewAuthService(userRepo *repositories.UserRepository) *AuthService {
-	return &AuthService{userRepo: userRepo}
+func NewAuthService(userRepo repositories.UserRepository, jwtSecret []byte, metrics *metrics.Metrics) *AuthService {
+	return &AuthService{userRepo: userRepo, jwtSecret: jwtSecret, metrics: metrics}
 }
 
 func (s *AuthService) Register(email, password, username string) (*entities.User, error) {
+	user, err := s.CreateUser(email, password, username, "client")
+	if err == nil {
+		s.metrics.UserRegistered()
+	}
+	return user, err
+}
+
+// CreateUser registers an account with the given role. Self-registration always creates
+// clients; other roles are only granted by operators through the CLI.
+func (s *AuthService) CreateUser(email, password, username, role string) (*entities.User, error) {
 	if existing, err := s.userRepo.FindEmail(email); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, err
 	} else if existing != nil {
@@ -44,7 +57,7 @@ func (s *AuthService) Register(email, password, username string) (*entities.User
 		Email:    email,
 		Password: hashedPassword,
 		Username: username,
-		Role:     "client", 
+		Role:     role,
 	}
 
 	if err := s.userRepo.Create(user); err != nil {
@@ -55,6 +68,12 @@ func (s *AuthService) Register(email, password, username string) (*entities.User
 }
 
 func (s *AuthService) Login(email, password string) (*entities.User, string, []string) {
+	user, token, errs := s.login(email, password)
+	s.metrics.LoginAttempted(errs == nil)
+	return user, token, errs
+}
+
+func (s *AuthService) login(email, password string) (*entities.User, string, []string) {
     var errs []string
     user, err := s.userRepo.FindEmail(email)
     if err != nil || user == nil {
@@ -73,7 +92,7 @@ func (s *AuthService) Login(email, password string) (*entities.User, string, []s
         return nil, "", errs
     }
 
-    token, err := utils.GenerateToken(uint(user.ID), user.Role)
+    token, err := utils.GenerateToken(s.jwtSecret, uint(user.ID), user.Role)
 	if err != nil {
 		errs = append(errs, "Failed to generate token")
 		return nil, "", errs
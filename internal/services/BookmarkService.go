@@ -0,0 +1,51 @@
+package services
+
+import (
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+type BookmarkService struct {
+	repo     repositories.BookmarkRepository
+	postRepo repositories.PostRepository
+}
+
+func NewBookmarkService(repo repositories.BookmarkRepository, postRepo repositories.PostRepository) *BookmarkService {
+	return &BookmarkService{repo: repo, postRepo: postRepo}
+}
+
+// AddBookmark saves a published post to the user's reading list, in the named collection
+// when one is given (creating it if needed). Bookmarking again moves the post.
+func (s *BookmarkService) AddBookmark(userID, postID uint, collection string) error {
+	post, err := s.postRepo.FindByID(postID)
+	if err != nil {
+		return err
+	}
+	if post.Status != "published" {
+		return gorm.ErrRecordNotFound
+	}
+
+	bookmark := &entities.Bookmark{UserID: userID, PostID: postID}
+	if collection != "" {
+		c, err := s.repo.FindOrCreateCollection(userID, collection)
+		if err != nil {
+			return err
+		}
+		bookmark.CollectionID = &c.ID
+	}
+	return s.repo.Save(bookmark)
+}
+
+func (s *BookmarkService) RemoveBookmark(userID, postID uint) error {
+	return s.repo.Delete(userID, postID)
+}
+
+func (s *BookmarkService) ListBookmarks(userID uint, collection string, page, pageSize int) ([]entities.Bookmark, int64, error) {
+	return s.repo.ListByUser(userID, collection, page, pageSize)
+}
+
+func (s *BookmarkService) ListCollections(userID uint) ([]entities.BookmarkCollection, error) {
+	return s.repo.ListCollections(userID)
+}
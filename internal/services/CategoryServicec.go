@@ -8,34 +8,43 @@ import (
 )
 
 type CategoryService struct {
-    repo *repositories.CategoryRepository
+    repo repositories.CategoryRepository
 }
 
-func NewCategoryService(repo *repositories.CategoryRepository) *CategoryService {
+func NewCategoryService(repo repositories.CategoryRepository) *CategoryService {
     return &CategoryService{repo: repo}
 }
 
 func (s *CategoryService) CreateCategory(req *dto.CreateCategoryRequest) error {
     category := &entities.Category{
-        Name: req.Name,
-        Slug: req.Slug,
+        Name:            req.Name,
+        Slug:            req.Slug,
+        CommentsEnabled: req.CommentsEnabled,
+    }
+    if req.CommentsAutoCloseDays != nil {
+        category.CommentsAutoCloseDays = *req.CommentsAutoCloseDays
     }
     return s.repo.Create(category)
 }
 
 func (s *CategoryService) UpdateCategory(id uint, req *dto.UpdateCategoryRequest) error {
-    
-    var updated = &entities.Category{}
+    updates := make(map[string]interface{})
     if req.Name != "" {
-        updated.Name = req.Name
+        updates["name"] = req.Name
     }
     if req.Slug != "" {
-        updated.Slug = req.Slug
+        updates["slug"] = req.Slug
+    }
+    if req.CommentsEnabled != nil {
+        updates["comments_enabled"] = *req.CommentsEnabled
     }
-    if updated.Name == "" && updated.Slug == "" {
+    if req.CommentsAutoCloseDays != nil {
+        updates["comments_auto_close_days"] = *req.CommentsAutoCloseDays
+    }
+    if len(updates) == 0 {
         return errors.New("no fields to update")
     }
-    return s.repo.Update(id, updated)
+    return s.repo.Update(id, updates)
 }
 
 func (s *CategoryService) DeleteCategory(id uint) error {
@@ -44,4 +53,17 @@ func (s *CategoryService) DeleteCategory(id uint) error {
 
 func (s *CategoryService) GetAllCategories() ([]entities.Category, error) {
     return s.repo.ListAll()
-}
\ No newline at end of file
+}
+// MergeCategories moves the posts and followers of one category into another and deletes
+// the emptied category. It returns how many posts were moved.
+func (s *CategoryService) MergeCategories(req *dto.MergeCategoriesRequest) (int64, error) {
+    from, err := s.repo.FindBySlug(req.From)
+    if err != nil {
+        return 0, err
+    }
+    into, err := s.repo.FindBySlug(req.Into)
+    if err != nil {
+        return 0, err
+    }
+    return s.repo.Merge(from.ID, into.ID)
+}
@@ -4,33 +4,167 @@ import (
 	"blog-api/internal/dto"
 	"blog-api/internal/entities"
 	"blog-api/internal/repositories"
+	"blog-api/pkg/pubsub"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	EventCommentCreated = "comment.created"
+	EventCommentUpdated = "comment.updated"
+	EventCommentDeleted = "comment.deleted"
+)
+
+var (
+	ErrCommentsLocked  = errors.New("comments are locked for this post")
+	ErrInvalidParent   = errors.New("parent comment does not belong to this post")
+	ErrCommentNotFound = errors.New("comment not found")
+	ErrPostNotFound    = errors.New("post not found")
 )
 
 type CommentService struct {
-	repo *repositories.CommentRepository
+	repo                repositories.CommentRepository
+	postRepo            repositories.PostRepository
+	mentionService      *MentionService
+	notificationService *NotificationService
+	reactionRepo        repositories.ReactionRepository
+	bus                 pubsub.Bus
+}
+
+func NewCommentService(repo repositories.CommentRepository, postRepo repositories.PostRepository, mentionService *MentionService, notificationService *NotificationService, reactionRepo repositories.ReactionRepository, bus pubsub.Bus) *CommentService {
+	return &CommentService{repo: repo, postRepo: postRepo, mentionService: mentionService, notificationService: notificationService, reactionRepo: reactionRepo, bus: bus}
+}
+
+func commentTopic(postID uint) string {
+	return fmt.Sprintf("post:%d:comments", postID)
+}
+
+// publish announces a comment change to stream subscribers of the post.
+func (s *CommentService) publish(postID uint, eventType string, data interface{}) {
+	if err := s.bus.Publish(context.Background(), commentTopic(postID), eventType, data); err != nil {
+		log.Println("publish comment event failed:", err)
+	}
+}
+
+// publishComment reloads the comment so the event carries the same payload as the list endpoint.
+func (s *CommentService) publishComment(id uint, eventType string) {
+	comment, err := s.repo.FindByID(id)
+	if err != nil {
+		log.Println("publish comment event failed:", err)
+		return
+	}
+	s.publish(comment.PostID, eventType, dto.NewCommentResponse(comment))
+}
+
+// SubscribeComments streams comment events of a post, resuming after lastEventID when it is set.
+func (s *CommentService) SubscribeComments(ctx context.Context, postID uint, lastEventID uint64) (<-chan pubsub.Event, error) {
+	if _, err := s.postRepo.FindByID(postID); err != nil {
+		return nil, err
+	}
+	return s.bus.Subscribe(ctx, commentTopic(postID), lastEventID)
 }
 
-func NewCommentService(repo *repositories.CommentRepository) *CommentService {
-	return &CommentService{repo: repo}
+// openPost loads the post comments are written on. A post that does not exist or was
+// deleted is ErrPostNotFound, one whose comments are closed ErrCommentsLocked.
+func (s *CommentService) openPost(postID uint) (*entities.Post, error) {
+	post, err := s.postRepo.FindByID(postID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrPostNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !post.CommentsOpen(time.Now()) {
+		return nil, ErrCommentsLocked
+	}
+	return post, nil
 }
 
 func (s *CommentService) CreateComment(req *dto.CreateCommentRequest, userID uint) error {
+	post, err := s.openPost(req.PostID)
+	if err != nil {
+		return err
+	}
+
+	var parent *entities.Comment
+	if req.ParentID != nil {
+		parent, err = s.repo.FindByID(*req.ParentID)
+		if err != nil || parent.PostID != req.PostID {
+			return ErrInvalidParent
+		}
+	}
+
 	comment := &entities.Comment{
-		PostID:  req.PostID,
-		UserID:  userID,
-		Content: req.Content,
+		PostID:   req.PostID,
+		ParentID: req.ParentID,
+		UserID:   userID,
+		Content:  req.Content,
+	}
+	if err := s.repo.Create(comment); err != nil {
+		return err
+	}
+	s.notificationService.NotifyComment(comment, post, parent, s.syncMentions(comment))
+	s.publishComment(comment.ID, EventCommentCreated)
+	return nil
+}
+
+// syncMentions records the mentions of a comment, returning the new ones.
+func (s *CommentService) syncMentions(comment *entities.Comment) []*entities.Mention {
+	mentions, err := s.mentionService.SyncMentions(entities.MentionSourceComment, comment.ID, comment.UserID, comment.Content)
+	if err != nil {
+		log.Println("sync comment mentions failed:", err)
 	}
-	return s.repo.Create(comment)
+	return mentions
 }
 
 func (s *CommentService) UpdateComment(id uint, content string) error {
-    return s.repo.Update(id, content)
+    comment, err := s.repo.FindByID(id)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        return ErrCommentNotFound
+    }
+    if err != nil {
+        return err
+    }
+    // the preloaded post is empty once the post is deleted
+    if _, err := s.openPost(comment.PostID); err != nil {
+        return err
+    }
+    if err := s.repo.Update(id, content); err != nil {
+        return err
+    }
+    comment.Content = content
+    for _, mention := range s.syncMentions(comment) {
+        s.notificationService.NotifyMention(mention, comment.PostID)
+    }
+    s.publishComment(id, EventCommentUpdated)
+    return nil
 }
 
 func (s *CommentService) DeleteComment(id uint) error {
-    return s.repo.Delete(id)
+    comment, err := s.repo.FindByID(id)
+    if errors.Is(err, gorm.ErrRecordNotFound) {
+        return ErrCommentNotFound
+    }
+    if err != nil {
+        return err
+    }
+    if err := s.repo.Delete(id); err != nil {
+        return err
+    }
+    s.publish(comment.PostID, EventCommentDeleted, dto.DeletedCommentEvent{ID: id, PostID: comment.PostID})
+    return s.mentionService.DeleteMentions(entities.MentionSourceComment, id)
 }
 
 func (s *CommentService) GetCommentsByPostID(postID uint, page, pageSize int) ([]entities.Comment, int64, error) {
     return s.repo.ListByPostID(postID, page, pageSize)
-}
\ No newline at end of file
+}
+
+// ViewerReactions returns the viewer's reaction to each of the given comments they reacted to.
+func (s *CommentService) ViewerReactions(commentIDs []uint, viewerID uint) (map[uint]string, error) {
+    return s.reactionRepo.UserReactions(entities.ReactionTargetComment, commentIDs, viewerID)
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"blog-api/internal/dto"
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories/memory"
+	"blog-api/pkg/pubsub"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+type commentFixture struct {
+	*postFixture
+	comments *CommentService
+	reader   *entities.User
+	post     *entities.Post
+}
+
+// newCommentFixture adds a reader, bob, and a published post by alice to a post fixture.
+func newCommentFixture(t *testing.T) *commentFixture {
+	t.Helper()
+	f := newPostFixture(t)
+	reader := &entities.User{Username: "bob", Email: "bob@example.com", Password: "x", Role: "client"}
+	if err := f.service.userRepo.Create(reader); err != nil {
+		t.Fatal(err)
+	}
+	post := f.create(t, "published", "Hello")
+	bus := pubsub.NewMemoryBus(16)
+	t.Cleanup(func() { bus.Close() })
+	comments := NewCommentService(memory.NewCommentRepository(f.store), f.service.repo, f.service.mentionService,
+		f.notifications, memory.NewReactionRepository(f.store), bus)
+	return &commentFixture{postFixture: f, comments: comments, reader: reader, post: post}
+}
+
+func (f *commentFixture) comment(t *testing.T, userID uint, content string) *entities.Comment {
+	t.Helper()
+	if err := f.comments.CreateComment(&dto.CreateCommentRequest{PostID: f.post.ID, Content: content}, userID); err != nil {
+		t.Fatal(err)
+	}
+	comments, _, err := f.comments.GetCommentsByPostID(f.post.ID, 1, 100)
+	if err != nil || len(comments) == 0 {
+		t.Fatalf("comments = %v, %v", comments, err)
+	}
+	latest := comments[0]
+	for _, c := range comments {
+		if c.ID > latest.ID {
+			latest = c
+		}
+	}
+	return &latest
+}
+
+func TestCommentsOnDeletedPost(t *testing.T) {
+	f := newCommentFixture(t)
+	comment := f.comment(t, uint(f.reader.ID), "First")
+	if err := f.service.DeletePost(f.post.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	err := f.comments.CreateComment(&dto.CreateCommentRequest{PostID: f.post.ID, Content: "Too late"}, uint(f.reader.ID))
+	if !errors.Is(err, ErrPostNotFound) {
+		t.Errorf("commenting on a deleted post = %v; want ErrPostNotFound", err)
+	}
+	if err := f.comments.UpdateComment(comment.ID, "Edited"); !errors.Is(err, ErrPostNotFound) {
+		t.Errorf("editing a comment of a deleted post = %v; want ErrPostNotFound", err)
+	}
+	if err := f.comments.UpdateComment(comment.ID+100, "Edited"); !errors.Is(err, ErrCommentNotFound) {
+		t.Errorf("editing a missing comment = %v; want ErrCommentNotFound", err)
+	}
+	if err := f.comments.DeleteComment(comment.ID + 100); !errors.Is(err, ErrCommentNotFound) {
+		t.Errorf("deleting a missing comment = %v; want ErrCommentNotFound", err)
+	}
+}
+
+func TestCommentUpdatedAt(t *testing.T) {
+	f := newCommentFixture(t)
+	comment := f.comment(t, uint(f.reader.ID), "First")
+	if !comment.UpdatedAt.Equal(comment.CreatedAt) {
+		t.Errorf("new comment updated at %v; want its creation time %v", comment.UpdatedAt, comment.CreatedAt)
+	}
+
+	time.Sleep(time.Millisecond)
+	if err := f.comments.UpdateComment(comment.ID, "Edited"); err != nil {
+		t.Fatal(err)
+	}
+	comments, _, err := f.comments.GetCommentsByPostID(f.post.ID, 1, 100)
+	if err != nil || len(comments) != 1 {
+		t.Fatalf("comments = %v, %v", comments, err)
+	}
+	resp := dto.NewCommentResponse(&comments[0])
+	if !resp.UpdatedAt.After(resp.CreatedAt) {
+		t.Errorf("edited comment: created at %v, updated at %v; want a later update", resp.CreatedAt, resp.UpdatedAt)
+	}
+}
+
+func TestCommentNotifiesEachUserOnce(t *testing.T) {
+	f := newCommentFixture(t)
+	carol := &entities.User{Username: "carol", Email: "carol@example.com", Password: "x", Role: "client"}
+	if err := f.service.userRepo.Create(carol); err != nil {
+		t.Fatal(err)
+	}
+	parent := f.comment(t, uint(f.reader.ID), "First")
+
+	// carol's reply mentions both the parent's author and the post's
+	req := &dto.CreateCommentRequest{PostID: f.post.ID, ParentID: &parent.ID, Content: "@bob @alice agreed"}
+	if err := f.comments.CreateComment(req, uint(carol.ID)); err != nil {
+		t.Fatal(err)
+	}
+	req = &dto.CreateCommentRequest{PostID: f.post.ID, ParentID: &parent.ID, Content: "Me too"}
+	if err := f.comments.CreateComment(req, uint(carol.ID)); err != nil {
+		t.Fatal(err)
+	}
+
+	for user, want := range map[*entities.User][]string{
+		f.author: {entities.NotificationCommentOnPost, entities.NotificationMentioned, entities.NotificationCommentOnPost},
+		f.reader: {entities.NotificationMentioned, entities.NotificationReplyToComment},
+		carol:    nil,
+	} {
+		notifications, _, err := f.notifications.ListNotifications(uint(user.ID), 0, 100, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for i := len(notifications) - 1; i >= 0; i-- {
+			got = append(got, notifications[i].Type)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("%s was notified of %v; want %v", user.Username, got, want)
+		}
+	}
+}
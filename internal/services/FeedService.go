@@ -0,0 +1,119 @@
+package services
+
+import (
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+	"blog-api/pkg/feed"
+	"blog-api/pkg/tracing"
+	"blog-api/pkg/utils"
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+const feedItemCount = 20
+
+// FeedService builds syndication feeds of the latest published posts.
+type FeedService struct {
+	postRepo     repositories.PostRepository
+	categoryRepo repositories.CategoryRepository
+	userRepo     repositories.UserRepository
+	siteURL      string
+	siteTitle    string
+}
+
+func NewFeedService(postRepo repositories.PostRepository, categoryRepo repositories.CategoryRepository, userRepo repositories.UserRepository, siteURL, siteTitle string) *FeedService {
+	return &FeedService{postRepo: postRepo, categoryRepo: categoryRepo, userRepo: userRepo, siteURL: siteURL, siteTitle: siteTitle}
+}
+
+// SiteFeed returns the feed of all posts. feedPath is the path the feed is served at.
+func (s *FeedService) SiteFeed(ctx context.Context, feedPath string) (_ *feed.Feed, err error) {
+	ctx, span := tracing.Start(ctx, "FeedService.SiteFeed")
+	defer func() { tracing.End(span, err) }()
+
+	f := &feed.Feed{
+		Title:       s.siteTitle,
+		Description: "Latest posts from " + s.siteTitle,
+		Link:        s.siteURL + "/",
+	}
+	return s.build(ctx, f, feedPath, repositories.PostFilter{})
+}
+
+func (s *FeedService) CategoryFeed(ctx context.Context, slug, feedPath string) (_ *feed.Feed, err error) {
+	ctx, span := tracing.Start(ctx, "FeedService.CategoryFeed")
+	defer func() { tracing.End(span, err) }()
+
+	category, err := s.categoryRepo.FindBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+	f := &feed.Feed{
+		Title:       category.Name + " - " + s.siteTitle,
+		Description: "Latest posts in " + category.Name,
+		Link:        s.siteURL + "/categories/" + category.Slug,
+	}
+	return s.build(ctx, f, feedPath, repositories.PostFilter{CategoryID: category.ID})
+}
+
+func (s *FeedService) AuthorFeed(ctx context.Context, username, feedPath string) (_ *feed.Feed, err error) {
+	ctx, span := tracing.Start(ctx, "FeedService.AuthorFeed")
+	defer func() { tracing.End(span, err) }()
+
+	user, err := s.userRepo.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	name := user.Username
+	if user.DisplayName != "" {
+		name = user.DisplayName
+	}
+	f := &feed.Feed{
+		Title:       name + " - " + s.siteTitle,
+		Description: "Latest posts by " + name,
+		Link:        s.siteURL + utils.ProfilePath(user.Username),
+	}
+	return s.build(ctx, f, feedPath, repositories.PostFilter{AuthorID: uint(user.ID)})
+}
+
+func (s *FeedService) build(ctx context.Context, f *feed.Feed, feedPath string, filter repositories.PostFilter) (*feed.Feed, error) {
+	filter.Status = "published"
+	filter.ByPublication = true
+	filter.WithoutComments = true
+	posts, _, err := s.postRepo.ListPosts(ctx, filter, 1, feedItemCount)
+	if err != nil {
+		return nil, err
+	}
+	f.FeedURL = s.siteURL + feedPath
+	for i := range posts {
+		f.Items = append(f.Items, s.item(&posts[i]))
+	}
+	return f, nil
+}
+
+func (s *FeedService) item(p *entities.Post) feed.Item {
+	published := p.CreatedAt
+	if p.PublishedAt != nil {
+		published = *p.PublishedAt
+	}
+	item := feed.Item{
+		ID:        fmt.Sprintf("%s/posts/%d", s.siteURL, p.ID),
+		Title:     p.Title,
+		Link:      s.siteURL + "/posts/" + p.Slug,
+		Summary:   p.Excerpt(),
+		Content:   p.Content,
+		Author:    p.Author.Username,
+		AuthorURL: s.siteURL + utils.ProfilePath(p.Author.Username),
+		Category:  p.Category.Name,
+		Image:     p.Thumbnail,
+		Published: published,
+		Updated:   p.UpdatedAt,
+	}
+	if p.Author.DisplayName != "" {
+		item.Author = p.Author.DisplayName
+	}
+	return item
+}
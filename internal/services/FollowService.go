@@ -0,0 +1,68 @@
+package services
+
+import (
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var ErrCannotFollowSelf = errors.New("you cannot follow yourself")
+
+type FollowService struct {
+	repo         repositories.FollowRepository
+	userRepo     repositories.UserRepository
+	categoryRepo repositories.CategoryRepository
+}
+
+func NewFollowService(repo repositories.FollowRepository, userRepo repositories.UserRepository, categoryRepo repositories.CategoryRepository) *FollowService {
+	return &FollowService{repo: repo, userRepo: userRepo, categoryRepo: categoryRepo}
+}
+
+// FollowUser makes followerID follow the author with the given username. Following twice is a no-op.
+func (s *FollowService) FollowUser(followerID uint, username string) error {
+	user, err := s.findUser(username)
+	if err != nil {
+		return err
+	}
+	if uint(user.ID) == followerID {
+		return ErrCannotFollowSelf
+	}
+	return s.repo.Create(&entities.Follow{FollowerID: followerID, TargetType: entities.FollowTargetUser, TargetID: uint(user.ID)})
+}
+
+func (s *FollowService) UnfollowUser(followerID uint, username string) error {
+	user, err := s.findUser(username)
+	if err != nil {
+		return err
+	}
+	return s.repo.Delete(followerID, entities.FollowTargetUser, uint(user.ID))
+}
+
+func (s *FollowService) FollowCategory(followerID uint, slug string) error {
+	category, err := s.categoryRepo.FindBySlug(slug)
+	if err != nil {
+		return err
+	}
+	return s.repo.Create(&entities.Follow{FollowerID: followerID, TargetType: entities.FollowTargetCategory, TargetID: category.ID})
+}
+
+func (s *FollowService) UnfollowCategory(followerID uint, slug string) error {
+	category, err := s.categoryRepo.FindBySlug(slug)
+	if err != nil {
+		return err
+	}
+	return s.repo.Delete(followerID, entities.FollowTargetCategory, category.ID)
+}
+
+func (s *FollowService) findUser(username string) (*entities.User, error) {
+	user, err := s.userRepo.FindByUsername(username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return user, nil
+}
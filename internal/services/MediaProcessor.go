@@ -0,0 +1,210 @@
+package services
+
+import (
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+	"blog-api/pkg/imaging"
+	"blog-api/pkg/storage"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	mediaQueueSize      = 100
+	mediaSweepInterval  = time.Minute
+	mediaClaimStaleness = 10 * time.Minute
+	mediaProcessTimeout = 2 * time.Minute
+)
+
+// MediaProcessor generates the resized variants of uploaded images in the background.
+// Uploads are queued in memory; a periodic sweep of the media table picks up anything the
+// queue dropped or a previous instance left unfinished, and claims in the database keep
+// replicas from processing the same item twice.
+type MediaProcessor struct {
+	repo   repositories.MediaRepository
+	store  storage.Storage
+	widths []int
+
+	queue    chan uint
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+	started  atomic.Bool
+}
+
+func NewMediaProcessor(repo repositories.MediaRepository, store storage.Storage, widths []int) *MediaProcessor {
+	return &MediaProcessor{
+		repo:   repo,
+		store:  store,
+		widths: widths,
+		queue:  make(chan uint, mediaQueueSize),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// Start runs the worker until Stop is called.
+func (p *MediaProcessor) Start() {
+	p.started.Store(true)
+	go p.run()
+}
+
+// Running reports whether the worker was started and has not stopped.
+func (p *MediaProcessor) Running() bool {
+	select {
+	case <-p.done:
+		return false
+	default:
+		return p.started.Load()
+	}
+}
+
+// Stop waits for the item being processed, if any, and stops the worker.
+// Queued items stay pending and are picked up by the next sweep.
+func (p *MediaProcessor) Stop() {
+	p.stopOnce.Do(func() { close(p.stop) })
+	<-p.done
+}
+
+// Enqueue schedules a media item for processing without blocking the caller.
+func (p *MediaProcessor) Enqueue(id uint) {
+	select {
+	case p.queue <- id:
+	default:
+		log.Printf("media queue full, media %d left for the next sweep", id)
+	}
+}
+
+func (p *MediaProcessor) run() {
+	defer close(p.done)
+	ticker := time.NewTicker(mediaSweepInterval)
+	defer ticker.Stop()
+
+	p.sweep()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case id := <-p.queue:
+			p.process(id)
+		case <-ticker.C:
+			p.sweep()
+		}
+	}
+}
+
+func (p *MediaProcessor) sweep() {
+	ids, err := p.repo.ListUnprocessedIDs(mediaClaimStaleness, mediaQueueSize)
+	if err != nil {
+		log.Println("list unprocessed media failed:", err)
+		return
+	}
+	for _, id := range ids {
+		select {
+		case <-p.stop:
+			return
+		default:
+			p.process(id)
+		}
+	}
+}
+
+func (p *MediaProcessor) process(id uint) {
+	claimed, err := p.repo.Claim(id, mediaClaimStaleness)
+	if err != nil || !claimed {
+		if err != nil {
+			log.Printf("claim media %d failed: %v", id, err)
+		}
+		return
+	}
+
+	variants, err := p.generate(id)
+	if err == nil {
+		err = p.repo.CompleteProcessing(id, variants)
+	}
+	if err != nil {
+		log.Printf("process media %d failed: %v", id, err)
+		if failErr := p.repo.FailProcessing(id, err.Error()); failErr != nil {
+			log.Printf("record media %d failure failed: %v", id, failErr)
+		}
+	}
+}
+
+// generate resizes the original to every applicable width and encodes each size in every
+// output format. Animated GIFs are left as they are, since resizing would drop the animation.
+func (p *MediaProcessor) generate(id uint) (variants []entities.MediaVariant, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("image processing panicked: %v", r)
+		}
+	}()
+
+	media, err := p.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if media.ContentType == "image/gif" {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), mediaProcessTimeout)
+	defer cancel()
+
+	original, err := p.store.Get(ctx, media.StorageKey)
+	if err != nil {
+		return nil, fmt.Errorf("read original: %w", err)
+	}
+	data, err := io.ReadAll(original)
+	original.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read original: %w", err)
+	}
+	img, err := imaging.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("decode original: %w", err)
+	}
+
+	base := strings.TrimSuffix(media.StorageKey, path.Ext(media.StorageKey))
+	for _, width := range imaging.VariantWidths(p.widths, img.Bounds().Dx()) {
+		resized := img
+		if width != img.Bounds().Dx() {
+			resized = imaging.Resize(img, width)
+		}
+		for _, format := range imaging.Formats {
+			encoded, contentType, err := imaging.Encode(resized, format)
+			if err != nil {
+				return nil, fmt.Errorf("encode %dw %s: %w", width, format, err)
+			}
+			key := fmt.Sprintf("%s-%dw.%s", base, width, variantExtension(format))
+			if err := p.store.Put(ctx, key, bytes.NewReader(encoded), int64(len(encoded)), contentType); err != nil {
+				return nil, fmt.Errorf("store %dw %s: %w", width, format, err)
+			}
+			variants = append(variants, entities.MediaVariant{
+				MediaID:     media.ID,
+				Width:       width,
+				Height:      resized.Bounds().Dy(),
+				Format:      format,
+				ContentType: contentType,
+				StorageKey:  key,
+				URL:         p.store.URL(key),
+				Size:        int64(len(encoded)),
+			})
+		}
+	}
+	return variants, nil
+}
+
+func variantExtension(format string) string {
+	if format == imaging.FormatJPEG {
+		return "jpg"
+	}
+	return format
+}
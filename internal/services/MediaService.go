@@ -0,0 +1,133 @@
+package services
+
+import (
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+	"blog-api/pkg/imaging"
+	"blog-api/pkg/storage"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrFileTooLarge     = errors.New("file is too large")
+	ErrUnsupportedMedia = errors.New("only JPEG, PNG, GIF and WebP images are accepted")
+	ErrImageTooLarge    = fmt.Errorf("images are limited to %d pixels per side and %d megapixels", imaging.MaxDimension, imaging.MaxPixels/1_000_000)
+)
+
+type MediaService struct {
+	repo     repositories.MediaRepository
+	userRepo repositories.UserRepository
+	store    storage.Storage
+	maxBytes int64
+	processor *MediaProcessor
+}
+
+func NewMediaService(repo repositories.MediaRepository, userRepo repositories.UserRepository, store storage.Storage, maxBytes int64, processor *MediaProcessor) *MediaService {
+	return &MediaService{repo: repo, userRepo: userRepo, store: store, maxBytes: maxBytes, processor: processor}
+}
+
+// MaxBytes is the largest upload accepted.
+func (s *MediaService) MaxBytes() int64 {
+	return s.maxBytes
+}
+
+// Upload checks that r holds a supported image no larger than the limit, strips its
+// metadata, stores it, adds it to the owner's media library and queues its variants. The
+// type is sniffed from the content; the client's file name is only kept for display.
+func (s *MediaService) Upload(ctx context.Context, ownerID uint, filename string, r io.Reader) (*entities.Media, error) {
+	data, err := io.ReadAll(io.LimitReader(r, s.maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.maxBytes {
+		return nil, ErrFileTooLarge
+	}
+	contentType, width, height, err := imaging.Inspect(data)
+	if errors.Is(err, imaging.ErrTooManyPixels) {
+		return nil, ErrImageTooLarge
+	}
+	if err != nil {
+		return nil, ErrUnsupportedMedia
+	}
+	data, err = imaging.StripMetadata(contentType, data)
+	if err != nil {
+		return nil, ErrUnsupportedMedia
+	}
+
+	key, err := newMediaKey(imaging.Extensions[contentType])
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		return nil, err
+	}
+
+	if len(filename) > 255 {
+		filename = filename[:255]
+	}
+	media := &entities.Media{
+		OwnerID:      ownerID,
+		StorageKey:   key,
+		URL:          s.store.URL(key),
+		ContentType:  contentType,
+		Size:         int64(len(data)),
+		Width:        width,
+		Height:       height,
+		OriginalName: filename,
+	}
+	if err := s.repo.Create(media); err != nil {
+		if delErr := s.store.Delete(ctx, key); delErr != nil {
+			log.Println("remove orphaned upload failed:", delErr)
+		}
+		return nil, err
+	}
+	s.processor.Enqueue(media.ID)
+	return media, nil
+}
+
+// UploadAvatar uploads an image to the user's media library and makes it their avatar.
+func (s *MediaService) UploadAvatar(ctx context.Context, userID uint, filename string, r io.Reader) (*entities.Media, error) {
+	media, err := s.Upload(ctx, userID, filename, r)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.UpdateProfile(userID, map[string]interface{}{"avatar_url": media.URL}); err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+// GetMedia returns one of the owner's media items with its variants and processing status.
+func (s *MediaService) GetMedia(ownerID, id uint) (*entities.Media, error) {
+	media, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if media.OwnerID != ownerID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return media, nil
+}
+
+func (s *MediaService) ListMedia(ownerID uint, page, pageSize int) ([]entities.Media, int64, error) {
+	return s.repo.ListByOwner(ownerID, page, pageSize)
+}
+
+// newMediaKey returns a random, unguessable storage key grouped by month.
+func newMediaKey(ext string) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "media/" + time.Now().UTC().Format("2006/01") + "/" + hex.EncodeToString(buf) + ext, nil
+}
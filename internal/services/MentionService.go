@@ -0,0 +1,82 @@
+package services
+
+import (
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+	"blog-api/pkg/utils"
+)
+
+type MentionService struct {
+	repo     repositories.MentionRepository
+	userRepo repositories.UserRepository
+}
+
+func NewMentionService(repo repositories.MentionRepository, userRepo repositories.UserRepository) *MentionService {
+	return &MentionService{repo: repo, userRepo: userRepo}
+}
+
+// SyncMentions brings the stored mentions of a post or comment in line with its content,
+// returning the mentions of users that were not mentioned before, for the caller to
+// notify; mentions that were removed and later added back are restored silently. Unknown users, blocked users and self-mentions
+// are ignored. The only block this API has is the admin ban from posting (PUT
+// /users/{id}/ban-post, which clears CanPost), so a user is blocked when CanPost is false.
+func (s *MentionService) SyncMentions(sourceType string, sourceID, authorID uint, content string) ([]*entities.Mention, error) {
+	var wanted []*entities.User
+	wantedIDs := make(map[uint]bool)
+	for _, username := range utils.ExtractMentions(content) {
+		user, err := s.userRepo.FindByUsername(username)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil || !user.CanPost || uint(user.ID) == authorID {
+			continue
+		}
+		wanted = append(wanted, user)
+		wantedIDs[uint(user.ID)] = true
+	}
+
+	existing, err := s.repo.ListBySource(sourceType, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[uint]entities.Mention)
+	var removed []uint
+	for _, m := range existing {
+		known[m.MentionedUserID] = m
+		if !wantedIDs[m.MentionedUserID] && !m.DeletedAt.Valid {
+			removed = append(removed, m.ID)
+		}
+	}
+	if err := s.repo.Delete(removed); err != nil {
+		return nil, err
+	}
+
+	var created []*entities.Mention
+	for _, user := range wanted {
+		if m, ok := known[uint(user.ID)]; ok {
+			if m.DeletedAt.Valid {
+				if err := s.repo.Restore(m.ID); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		mention := &entities.Mention{
+			SourceType:      sourceType,
+			SourceID:        sourceID,
+			MentionedUserID: uint(user.ID),
+			AuthorID:        authorID,
+		}
+		if err := s.repo.Create(mention); err != nil {
+			return nil, err
+		}
+		mention.MentionedUser = *user
+		created = append(created, mention)
+	}
+	return created, nil
+}
+
+func (s *MentionService) DeleteMentions(sourceType string, sourceID uint) error {
+	return s.repo.DeleteBySource(sourceType, sourceID)
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+)
+
+const (
+	defaultNotificationLimit = 20
+	maxNotificationLimit     = 100
+)
+
+type NotificationService struct {
+	repo repositories.NotificationRepository
+}
+
+func NewNotificationService(repo repositories.NotificationRepository) *NotificationService {
+	return &NotificationService{repo: repo}
+}
+
+// Notify stores a notification unless the recipient triggered it themselves
+// or has turned off notifications of that type.
+func (s *NotificationService) Notify(notification *entities.Notification) error {
+	if notification.ActorID != nil && *notification.ActorID == notification.UserID {
+		return nil
+	}
+	prefs, err := s.GetPreferences(notification.UserID)
+	if err != nil {
+		return err
+	}
+	if !prefs[notification.Type] {
+		return nil
+	}
+	return s.repo.Create(notification)
+}
+
+// notify is Notify for callers that should not fail because a notification could not be stored.
+func (s *NotificationService) notify(notification *entities.Notification) {
+	if err := s.Notify(notification); err != nil {
+		log.Println("create notification failed:", err)
+	}
+}
+
+// NotifyComment tells the users mentioned in a new comment, the parent comment's author
+// about a reply and the post author about the comment. Each of them gets one notification,
+// the first that applies of a mention, a reply and a comment on their post.
+func (s *NotificationService) NotifyComment(comment *entities.Comment, post *entities.Post, parent *entities.Comment, mentions []*entities.Mention) {
+	notified := make(map[uint]bool)
+	for _, mention := range mentions {
+		notified[mention.MentionedUserID] = true
+		s.notify(mentionNotification(mention, post.ID))
+	}
+	if parent != nil && !notified[parent.UserID] {
+		notified[parent.UserID] = true
+		s.notify(&entities.Notification{
+			UserID:    parent.UserID,
+			ActorID:   &comment.UserID,
+			Type:      entities.NotificationReplyToComment,
+			PostID:    &post.ID,
+			CommentID: &comment.ID,
+			Message:   fmt.Sprintf("replied to your comment on %q", post.Title),
+		})
+	}
+	if !notified[post.AuthorID] {
+		s.notify(&entities.Notification{
+			UserID:    post.AuthorID,
+			ActorID:   &comment.UserID,
+			Type:      entities.NotificationCommentOnPost,
+			PostID:    &post.ID,
+			CommentID: &comment.ID,
+			Message:   fmt.Sprintf("commented on your post %q", post.Title),
+		})
+	}
+}
+
+// NotifyMention tells a user they were mentioned in a post, or in a comment under it.
+func (s *NotificationService) NotifyMention(mention *entities.Mention, postID uint) {
+	s.notify(mentionNotification(mention, postID))
+}
+
+func mentionNotification(mention *entities.Mention, postID uint) *entities.Notification {
+	notification := &entities.Notification{
+		UserID:  mention.MentionedUserID,
+		ActorID: &mention.AuthorID,
+		Type:    entities.NotificationMentioned,
+		PostID:  &postID,
+		Message: "mentioned you in a post",
+	}
+	if mention.SourceType == entities.MentionSourceComment {
+		notification.CommentID = &mention.SourceID
+		notification.Message = "mentioned you in a comment"
+	}
+	return notification
+}
+
+func (s *NotificationService) NotifyPostApproved(post *entities.Post, actorID uint) {
+	s.notify(&entities.Notification{
+		UserID:  post.AuthorID,
+		ActorID: &actorID,
+		Type:    entities.NotificationPostApproved,
+		PostID:  &post.ID,
+		Message: fmt.Sprintf("approved and published your post %q", post.Title),
+	})
+}
+
+func (s *NotificationService) NotifyRoleChanged(userID uint, role string) {
+	s.notify(&entities.Notification{
+		UserID:  userID,
+		Type:    entities.NotificationRoleChanged,
+		Message: fmt.Sprintf("your role was changed to %s", role),
+	})
+}
+
+func (s *NotificationService) NotifyPostingPermissionChanged(userID uint, canPost bool) {
+	message := "you can no longer create posts"
+	if canPost {
+		message = "you can create posts again"
+	}
+	s.notify(&entities.Notification{
+		UserID:  userID,
+		Type:    entities.NotificationPostingPermissionChanged,
+		Message: message,
+	})
+}
+
+// ListNotifications returns a page of the user's notifications and the cursor of the next page,
+// which is zero when there are no more.
+func (s *NotificationService) ListNotifications(userID, cursor uint, limit int, unreadOnly bool) ([]entities.Notification, uint, error) {
+	if limit < 1 {
+		limit = defaultNotificationLimit
+	}
+	if limit > maxNotificationLimit {
+		limit = maxNotificationLimit
+	}
+	notifications, err := s.repo.ListByUser(userID, cursor, limit+1, unreadOnly)
+	if err != nil {
+		return nil, 0, err
+	}
+	var next uint
+	if len(notifications) > limit {
+		notifications = notifications[:limit]
+		next = notifications[limit-1].ID
+	}
+	return notifications, next, nil
+}
+
+func (s *NotificationService) CountUnread(userID uint) (int64, error) {
+	return s.repo.CountUnread(userID)
+}
+
+func (s *NotificationService) MarkRead(userID, id uint) error {
+	return s.repo.MarkRead(userID, id)
+}
+
+func (s *NotificationService) MarkAllRead(userID uint) error {
+	return s.repo.MarkAllRead(userID)
+}
+
+// GetPreferences returns whether each notification type is enabled for the user.
+func (s *NotificationService) GetPreferences(userID uint) (map[string]bool, error) {
+	stored, err := s.repo.ListPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+	prefs := make(map[string]bool, len(entities.NotificationTypes))
+	for _, t := range entities.NotificationTypes {
+		prefs[t] = true
+	}
+	for _, p := range stored {
+		prefs[p.Type] = p.Enabled
+	}
+	return prefs, nil
+}
+
+func (s *NotificationService) UpdatePreferences(userID uint, updates map[string]bool) error {
+	var prefs []entities.NotificationPreference
+	for t, enabled := range updates {
+		if !slices.Contains(entities.NotificationTypes, t) {
+			return errors.New("unknown notification type: " + t)
+		}
+		prefs = append(prefs, entities.NotificationPreference{UserID: userID, Type: t, Enabled: enabled})
+	}
+	return s.repo.SavePreferences(prefs)
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"blog-api/internal/dto"
+	"blog-api/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// PostMetaService builds the SEO and social metadata embedded in public post pages.
+type PostMetaService struct {
+	postRepo  repositories.PostRepository
+	siteURL   string
+	siteTitle string
+}
+
+func NewPostMetaService(postRepo repositories.PostRepository, siteURL, siteTitle string) *PostMetaService {
+	return &PostMetaService{postRepo: postRepo, siteURL: siteURL, siteTitle: siteTitle}
+}
+
+// PostMeta returns the metadata of a published post. Drafts have no public page, so they
+// are reported as not found.
+func (s *PostMetaService) PostMeta(id uint) (*dto.PostMetaResponse, error) {
+	post, err := s.postRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if post.Status != "published" {
+		return nil, gorm.ErrRecordNotFound
+	}
+	meta, err := dto.NewPostMetaResponse(post, s.siteURL, s.siteTitle)
+	if err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
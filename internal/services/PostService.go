@@ -4,19 +4,61 @@ import (
 	"blog-api/internal/dto"
 	"blog-api/internal/entities"
 	"blog-api/internal/repositories"
+	"blog-api/pkg/pubsub"
+	"blog-api/pkg/tracing"
 
 	// "blog-api/pkg/utils"
+	"context"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+    defaultFeedLimit = 20
+    maxFeedLimit     = 100
+)
+
+// PostsTopic carries an event whenever a post is published, updated or deleted, so caches
+// built from posts can be dropped.
+const PostsTopic = "posts"
+
+const (
+    EventPostPublished = "post.published"
+    EventPostUpdated   = "post.updated"
+    EventPostDeleted   = "post.deleted"
+)
+
+var (
+    ErrInvalidCursor         = errors.New("invalid cursor")
+    ErrInvalidThumbnailMedia = errors.New("thumbnail media not found in the author's media library")
 )
 
 type PostService struct {
-	repo *repositories.PostRepository
-	categoryRepo   *repositories.CategoryRepository
-	userRepo     *repositories.UserRepository
+	repo repositories.PostRepository
+	categoryRepo   repositories.CategoryRepository
+	userRepo     repositories.UserRepository
+	mentionService *MentionService
+	notificationService *NotificationService
+	reactionRepo repositories.ReactionRepository
+	bookmarkRepo repositories.BookmarkRepository
+	mediaRepo repositories.MediaRepository
+	bus pubsub.Bus
+}
+
+func NewPostService(repo repositories.PostRepository, categoryRepo repositories.CategoryRepository, userRepo repositories.UserRepository, mentionService *MentionService, notificationService *NotificationService, reactionRepo repositories.ReactionRepository, bookmarkRepo repositories.BookmarkRepository, mediaRepo repositories.MediaRepository, bus pubsub.Bus) *PostService {
+    return &PostService{repo: repo, categoryRepo: categoryRepo, userRepo: userRepo, mentionService: mentionService, notificationService: notificationService, reactionRepo: reactionRepo, bookmarkRepo: bookmarkRepo, mediaRepo: mediaRepo, bus: bus}
 }
 
-func NewPostService(repo *repositories.PostRepository, categoryRepo *repositories.CategoryRepository, userRepo *repositories.UserRepository) *PostService {
-    return &PostService{repo: repo, categoryRepo: categoryRepo, userRepo: userRepo}
+// publish announces a post change on PostsTopic.
+func (s *PostService) publish(postID uint, eventType string) {
+    if err := s.bus.Publish(context.Background(), PostsTopic, eventType, map[string]uint{"id": postID}); err != nil {
+        log.Println("publish post event failed:", err)
+    }
 }
 
 func (s *PostService) CategoryExists(id uint) (bool, error) {
@@ -37,6 +79,11 @@ func (s *PostService) CreatePost(req *dto.CreatePostRequest, authorID uint) erro
         return errors.New("you have been blocked from posting")
     }
 
+    category, err := s.categoryRepo.FindByID(req.CategoryID)
+    if err != nil {
+        return errors.New("category does not exist")
+    }
+
     post := &entities.Post{
         Title:      req.Title,
         Slug:       req.Slug,
@@ -45,11 +92,79 @@ func (s *PostService) CreatePost(req *dto.CreatePostRequest, authorID uint) erro
         CategoryID: req.CategoryID,
         AuthorID:   authorID,
         Status:     req.Status,
+        CommentsEnabled: category.CommentsEnabled,
+        Summary:         req.Summary,
+        MetaTitle:       req.MetaTitle,
+        MetaDescription: req.MetaDescription,
+        CanonicalURL:    req.CanonicalURL,
+        OGImage:         req.OGImage,
+        Noindex:         req.Noindex,
+    }
+    if req.CommentsEnabled != nil {
+        post.CommentsEnabled = req.CommentsEnabled
+    }
+    if req.ThumbnailMediaID != nil {
+        media, err := s.thumbnailMedia(*req.ThumbnailMediaID, authorID)
+        if err != nil {
+            return err
+        }
+        post.ThumbnailMediaID = &media.ID
+        post.Thumbnail = media.URL
+    }
+    if post.Status == "published" {
+        now := time.Now()
+        post.PublishedAt = &now
+        post.CommentsLockedAt = autoCloseTime(category, now)
     }
-    return s.repo.Create(post)
+    if err := s.repo.Create(post); err != nil {
+        return err
+    }
+    s.syncMentions(post)
+    if post.Status == "published" {
+        s.publish(post.ID, EventPostPublished)
+    }
+    return nil
 }
 
-func (s *PostService) UpdatePost(id uint, req *dto.UpdatePostRequest) error {
+// syncMentions records the mentions of a published post. Drafts are skipped so nobody
+// is notified about content they cannot read yet; mentions are picked up on publish.
+func (s *PostService) syncMentions(post *entities.Post) {
+    if post.Status != "published" {
+        return
+    }
+    mentions, err := s.mentionService.SyncMentions(entities.MentionSourcePost, post.ID, post.AuthorID, post.Content)
+    if err != nil {
+        log.Println("sync post mentions failed:", err)
+    }
+    for _, mention := range mentions {
+        s.notificationService.NotifyMention(mention, post.ID)
+    }
+}
+
+// thumbnailMedia looks up a media item to use as a post thumbnail. Only images from the
+// post author's own library may be used.
+func (s *PostService) thumbnailMedia(mediaID, authorID uint) (*entities.Media, error) {
+    media, err := s.mediaRepo.FindByID(mediaID)
+    if err != nil || media.OwnerID != authorID {
+        return nil, ErrInvalidThumbnailMedia
+    }
+    return media, nil
+}
+
+// autoCloseTime returns when comments on a post published at publishedAt
+// should lock, or nil if the category does not auto-close comments.
+func autoCloseTime(category *entities.Category, publishedAt time.Time) *time.Time {
+    if category.CommentsAutoCloseDays <= 0 {
+        return nil
+    }
+    lockAt := publishedAt.AddDate(0, 0, category.CommentsAutoCloseDays)
+    return &lockAt
+}
+
+// UpdatePost applies the changes made by actorID. Publishing somebody else's post
+// counts as approving it and notifies the author. Publishing a post or moving it to
+// another category recomputes when its comments close automatically.
+func (s *PostService) UpdatePost(id uint, req *dto.UpdatePostRequest, actorID uint) error {
     updates := make(map[string]interface{})
     if req.Title != nil {
         updates["title"] = *req.Title
@@ -62,6 +177,19 @@ func (s *PostService) UpdatePost(id uint, req *dto.UpdatePostRequest) error {
     }
     if req.Thumbnail != nil {
         updates["thumbnail"] = *req.Thumbnail
+        updates["thumbnail_media_id"] = nil
+    }
+    if req.ThumbnailMediaID != nil {
+        post, err := s.repo.FindByID(id)
+        if err != nil {
+            return err
+        }
+        media, err := s.thumbnailMedia(*req.ThumbnailMediaID, post.AuthorID)
+        if err != nil {
+            return err
+        }
+        updates["thumbnail"] = media.URL
+        updates["thumbnail_media_id"] = media.ID
     }
     if req.CategoryID != nil {
         updates["category_id"] = *req.CategoryID
@@ -76,22 +204,200 @@ func (s *PostService) UpdatePost(id uint, req *dto.UpdatePostRequest) error {
     if req.Status != nil {
         updates["status"] = *req.Status
     }
+    if req.CommentsEnabled != nil {
+        updates["comments_enabled"] = *req.CommentsEnabled
+    }
+    if req.Summary != nil {
+        updates["summary"] = *req.Summary
+    }
+    if req.MetaTitle != nil {
+        updates["meta_title"] = *req.MetaTitle
+    }
+    if req.MetaDescription != nil {
+        updates["meta_description"] = *req.MetaDescription
+    }
+    if req.CanonicalURL != nil {
+        updates["canonical_url"] = *req.CanonicalURL
+    }
+    if req.OGImage != nil {
+        updates["og_image"] = *req.OGImage
+    }
+    if req.Noindex != nil {
+        updates["noindex"] = *req.Noindex
+    }
 
     if len(updates) == 0 {
         return errors.New("no fields to update")
     }
 
+    var approved *entities.Post
+    publishing := req.Status != nil && *req.Status == "published"
+    if publishing || req.CategoryID != nil {
+        post, err := s.repo.FindByID(id)
+        if err != nil {
+            return err
+        }
+        publishedAt := post.PublishedAt
+        if publishing && post.PublishedAt == nil {
+            if post.AuthorID != actorID {
+                approved = post
+            }
+            now := time.Now()
+            updates["published_at"] = now
+            publishedAt = &now
+        }
+        categoryID := post.CategoryID
+        if req.CategoryID != nil {
+            categoryID = *req.CategoryID
+        }
+        // the automatic lock follows the publication date and the category's policy;
+        // disabled comments keep the date they were locked at
+        _, published := updates["published_at"]
+        disabled := post.CommentsEnabled != nil && !*post.CommentsEnabled && req.CommentsEnabled == nil
+        if publishedAt != nil && (published || categoryID != post.CategoryID) && !disabled {
+            category, err := s.categoryRepo.FindByID(categoryID)
+            if err != nil {
+                return err
+            }
+            updates["comments_locked_at"] = nil
+            if lockAt := autoCloseTime(category, *publishedAt); lockAt != nil {
+                updates["comments_locked_at"] = *lockAt
+            }
+        }
+    }
+
+    if err := s.repo.Update(id, updates); err != nil {
+        return err
+    }
+    if _, ok := updates["published_at"]; ok {
+        s.publish(id, EventPostPublished)
+    } else {
+        s.publish(id, EventPostUpdated)
+    }
+    if approved != nil {
+        s.notificationService.NotifyPostApproved(approved, actorID)
+    }
+
+    if req.Content != nil || req.Status != nil {
+        post, err := s.repo.FindByID(id)
+        if err != nil {
+            return err
+        }
+        s.syncMentions(post)
+    }
+    return nil
+}
+
+// SetCommentsLocked freezes or reopens the comment section of a post.
+func (s *PostService) SetCommentsLocked(id uint, locked bool) error {
+    if _, err := s.repo.FindByID(id); err != nil {
+        return err
+    }
+    updates := map[string]interface{}{
+        "comments_enabled":   !locked,
+        "comments_locked_at": nil,
+    }
+    if locked {
+        updates["comments_locked_at"] = time.Now()
+    }
     return s.repo.Update(id, updates)
 }
 
 func (s *PostService) DeletePost(id uint) error {
-    return s.repo.Delete(id)
+    if err := s.repo.Delete(id); err != nil {
+        return err
+    }
+    s.publish(id, EventPostDeleted)
+    return s.mentionService.DeleteMentions(entities.MentionSourcePost, id)
 }
 
 func (s *PostService) GetPostByID(id uint) (*entities.Post, error) {
     return s.repo.FindByID(id)
 }
 
-func (s *PostService) ListPosts(title, content, category, author, status string, page, pageSize int) ([]entities.Post, int64, error) {
-    return s.repo.ListPosts(title, content, category, author, status, page, pageSize)
-}
\ No newline at end of file
+func (s *PostService) ListPosts(ctx context.Context, title, content, category, author, status string, page, pageSize int) (posts []entities.Post, total int64, err error) {
+    ctx, span := tracing.Start(ctx, "PostService.ListPosts")
+    defer func() { tracing.End(span, err) }()
+
+    filter := repositories.PostFilter{Title: title, Content: content, Category: category, Author: author, Status: status}
+    return s.repo.ListPosts(ctx, filter, page, pageSize)
+}
+
+// ListPostsByAuthor returns a page of the published posts written by the user with the given username.
+func (s *PostService) ListPostsByAuthor(ctx context.Context, username string, page, pageSize int) (posts []entities.Post, total int64, err error) {
+    ctx, span := tracing.Start(ctx, "PostService.ListPostsByAuthor")
+    defer func() { tracing.End(span, err) }()
+
+    user, err := s.userRepo.FindByUsername(username)
+    if err != nil {
+        return nil, 0, err
+    }
+    if user == nil {
+        return nil, 0, gorm.ErrRecordNotFound
+    }
+    return s.repo.ListPublishedByAuthor(ctx, uint(user.ID), page, pageSize)
+}
+
+// ViewerReactions returns the viewer's reaction to each of the given posts they reacted to.
+func (s *PostService) ViewerReactions(postIDs []uint, viewerID uint) (map[uint]string, error) {
+    return s.reactionRepo.UserReactions(entities.ReactionTargetPost, postIDs, viewerID)
+}
+
+// ViewerBookmarks reports which of the given posts the viewer has bookmarked.
+func (s *PostService) ViewerBookmarks(postIDs []uint, viewerID uint) (map[uint]bool, error) {
+    return s.bookmarkRepo.BookmarkedPostIDs(viewerID, postIDs)
+}
+
+// Feed returns a page of the user's home feed and the cursor of the next page, which is
+// empty when there are no more posts.
+func (s *PostService) Feed(ctx context.Context, userID uint, cursor string, limit int) (posts []entities.Post, next string, err error) {
+    ctx, span := tracing.Start(ctx, "PostService.Feed")
+    defer func() { tracing.End(span, err) }()
+
+    if limit < 1 {
+        limit = defaultFeedLimit
+    }
+    if limit > maxFeedLimit {
+        limit = maxFeedLimit
+    }
+    var before *time.Time
+    var beforeID uint
+    if cursor != "" {
+        t, id, err := decodeFeedCursor(cursor)
+        if err != nil {
+            return nil, "", ErrInvalidCursor
+        }
+        before, beforeID = &t, id
+    }
+
+    posts, err = s.repo.ListFeed(ctx, userID, before, beforeID, limit+1)
+    if err != nil {
+        return nil, "", err
+    }
+    if len(posts) > limit {
+        posts = posts[:limit]
+        last := posts[limit-1]
+        next = encodeFeedCursor(*last.PublishedAt, last.ID)
+    }
+    return posts, next, nil
+}
+
+// Feed cursors are the publication time and ID of the last post on the page; posts can share
+// a timestamp, so the ID breaks ties.
+func encodeFeedCursor(publishedAt time.Time, id uint) string {
+    raw := fmt.Sprintf("%d:%d", publishedAt.UnixNano(), id)
+    return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeFeedCursor(cursor string) (time.Time, uint, error) {
+    raw, err := base64.RawURLEncoding.DecodeString(cursor)
+    if err != nil {
+        return time.Time{}, 0, err
+    }
+    var nanos int64
+    var id uint
+    if _, err := fmt.Sscanf(string(raw), "%d:%d", &nanos, &id); err != nil {
+        return time.Time{}, 0, err
+    }
+    return time.Unix(0, nanos), id, nil
+}
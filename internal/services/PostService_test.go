@@ -0,0 +1,209 @@
+package services
+
+import (
+	"blog-api/internal/dto"
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+	"blog-api/internal/repositories/memory"
+	"blog-api/pkg/pubsub"
+	"context"
+	"testing"
+	"time"
+)
+
+type postFixture struct {
+	store         *memory.Store
+	service       *PostService
+	notifications *NotificationService
+	events        <-chan pubsub.Event
+	author, admin *entities.User
+	category      *entities.Category
+}
+
+func newPostFixture(t *testing.T) *postFixture {
+	t.Helper()
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	categories := memory.NewCategoryRepository(store)
+	notifications := NewNotificationService(memory.NewNotificationRepository(store))
+	bus := pubsub.NewMemoryBus(16)
+	t.Cleanup(func() { bus.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	events, err := bus.Subscribe(ctx, PostsTopic, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := &postFixture{
+		store: store,
+		service: NewPostService(memory.NewPostRepository(store), categories, users,
+			NewMentionService(memory.NewMentionRepository(store), users), notifications,
+			memory.NewReactionRepository(store), memory.NewBookmarkRepository(store), memory.NewMediaRepository(store), bus),
+		notifications: notifications,
+		events:        events,
+		author:        &entities.User{Username: "alice", Email: "alice@example.com", Password: "x", Role: "client"},
+		admin:         &entities.User{Username: "root", Email: "root@example.com", Password: "x", Role: "admin"},
+		category:      &entities.Category{Name: "News", Slug: "news", CommentsAutoCloseDays: 7},
+	}
+	for _, u := range []*entities.User{f.author, f.admin} {
+		if err := users.Create(u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := categories.Create(f.category); err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+// event waits for the next post event.
+func (f *postFixture) event(t *testing.T) string {
+	t.Helper()
+	select {
+	case ev := <-f.events:
+		return ev.Type
+	case <-time.After(time.Second):
+		t.Fatal("no post event published")
+		return ""
+	}
+}
+
+func (f *postFixture) create(t *testing.T, status, content string) *entities.Post {
+	t.Helper()
+	err := f.service.CreatePost(&dto.CreatePostRequest{
+		Title: "Hello", Slug: "hello", Content: content, Thumbnail: "https://example.com/a.png",
+		CategoryID: f.category.ID, Status: status,
+	}, uint(f.author.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	posts, _, err := f.service.repo.ListPosts(context.Background(), repositories.PostFilter{}, 1, 1)
+	if err != nil || len(posts) != 1 {
+		t.Fatalf("created post not listed: %v", err)
+	}
+	return &posts[0]
+}
+
+func TestCreatePublishedPost(t *testing.T) {
+	f := newPostFixture(t)
+	post := f.create(t, "published", "Hi @root")
+
+	if post.PublishedAt == nil || post.CommentsLockedAt == nil || post.CommentsLockedAt.Sub(*post.PublishedAt) != 7*24*time.Hour {
+		t.Errorf("published at %v, comments lock at %v; want comments to close a week after publishing", post.PublishedAt, post.CommentsLockedAt)
+	}
+	if ev := f.event(t); ev != EventPostPublished {
+		t.Errorf("event = %q; want %q", ev, EventPostPublished)
+	}
+	if n, _ := f.notifications.CountUnread(uint(f.admin.ID)); n != 1 {
+		t.Errorf("mentioned user has %d notifications; want 1", n)
+	}
+}
+
+func TestCreatePostBlockedAuthor(t *testing.T) {
+	f := newPostFixture(t)
+	if err := f.service.userRepo.UpdateCanPost(uint(f.author.ID), false); err != nil {
+		t.Fatal(err)
+	}
+	err := f.service.CreatePost(&dto.CreatePostRequest{Title: "Hello", Slug: "hello", Content: "x", CategoryID: f.category.ID, Status: "draft"}, uint(f.author.ID))
+	if err == nil {
+		t.Fatal("blocked author created a post")
+	}
+	if exists, _ := f.service.IsSlugExists("hello"); exists {
+		t.Error("post stored for a blocked author")
+	}
+}
+
+func TestApproveDraft(t *testing.T) {
+	f := newPostFixture(t)
+	post := f.create(t, "draft", "Hi @root")
+	if n, _ := f.notifications.CountUnread(uint(f.admin.ID)); n != 0 {
+		t.Errorf("draft mentions notified %d times; want none before publishing", n)
+	}
+
+	published := "published"
+	if err := f.service.UpdatePost(post.ID, &dto.UpdatePostRequest{Status: &published}, uint(f.admin.ID)); err != nil {
+		t.Fatal(err)
+	}
+	if ev := f.event(t); ev != EventPostPublished {
+		t.Errorf("event = %q; want %q", ev, EventPostPublished)
+	}
+	notifications, _, err := f.notifications.ListNotifications(uint(f.author.ID), 0, 10, false)
+	if err != nil || len(notifications) != 1 || notifications[0].Type != entities.NotificationPostApproved {
+		t.Errorf("author notifications = %+v, %v; want the approval", notifications, err)
+	}
+	if n, _ := f.notifications.CountUnread(uint(f.admin.ID)); n != 1 {
+		t.Errorf("mentioned user has %d notifications after publishing; want 1", n)
+	}
+
+	// editing a published post is an update, not a second publication
+	title := "Hello again"
+	if err := f.service.UpdatePost(post.ID, &dto.UpdatePostRequest{Title: &title}, uint(f.author.ID)); err != nil {
+		t.Fatal(err)
+	}
+	if ev := f.event(t); ev != EventPostUpdated {
+		t.Errorf("event = %q; want %q", ev, EventPostUpdated)
+	}
+}
+
+func TestDeletePost(t *testing.T) {
+	f := newPostFixture(t)
+	post := f.create(t, "published", "Hi @root")
+	f.event(t)
+
+	if err := f.service.DeletePost(post.ID); err != nil {
+		t.Fatal(err)
+	}
+	if ev := f.event(t); ev != EventPostDeleted {
+		t.Errorf("event = %q; want %q", ev, EventPostDeleted)
+	}
+	if _, err := f.service.GetPostByID(post.ID); err == nil {
+		t.Error("deleted post still found")
+	}
+	if err := f.service.DeletePost(post.ID); err == nil {
+		t.Error("deleting twice succeeded")
+	}
+}
+
+func TestUpdatePostRecomputesCommentLock(t *testing.T) {
+	f := newPostFixture(t)
+	post := f.create(t, "published", "Hello")
+	if post.CommentsLockedAt == nil || !post.CommentsLockedAt.Equal(post.PublishedAt.AddDate(0, 0, 7)) {
+		t.Fatalf("comments lock at %v; want a week after publication", post.CommentsLockedAt)
+	}
+
+	open := &entities.Category{Name: "Open", Slug: "open"}
+	monthly := &entities.Category{Name: "Monthly", Slug: "monthly", CommentsAutoCloseDays: 30}
+	for _, c := range []*entities.Category{open, monthly} {
+		if err := f.service.categoryRepo.Create(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lockAt := func(categoryID uint) *time.Time {
+		t.Helper()
+		if err := f.service.UpdatePost(post.ID, &dto.UpdatePostRequest{CategoryID: &categoryID}, uint(f.author.ID)); err != nil {
+			t.Fatal(err)
+		}
+		updated, err := f.service.repo.FindByID(post.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return updated.CommentsLockedAt
+	}
+	if got := lockAt(open.ID); got != nil {
+		t.Errorf("in a category without auto-close, comments lock at %v", got)
+	}
+	if got := lockAt(monthly.ID); got == nil || !got.Equal(post.PublishedAt.AddDate(0, 0, 30)) {
+		t.Errorf("comments lock at %v; want 30 days after publication", got)
+	}
+
+	// comments locked by hand stay locked at the time they were
+	if err := f.service.SetCommentsLocked(post.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	locked, _ := f.service.repo.FindByID(post.ID)
+	if got := lockAt(open.ID); got == nil || !got.Equal(*locked.CommentsLockedAt) {
+		t.Errorf("hand lock moved to %v", got)
+	}
+}
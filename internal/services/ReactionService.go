@@ -0,0 +1,57 @@
+package services
+
+import (
+	"blog-api/internal/dto"
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+	"errors"
+	"slices"
+)
+
+var ErrInvalidReaction = errors.New("unsupported reaction type")
+
+type ReactionService struct {
+	repo        repositories.ReactionRepository
+	postRepo    repositories.PostRepository
+	commentRepo repositories.CommentRepository
+	types       []string
+}
+
+func NewReactionService(repo repositories.ReactionRepository, postRepo repositories.PostRepository, commentRepo repositories.CommentRepository, types []string) *ReactionService {
+	return &ReactionService{repo: repo, postRepo: postRepo, commentRepo: commentRepo, types: types}
+}
+
+func (s *ReactionService) Types() []string {
+	return s.types
+}
+
+// TogglePostReaction reacts to a post, or undoes the reaction when it is repeated.
+// It returns the caller's reaction afterwards ("" for none) and the post's counts.
+func (s *ReactionService) TogglePostReaction(postID, userID uint, reactionType string) (string, map[string]int64, error) {
+	if _, err := s.postRepo.FindByID(postID); err != nil {
+		return "", nil, err
+	}
+	return s.toggle(entities.ReactionTargetPost, postID, userID, reactionType)
+}
+
+func (s *ReactionService) ToggleCommentReaction(commentID, userID uint, reactionType string) (string, map[string]int64, error) {
+	if _, err := s.commentRepo.FindByID(commentID); err != nil {
+		return "", nil, err
+	}
+	return s.toggle(entities.ReactionTargetComment, commentID, userID, reactionType)
+}
+
+func (s *ReactionService) toggle(targetType string, targetID, userID uint, reactionType string) (string, map[string]int64, error) {
+	if !slices.Contains(s.types, reactionType) {
+		return "", nil, ErrInvalidReaction
+	}
+	current, err := s.repo.Toggle(targetType, targetID, userID, reactionType)
+	if err != nil {
+		return "", nil, err
+	}
+	counts, err := s.repo.Counts(targetType, targetID)
+	if err != nil {
+		return "", nil, err
+	}
+	return current, dto.NewReactionCounts(counts), nil
+}
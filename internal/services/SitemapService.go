@@ -0,0 +1,248 @@
+package services
+
+import (
+	"blog-api/internal/repositories"
+	"blog-api/pkg/pubsub"
+	"blog-api/pkg/sitemap"
+	"blog-api/pkg/utils"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sitemapCacheTTL bounds how stale a sitemap can get from changes that do not publish a
+// post event, such as renamed categories or usernames.
+const sitemapCacheTTL = time.Hour
+
+var ErrSitemapNotFound = errors.New("sitemap not found")
+
+// SitemapDocument is a rendered sitemap file.
+type SitemapDocument struct {
+	Body    []byte
+	Gzipped []byte
+	ETag    string
+	LastMod time.Time
+	builtAt time.Time
+}
+
+// SitemapService builds the sitemap index and its child sitemaps of posts, categories and
+// authors. Files are cached until a post changes or the cache expires.
+type SitemapService struct {
+	postRepo repositories.PostRepository
+	siteURL  string
+	gzip     bool
+	pageSize int
+
+	mu         sync.Mutex
+	cache      map[string]*SitemapDocument
+	generation uint64
+}
+
+// NewSitemapService creates the service. When gzip is set the index links to the
+// compressed .xml.gz files.
+func NewSitemapService(postRepo repositories.PostRepository, siteURL string, gzip bool) *SitemapService {
+	return &SitemapService{
+		postRepo: postRepo,
+		siteURL:  siteURL,
+		gzip:     gzip,
+		pageSize: sitemap.MaxURLs,
+		cache:    make(map[string]*SitemapDocument),
+	}
+}
+
+// Index returns the sitemap index.
+func (s *SitemapService) Index() (*SitemapDocument, error) {
+	return s.document("index")
+}
+
+// Sitemap returns a child sitemap by file name, e.g. "posts-1.xml" or "categories.xml".
+func (s *SitemapService) Sitemap(name string) (*SitemapDocument, error) {
+	name = strings.TrimSuffix(name, ".gz")
+	if name == "index" {
+		return nil, ErrSitemapNotFound
+	}
+	return s.document(name)
+}
+
+// Invalidate drops every cached sitemap.
+func (s *SitemapService) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache = make(map[string]*SitemapDocument)
+	s.generation++
+}
+
+// WatchPosts invalidates the cache whenever a post event is published, until ctx is done.
+// Events may be missed while resubscribing, so the cache is also dropped then.
+func (s *SitemapService) WatchPosts(ctx context.Context, bus pubsub.Bus) {
+	for ctx.Err() == nil {
+		events, err := bus.Subscribe(ctx, PostsTopic, 0)
+		if err != nil {
+			log.Println("sitemap: subscribe to post events failed:", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(5 * time.Second):
+			}
+			continue
+		}
+		s.Invalidate()
+		for range events {
+			s.Invalidate()
+		}
+	}
+}
+
+// document returns a cached file or builds it. A file built while the cache was being
+// invalidated is served but not stored, so it cannot outlive the change.
+func (s *SitemapService) document(name string) (*SitemapDocument, error) {
+	s.mu.Lock()
+	doc, ok := s.cache[name]
+	generation := s.generation
+	s.mu.Unlock()
+	if ok && time.Since(doc.builtAt) < sitemapCacheTTL {
+		return doc, nil
+	}
+
+	doc, err := s.build(name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if s.generation == generation {
+		s.cache[name] = doc
+	}
+	s.mu.Unlock()
+	return doc, nil
+}
+
+func (s *SitemapService) build(name string) (*SitemapDocument, error) {
+	if name == "index" {
+		return s.buildIndex()
+	}
+
+	var entries []repositories.SitemapEntry
+	var loc func(key string) string
+	var err error
+	switch {
+	case name == "categories.xml":
+		entries, err = s.postRepo.SitemapCategories()
+		loc = func(slug string) string { return s.siteURL + "/categories/" + slug }
+	case strings.HasPrefix(name, "posts-"):
+		entries, err = s.page(name, "posts", s.postRepo.SitemapPosts)
+		loc = func(slug string) string { return s.siteURL + "/posts/" + slug }
+	case strings.HasPrefix(name, "authors-"):
+		entries, err = s.page(name, "authors", s.postRepo.SitemapAuthors)
+		loc = func(username string) string { return s.siteURL + utils.ProfilePath(username) }
+	default:
+		return nil, ErrSitemapNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	urls := make([]sitemap.URL, 0, len(entries))
+	for _, e := range entries {
+		urls = append(urls, sitemap.URL{Loc: loc(e.Key), LastMod: e.LastMod})
+	}
+	body, err := sitemap.URLSet(urls)
+	if err != nil {
+		return nil, err
+	}
+	return s.newDocument(body, latest(urls))
+}
+
+// page loads the entries of a numbered sitemap such as "posts-2.xml". Pages past the end
+// do not exist, except for the first one, which is listed even when it is empty.
+func (s *SitemapService) page(name, prefix string, list func(offset, limit int) ([]repositories.SitemapEntry, error)) ([]repositories.SitemapEntry, error) {
+	var n int
+	if _, err := fmt.Sscanf(name, prefix+"-%d.xml", &n); err != nil || n < 1 || name != fmt.Sprintf("%s-%d.xml", prefix, n) {
+		return nil, ErrSitemapNotFound
+	}
+	entries, err := list((n-1)*s.pageSize, s.pageSize)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 && n > 1 {
+		return nil, ErrSitemapNotFound
+	}
+	return entries, nil
+}
+
+func (s *SitemapService) buildIndex() (*SitemapDocument, error) {
+	posts, err := s.postRepo.CountSitemapPosts()
+	if err != nil {
+		return nil, err
+	}
+	authors, err := s.postRepo.CountPublishingAuthors()
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{"categories.xml"}
+	names = append(names, pageNames("posts", posts, s.pageSize)...)
+	names = append(names, pageNames("authors", authors, s.pageSize)...)
+
+	sitemaps := make([]sitemap.URL, 0, len(names))
+	for _, name := range names {
+		child, err := s.document(name)
+		if err != nil {
+			return nil, err
+		}
+		file := name
+		if s.gzip {
+			file += ".gz"
+		}
+		sitemaps = append(sitemaps, sitemap.URL{Loc: s.siteURL + "/sitemaps/" + file, LastMod: child.LastMod})
+	}
+	body, err := sitemap.Index(sitemaps)
+	if err != nil {
+		return nil, err
+	}
+	return s.newDocument(body, latest(sitemaps))
+}
+
+func (s *SitemapService) newDocument(body []byte, lastMod time.Time) (*SitemapDocument, error) {
+	sum := sha256.Sum256(body)
+	doc := &SitemapDocument{
+		Body:    body,
+		ETag:    `"` + hex.EncodeToString(sum[:16]) + `"`,
+		LastMod: lastMod,
+		builtAt: time.Now(),
+	}
+	gzipped, err := sitemap.Gzip(body)
+	if err != nil {
+		return nil, err
+	}
+	doc.Gzipped = gzipped
+	return doc, nil
+}
+
+// pageNames lists the files needed for count URLs; there is always at least one.
+func pageNames(prefix string, count int64, pageSize int) []string {
+	pages := int((count + int64(pageSize) - 1) / int64(pageSize))
+	if pages < 1 {
+		pages = 1
+	}
+	names := make([]string, 0, pages)
+	for i := 1; i <= pages; i++ {
+		names = append(names, fmt.Sprintf("%s-%d.xml", prefix, i))
+	}
+	return names
+}
+
+func latest(urls []sitemap.URL) time.Time {
+	var t time.Time
+	for _, u := range urls {
+		if u.LastMod.After(t) {
+			t = u.LastMod
+		}
+	}
+	return t
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories/memory"
+	"blog-api/pkg/sitemap"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPageNames(t *testing.T) {
+	for _, test := range []struct {
+		count int64
+		pages int
+	}{
+		{0, 1},
+		{1, 1},
+		{sitemap.MaxURLs, 1},
+		{sitemap.MaxURLs + 1, 2},
+		{3 * sitemap.MaxURLs, 3},
+	} {
+		names := pageNames("posts", test.count, sitemap.MaxURLs)
+		if len(names) != test.pages || names[0] != "posts-1.xml" || names[len(names)-1] != fmt.Sprintf("posts-%d.xml", test.pages) {
+			t.Errorf("pageNames(%d) = %v; want %d pages", test.count, names, test.pages)
+		}
+	}
+}
+
+func TestSitemapServicePages(t *testing.T) {
+	store := memory.NewStore()
+	author := &entities.User{Username: "alice", Email: "alice@example.com", Password: "x", Role: "client"}
+	if err := memory.NewUserRepository(store).Create(author); err != nil {
+		t.Fatal(err)
+	}
+	category := &entities.Category{Name: "News", Slug: "news"}
+	if err := memory.NewCategoryRepository(store).Create(category); err != nil {
+		t.Fatal(err)
+	}
+	posts := memory.NewPostRepository(store)
+	for i := 1; i <= 5; i++ {
+		post := &entities.Post{Title: "Post", Slug: fmt.Sprintf("post-%d", i), Content: "x", Status: "published",
+			AuthorID: uint(author.ID), CategoryID: category.ID}
+		if err := posts.Create(post); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewSitemapService(posts, "https://blog.example.com", true)
+	s.pageSize = 2
+
+	index, err := s.Index()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"categories.xml.gz", "posts-1.xml.gz", "posts-3.xml.gz", "authors-1.xml.gz"} {
+		if !strings.Contains(string(index.Body), "https://blog.example.com/sitemaps/"+want) {
+			t.Errorf("index does not list %s:\n%s", want, index.Body)
+		}
+	}
+	if strings.Contains(string(index.Body), "posts-4") {
+		t.Error("index lists an empty page")
+	}
+
+	var slugs []string
+	for page := 1; page <= 3; page++ {
+		doc, err := s.Sitemap(fmt.Sprintf("posts-%d.xml.gz", page))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, line := range strings.Split(string(doc.Body), "<loc>")[1:] {
+			slugs = append(slugs, strings.TrimPrefix(line[:strings.Index(line, "</loc>")], "https://blog.example.com/posts/"))
+		}
+		if len(doc.Gzipped) == 0 {
+			t.Errorf("page %d has no compressed form", page)
+		}
+	}
+	slices.Sort(slugs)
+	if want := []string{"post-1", "post-2", "post-3", "post-4", "post-5"}; !slices.Equal(slugs, want) {
+		t.Errorf("pages list %v; want every post once", slugs)
+	}
+
+	for _, name := range []string{"posts-4.xml", "posts-0.xml", "posts-01.xml", "index", "other.xml"} {
+		if _, err := s.Sitemap(name); !errors.Is(err, ErrSitemapNotFound) {
+			t.Errorf("Sitemap(%q) = %v; want ErrSitemapNotFound", name, err)
+		}
+	}
+}
@@ -1,18 +1,40 @@
 package services
 
 import (
+	"blog-api/internal/dto"
 	"blog-api/internal/entities"
 	"blog-api/internal/repositories"
 	"blog-api/pkg/helper"
+	"blog-api/pkg/tracing"
+	"blog-api/pkg/utils"
+	"context"
 	"errors"
+	"net/url"
+	"time"
+
+	"gorm.io/gorm"
 )
 
 type UserService struct {
-	userRepo *repositories.UserRepository
+	userRepo repositories.UserRepository
+	notificationService *NotificationService
+	followRepo repositories.FollowRepository
+	postRepo repositories.PostRepository
+	siteURL string
+	jwtSecret []byte
+	mailer *helper.Mailer
 }
 
-func NewUserService(userRepo *repositories.UserRepository) *UserService{
-	return &UserService{userRepo: userRepo}
+const emailChangeTokenTTL = 24 * time.Hour
+
+var (
+	ErrUsernameTaken           = errors.New("username already exists")
+	ErrEmailTaken              = errors.New("email already exists")
+	ErrInvalidEmailChangeToken = errors.New("invalid or expired email confirmation link")
+)
+
+func NewUserService(userRepo repositories.UserRepository, notificationService *NotificationService, followRepo repositories.FollowRepository, postRepo repositories.PostRepository, siteURL string, jwtSecret []byte, mailer *helper.Mailer) *UserService{
+	return &UserService{userRepo: userRepo, notificationService: notificationService, followRepo: followRepo, postRepo: postRepo, siteURL: siteURL, jwtSecret: jwtSecret, mailer: mailer}
 }
 
 func (s *UserService) GetUserByID(id uint) (*entities.User, error){
@@ -51,10 +73,147 @@ func (s *UserService) ChangeUserRole(userID uint, newRole string) error {
     if newRole != "admin" && newRole != "client" {
         return errors.New("invalid role")
     }
+    oldRole := user.Role
     user.Role = newRole
-    return s.userRepo.Update(user)
+    if err := s.userRepo.Update(user); err != nil {
+        return err
+    }
+    if oldRole != newRole {
+        s.notificationService.NotifyRoleChanged(userID, newRole)
+    }
+    return nil
 }
 
 func (s *UserService) UpdateCanPost(userID uint, canPost bool) error {
-    return s.userRepo.UpdateCanPost(userID, canPost)
-}
\ No newline at end of file
+    if err := s.userRepo.UpdateCanPost(userID, canPost); err != nil {
+        return err
+    }
+    s.notificationService.NotifyPostingPermissionChanged(userID, canPost)
+    return nil
+}
+
+// FollowCounts returns how many users follow the user and how many authors they follow.
+func (s *UserService) FollowCounts(userID uint) (followers, following int64, err error) {
+    followers, err = s.followRepo.CountFollowers(entities.FollowTargetUser, userID)
+    if err != nil {
+        return 0, 0, err
+    }
+    following, err = s.followRepo.CountFollowing(userID, entities.FollowTargetUser)
+    return followers, following, err
+}
+
+// GetPublicProfile builds the public profile of the user with the given username.
+func (s *UserService) GetPublicProfile(username string) (*dto.PublicProfileResponse, error) {
+    user, err := s.userRepo.FindByUsername(username)
+    if err != nil {
+        return nil, err
+    }
+    if user == nil {
+        return nil, gorm.ErrRecordNotFound
+    }
+    postCount, err := s.postRepo.CountPublishedByAuthor(uint(user.ID))
+    if err != nil {
+        return nil, err
+    }
+    followers, following, err := s.FollowCounts(uint(user.ID))
+    if err != nil {
+        return nil, err
+    }
+    return &dto.PublicProfileResponse{
+        Username:       user.Username,
+        DisplayName:    user.DisplayName,
+        Bio:            user.Bio,
+        AvatarURL:      user.AvatarURL,
+        Website:        user.Website,
+        JoinedAt:       user.CreatedAt.Format("2006-01-02 15:04:05"),
+        PostCount:      postCount,
+        FollowersCount: followers,
+        FollowingCount: following,
+    }, nil
+}
+
+func (s *UserService) UpdateProfile(userID uint, req *dto.UpdateProfileRequest) error {
+    updates := make(map[string]interface{})
+    if req.DisplayName != nil {
+        updates["display_name"] = *req.DisplayName
+    }
+    if req.Bio != nil {
+        updates["bio"] = *req.Bio
+    }
+    if req.AvatarURL != nil {
+        updates["avatar_url"] = *req.AvatarURL
+    }
+    if req.Website != nil {
+        updates["website"] = *req.Website
+    }
+    if len(updates) == 0 {
+        return errors.New("no fields to update")
+    }
+    return s.userRepo.UpdateProfile(userID, updates)
+}
+
+// UpdateMe changes the user's own username and email. A new username takes effect at once;
+// a new email only after the link sent to it is confirmed, in which case emailPending is true.
+// Both changes are checked before either is applied.
+func (s *UserService) UpdateMe(ctx context.Context, userID uint, req *dto.UpdateMeRequest) (emailPending bool, err error) {
+    ctx, span := tracing.Start(ctx, "UserService.UpdateMe")
+    defer func() { tracing.End(span, err) }()
+
+    user, err := s.userRepo.FindByID(userID)
+    if err != nil {
+        return false, err
+    }
+
+    changeUsername := req.Username != nil && *req.Username != user.Username
+    changeEmail := req.Email != nil && *req.Email != user.Email
+    if changeUsername {
+        if existing, err := s.userRepo.FindByUsername(*req.Username); err != nil {
+            return false, err
+        } else if existing != nil {
+            return false, ErrUsernameTaken
+        }
+    }
+    if changeEmail {
+        if existing, err := s.userRepo.FindEmail(*req.Email); err != nil {
+            return false, err
+        } else if existing != nil {
+            return false, ErrEmailTaken
+        }
+    }
+
+    if changeUsername {
+        if err := s.userRepo.UpdateUsername(userID, *req.Username); err != nil {
+            return false, err
+        }
+    }
+    if changeEmail {
+        token, err := utils.GenerateEmailChangeToken(s.jwtSecret, userID, user.Email, *req.Email, emailChangeTokenTTL)
+        if err != nil {
+            return false, err
+        }
+        link := s.siteURL + "/users/confirm-email?token=" + url.QueryEscape(token)
+        if err := s.mailer.SendEmailChangeConfirmation(ctx, *req.Email, link); err != nil {
+            return false, err
+        }
+    }
+    return changeEmail, nil
+}
+
+// ConfirmEmailChange switches the user to the address the token was sent to. The token is
+// rejected once the account's email no longer matches the one it was issued against.
+func (s *UserService) ConfirmEmailChange(token string) error {
+    userID, oldEmail, newEmail, err := utils.ValidateEmailChangeToken(s.jwtSecret, token)
+    if err != nil {
+        return ErrInvalidEmailChangeToken
+    }
+    user, err := s.userRepo.FindByID(userID)
+    if err != nil || user.Email != oldEmail {
+        return ErrInvalidEmailChangeToken
+    }
+    if existing, err := s.userRepo.FindEmail(newEmail); err != nil {
+        return err
+    } else if existing != nil {
+        return ErrEmailTaken
+    }
+    return s.userRepo.UpdateEmail(userID, newEmail)
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"blog-api/internal/entities"
+	"blog-api/internal/repositories"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	viewFlushInterval  = 30 * time.Second
+	viewFlushThreshold = 1000
+	viewDedupWindow    = 30 * time.Minute
+	// viewMaxPending caps the buffer while the database is unreachable; counts beyond it
+	// are dropped rather than growing memory without bound
+	viewMaxPending = 100000
+	// viewMaxSeen caps the visitors remembered for deduplication; beyond it views are still
+	// counted but their visitors are not remembered until expired ones are cleared
+	viewMaxSeen = 100000
+)
+
+type viewerKey struct {
+	postID  uint
+	visitor string
+}
+
+type viewKey struct {
+	postID   uint
+	day      time.Time
+	referrer string
+}
+
+// ViewCounter counts post views in memory and periodically adds them to the daily
+// aggregates in one batched write. A visitor is counted once per post within
+// viewDedupWindow. Views buffered on a crashed instance are lost, which is acceptable
+// for analytics.
+type ViewCounter struct {
+	repo repositories.PostViewRepository
+
+	mu         sync.Mutex
+	pending    map[viewKey]int64
+	seen       map[viewerKey]time.Time
+	maxPending int
+	maxSeen    int
+
+	flushNow chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+	started  atomic.Bool
+}
+
+func NewViewCounter(repo repositories.PostViewRepository) *ViewCounter {
+	return &ViewCounter{
+		repo:       repo,
+		pending:    make(map[viewKey]int64),
+		seen:       make(map[viewerKey]time.Time),
+		maxPending: viewMaxPending,
+		maxSeen:    viewMaxSeen,
+		flushNow:   make(chan struct{}, 1),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+}
+
+// Start runs the flusher until Stop is called.
+func (c *ViewCounter) Start() {
+	c.started.Store(true)
+	go c.run()
+}
+
+// Running reports whether the worker was started and has not stopped.
+func (c *ViewCounter) Running() bool {
+	select {
+	case <-c.done:
+		return false
+	default:
+		return c.started.Load()
+	}
+}
+
+// Stop writes the buffered views and stops the flusher.
+func (c *ViewCounter) Stop() {
+	c.stopOnce.Do(func() { close(c.stop) })
+	<-c.done
+}
+
+// Record counts a view of a post unless the same visitor viewed it recently. visitor is
+// any stable identifier of the reader; referrer is the referring host, if any.
+func (c *ViewCounter) Record(postID uint, visitor, referrer string) {
+	now := time.Now()
+	seenKey := viewerKey{postID: postID, visitor: visitor}
+
+	c.mu.Lock()
+	if last, ok := c.seen[seenKey]; ok && now.Sub(last) < viewDedupWindow {
+		c.mu.Unlock()
+		return
+	}
+	key := viewKey{postID: postID, day: now.UTC().Truncate(24 * time.Hour), referrer: referrer}
+	if _, ok := c.pending[key]; !ok && len(c.pending) >= c.maxPending {
+		c.mu.Unlock()
+		return
+	}
+	c.pending[key]++
+	// the visitor is remembered only once the view is counted, so a dropped view is not
+	// deduplicated against
+	if _, ok := c.seen[seenKey]; ok || len(c.seen) < c.maxSeen {
+		c.seen[seenKey] = now
+	}
+	full := len(c.pending) >= viewFlushThreshold
+	c.mu.Unlock()
+
+	if full {
+		select {
+		case c.flushNow <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (c *ViewCounter) run() {
+	defer close(c.done)
+	ticker := time.NewTicker(viewFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stop:
+			c.flush()
+			return
+		case <-c.flushNow:
+			c.flush()
+		case <-ticker.C:
+			c.flush()
+		}
+	}
+}
+
+// flush swaps out the buffer and writes it. On failure the counts are merged back so the
+// next flush retries them.
+func (c *ViewCounter) flush() {
+	c.mu.Lock()
+	pending := c.pending
+	c.pending = make(map[viewKey]int64)
+	cutoff := time.Now().Add(-viewDedupWindow)
+	for key, last := range c.seen {
+		if last.Before(cutoff) {
+			delete(c.seen, key)
+		}
+	}
+	c.mu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+	rows := make([]entities.PostDailyView, 0, len(pending))
+	for key, views := range pending {
+		rows = append(rows, entities.PostDailyView{PostID: key.postID, Day: key.day, Referrer: key.referrer, Views: views})
+	}
+	if err := c.repo.AddViews(rows); err != nil {
+		log.Printf("flush %d view counts failed: %v", len(rows), err)
+		c.mu.Lock()
+		for key, views := range pending {
+			if _, ok := c.pending[key]; ok || len(c.pending) < c.maxPending {
+				c.pending[key] += views
+			}
+		}
+		c.mu.Unlock()
+	}
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"blog-api/internal/repositories/memory"
+	"testing"
+)
+
+func (c *ViewCounter) views(postID uint) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var n int64
+	for key, views := range c.pending {
+		if key.postID == postID {
+			n += views
+		}
+	}
+	return n
+}
+
+func TestViewCounterDeduplicates(t *testing.T) {
+	c := NewViewCounter(memory.NewPostViewRepository(memory.NewStore()))
+	c.Record(1, "alice", "")
+	c.Record(1, "alice", "news.example.com")
+	c.Record(1, "bob", "")
+	c.Record(2, "alice", "")
+	if c.views(1) != 2 || c.views(2) != 1 {
+		t.Errorf("views = %d, %d; want 2, 1", c.views(1), c.views(2))
+	}
+}
+
+func TestViewCounterDroppedViewIsNotSeen(t *testing.T) {
+	c := NewViewCounter(memory.NewPostViewRepository(memory.NewStore()))
+	c.maxPending = 1
+	c.Record(1, "alice", "")
+	c.Record(2, "bob", "") // dropped: the buffer is full
+	if c.views(2) != 0 {
+		t.Fatalf("views of post 2 = %d; want the view dropped", c.views(2))
+	}
+
+	// once the buffer is written the visitor's next view counts
+	c.flush()
+	c.Record(2, "bob", "")
+	if c.views(2) != 1 {
+		t.Errorf("views of post 2 = %d; want the retried view counted", c.views(2))
+	}
+}
+
+func TestViewCounterBoundsSeen(t *testing.T) {
+	c := NewViewCounter(memory.NewPostViewRepository(memory.NewStore()))
+	c.maxSeen = 1
+	c.Record(1, "alice", "")
+	c.Record(1, "bob", "")
+	c.Record(1, "bob", "")
+	c.Record(1, "alice", "")
+	// bob's views count, but bob is not remembered while alice fills the set
+	if c.views(1) != 3 || len(c.seen) != 1 {
+		t.Errorf("views = %d with %d visitors remembered; want 3 and 1", c.views(1), len(c.seen))
+	}
+}
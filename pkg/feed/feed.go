@@ -0,0 +1,76 @@
+// Package feed renders syndication feeds in RSS 2.0, Atom 1.0 and JSON Feed 1.1.
+package feed
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// Formats maps the supported format names to their content types.
+var Formats = map[string]string{
+	"rss":  "application/rss+xml; charset=utf-8",
+	"atom": "application/atom+xml; charset=utf-8",
+	"json": "application/feed+json; charset=utf-8",
+}
+
+// Feed is a format-independent feed. All URLs must be absolute.
+type Feed struct {
+	Title       string
+	Description string
+	Link        string // the HTML page the feed belongs to
+	FeedURL     string // the feed itself, in the format being rendered
+	Language    string
+	Items       []Item
+}
+
+type Item struct {
+	ID        string // stable, globally unique identifier
+	Title     string
+	Link      string
+	Summary   string
+	Content   string
+	Author    string
+	AuthorURL string
+	Category  string
+	Image     string
+	Published time.Time
+	Updated   time.Time
+}
+
+// Updated is when the most recently changed item was last modified, or the zero time for
+// an empty feed.
+func (f *Feed) Updated() time.Time {
+	var latest time.Time
+	for _, item := range f.Items {
+		if item.Updated.After(latest) {
+			latest = item.Updated
+		}
+	}
+	return latest
+}
+
+// ETag identifies the feed's content: it changes whenever an item is added, removed or updated.
+func (f *Feed) ETag() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n", f.FeedURL, f.Title)
+	for _, item := range f.Items {
+		fmt.Fprintf(h, "%s %d\n", item.ID, item.Updated.UnixNano())
+	}
+	return `W/"` + hex.EncodeToString(h.Sum(nil))[:32] + `"`
+}
+
+// Render encodes the feed in the named format.
+func Render(f *Feed, format string) ([]byte, error) {
+	switch format {
+	case "rss":
+		return RSS(f)
+	case "atom":
+		return Atom(f)
+	case "json":
+		return JSON(f)
+	default:
+		return nil, fmt.Errorf("feed: unknown format %q", format)
+	}
+}
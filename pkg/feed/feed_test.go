@@ -0,0 +1,229 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	published = time.Date(2024, 5, 1, 8, 0, 0, 0, time.FixedZone("ICT", 7*3600))
+	updated   = time.Date(2024, 5, 2, 9, 30, 0, 0, time.UTC)
+)
+
+func sample() *Feed {
+	return &Feed{
+		Title:       "Blog & friends",
+		Description: "Latest posts",
+		Link:        "https://blog.example.com/",
+		FeedURL:     "https://blog.example.com/feed.xml",
+		Language:    "vi",
+		Items: []Item{{
+			ID:        "https://blog.example.com/posts/hello",
+			Title:     "Hello <world>",
+			Link:      "https://blog.example.com/posts/hello",
+			Summary:   "A first post",
+			Content:   "a < b & c\nsame paragraph\n\nsecond ]]> paragraph",
+			Author:    "Alice",
+			AuthorURL: "https://blog.example.com/users/alice",
+			Category:  "News",
+			Image:     "https://cdn.example.com/hello.webp",
+			Published: published,
+			Updated:   updated,
+		}, {
+			ID:        "https://blog.example.com/posts/draft-notes",
+			Title:     "Notes",
+			Link:      "https://blog.example.com/posts/draft-notes",
+			Published: published,
+			Updated:   published,
+		}},
+	}
+}
+
+func TestRSS(t *testing.T) {
+	out, err := RSS(sample())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Version string `xml:"version,attr"`
+		Channel struct {
+			Title         string `xml:"title"`
+			LastBuildDate string `xml:"lastBuildDate"`
+			Self          struct {
+				Href string `xml:"href,attr"`
+			} `xml:"http://www.w3.org/2005/Atom link"`
+			Items []struct {
+				Title string `xml:"title"`
+				GUID  struct {
+					Value       string `xml:",chardata"`
+					IsPermaLink string `xml:"isPermaLink,attr"`
+				} `xml:"guid"`
+				Content   string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+				Creator   string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+				PubDate   string `xml:"pubDate"`
+				Enclosure struct {
+					URL  string `xml:"url,attr"`
+					Type string `xml:"type,attr"`
+				} `xml:"enclosure"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, out)
+	}
+	ch := doc.Channel
+	if doc.Version != "2.0" || ch.Title != "Blog & friends" || ch.Self.Href != "https://blog.example.com/feed.xml" {
+		t.Errorf("channel = %+v", ch)
+	}
+	if ch.LastBuildDate != "Thu, 02 May 2024 09:30:00 +0000" {
+		t.Errorf("lastBuildDate = %q; want the latest update", ch.LastBuildDate)
+	}
+	if len(ch.Items) != 2 {
+		t.Fatalf("%d items", len(ch.Items))
+	}
+	item := ch.Items[0]
+	if item.Title != "Hello <world>" || item.GUID.Value != "https://blog.example.com/posts/hello" || item.GUID.IsPermaLink != "false" ||
+		item.Creator != "Alice" || item.PubDate != "Wed, 01 May 2024 01:00:00 +0000" {
+		t.Errorf("item = %+v", item)
+	}
+	// the text is escaped into HTML paragraphs, even with a CDATA terminator in it
+	if want := "<p>a &lt; b &amp; c<br>same paragraph</p><p>second ]]&gt; paragraph</p>"; item.Content != want {
+		t.Errorf("content = %q; want %q", item.Content, want)
+	}
+	if item.Enclosure.URL != "https://cdn.example.com/hello.webp" || item.Enclosure.Type != "image/webp" {
+		t.Errorf("enclosure = %+v", item.Enclosure)
+	}
+	if ch.Items[1].Content != "" || bytes.Count(out, []byte("<enclosure")) != 1 {
+		t.Error("empty content or image rendered")
+	}
+}
+
+func TestAtom(t *testing.T) {
+	out, err := Atom(sample())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		XMLName xml.Name
+		ID      string `xml:"id"`
+		Updated string `xml:"updated"`
+		Links   []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Entries []struct {
+			Title     string `xml:"title"`
+			Published string `xml:"published"`
+			Updated   string `xml:"updated"`
+			Author    *struct {
+				Name string `xml:"name"`
+				URI  string `xml:"uri"`
+			} `xml:"author"`
+			Content *struct {
+				Type  string `xml:"type,attr"`
+				Value string `xml:",chardata"`
+			} `xml:"content"`
+			Links []struct {
+				Href string `xml:"href,attr"`
+				Rel  string `xml:"rel,attr"`
+			} `xml:"link"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, out)
+	}
+	if doc.XMLName.Space != "http://www.w3.org/2005/Atom" || doc.ID != "https://blog.example.com/feed.xml" || doc.Updated != "2024-05-02T09:30:00Z" {
+		t.Errorf("feed = %+v", doc)
+	}
+	if len(doc.Links) != 2 || doc.Links[1].Rel != "self" {
+		t.Errorf("links = %+v", doc.Links)
+	}
+	entry := doc.Entries[0]
+	if entry.Title != "Hello <world>" || entry.Published != "2024-05-01T01:00:00Z" || entry.Author == nil ||
+		entry.Author.URI != "https://blog.example.com/users/alice" || entry.Content == nil || entry.Content.Type != "text" ||
+		!strings.HasPrefix(entry.Content.Value, "a < b & c") || len(entry.Links) != 2 || entry.Links[1].Rel != "enclosure" {
+		t.Errorf("entry = %+v", entry)
+	}
+	if doc.Entries[1].Author != nil || doc.Entries[1].Content != nil {
+		t.Errorf("empty author or content rendered: %+v", doc.Entries[1])
+	}
+
+	// an empty feed still has the required updated element
+	out, err = Atom(&Feed{Title: "Empty", FeedURL: "https://blog.example.com/atom.xml"})
+	if err != nil || !bytes.Contains(out, []byte("<updated>1970-01-01T00:00:00Z</updated>")) {
+		t.Errorf("empty feed = %s, %v", out, err)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	out, err := JSON(sample())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Version string `json:"version"`
+		Title   string `json:"title"`
+		Items   []struct {
+			ID            string   `json:"id"`
+			ContentText   string   `json:"content_text"`
+			DatePublished string   `json:"date_published"`
+			Tags          []string `json:"tags"`
+			Authors       []struct {
+				Name string `json:"name"`
+			} `json:"authors"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if doc.Version != "https://jsonfeed.org/version/1.1" || doc.Title != "Blog & friends" || len(doc.Items) != 2 {
+		t.Fatalf("feed = %+v", doc)
+	}
+	item := doc.Items[0]
+	if item.ContentText != sample().Items[0].Content || item.DatePublished != "2024-05-01T01:00:00Z" ||
+		len(item.Tags) != 1 || item.Tags[0] != "News" || len(item.Authors) != 1 || item.Authors[0].Name != "Alice" {
+		t.Errorf("item = %+v", item)
+	}
+	if !bytes.Contains(out, []byte(`"Blog & friends"`)) {
+		t.Error("HTML characters escaped in the JSON")
+	}
+
+	out, err = JSON(&Feed{Title: "Empty"})
+	if err != nil || !bytes.Contains(out, []byte(`"items": []`)) {
+		t.Errorf("empty feed = %s, %v; want an empty items array", out, err)
+	}
+}
+
+func TestETag(t *testing.T) {
+	f := sample()
+	etag := f.ETag()
+	if !strings.HasPrefix(etag, `W/"`) || sample().ETag() != etag {
+		t.Fatalf("ETag = %s; want a stable weak tag", etag)
+	}
+	f.Items[1].Updated = updated.Add(time.Minute)
+	if f.ETag() == etag {
+		t.Error("ETag unchanged after an item was updated")
+	}
+	if !f.Updated().Equal(updated.Add(time.Minute)) {
+		t.Errorf("Updated = %v", f.Updated())
+	}
+	f.Items = f.Items[:1]
+	if f.ETag() == etag {
+		t.Error("ETag unchanged after an item was removed")
+	}
+}
+
+func TestRender(t *testing.T) {
+	for format := range Formats {
+		if _, err := Render(sample(), format); err != nil {
+			t.Errorf("Render(%s) = %v", format, err)
+		}
+	}
+	if _, err := Render(sample(), "yaml"); err == nil {
+		t.Error("rendered an unknown format")
+	}
+}
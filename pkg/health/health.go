@@ -0,0 +1,120 @@
+// Package health runs the readiness checks of a service: named checks, each bounded by its
+// own timeout, run concurrently and reported together.
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrShuttingDown is reported while the service is shutting down.
+var ErrShuttingDown = errors.New("shutting down")
+
+// DefaultTimeout bounds checks added without a timeout.
+const DefaultTimeout = 2 * time.Second
+
+// Check is one dependency the service needs to serve requests.
+type Check struct {
+	Name    string
+	Timeout time.Duration
+	Run     func(ctx context.Context) error
+}
+
+// Result is the outcome of a check.
+type Result struct {
+	Name     string `json:"name"`
+	OK       bool   `json:"ok"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report is the outcome of every check.
+type Report struct {
+	Ready  bool     `json:"ready"`
+	Checks []Result `json:"checks"`
+}
+
+// Checker holds the checks of a service. It is safe for concurrent use.
+type Checker struct {
+	mu           sync.RWMutex
+	checks       []Check
+	shuttingDown atomic.Bool
+}
+
+func NewChecker() *Checker {
+	return &Checker{}
+}
+
+// Add registers a check. Checks are reported in the order they were added.
+func (c *Checker) Add(check Check) {
+	if check.Timeout <= 0 {
+		check.Timeout = DefaultTimeout
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, check)
+}
+
+// ShutDown makes every later report not ready, whatever the checks say, so that traffic
+// is routed away while the service drains.
+func (c *Checker) ShutDown() {
+	c.shuttingDown.Store(true)
+}
+
+// Run runs every check concurrently and waits for them, at most their timeout each. The
+// service is ready when all of them pass.
+func (c *Checker) Run(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := append([]Check(nil), c.checks...)
+	c.mu.RUnlock()
+
+	report := Report{Ready: true, Checks: make([]Result, len(checks))}
+	if c.shuttingDown.Load() {
+		report.Ready = false
+		report.Checks = []Result{{Name: "shutdown", Error: ErrShuttingDown.Error(), Duration: "0s"}}
+		return report
+	}
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Checks[i] = run(ctx, check)
+		}()
+	}
+	wg.Wait()
+	for _, result := range report.Checks {
+		report.Ready = report.Ready && result.OK
+	}
+	return report
+}
+
+// run runs a check within its timeout. A check that ignores its context is abandoned at
+// the timeout and reported as failed.
+func run(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, check.Timeout)
+	defer cancel()
+	start := time.Now()
+
+	done := make(chan error, 1)
+	go func() { done <- check.Run(ctx) }()
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = errors.New("timed out after " + check.Timeout.String())
+	}
+
+	result := Result{Name: check.Name, OK: err == nil, Duration: time.Since(start).Round(time.Millisecond).String()}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
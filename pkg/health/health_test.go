@@ -0,0 +1,51 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	c := NewChecker()
+	c.Add(Check{Name: "ok", Run: func(context.Context) error { return nil }})
+	c.Add(Check{Name: "failing", Run: func(context.Context) error { return errors.New("down") }})
+	c.Add(Check{Name: "stuck", Timeout: 20 * time.Millisecond, Run: func(context.Context) error {
+		time.Sleep(time.Second) // ignores its context
+		return nil
+	}})
+
+	start := time.Now()
+	report := c.Run(context.Background())
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("checks took %v; the stuck one should be abandoned", elapsed)
+	}
+	if report.Ready {
+		t.Error("ready with failing checks")
+	}
+	want := []struct {
+		name, err string
+	}{{"ok", ""}, {"failing", "down"}, {"stuck", "timed out after 20ms"}}
+	if len(report.Checks) != len(want) {
+		t.Fatalf("checks = %+v", report.Checks)
+	}
+	for i, w := range want {
+		got := report.Checks[i]
+		if got.Name != w.name || got.Error != w.err || got.OK != (w.err == "") {
+			t.Errorf("check %d = %+v; want %s with error %q", i, got, w.name, w.err)
+		}
+	}
+}
+
+func TestShutDown(t *testing.T) {
+	c := NewChecker()
+	c.Add(Check{Name: "ok", Run: func(context.Context) error { return nil }})
+	if !c.Run(context.Background()).Ready {
+		t.Fatal("not ready")
+	}
+	c.ShutDown()
+	if report := c.Run(context.Background()); report.Ready || report.Checks[0].Error != ErrShuttingDown.Error() {
+		t.Errorf("report while shutting down = %+v", report)
+	}
+}
@@ -1,23 +1,42 @@
 package helper
 
 import (
-	"os"
+	"blog-api/pkg/tracing"
+	"context"
 
 	"github.com/go-gomail/gomail"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func SendResetEmail(to, resetLink string) error {
-	m := gomail.NewMessage()
-	m.SetHeader("From", os.Getenv("SMTP_FROM"))
-	m.SetHeader("To", to)
-	m.SetHeader("Subject", "Reset your password")
-	m.SetBody("text/html", "Click the link to reset your password: <a href='"+resetLink+"'>Reset Password</a>")
-
-	d := gomail.NewDialer(
-		os.Getenv("SMTP_HOST"),
-		587,
-		os.Getenv("SMTP_USER"),
-		os.Getenv("SMTP_PASS"),
-	)
-	return d.DialAndSend(m)
-}
\ No newline at end of file
+// Mailer sends the account emails through an SMTP server.
+type Mailer struct {
+	dialer *gomail.Dialer
+	from   string
+}
+
+func NewMailer(host string, port int, username, password, from string) *Mailer {
+	return &Mailer{dialer: gomail.NewDialer(host, port, username, password), from: from}
+}
+
+func (m *Mailer) SendResetEmail(ctx context.Context, to, resetLink string) error {
+	return m.sendMail(ctx, to, "Reset your password", "Click the link to reset your password: <a href='"+resetLink+"'>Reset Password</a>")
+}
+
+func (m *Mailer) SendEmailChangeConfirmation(ctx context.Context, to, confirmLink string) error {
+	return m.sendMail(ctx, to, "Confirm your new email address", "Click the link to confirm this address for your account: <a href='"+confirmLink+"'>Confirm email</a>. If you did not ask for this change, ignore this email.")
+}
+
+// sendMail sends one email, traced as a child of the span in ctx.
+func (m *Mailer) sendMail(ctx context.Context, to, subject, body string) (err error) {
+	_, span := tracing.Start(ctx, "smtp send", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.ServerAddress(m.dialer.Host), semconv.ServerPort(m.dialer.Port)))
+	defer func() { tracing.End(span, err) }()
+
+	msg := gomail.NewMessage()
+	msg.SetHeader("From", m.from)
+	msg.SetHeader("To", to)
+	msg.SetHeader("Subject", subject)
+	msg.SetBody("text/html", body)
+	return m.dialer.DialAndSend(msg)
+}
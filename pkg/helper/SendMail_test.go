@@ -0,0 +1,45 @@
+package helper
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSendMailIsTraced(t *testing.T) {
+	// a port nothing listens on: the email fails to send
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer provider.Shutdown(context.Background())
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "UserService.UpdateMe")
+
+	mailer := NewMailer("127.0.0.1", port, "", "", "blog@example.com")
+	if err := mailer.SendEmailChangeConfirmation(ctx, "alice@example.com", "http://localhost/confirm"); err == nil {
+		t.Fatal("email sent without a server")
+	}
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 || spans[0].Name != "smtp send" {
+		t.Fatalf("spans = %v; want the email span", spans)
+	}
+	span := spans[0]
+	if span.Parent.SpanID() != parent.SpanContext().SpanID() || span.SpanKind != trace.SpanKindClient {
+		t.Errorf("email span is not a client span under the service span")
+	}
+	if span.Status.Code != codes.Error {
+		t.Errorf("email span status = %+v; want the failure", span.Status)
+	}
+}
@@ -0,0 +1,67 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"net/http"
+
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	_ "golang.org/x/image/webp"
+)
+
+var (
+	ErrUnsupportedType = errors.New("imaging: unsupported image type")
+	ErrTooManyPixels   = errors.New("imaging: image dimensions too large")
+)
+
+// MaxDimension and MaxPixels bound the images accepted. Decoding allocates memory for
+// every pixel, so a small file declaring huge dimensions could otherwise exhaust it.
+const (
+	MaxDimension = 10000
+	MaxPixels    = 40_000_000
+)
+
+// Extensions maps the accepted image types to the file extension they are stored with.
+var Extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// Inspect identifies an image from its content rather than its file name or the
+// client-supplied header, and reads its dimensions. Anything that is not a decodable
+// JPEG, PNG, GIF or WebP image is rejected, and so is an image above MaxDimension or
+// MaxPixels, before any pixel is decoded. The dimensions are those the image is shown
+// at, once turned upright.
+func Inspect(data []byte) (contentType string, width, height int, err error) {
+	contentType = http.DetectContentType(data)
+	if _, ok := Extensions[contentType]; !ok {
+		return "", 0, 0, ErrUnsupportedType
+	}
+	cfg, err := decodeConfig(data)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	if contentType == "image/jpeg" && jpegOrientation(data) >= 5 {
+		return contentType, cfg.Height, cfg.Width, nil
+	}
+	return contentType, cfg.Width, cfg.Height, nil
+}
+
+// decodeConfig reads the dimensions from the image header and checks them against the
+// limits.
+func decodeConfig(data []byte) (image.Config, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || cfg.Width <= 0 || cfg.Height <= 0 {
+		return image.Config{}, ErrMalformed
+	}
+	if cfg.Width > MaxDimension || cfg.Height > MaxDimension || cfg.Width*cfg.Height > MaxPixels {
+		return image.Config{}, ErrTooManyPixels
+	}
+	return cfg, nil
+}
@@ -0,0 +1,100 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	img.Set(0, 0, color.RGBA{255, 0, 0, 255})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// webpImage is a 4x4 transparent lossless WebP.
+var webpImage = []byte("RIFFB\x00\x00\x00WEBPVP8L6\x00\x00\x00/\x03\xc0\x00\x10\x8dRF\xf4?$\x02\x88d\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00 \x00\x80\x01\x00\x11\x99-")
+
+// declaring rewrites the dimensions in the header of a PNG, as a decompression bomb does:
+// the file stays small whatever size it claims.
+func declaring(data []byte, width, height uint32) []byte {
+	out := bytes.Clone(data)
+	// signature, then the IHDR chunk: length, type, width, height, ..., CRC of type and data
+	binary.BigEndian.PutUint32(out[16:], width)
+	binary.BigEndian.PutUint32(out[20:], height)
+	binary.BigEndian.PutUint32(out[29:], crc32.ChecksumIEEE(out[12:29]))
+	return out
+}
+
+func TestInspectDimensions(t *testing.T) {
+	small := encodePNG(t, 4, 3)
+	if contentType, width, height, err := Inspect(small); err != nil || contentType != "image/png" || width != 4 || height != 3 {
+		t.Fatalf("Inspect = %q, %dx%d, %v; want a 4x3 PNG", contentType, width, height, err)
+	}
+
+	for _, size := range []struct {
+		width, height uint32
+		err           error
+	}{
+		{MaxDimension, MaxPixels / MaxDimension, nil},
+		{MaxDimension + 1, 1, ErrTooManyPixels},
+		{1, MaxDimension + 1, ErrTooManyPixels},
+		{MaxDimension, MaxDimension, ErrTooManyPixels},
+		{50000, 50000, ErrTooManyPixels},
+		{0, 10, ErrMalformed},
+	} {
+		_, _, _, err := Inspect(declaring(small, size.width, size.height))
+		if err != size.err {
+			t.Errorf("Inspect(%dx%d) = %v; want %v", size.width, size.height, err, size.err)
+		}
+	}
+}
+
+func TestDecodeRejectsLargeImages(t *testing.T) {
+	if _, err := Decode(declaring(encodePNG(t, 4, 3), MaxDimension, MaxDimension)); !errors.Is(err, ErrTooManyPixels) {
+		t.Errorf("Decode = %v; want the size rejected before decoding", err)
+	}
+	img, err := Decode(encodePNG(t, 4, 3))
+	if err != nil || img.Bounds().Dx() != 4 {
+		t.Errorf("Decode = %v, %v; want the image", img, err)
+	}
+}
+
+func TestInspectSniffsContent(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	var jpg bytes.Buffer
+	if err := jpeg.Encode(&jpg, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	pngData := encodePNG(t, 2, 2)
+
+	for _, test := range []struct {
+		name        string
+		data        []byte
+		contentType string
+		err         error
+	}{
+		{"png", pngData, "image/png", nil},
+		{"jpeg", jpg.Bytes(), "image/jpeg", nil},
+		{"webp", webpImage, "image/webp", nil},
+		{"text", []byte("<svg xmlns='http://www.w3.org/2000/svg'/>"), "", ErrUnsupportedType},
+		{"pdf", []byte("%PDF-1.7\n"), "", ErrUnsupportedType},
+		{"truncated png", pngData[:20], "", ErrMalformed},
+	} {
+		contentType, _, _, err := Inspect(test.data)
+		if contentType != test.contentType || err != test.err {
+			t.Errorf("%s: Inspect = %q, %v; want %q, %v", test.name, contentType, err, test.contentType, test.err)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+
+	"golang.org/x/image/draw"
+)
+
+// orientationTag is the EXIF tag telling how the stored pixels must be turned to show
+// the picture upright: 1 is as stored, 2 to 8 are mirrorings and quarter turns. Phones
+// store portrait shots sideways with an orientation of 6.
+const orientationTag = 0x0112
+
+var exifHeader = []byte("Exif\x00\x00")
+
+// exifOrientation reads the orientation from the payload of an APP1 segment, returning 0
+// when the segment holds none.
+func exifOrientation(payload []byte) int {
+	tiff, ok := bytes.CutPrefix(payload, exifHeader)
+	if !ok || len(tiff) < 8 {
+		return 0
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+	// the first IFD: an entry count, then 12-byte entries of tag, type, count and value
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 0
+	}
+	n := int(order.Uint16(tiff[ifd:]))
+	for e := ifd + 2; n > 0 && e+12 <= len(tiff); e, n = e+12, n-1 {
+		if order.Uint16(tiff[e:]) != orientationTag {
+			continue
+		}
+		// a SHORT, stored at the start of the value field
+		if o := int(order.Uint16(tiff[e+8:])); order.Uint16(tiff[e+2:]) == 3 && o >= 1 && o <= 8 {
+			return o
+		}
+		return 0
+	}
+	return 0
+}
+
+// orientationSegment builds an APP1 segment holding only the given orientation.
+func orientationSegment(o int) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08") // big-endian, first IFD right after
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientationTag)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3) // SHORT
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, uint16(o))
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0) // value padding, then no next IFD
+	segment := []byte{0xFF, 0xE1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(2+len(exifHeader)+len(tiff)))
+	segment = append(segment, exifHeader...)
+	return append(segment, tiff...)
+}
+
+// jpegOrientation returns the EXIF orientation of JPEG data, 1 when it has none.
+func jpegOrientation(data []byte) int {
+	if !bytes.HasPrefix(data, []byte{0xFF, 0xD8}) {
+		return 1
+	}
+	// the metadata segments come before the start of scan
+	for i := 2; i+4 <= len(data) && data[i] == 0xFF; {
+		marker := data[i+1]
+		if marker == 0xDA || marker == 0xD9 {
+			break
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			break
+		}
+		if marker == 0xE1 {
+			if o := exifOrientation(data[i+4 : i+2+length]); o != 0 {
+				return o
+			}
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+// orient turns img the way an EXIF orientation says, so it shows upright without the tag.
+func orient(img image.Image, o int) image.Image {
+	if o < 2 || o > 8 {
+		return img
+	}
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	src := image.NewNRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Src)
+	dw, dh := w, h
+	if o >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch o {
+			case 2: // mirror
+				sx, sy = w-1-x, y
+			case 3: // half turn
+				sx, sy = w-1-x, h-1-y
+			case 4: // flip
+				sx, sy = x, h-1-y
+			case 5: // transpose
+				sx, sy = y, x
+			case 6: // quarter turn clockwise
+				sx, sy = y, h-1-x
+			case 7: // transverse
+				sx, sy = w-1-y, h-1-x
+			case 8: // quarter turn counter-clockwise
+				sx, sy = w-1-y, x
+			}
+			copy(dst.Pix[dst.PixOffset(x, y):][:4], src.Pix[src.PixOffset(sx, sy):][:4])
+		}
+	}
+	return dst
+}
@@ -0,0 +1,119 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+var (
+	red  = color.NRGBA{255, 0, 0, 255}
+	blue = color.NRGBA{0, 0, 255, 255}
+)
+
+// sideways returns a 16x8 picture, red on the left and blue on the right, as a phone
+// stores a portrait shot whose top is red.
+func sideways() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 16, 8))
+	for x := range 16 {
+		for y := range 8 {
+			if x < 8 {
+				img.Set(x, y, red)
+			} else {
+				img.Set(x, y, blue)
+			}
+		}
+	}
+	return img
+}
+
+// exifJPEG encodes img as a JPEG whose EXIF segment holds the given orientation, then a
+// GPS position.
+func exifJPEG(t *testing.T, img image.Image, orientation int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatal(err)
+	}
+	// little-endian, as most cameras write it: two entries, the orientation first
+	tiff := []byte("II\x2a\x00\x08\x00\x00\x00")
+	tiff = binary.LittleEndian.AppendUint16(tiff, 2)
+	tiff = binary.LittleEndian.AppendUint16(tiff, orientationTag)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 3)
+	tiff = binary.LittleEndian.AppendUint32(tiff, 1)
+	tiff = binary.LittleEndian.AppendUint32(tiff, uint32(orientation))
+	tiff = binary.LittleEndian.AppendUint16(tiff, 0x8825) // GPS IFD pointer
+	tiff = binary.LittleEndian.AppendUint16(tiff, 4)
+	tiff = binary.LittleEndian.AppendUint32(tiff, 1)
+	tiff = binary.LittleEndian.AppendUint32(tiff, 38)
+	tiff = binary.LittleEndian.AppendUint32(tiff, 0)
+	tiff = append(tiff, secret...)
+
+	encoded := buf.Bytes()
+	var data []byte
+	data = append(data, encoded[:2]...) // SOI
+	data = append(data, jpegSegment(0xE1, string(exifHeader)+string(tiff))...)
+	return append(data, encoded[2:]...)
+}
+
+// isColour tells whether c is close to want, JPEG being lossy.
+func isColour(c color.Color, want color.NRGBA) bool {
+	r, g, b, _ := c.RGBA()
+	near := func(v uint32, w uint8) bool { return int(v>>8)-int(w) < 40 && int(w)-int(v>>8) < 40 }
+	return near(r, want.R) && near(g, want.G) && near(b, want.B)
+}
+
+func TestExifOrientation(t *testing.T) {
+	for o := 1; o <= 8; o++ {
+		if got := jpegOrientation(exifJPEG(t, sideways(), o)); got != o {
+			t.Errorf("orientation %d read as %d", o, got)
+		}
+		if got := exifOrientation(orientationSegment(o)[4:]); got != o {
+			t.Errorf("orientation %d written as %d", o, got)
+		}
+	}
+	if got := jpegOrientation(exifJPEG(t, sideways(), 9)); got != 1 {
+		t.Errorf("invalid orientation read as %d; want 1", got)
+	}
+	if got := jpegOrientation(encodePNG(t, 2, 2)); got != 1 {
+		t.Errorf("PNG orientation = %d; want 1", got)
+	}
+}
+
+func TestOrient(t *testing.T) {
+	// where the top-left stored pixel ends up in the upright picture of 3x2 pixels
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 2))
+	img.Set(0, 0, red)
+	for o, want := range map[int]image.Point{
+		1: {0, 0}, 2: {2, 0}, 3: {2, 1}, 4: {0, 1},
+		5: {0, 0}, 6: {1, 0}, 7: {1, 2}, 8: {0, 2},
+	} {
+		upright := orient(img, o)
+		if o >= 5 && upright.Bounds().Dx() != 2 {
+			t.Errorf("orientation %d: bounds %v; want the sides swapped", o, upright.Bounds())
+		}
+		if upright.At(want.X, want.Y) != red {
+			t.Errorf("orientation %d: the corner is not at %v", o, want)
+		}
+	}
+}
+
+func TestDecodeTurnsUpright(t *testing.T) {
+	data := exifJPEG(t, sideways(), 6)
+	if _, width, height, err := Inspect(data); err != nil || width != 8 || height != 16 {
+		t.Errorf("Inspect = %dx%d, %v; want the upright 8x16", width, height, err)
+	}
+	img, err := Decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 8 || b.Dy() != 16 {
+		t.Fatalf("decoded %v; want 8x16", b)
+	}
+	if !isColour(img.At(4, 3), red) || !isColour(img.At(4, 12), blue) {
+		t.Errorf("decoded picture is not upright: %v at the top, %v at the bottom", img.At(4, 3), img.At(4, 12))
+	}
+}
@@ -0,0 +1,86 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+
+	"golang.org/x/image/draw"
+)
+
+// FormatJPEG is the output format of resized variants.
+const FormatJPEG = "jpeg"
+
+const jpegQuality = 82
+
+// Formats lists the encodings every variant is produced in. WebP is left out until a
+// lossy encoder is available: a lossless WebP of a photo is larger than its JPEG.
+var Formats = []string{FormatJPEG}
+
+// Decode decodes a JPEG, PNG, GIF (first frame) or WebP image within the limits
+// Inspect checks. A JPEG is turned upright as its EXIF orientation says.
+func Decode(data []byte) (image.Image, error) {
+	if _, err := decodeConfig(data); err != nil {
+		return nil, err
+	}
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if format == "jpeg" {
+		img = orient(img, jpegOrientation(data))
+	}
+	return img, nil
+}
+
+// VariantWidths picks which of the configured widths to produce for an image of the
+// given width. Images are never upscaled: the widths above the original are replaced by
+// a single variant at the original width, so displays wider than the image still get an
+// optimized file. An image wider than every configured width is capped at the largest.
+func VariantWidths(widths []int, original int) []int {
+	var picked []int
+	capped := false
+	for _, w := range widths {
+		switch {
+		case w <= 0:
+		case w < original:
+			picked = append(picked, w)
+		default:
+			capped = true
+		}
+	}
+	if capped && original > 0 {
+		picked = append(picked, original)
+	}
+	return picked
+}
+
+// Resize scales img to the given width, keeping its aspect ratio.
+func Resize(img image.Image, width int) image.Image {
+	b := img.Bounds()
+	height := b.Dy() * width / b.Dx()
+	if height < 1 {
+		height = 1
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+// Encode encodes img in the given format and returns the data and its content type.
+// JPEG has no alpha channel, so transparent areas are flattened onto white.
+func Encode(img image.Image, format string) ([]byte, string, error) {
+	if format != FormatJPEG {
+		return nil, "", fmt.Errorf("imaging: cannot encode %s", format)
+	}
+	var buf bytes.Buffer
+	flat := image.NewRGBA(img.Bounds())
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), img, img.Bounds().Min, draw.Over)
+	if err := jpeg.Encode(&buf, flat, &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "image/jpeg", nil
+}
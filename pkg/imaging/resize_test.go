@@ -0,0 +1,44 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"slices"
+	"testing"
+)
+
+func TestVariantWidths(t *testing.T) {
+	widths := []int{320, 768, 1280}
+	for _, test := range []struct {
+		original int
+		want     []int
+	}{
+		{4000, []int{320, 768, 1280}},
+		{1280, []int{320, 768, 1280}},
+		// between two widths: the original size rather than nothing above 768
+		{1000, []int{320, 768, 1000}},
+		{500, []int{320, 500}},
+		{100, []int{100}},
+		{0, nil},
+	} {
+		if got := VariantWidths(widths, test.original); !slices.Equal(got, test.want) {
+			t.Errorf("VariantWidths(%d) = %v; want %v", test.original, got, test.want)
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	img := Resize(image.NewNRGBA(image.Rect(0, 0, 40, 20)), 10)
+	data, contentType, err := Encode(img, FormatJPEG)
+	if err != nil || contentType != "image/jpeg" {
+		t.Fatalf("Encode = %q, %v", contentType, err)
+	}
+	decoded, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil || decoded.Bounds().Dx() != 10 || decoded.Bounds().Dy() != 5 {
+		t.Errorf("encoded variant = %v, %v; want 10x5", decoded, err)
+	}
+	if _, _, err := Encode(img, "webp"); err == nil {
+		t.Error("encoded a format without an encoder")
+	}
+}
@@ -0,0 +1,166 @@
+// Package imaging inspects and cleans uploaded images.
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+var ErrMalformed = errors.New("imaging: malformed image")
+
+// StripMetadata removes EXIF, XMP, IPTC and text metadata (camera details, GPS
+// position, comments) from JPEG, PNG and WebP data without re-encoding the pixels.
+// Colour profiles are kept, and so is the orientation of a JPEG, alone in a minimal EXIF
+// segment, so photos taken sideways still show upright. Other formats are returned
+// unchanged.
+func StripMetadata(contentType string, data []byte) ([]byte, error) {
+	switch contentType {
+	case "image/jpeg":
+		return stripJPEG(data)
+	case "image/png":
+		return stripPNG(data)
+	case "image/webp":
+		return stripWebP(data)
+	default:
+		return data, nil
+	}
+}
+
+// stripJPEG drops APP1 (EXIF/XMP), APP13 (IPTC) and COM segments, writing back an EXIF
+// segment with only the orientation when it is not the default. Everything from the
+// start-of-scan marker on is entropy-coded image data and is copied verbatim.
+func stripJPEG(data []byte) ([]byte, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, ErrMalformed
+	}
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:2])
+	oriented := false
+	i := 2
+	for i < len(data) {
+		if data[i] != 0xFF {
+			return nil, ErrMalformed
+		}
+		// Markers may be preceded by any number of 0xFF fill bytes.
+		for i < len(data) && data[i] == 0xFF {
+			i++
+		}
+		if i >= len(data) {
+			return nil, ErrMalformed
+		}
+		marker := data[i]
+		i++
+		// Standalone markers carry no length.
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			out.Write([]byte{0xFF, marker})
+			continue
+		}
+		if marker == 0xD9 {
+			out.Write([]byte{0xFF, marker})
+			return out.Bytes(), nil
+		}
+		if i+2 > len(data) {
+			return nil, ErrMalformed
+		}
+		length := int(binary.BigEndian.Uint16(data[i:]))
+		if length < 2 || i+length > len(data) {
+			return nil, ErrMalformed
+		}
+		segment := data[i : i+length]
+		i += length
+		if marker == 0xDA {
+			out.Write([]byte{0xFF, marker})
+			out.Write(segment)
+			out.Write(data[i:])
+			return out.Bytes(), nil
+		}
+		if marker == 0xE1 {
+			if o := exifOrientation(segment[2:]); o > 1 && !oriented {
+				out.Write(orientationSegment(o))
+				oriented = true
+			}
+			continue
+		}
+		if marker == 0xED || marker == 0xFE {
+			continue
+		}
+		out.Write([]byte{0xFF, marker})
+		out.Write(segment)
+	}
+	return nil, ErrMalformed
+}
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// pngMetadataChunks are the ancillary chunks that carry text, EXIF or timestamps.
+var pngMetadataChunks = map[string]bool{"tEXt": true, "zTXt": true, "iTXt": true, "eXIf": true, "tIME": true}
+
+func stripPNG(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, ErrMalformed
+	}
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(pngSignature)
+	i := len(pngSignature)
+	for i < len(data) {
+		if i+8 > len(data) {
+			return nil, ErrMalformed
+		}
+		length := int(binary.BigEndian.Uint32(data[i:]))
+		typ := string(data[i+4 : i+8])
+		end := i + 12 + length // length, type, data, CRC
+		if length < 0 || end > len(data) {
+			return nil, ErrMalformed
+		}
+		if !pngMetadataChunks[typ] {
+			out.Write(data[i:end])
+		}
+		i = end
+		if typ == "IEND" {
+			return out.Bytes(), nil
+		}
+	}
+	return nil, ErrMalformed
+}
+
+// stripWebP drops the EXIF and XMP chunks of an extended WebP file and clears the
+// matching flags in its VP8X header.
+func stripWebP(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, ErrMalformed
+	}
+	const (
+		flagXMP  = 0x04
+		flagEXIF = 0x08
+	)
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:12])
+	i := 12
+	for i < len(data) {
+		if i+8 > len(data) {
+			return nil, ErrMalformed
+		}
+		fourCC := string(data[i : i+4])
+		size := int(binary.LittleEndian.Uint32(data[i+4:]))
+		end := i + 8 + size + size%2 // chunks are padded to an even size
+		if size < 0 || end > len(data) {
+			return nil, ErrMalformed
+		}
+		switch fourCC {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			chunk := append([]byte(nil), data[i:end]...)
+			if size > 0 {
+				chunk[8] &^= flagXMP | flagEXIF
+			}
+			out.Write(chunk)
+		default:
+			out.Write(data[i:end])
+		}
+		i = end
+	}
+	result := out.Bytes()
+	binary.LittleEndian.PutUint32(result[4:], uint32(len(result)-8))
+	return result, nil
+}
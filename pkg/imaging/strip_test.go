@@ -0,0 +1,156 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/jpeg"
+	"testing"
+)
+
+const secret = "GPS 48.8584 N 2.2945 E"
+
+// jpegSegment builds a JPEG marker segment.
+func jpegSegment(marker byte, payload string) []byte {
+	seg := []byte{0xFF, marker, 0, 0}
+	binary.BigEndian.PutUint16(seg[2:], uint16(len(payload)+2))
+	return append(seg, payload...)
+}
+
+// pngChunk builds a PNG chunk with its CRC.
+func pngChunk(typ, payload string) []byte {
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(payload)))
+	chunk = append(chunk, typ...)
+	chunk = append(chunk, payload...)
+	return binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+}
+
+// riffChunk builds a WebP chunk, padded to an even size.
+func riffChunk(fourCC string, payload []byte) []byte {
+	chunk := append([]byte(fourCC), binary.LittleEndian.AppendUint32(nil, uint32(len(payload)))...)
+	chunk = append(chunk, payload...)
+	if len(payload)%2 == 1 {
+		chunk = append(chunk, 0)
+	}
+	return chunk
+}
+
+func TestStripJPEG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 8, 8)), nil); err != nil {
+		t.Fatal(err)
+	}
+	encoded := buf.Bytes()
+	var data []byte
+	data = append(data, encoded[:2]...) // SOI
+	data = append(data, jpegSegment(0xE1, "Exif\x00\x00"+secret)...)
+	data = append(data, jpegSegment(0xE2, "ICC_PROFILE\x00profile")...)
+	data = append(data, jpegSegment(0xED, "Photoshop 3.0\x00"+secret)...)
+	data = append(data, jpegSegment(0xFE, secret)...)
+	data = append(data, encoded[2:]...)
+
+	stripped, err := StripMetadata("image/jpeg", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(stripped, []byte(secret)) {
+		t.Error("metadata left in the JPEG")
+	}
+	if !bytes.Contains(stripped, []byte("ICC_PROFILE")) {
+		t.Error("colour profile removed")
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(stripped)); err != nil {
+		t.Errorf("stripped JPEG does not decode: %v", err)
+	}
+	if _, err := StripMetadata("image/jpeg", []byte("not a jpeg")); err != ErrMalformed {
+		t.Errorf("StripMetadata(garbage) = %v; want ErrMalformed", err)
+	}
+}
+
+func TestStripJPEGKeepsOrientation(t *testing.T) {
+	stripped, err := StripMetadata("image/jpeg", exifJPEG(t, sideways(), 6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(stripped, []byte(secret)) {
+		t.Error("metadata left in the JPEG")
+	}
+	if o := jpegOrientation(stripped); o != 6 {
+		t.Errorf("orientation after stripping = %d; want 6", o)
+	}
+	img, err := Decode(stripped)
+	if err != nil || !isColour(img.At(4, 3), red) {
+		t.Errorf("stripped JPEG does not decode upright: %v", err)
+	}
+
+	// the default orientation needs no segment
+	stripped, err = StripMetadata("image/jpeg", exifJPEG(t, sideways(), 1))
+	if err != nil || bytes.Contains(stripped, exifHeader) {
+		t.Errorf("StripMetadata kept an EXIF segment for the default orientation: %v", err)
+	}
+}
+
+func TestStripPNG(t *testing.T) {
+	encoded := encodePNG(t, 4, 4)
+	iend := len(encoded) - 12
+	var data []byte
+	data = append(data, encoded[:iend]...)
+	data = append(data, pngChunk("tEXt", "Comment\x00"+secret)...)
+	data = append(data, pngChunk("eXIf", "MM\x00\x2a"+secret)...)
+	data = append(data, pngChunk("tIME", "\x07\xe8\x05\x01\x0c\x00\x00")...)
+	data = append(data, encoded[iend:]...)
+
+	stripped, err := StripMetadata("image/png", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(stripped, []byte(secret)) || bytes.Contains(stripped, []byte("tIME")) {
+		t.Error("metadata left in the PNG")
+	}
+	if !bytes.Equal(stripped, encoded) {
+		t.Error("image chunks changed")
+	}
+	if _, err := StripMetadata("image/png", encoded[:iend]); err != ErrMalformed {
+		t.Errorf("StripMetadata(no IEND) = %v; want ErrMalformed", err)
+	}
+}
+
+func TestStripWebP(t *testing.T) {
+	encoded := webpImage
+	// an extended file: a VP8X header announcing EXIF and XMP, the image, then the metadata
+	vp8x := make([]byte, 10)
+	vp8x[0] = 0x08 | 0x04
+	vp8x[4], vp8x[7] = 3, 3 // canvas size minus one
+	body := []byte("WEBP")
+	body = append(body, riffChunk("VP8X", vp8x)...)
+	body = append(body, encoded[12:]...)
+	body = append(body, riffChunk("EXIF", []byte("MM\x00\x2a"+secret))...)
+	body = append(body, riffChunk("XMP ", []byte("<x:xmpmeta>"+secret+"</x:xmpmeta>"))...)
+	data := append([]byte("RIFF"), binary.LittleEndian.AppendUint32(nil, uint32(len(body)))...)
+	data = append(data, body...)
+
+	stripped, err := StripMetadata("image/webp", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(stripped, []byte(secret)) {
+		t.Error("metadata left in the WebP")
+	}
+	if flags := stripped[20]; flags&(0x08|0x04) != 0 {
+		t.Errorf("VP8X flags = %#x; want EXIF and XMP cleared", flags)
+	}
+	if size := binary.LittleEndian.Uint32(stripped[4:]); int(size) != len(stripped)-8 {
+		t.Errorf("RIFF size = %d; want %d", size, len(stripped)-8)
+	}
+	if _, _, _, err := Inspect(stripped); err != nil {
+		t.Errorf("stripped WebP is not readable: %v", err)
+	}
+}
+
+func TestStripOtherFormats(t *testing.T) {
+	data := []byte("GIF89a")
+	if out, err := StripMetadata("image/gif", data); err != nil || !bytes.Equal(out, data) {
+		t.Errorf("StripMetadata(gif) = %q, %v; want it unchanged", out, err)
+	}
+}
@@ -0,0 +1,25 @@
+package metrics
+
+import (
+	"blog-api/pkg/pubsub"
+	"context"
+)
+
+// InstrumentBus counts the events published through bus by type. Only the publishing
+// instance counts an event, so the totals of replicas sharing a bus add up.
+func (m *Metrics) InstrumentBus(bus pubsub.Bus) pubsub.Bus {
+	return &countingBus{Bus: bus, events: m}
+}
+
+type countingBus struct {
+	pubsub.Bus
+	events *Metrics
+}
+
+func (b *countingBus) Publish(ctx context.Context, topic, eventType string, data interface{}) error {
+	if err := b.Bus.Publish(ctx, topic, eventType, data); err != nil {
+		return err
+	}
+	b.events.events.WithLabelValues(eventType).Inc()
+	return nil
+}
@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unmatched is the route label of requests no route matched, so that scanners probing
+// random paths cannot create series without bound.
+const unmatched = "unmatched"
+
+// Middleware records every request under its route template, e.g. /posts/:id, rather
+// than its path.
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		m.inFlight.Inc()
+		defer m.inFlight.Dec()
+
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = unmatched
+		}
+		method := ctx.Request.Method
+		m.requests.WithLabelValues(method, route, strconv.Itoa(ctx.Writer.Status())).Inc()
+		m.duration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,98 @@
+// Package metrics exposes the server's Prometheus metrics: rate, errors and duration of
+// HTTP requests per route, database queries and connection pool, and business events.
+// Every metric is named blog_*, besides the standard Go, process and database/sql ones.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics holds the collectors of one server, on their own registry.
+type Metrics struct {
+	Registry *prometheus.Registry
+
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight prometheus.Gauge
+
+	queries     *prometheus.HistogramVec
+	queryErrors *prometheus.CounterVec
+
+	registrations prometheus.Counter
+	logins        *prometheus.CounterVec
+	events        *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "blog_http_requests_total",
+			Help: "HTTP requests by method, route template and status code.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "blog_http_request_duration_seconds",
+			Help:    "Time to serve HTTP requests, by method and route template.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "blog_http_requests_in_flight",
+			Help: "HTTP requests being served.",
+		}),
+		queries: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "blog_db_query_duration_seconds",
+			Help:    "Time of database queries, by operation and table.",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation", "table"}),
+		queryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "blog_db_query_errors_total",
+			Help: "Failed database queries, by operation and table. Lookups finding no row are not errors.",
+		}, []string{"operation", "table"}),
+		registrations: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "blog_user_registrations_total",
+			Help: "Accounts created through sign-up.",
+		}),
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "blog_user_logins_total",
+			Help: "Login attempts by result, success or failure.",
+		}, []string{"result"}),
+		events: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "blog_events_published_total",
+			Help: "Business events published by this instance, by type: post.published, comment.created...",
+		}, []string{"type"}),
+	}
+	m.Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests, m.duration, m.inFlight,
+		m.queries, m.queryErrors,
+		m.registrations, m.logins, m.events,
+	)
+	// the result label is known in advance, so both series exist from the start
+	m.logins.WithLabelValues("success")
+	m.logins.WithLabelValues("failure")
+	return m
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{Registry: m.Registry})
+}
+
+// UserRegistered counts a sign-up.
+func (m *Metrics) UserRegistered() {
+	m.registrations.Inc()
+}
+
+// LoginAttempted counts a login, successful or not.
+func (m *Metrics) LoginAttempted(ok bool) {
+	result := "failure"
+	if ok {
+		result = "success"
+	}
+	m.logins.WithLabelValues(result).Inc()
+}
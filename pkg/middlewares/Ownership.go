@@ -32,7 +32,11 @@ func OwnerOrAdminMiddleware(db *gorm.DB) gin.HandlerFunc{
         }
         uid := uint(uidFloat)
 
+        // GET routes under /posts name the parameter post_id, the others id
         postIDParam := ctx.Param("id")
+        if postIDParam == "" {
+            postIDParam = ctx.Param("post_id")
+        }
         postID, err := strconv.ParseUint(postIDParam, 10, 64)
         if err != nil {
             utils.SendFail(ctx, http.StatusBadRequest, "400", utils.ErrInvalidPostID, nil)
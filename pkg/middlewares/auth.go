@@ -9,7 +9,8 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func AuthMiddleware() gin.HandlerFunc{
+// AuthMiddleware rejects requests without a valid token signed with secret.
+func AuthMiddleware(secret []byte) gin.HandlerFunc{
 	return func(ctx *gin.Context){
 		// get token from header
 		tokenString := ctx.GetHeader("Authorization")
@@ -25,7 +26,7 @@ func AuthMiddleware() gin.HandlerFunc{
             tokenString = tokenString[7:]
         }
 		
-		token, err := utils.ValidateToken(tokenString)
+		token, err := utils.ValidateToken(secret, tokenString)
 		if err != nil {
 			if errors.Is(err, jwt.ErrTokenExpired) {
 				log.Println("Auth failed: token expired")
@@ -49,4 +50,24 @@ func AuthMiddleware() gin.HandlerFunc{
 		}
 		ctx.Next()
 	}
-}
\ No newline at end of file
+}
+
+// OptionalAuthMiddleware identifies the caller when a valid token is sent, for public
+// endpoints that personalise their response. Missing or invalid tokens are ignored.
+func OptionalAuthMiddleware(secret []byte) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tokenString := ctx.GetHeader("Authorization")
+		if len(tokenString) > 7 && tokenString[:7] == "Bearer " {
+			tokenString = tokenString[7:]
+		}
+		if tokenString != "" {
+			if token, err := utils.ValidateToken(secret, tokenString); err == nil {
+				if claims, ok := token.Claims.(jwt.MapClaims); ok {
+					ctx.Set("userID", claims["user_id"])
+					ctx.Set("role", claims["role"])
+				}
+			}
+		}
+		ctx.Next()
+	}
+}
@@ -0,0 +1,21 @@
+package middlewares
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CancelOnShutdown cancels the request context when shutdown is done, so that long-lived
+// requests such as event streams end instead of holding up the server's shutdown.
+func CancelOnShutdown(shutdown context.Context) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		reqCtx, cancel := context.WithCancel(ctx.Request.Context())
+		defer cancel()
+		stop := context.AfterFunc(shutdown, cancel)
+		defer stop()
+
+		ctx.Request = ctx.Request.WithContext(reqCtx)
+		ctx.Next()
+	}
+}
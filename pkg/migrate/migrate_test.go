@@ -0,0 +1,257 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var migrations = fstest.MapFS{
+	"0001_users.up.sql":     {Data: []byte("CREATE TABLE users (id int)")},
+	"0001_users.down.sql":   {Data: []byte("DROP TABLE users")},
+	"0002_posts.up.sql":     {Data: []byte("CREATE TABLE posts (id int)")},
+	"0002_posts.down.sql":   {Data: []byte("DROP TABLE posts")},
+	"0003_index.up.sql":     {Data: []byte(noTransaction + "\nCREATE INDEX CONCURRENTLY idx ON posts (id)")},
+	"0003_index.down.sql":   {Data: []byte(noTransaction + "\nDROP INDEX CONCURRENTLY idx")},
+	"README.md":             {Data: []byte("not a migration")},
+	"0004_no_down.up.sql":   {Data: []byte("CREATE TABLE tags (id int)")},
+	"0005_extra.up.sql.bak": {Data: []byte("ignored")},
+}
+
+func newMigrator(t *testing.T) (*Migrator, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	m, err := New(db, migrations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m, mock
+}
+
+func exec(query string) string { return regexp.QuoteMeta(query) }
+
+// expectLock expects the migration lock to be taken and the applied versions read.
+func expectLock(mock sqlmock.Sqlmock, applied ...int64) {
+	mock.ExpectExec(exec("SELECT pg_advisory_lock($1)")).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(exec("CREATE TABLE IF NOT EXISTS schema_migrations")).WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows([]string{"version", "applied_at"})
+	for _, version := range applied {
+		rows.AddRow(version, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	}
+	mock.ExpectQuery(exec("SELECT version, applied_at FROM schema_migrations")).WillReturnRows(rows)
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(exec("SELECT pg_advisory_unlock($1)")).WithArgs(lockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func versions(migrations []Migration) []int64 {
+	var v []int64
+	for _, m := range migrations {
+		v = append(v, m.Version)
+	}
+	return v
+}
+
+func TestLoad(t *testing.T) {
+	m, _ := newMigrator(t)
+	if got := versions(m.migrations); len(got) != 4 || got[0] != 1 || got[3] != 4 {
+		t.Fatalf("versions = %v; want 1 to 4 in order", got)
+	}
+	if m.migrations[1].Name != "posts" || m.migrations[1].Down != "DROP TABLE posts" {
+		t.Errorf("migration 2 = %+v", m.migrations[1])
+	}
+
+	for name, fsys := range map[string]fstest.MapFS{
+		"bad name":   {"1-users.up.sql": {}},
+		"two names":  {"0001_users.up.sql": {Data: []byte("x")}, "0001_people.down.sql": {}},
+		"no up file": {"0001_users.down.sql": {Data: []byte("x")}},
+	} {
+		if _, err := load(fsys); err == nil {
+			t.Errorf("%s: loaded", name)
+		}
+	}
+}
+
+func TestUp(t *testing.T) {
+	m, mock := newMigrator(t)
+	expectLock(mock, 1)
+	mock.ExpectBegin()
+	mock.ExpectExec(exec("CREATE TABLE posts (id int)")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(exec("INSERT INTO schema_migrations (version, name) VALUES ($1, $2)")).
+		WithArgs(int64(2), "posts").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	// outside a transaction
+	mock.ExpectExec(exec("CREATE INDEX CONCURRENTLY idx ON posts (id)")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(exec("INSERT INTO schema_migrations (version, name) VALUES ($1, $2)")).
+		WithArgs(int64(3), "index").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectBegin()
+	mock.ExpectExec(exec("CREATE TABLE tags (id int)")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(exec("INSERT INTO schema_migrations (version, name) VALUES ($1, $2)")).
+		WithArgs(int64(4), "no_down").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUnlock(mock)
+
+	applied, err := m.Up(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(applied); len(got) != 3 || got[0] != 2 || got[2] != 4 {
+		t.Errorf("applied %v; want 2, 3 and 4", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUpFailure(t *testing.T) {
+	m, mock := newMigrator(t)
+	expectLock(mock, 1)
+	mock.ExpectBegin()
+	mock.ExpectExec(exec("CREATE TABLE posts (id int)")).WillReturnError(errors.New("syntax error"))
+	// the migration is rolled back and not recorded, and the next ones are not run
+	mock.ExpectRollback()
+	expectUnlock(mock)
+
+	applied, err := m.Up(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "migration 2_posts up: syntax error") {
+		t.Errorf("error = %v; want the failed migration", err)
+	}
+	if len(applied) != 0 {
+		t.Errorf("applied %v", versions(applied))
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestUpFailureWithoutTransaction(t *testing.T) {
+	m, mock := newMigrator(t)
+	expectLock(mock, 1, 2)
+	mock.ExpectExec(exec("CREATE INDEX CONCURRENTLY idx ON posts (id)")).WillReturnError(errors.New("deadlock detected"))
+	// not recorded, so the next run tries again
+	expectUnlock(mock)
+
+	if _, err := m.Up(context.Background()); err == nil || !strings.Contains(err.Error(), "migration 3_index up") {
+		t.Errorf("error = %v; want the failed migration", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLockFailure(t *testing.T) {
+	m, mock := newMigrator(t)
+	mock.ExpectExec(exec("SELECT pg_advisory_lock($1)")).WithArgs(lockKey).WillReturnError(context.DeadlineExceeded)
+
+	if _, err := m.Up(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v; want the lock failure", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDown(t *testing.T) {
+	m, mock := newMigrator(t)
+	expectLock(mock, 1, 2, 3)
+	mock.ExpectExec(exec("DROP INDEX CONCURRENTLY idx")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(exec("DELETE FROM schema_migrations WHERE version = $1")).
+		WithArgs(int64(3)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectBegin()
+	mock.ExpectExec(exec("DROP TABLE posts")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(exec("DELETE FROM schema_migrations WHERE version = $1")).
+		WithArgs(int64(2)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	expectUnlock(mock)
+
+	reverted, err := m.Down(context.Background(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := versions(reverted); len(got) != 2 || got[0] != 3 || got[1] != 2 {
+		t.Errorf("rolled back %v; want 3 then 2", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDownWithoutDownFile(t *testing.T) {
+	m, mock := newMigrator(t)
+	expectLock(mock, 1, 2, 3, 4)
+	expectUnlock(mock)
+
+	if _, err := m.Down(context.Background(), 1); err == nil || !strings.Contains(err.Error(), "has no down file") {
+		t.Errorf("error = %v; want the missing down file", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	m, mock := newMigrator(t)
+	expectLock(mock, 1, 2)
+	expectUnlock(mock)
+
+	statuses, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 4 || statuses[1].AppliedAt == nil || statuses[2].AppliedAt != nil {
+		t.Errorf("statuses = %+v; want 1 and 2 applied", statuses)
+	}
+
+	// a database migrated by a newer build
+	expectLock(mock, 1, 2, 9)
+	expectUnlock(mock)
+	if _, err := m.Status(context.Background()); err == nil || !strings.Contains(err.Error(), "migration 9") {
+		t.Errorf("error = %v; want the unknown version", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPending(t *testing.T) {
+	m, mock := newMigrator(t)
+	mock.ExpectQuery(exec("SELECT to_regclass('schema_migrations') IS NOT NULL")).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectQuery(exec("SELECT to_regclass('schema_migrations') IS NOT NULL")).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(exec("SELECT version FROM schema_migrations")).
+		WillReturnRows(sqlmock.NewRows([]string{"version"}).AddRow(int64(1)).AddRow(int64(2)))
+
+	// no lock is taken
+	pending, err := m.Pending(context.Background())
+	if err != nil || len(pending) != 4 {
+		t.Errorf("pending on a new database = %v, %v; want every migration", versions(pending), err)
+	}
+	pending, err = m.Pending(context.Background())
+	if got := versions(pending); err != nil || len(got) != 2 || got[0] != 3 {
+		t.Errorf("pending = %v, %v; want 3 and 4", got, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCommandUsage(t *testing.T) {
+	m, _ := newMigrator(t)
+	for _, args := range [][]string{nil, {"sideways"}, {"down", "0"}, {"down", "two"}} {
+		if err := m.Command(context.Background(), args, &strings.Builder{}); !errors.Is(err, ErrUsage) {
+			t.Errorf("Command(%q) = %v; want the usage", args, err)
+		}
+	}
+}
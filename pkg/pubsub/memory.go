@@ -0,0 +1,69 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// MemoryBus delivers events within a single process and keeps the most recent
+// events of all topics for resuming subscribers.
+type MemoryBus struct {
+	hub *hub
+
+	mu      sync.Mutex
+	lastID  uint64
+	history []Event
+	size    int
+}
+
+func NewMemoryBus(historySize int) *MemoryBus {
+	return &MemoryBus{hub: newHub(), size: historySize}
+}
+
+func (b *MemoryBus) Publish(ctx context.Context, topic, eventType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.lastID++
+	ev := Event{ID: b.lastID, Topic: topic, Type: eventType, Data: payload}
+	b.history = append(b.history, ev)
+	if len(b.history) > b.size {
+		b.history = b.history[len(b.history)-b.size:]
+	}
+	// Deliver while holding the lock so subscribers see events in ID order.
+	b.hub.deliver(ev)
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *MemoryBus) Subscribe(ctx context.Context, topic string, lastEventID uint64) (<-chan Event, error) {
+	return b.hub.subscribe(ctx, topic, func() ([]Event, error) {
+		if lastEventID == 0 {
+			return nil, nil
+		}
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		// events after lastEventID fell out of the history, or were published before a restart
+		if lastEventID > b.lastID || (len(b.history) > 0 && b.history[0].ID > lastEventID+1) {
+			return nil, errGap
+		}
+		var events []Event
+		for _, ev := range b.history {
+			if ev.Topic == topic && ev.ID > lastEventID {
+				events = append(events, ev)
+			}
+		}
+		if len(events) > maxQueued {
+			return nil, errGap
+		}
+		return events, nil
+	})
+}
+
+func (b *MemoryBus) Close() error {
+	return nil
+}
@@ -0,0 +1,138 @@
+package pubsub
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// next waits for the next event, or for the channel to close.
+func next(t *testing.T, events <-chan Event) (Event, bool) {
+	t.Helper()
+	select {
+	case ev, ok := <-events:
+		return ev, ok
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event")
+		return Event{}, false
+	}
+}
+
+func TestMemoryBus(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bus := NewMemoryBus(10)
+	defer bus.Close()
+
+	events, err := bus.Subscribe(ctx, "post:1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus.Publish(ctx, "post:1", "comment.created", map[string]int{"id": 1})
+	bus.Publish(ctx, "post:2", "comment.created", map[string]int{"id": 2})
+	bus.Publish(ctx, "post:1", "comment.deleted", map[string]int{"id": 1})
+
+	first, _ := next(t, events)
+	second, _ := next(t, events)
+	if first.ID != 1 || first.Type != "comment.created" || string(first.Data) != `{"id":1}` {
+		t.Errorf("first event = %+v", first)
+	}
+	// events of other topics are not delivered, but take an ID
+	if second.ID != 3 || second.Type != "comment.deleted" {
+		t.Errorf("second event = %+v; want the deletion, ID 3", second)
+	}
+
+	if err := bus.Publish(ctx, "post:1", "bad", func() {}); err == nil {
+		t.Error("published data that is not JSON")
+	}
+
+	cancel()
+	if _, ok := next(t, events); ok {
+		t.Error("stream still open after its context is done")
+	}
+}
+
+func TestMemoryBusResume(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bus := NewMemoryBus(3)
+	for i := 0; i < 5; i++ {
+		bus.Publish(ctx, "post:1", "comment.created", i)
+	}
+
+	// events 1 and 2 fell out of the history; 3 is before the client's position
+	events, err := bus.Subscribe(ctx, "post:1", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus.Publish(ctx, "post:1", "comment.created", 5)
+	for _, want := range []uint64{4, 5, 6} {
+		if ev, _ := next(t, events); ev.ID != want {
+			t.Fatalf("event %d; want %d", ev.ID, want)
+		}
+	}
+}
+
+func TestMemoryBusDropsSlowSubscribers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bus := NewMemoryBus(10)
+	events, err := bus.Subscribe(ctx, "post:1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const published = maxQueued + 10
+	for i := 0; i < published; i++ {
+		bus.Publish(ctx, "post:1", "comment.created", i)
+	}
+
+	received := 0
+	var last Event
+	for {
+		ev, ok := next(t, events)
+		if !ok {
+			break
+		}
+		received++
+		last = ev
+	}
+	if received >= published {
+		t.Errorf("received all %d events; want the stream closed once it fell behind", published)
+	}
+	if last.Type != TypeReset {
+		t.Errorf("last event = %+v; want a reset", last)
+	}
+}
+
+func TestMemoryBusResetsMissedEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	publish := func(historySize, n int) *MemoryBus {
+		bus := NewMemoryBus(historySize)
+		for i := 0; i < n; i++ {
+			bus.Publish(ctx, "post:1", "comment.created", i)
+		}
+		return bus
+	}
+
+	for _, tc := range []struct {
+		name        string
+		bus         *MemoryBus
+		lastEventID uint64
+	}{
+		{"too many to replay", publish(maxQueued+10, maxQueued+5), 1},
+		{"out of the history", publish(3, 5), 1},
+		{"published before a restart", publish(3, 0), 7},
+	} {
+		events, err := tc.bus.Subscribe(ctx, "post:1", tc.lastEventID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ev, _ := next(t, events); ev.Type != TypeReset || ev.ID != 0 {
+			t.Errorf("%s: first event = %+v; want a reset", tc.name, ev)
+		}
+		if _, ok := next(t, events); ok {
+			t.Errorf("%s: stream still open after the reset", tc.name)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+const (
+	notifyChannel = "pubsub_events"
+	retention     = 24 * time.Hour
+
+	// publishLock is the advisory lock key serializing publishers, so that event IDs are
+	// assigned in commit order and a subscriber resuming after an ID misses nothing.
+	publishLock = 0x70756273756201
+
+	// catchUpGrace is how far back the listener looks after a reconnect, covering events
+	// committed late by other replicas and the skew between their clocks.
+	catchUpGrace = time.Minute
+)
+
+// eventRecord is the persisted form of an Event. Rows are kept for a day so that
+// subscribers on any replica can resume from Last-Event-ID.
+type eventRecord struct {
+	ID        uint64    `gorm:"primaryKey"`
+	Topic     string    `gorm:"type:varchar(100);not null;index"`
+	Type      string    `gorm:"type:varchar(50);not null"`
+	Data      []byte    `gorm:"type:jsonb;not null"`
+	CreatedAt time.Time `gorm:"index"`
+}
+
+func (eventRecord) TableName() string {
+	return "pubsub_events"
+}
+
+func (r eventRecord) event() Event {
+	return Event{ID: r.ID, Topic: r.Topic, Type: r.Type, Data: r.Data}
+}
+
+// PostgresBus stores events in a table and announces them with LISTEN/NOTIFY,
+// so events published on one replica reach subscribers on every replica.
+type PostgresBus struct {
+	db     *gorm.DB
+	dsn    string
+	hub    *hub
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	// recent holds the events delivered by the listener within catchUpGrace of the newest
+	// one, so that catching up after a reconnect delivers each event once.
+	recent map[uint64]time.Time
+	newest time.Time
+}
+
+// NewPostgresBus starts listening for the events of every replica. The pubsub_events table
+// is created by the database migrations.
+func NewPostgresBus(db *gorm.DB, dsn string) *PostgresBus {
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &PostgresBus{
+		db: db, dsn: dsn, hub: newHub(), cancel: cancel, done: make(chan struct{}),
+		recent: make(map[uint64]time.Time),
+	}
+	go b.listen(ctx)
+	return b
+}
+
+func (b *PostgresBus) Publish(ctx context.Context, topic, eventType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return b.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// held until commit, so no event with a lower ID can commit after this one
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", publishLock).Error; err != nil {
+			return err
+		}
+		record := eventRecord{Topic: topic, Type: eventType, Data: payload}
+		if err := tx.Create(&record).Error; err != nil {
+			return err
+		}
+		// NOTIFY is delivered on commit, after the row is visible to listeners.
+		return tx.Exec("SELECT pg_notify(?, ?)", notifyChannel, strconv.FormatUint(record.ID, 10)).Error
+	})
+}
+
+func (b *PostgresBus) Subscribe(ctx context.Context, topic string, lastEventID uint64) (<-chan Event, error) {
+	return b.hub.subscribe(ctx, topic, func() ([]Event, error) {
+		if lastEventID == 0 {
+			return nil, nil
+		}
+		// an event the table no longer holds was pruned, along with those after it
+		var known int64
+		if err := b.db.WithContext(ctx).Model(&eventRecord{}).Where("id = ?", lastEventID).Count(&known).Error; err != nil {
+			return nil, err
+		}
+		if known == 0 {
+			return nil, errGap
+		}
+		var records []eventRecord
+		err := b.db.WithContext(ctx).Where("topic = ? AND id > ?", topic, lastEventID).Order("id asc").Limit(maxQueued + 1).Find(&records).Error
+		if err != nil {
+			return nil, err
+		}
+		if len(records) > maxQueued {
+			return nil, errGap
+		}
+		events := make([]Event, 0, len(records))
+		for _, r := range records {
+			events = append(events, r.event())
+		}
+		return events, nil
+	})
+}
+
+func (b *PostgresBus) Close() error {
+	b.cancel()
+	<-b.done
+	return nil
+}
+
+// listen keeps a dedicated connection subscribed to the notify channel, reconnecting
+// with a delay when it drops, and prunes expired events once an hour.
+func (b *PostgresBus) listen(ctx context.Context) {
+	defer close(b.done)
+
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				b.db.Where("created_at < ?", time.Now().Add(-retention)).Delete(&eventRecord{})
+			}
+		}
+	}()
+
+	for ctx.Err() == nil {
+		if err := b.listenOnce(ctx); err != nil && ctx.Err() == nil {
+			log.Println("pubsub listener failed, reconnecting:", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(2 * time.Second):
+			}
+		}
+	}
+}
+
+func (b *PostgresBus) listenOnce(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, b.dsn)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+notifyChannel); err != nil {
+		return err
+	}
+
+	// Catch up on what was published while disconnected. Another replica may commit an
+	// event with a lower ID after a higher one was delivered, so look back by time and
+	// skip the events already delivered rather than resuming after the newest ID.
+	if b.newest.IsZero() {
+		b.newest = time.Now()
+	} else {
+		var missed []eventRecord
+		if err := b.db.WithContext(ctx).Where("created_at >= ?", b.newest.Add(-catchUpGrace)).Order("id asc").Find(&missed).Error; err != nil {
+			return err
+		}
+		for _, record := range missed {
+			b.deliver(record)
+		}
+	}
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		id, err := strconv.ParseUint(n.Payload, 10, 64)
+		if err != nil {
+			continue
+		}
+		var record eventRecord
+		if err := b.db.First(&record, id).Error; err != nil {
+			log.Println("pubsub: load event failed:", err)
+			continue
+		}
+		b.deliver(record)
+	}
+}
+
+// deliver forwards an event once, however often a notification or catch-up loads it.
+func (b *PostgresBus) deliver(record eventRecord) {
+	if _, ok := b.recent[record.ID]; ok {
+		return
+	}
+	b.recent[record.ID] = record.CreatedAt
+	if record.CreatedAt.After(b.newest) {
+		b.newest = record.CreatedAt
+		for id, at := range b.recent {
+			if at.Before(b.newest.Add(-catchUpGrace)) {
+				delete(b.recent, id)
+			}
+		}
+	}
+	b.hub.deliver(record.event())
+}
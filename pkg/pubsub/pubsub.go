@@ -0,0 +1,169 @@
+// Package pubsub is a small topic-based event bus used to push changes to
+// long-lived clients such as Server-Sent Events streams.
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+// Event is a message published on a topic. IDs increase over time, so a client
+// can resume a stream from the last ID it has seen.
+type Event struct {
+	ID    uint64          `json:"id"`
+	Topic string          `json:"topic"`
+	Type  string          `json:"type"`
+	Data  json.RawMessage `json:"data"`
+}
+
+type Bus interface {
+	// Publish sends data, encoded as JSON, to every subscriber of topic.
+	Publish(ctx context.Context, topic, eventType string, data interface{}) error
+	// Subscribe streams the events published on topic until ctx is done. When lastEventID
+	// is non-zero, retained events published after it are replayed first. When events
+	// were missed, because there are more to replay than maxQueued, they are no longer
+	// retained, or the subscriber fell too far behind, a TypeReset event is sent and the
+	// channel is closed.
+	Subscribe(ctx context.Context, topic string, lastEventID uint64) (<-chan Event, error)
+	Close() error
+}
+
+// TypeReset is the type of the event ending a stream that missed events. It has no ID:
+// the client should reload its state rather than resume.
+const TypeReset = "reset"
+
+// maxQueued is how many events are replayed to a resuming subscriber, and how many
+// undelivered events a subscriber may accumulate before it is dropped.
+const maxQueued = 256
+
+// errGap is returned by a backlog that cannot replay every event after the one requested.
+var errGap = errors.New("pubsub: events missed")
+
+// hub fans events out to the local subscribers of each topic.
+type hub struct {
+	mu     sync.Mutex
+	topics map[string]map[*subscriber]struct{}
+}
+
+type subscriber struct {
+	mu       sync.Mutex
+	queue    []Event
+	overflow bool
+	signal   chan struct{}
+}
+
+func newHub() *hub {
+	return &hub{topics: make(map[string]map[*subscriber]struct{})}
+}
+
+func (h *hub) deliver(ev Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.topics[ev.Topic] {
+		sub.push(ev)
+	}
+}
+
+func (s *subscriber) push(ev Event) {
+	s.mu.Lock()
+	if len(s.queue) >= maxQueued {
+		s.overflow = true
+	} else {
+		s.queue = append(s.queue, ev)
+	}
+	s.mu.Unlock()
+
+	select {
+	case s.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (s *subscriber) drain() ([]Event, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	batch := s.queue
+	s.queue = nil
+	return batch, s.overflow
+}
+
+func (h *hub) remove(topic string, sub *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.topics[topic], sub)
+	if len(h.topics[topic]) == 0 {
+		delete(h.topics, topic)
+	}
+}
+
+// subscribe registers a subscriber before loading the backlog, so nothing published
+// in between is lost, then forwards the backlog followed by live events. A backlog
+// failing with errGap ends the stream with a reset event.
+func (h *hub) subscribe(ctx context.Context, topic string, backlog func() ([]Event, error)) (<-chan Event, error) {
+	sub := &subscriber{signal: make(chan struct{}, 1)}
+	h.mu.Lock()
+	if h.topics[topic] == nil {
+		h.topics[topic] = make(map[*subscriber]struct{})
+	}
+	h.topics[topic][sub] = struct{}{}
+	h.mu.Unlock()
+
+	replay, err := backlog()
+	gap := errors.Is(err, errGap)
+	if err != nil && !gap {
+		h.remove(topic, sub)
+		return nil, err
+	}
+
+	out := make(chan Event)
+	go func() {
+		defer close(out)
+		defer h.remove(topic, sub)
+
+		replayed := make(map[uint64]bool, len(replay))
+		send := func(ev Event) bool {
+			select {
+			case out <- ev:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		reset := func() {
+			send(Event{Topic: topic, Type: TypeReset, Data: json.RawMessage("{}")})
+		}
+		if gap {
+			reset()
+			return
+		}
+		for _, ev := range replay {
+			replayed[ev.ID] = true
+			if !send(ev) {
+				return
+			}
+		}
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sub.signal:
+			}
+			batch, overflow := sub.drain()
+			if overflow {
+				reset()
+				return
+			}
+			for _, ev := range batch {
+				if replayed[ev.ID] {
+					continue
+				}
+				if !send(ev) {
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
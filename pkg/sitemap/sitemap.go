@@ -0,0 +1,80 @@
+// Package sitemap renders sitemaps and sitemap indexes as defined by sitemaps.org.
+package sitemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"time"
+)
+
+// MaxURLs is the most URLs a single sitemap file may list.
+const MaxURLs = 50000
+
+const namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// URL is a page listed in a sitemap, or a sitemap listed in an index.
+type URL struct {
+	Loc     string
+	LastMod time.Time
+}
+
+type urlSet struct {
+	XMLName xml.Name `xml:"urlset"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	URLs    []entry  `xml:"url"`
+}
+
+type index struct {
+	XMLName  xml.Name `xml:"sitemapindex"`
+	XMLNS    string   `xml:"xmlns,attr"`
+	Sitemaps []entry  `xml:"sitemap"`
+}
+
+type entry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// URLSet renders a sitemap of at most MaxURLs pages.
+func URLSet(urls []URL) ([]byte, error) {
+	return marshal(urlSet{XMLNS: namespace, URLs: entries(urls)})
+}
+
+// Index renders a sitemap index pointing at child sitemaps.
+func Index(sitemaps []URL) ([]byte, error) {
+	return marshal(index{XMLNS: namespace, Sitemaps: entries(sitemaps)})
+}
+
+// Gzip compresses a rendered sitemap for serving as .xml.gz.
+func Gzip(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func entries(urls []URL) []entry {
+	out := make([]entry, 0, len(urls))
+	for _, u := range urls {
+		e := entry{Loc: u.Loc}
+		if !u.LastMod.IsZero() {
+			e.LastMod = u.LastMod.UTC().Format(time.RFC3339)
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+func marshal(v interface{}) ([]byte, error) {
+	out, err := xml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
@@ -0,0 +1,104 @@
+package sitemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+type parsed struct {
+	XMLName xml.Name
+	URLs    []struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod"`
+	} `xml:"url"`
+	Sitemaps []struct {
+		Loc string `xml:"loc"`
+	} `xml:"sitemap"`
+}
+
+func parse(t *testing.T, data []byte) parsed {
+	t.Helper()
+	if !bytes.HasPrefix(data, []byte(xml.Header)) {
+		t.Errorf("no XML declaration: %.60s", data)
+	}
+	var doc parsed
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid XML: %v", err)
+	}
+	return doc
+}
+
+func TestURLSet(t *testing.T) {
+	modified := time.Date(2024, 5, 1, 8, 0, 0, 0, time.FixedZone("ICT", 7*3600))
+	out, err := URLSet([]URL{
+		{Loc: "https://blog.example.com/posts/a?x=1&y=2", LastMod: modified},
+		{Loc: "https://blog.example.com/categories/news"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := parse(t, out)
+	if doc.XMLName.Space != namespace || doc.XMLName.Local != "urlset" || len(doc.URLs) != 2 {
+		t.Fatalf("sitemap = %+v", doc)
+	}
+	if doc.URLs[0].Loc != "https://blog.example.com/posts/a?x=1&y=2" || doc.URLs[0].LastMod != "2024-05-01T01:00:00Z" {
+		t.Errorf("first URL = %+v; want the query escaped and the date in UTC", doc.URLs[0])
+	}
+	if bytes.Count(out, []byte("<lastmod>")) != 1 {
+		t.Error("lastmod rendered for a URL without one")
+	}
+}
+
+func TestURLSetHoldsMaxURLs(t *testing.T) {
+	urls := make([]URL, MaxURLs)
+	for i := range urls {
+		urls[i] = URL{Loc: fmt.Sprintf("https://blog.example.com/posts/post-%d", i), LastMod: time.Unix(int64(i), 0)}
+	}
+	out, err := URLSet(urls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// sitemaps.org also limits a file to 50MB uncompressed
+	if len(out) > 50<<20 {
+		t.Errorf("a full sitemap is %d bytes", len(out))
+	}
+	if doc := parse(t, out); len(doc.URLs) != MaxURLs {
+		t.Errorf("%d URLs; want %d", len(doc.URLs), MaxURLs)
+	}
+}
+
+func TestIndex(t *testing.T) {
+	out, err := Index([]URL{{Loc: "https://blog.example.com/sitemaps/posts-1.xml.gz"}, {Loc: "https://blog.example.com/sitemaps/posts-2.xml.gz"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := parse(t, out)
+	if doc.XMLName.Local != "sitemapindex" || doc.XMLName.Space != namespace || len(doc.Sitemaps) != 2 ||
+		doc.Sitemaps[1].Loc != "https://blog.example.com/sitemaps/posts-2.xml.gz" {
+		t.Errorf("index = %+v", doc)
+	}
+}
+
+func TestGzip(t *testing.T) {
+	body, err := URLSet([]URL{{Loc: "https://blog.example.com/"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gzipped, err := Gzip(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(gzipped))
+	if err != nil {
+		t.Fatal(err)
+	}
+	round, err := io.ReadAll(zr)
+	if err != nil || !bytes.Equal(round, body) {
+		t.Errorf("gunzipped = %q, %v; want the sitemap", round, err)
+	}
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocal(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	l, err := NewLocal(filepath.Join(dir, "uploads"), "/uploads/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const key = "media/2024/05/abc.jpg"
+	if err := l.Put(ctx, key, strings.NewReader("first"), 5, "image/jpeg"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Put(ctx, key, strings.NewReader("second"), 6, "image/jpeg"); err != nil {
+		t.Fatal(err)
+	}
+	r, err := l.Get(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(r)
+	r.Close()
+	if string(data) != "second" {
+		t.Errorf("Get = %q; want the replaced object", data)
+	}
+	if url := l.URL(key); url != "/uploads/"+key {
+		t.Errorf("URL = %q", url)
+	}
+	// the temporary files are renamed into place
+	entries, _ := os.ReadDir(filepath.Join(l.Dir(), "media", "2024", "05"))
+	if len(entries) != 1 {
+		t.Errorf("%d files written; want only the object", len(entries))
+	}
+
+	if err := l.Delete(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete = %v; want ErrNotFound", err)
+	}
+	if err := l.Delete(ctx, key); err != nil {
+		t.Errorf("deleting a missing object = %v", err)
+	}
+}
+
+func TestLocalRejectsEscapingKeys(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	l, err := NewLocal(filepath.Join(dir, "uploads"), "/uploads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"", "../secret", "media/../../secret", "/etc/passwd", "media//a.jpg", "media/./a.jpg"} {
+		if err := l.Put(ctx, key, strings.NewReader("x"), 1, ""); err == nil {
+			t.Errorf("Put(%q) accepted", key)
+		}
+		if _, err := l.Get(ctx, key); err == nil || errors.Is(err, ErrNotFound) {
+			t.Errorf("Get(%q) = %v; want the key refused", key, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "secret")); !errors.Is(err, os.ErrNotExist) {
+		t.Error("a file was written outside the directory")
+	}
+}
@@ -0,0 +1,209 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testAccessKey = "AKIDEXAMPLE"
+	testSecretKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+	testRegion    = "eu-west-1"
+)
+
+// fakeS3 is a bucket that checks the Signature V4 of every request as S3 does, from
+// what it received, and keeps objects in memory.
+type fakeS3 struct {
+	t       *testing.T
+	mu      sync.Mutex
+	objects map[string]object
+}
+
+type object struct {
+	data        []byte
+	contentType string
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *httptest.Server) {
+	f := &fakeS3{t: t, objects: make(map[string]object)}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, srv
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if msg := f.verify(r); msg != "" {
+		http.Error(w, "<Error><Code>SignatureDoesNotMatch</Code><Message>"+msg+"</Message></Error>", http.StatusForbidden)
+		return
+	}
+	key := r.URL.Path
+	if key == "/bucket/broken" {
+		http.Error(w, "<Error><Code>InternalError</Code></Error>", http.StatusInternalServerError)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := io.ReadAll(r.Body)
+		if int64(len(data)) != r.ContentLength {
+			http.Error(w, "<Error><Code>IncompleteBody</Code></Error>", http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = object{data: data, contentType: r.Header.Get("Content-Type")}
+	case http.MethodGet:
+		obj, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", obj.contentType)
+		w.Write(obj.data)
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// verify recomputes the signature of r and returns what is wrong with it, if anything.
+func (f *fakeS3) verify(r *http.Request) string {
+	auth := r.Header.Get("Authorization")
+	fields := map[string]string{}
+	for _, field := range strings.Split(strings.TrimPrefix(auth, "AWS4-HMAC-SHA256 "), ", ") {
+		name, value, _ := strings.Cut(field, "=")
+		fields[name] = value
+	}
+	amzDate := r.Header.Get("X-Amz-Date")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 ") || len(amzDate) != 16 {
+		return "malformed authorization"
+	}
+	scope := amzDate[:8] + "/" + testRegion + "/s3/aws4_request"
+	if fields["Credential"] != testAccessKey+"/"+scope {
+		return "credential " + fields["Credential"]
+	}
+
+	signed := strings.Split(fields["SignedHeaders"], ";")
+	if !sort.StringsAreSorted(signed) || !strings.Contains(fields["SignedHeaders"], "host") {
+		return "signed headers " + fields["SignedHeaders"]
+	}
+	var headers strings.Builder
+	for _, name := range signed {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		headers.WriteString(name + ":" + value + "\n")
+	}
+	canonical := strings.Join([]string{r.Method, r.URL.EscapedPath(), r.URL.RawQuery, headers.String(),
+		fields["SignedHeaders"], r.Header.Get("X-Amz-Content-Sha256")}, "\n")
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hexSHA256(canonical)
+
+	key := hmacSHA256([]byte("AWS4"+testSecretKey), amzDate[:8])
+	for _, part := range []string{testRegion, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	want := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	if !hmac.Equal([]byte(fields["Signature"]), []byte(want)) {
+		return "signature"
+	}
+	return ""
+}
+
+func newTestS3(t *testing.T, endpoint, secret string) *S3 {
+	t.Helper()
+	s, err := NewS3(S3Config{Endpoint: endpoint + "/", Region: testRegion, Bucket: "bucket", AccessKey: testAccessKey, SecretKey: secret})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.now = func() time.Time { return time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC) }
+	return s
+}
+
+func TestS3(t *testing.T) {
+	ctx := context.Background()
+	fake, srv := newFakeS3(t)
+	s := newTestS3(t, srv.URL, testSecretKey)
+
+	const key = "media/2024/05/a photo+1.jpg"
+	if err := s.Put(ctx, key, strings.NewReader("jpeg data"), 9, "image/jpeg"); err != nil {
+		t.Fatal(err)
+	}
+	stored, ok := fake.objects["/bucket/"+key]
+	if !ok || string(stored.data) != "jpeg data" || stored.contentType != "image/jpeg" {
+		t.Fatalf("stored objects = %v", fake.objects)
+	}
+
+	r, err := s.Get(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(r)
+	r.Close()
+	if string(data) != "jpeg data" {
+		t.Errorf("Get = %q", data)
+	}
+
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete = %v; want ErrNotFound", err)
+	}
+	if err := s.Delete(ctx, key); err != nil {
+		t.Errorf("deleting a missing object = %v", err)
+	}
+
+	if url := s.URL(key); url != srv.URL+"/bucket/media/2024/05/a%20photo%2B1.jpg" {
+		t.Errorf("URL = %q", url)
+	}
+}
+
+func TestS3Errors(t *testing.T) {
+	ctx := context.Background()
+	_, srv := newFakeS3(t)
+
+	// a request signed with the wrong key is refused, with S3's explanation
+	wrong := newTestS3(t, srv.URL, "not-the-secret")
+	err := wrong.Put(ctx, "a.jpg", strings.NewReader("x"), 1, "image/jpeg")
+	if err == nil || !strings.Contains(err.Error(), "403 Forbidden") || !strings.Contains(err.Error(), "SignatureDoesNotMatch") {
+		t.Errorf("Put with a wrong key = %v; want the 403", err)
+	}
+
+	s := newTestS3(t, srv.URL, testSecretKey)
+	if _, err := s.Get(ctx, "broken"); err == nil || errors.Is(err, ErrNotFound) || !strings.Contains(err.Error(), "500") {
+		t.Errorf("Get on a failing server = %v; want the 500", err)
+	}
+	if err := s.Delete(ctx, "broken"); err == nil {
+		t.Error("Delete on a failing server succeeded")
+	}
+
+	srv.Close()
+	if err := s.Put(ctx, "a.jpg", strings.NewReader("x"), 1, ""); err == nil {
+		t.Error("Put without a server succeeded")
+	}
+
+	if _, err := NewS3(S3Config{Endpoint: srv.URL}); err == nil {
+		t.Error("NewS3 without a bucket succeeded")
+	}
+}
+
+func TestS3PublicURL(t *testing.T) {
+	s, err := NewS3(S3Config{Endpoint: "https://s3.example.com", Bucket: "media", PublicURL: "https://cdn.example.com/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if url := s.URL("a/b c.png"); url != "https://cdn.example.com/a/b%20c.png" {
+		t.Errorf("URL = %q", url)
+	}
+}
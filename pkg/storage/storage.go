@@ -0,0 +1,24 @@
+// Package storage keeps uploaded files behind a small interface so the API can
+// write to the local disk in development and to an S3-compatible bucket in production.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound is returned by Get when no object is stored under the key.
+var ErrNotFound = errors.New("storage: object not found")
+
+// Storage stores objects under slash-separated keys such as "media/2024/05/abc.jpg".
+type Storage interface {
+	// Put stores size bytes read from r under key, replacing any existing object.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the object stored under key. The caller must close it.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the object. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+	// URL returns the public URL the object is served from.
+	URL(key string) string
+}
@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const spanKey = "tracing:span"
+
+// InstrumentDB records a span for every query made through db with a traced context, e.g.
+// db.WithContext(ctx) in a repository. The span carries the SQL with its placeholders, never
+// the values bound to them. A database can only be instrumented once.
+func InstrumentDB(db *gorm.DB) error {
+	return db.Use(gormPlugin{})
+}
+
+// gormPlugin hooks callbacks around each kind of GORM operation, like the metrics plugin.
+type gormPlugin struct{}
+
+func (gormPlugin) Name() string { return "tracing" }
+
+func (gormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	hooks := []struct {
+		operation string
+		before    func(name string, fn func(*gorm.DB)) error
+		after     func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+	for _, hook := range hooks {
+		if err := hook.before("tracing:before_"+hook.operation, startQuery); err != nil {
+			return err
+		}
+		if err := hook.after("tracing:after_"+hook.operation, endQuery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func startQuery(db *gorm.DB) {
+	_, span := Start(db.Statement.Context, "gorm", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemNamePostgreSQL))
+	if span.IsRecording() {
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+// endQuery names the span after the statement and its table, e.g. "SELECT posts", as the
+// SQL is only known once it is built. A query that failed before has the name "gorm".
+func endQuery(db *gorm.DB) {
+	value, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span := value.(trace.Span)
+	sql := db.Statement.SQL.String()
+	operation, _, _ := strings.Cut(strings.TrimSpace(sql), " ")
+	operation = strings.ToUpper(operation)
+	table := db.Statement.Table
+
+	var attributes []attribute.KeyValue
+	if operation != "" {
+		span.SetName(strings.TrimSpace(operation + " " + table))
+		attributes = append(attributes, semconv.DBQueryText(sql), semconv.DBOperationName(operation))
+	}
+	if table != "" {
+		attributes = append(attributes, semconv.DBCollectionName(table))
+	}
+	if operation == "SELECT" && db.Error == nil {
+		attributes = append(attributes, semconv.DBResponseReturnedRows(int(db.RowsAffected)))
+	}
+	span.SetAttributes(attributes...)
+	End(span, db.Error)
+}
@@ -0,0 +1,34 @@
+package tracing
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// untraced are the routes polled by probes and scrapers, whose spans would drown the
+// traces of real requests.
+var untraced = []string{"/healthz", "/readyz", "/metrics", "/swagger/"}
+
+// Middleware starts a server span for every request, named after its route template, and
+// passes it down in the request's context. The trace of the caller is continued when the
+// request has a traceparent header.
+func Middleware(service string, provider trace.TracerProvider) gin.HandlerFunc {
+	return otelgin.Middleware(service,
+		otelgin.WithTracerProvider(provider),
+		otelgin.WithPropagators(Propagator),
+		// requests are already measured by the Prometheus metrics
+		otelgin.WithMeterProvider(noop.NewMeterProvider()),
+		otelgin.WithGinFilter(func(ctx *gin.Context) bool {
+			for _, prefix := range untraced {
+				if strings.HasPrefix(ctx.Request.URL.Path, prefix) {
+					return false
+				}
+			}
+			return true
+		}),
+	)
+}
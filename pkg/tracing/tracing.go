@@ -0,0 +1,47 @@
+// Package tracing records OpenTelemetry spans: one per HTTP request, continuing the trace
+// of the caller given in a W3C traceparent header, with children for the service methods,
+// database queries and emails sent on its behalf.
+//
+// Only the request middleware is given a tracer provider. The other spans are started
+// with the provider of the span in their context, so work done outside of a request, or
+// with a context that does not come from one, is not traced.
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// scope is the instrumentation scope of the spans started by the application.
+const scope = "blog-api"
+
+// Propagator reads and writes the W3C trace context and baggage headers.
+var Propagator propagation.TextMapPropagator = propagation.NewCompositeTextMapPropagator(
+	propagation.TraceContext{},
+	propagation.Baggage{},
+)
+
+// Start starts a span named name as a child of the span in ctx, e.g.
+// "PostService.ListPosts". Without a span in ctx, the returned span does nothing.
+func Start(ctx context.Context, name string, options ...trace.SpanStartOption) (context.Context, trace.Span) {
+	parent := trace.SpanFromContext(ctx)
+	if !parent.SpanContext().IsValid() {
+		return ctx, parent
+	}
+	return parent.TracerProvider().Tracer(scope).Start(ctx, name, options...)
+}
+
+// End ends a span started by Start, marking it failed if err is not nil. A record that
+// was not found is a normal outcome and does not fail the span.
+func End(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,139 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// recorder returns a tracer provider whose spans are kept in memory.
+func recorder(t *testing.T) (*sdktrace.TracerProvider, *tracetest.InMemoryExporter) {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+	return provider, exporter
+}
+
+func find(spans tracetest.SpanStubs, name string) *tracetest.SpanStub {
+	for i := range spans {
+		if spans[i].Name == name {
+			return &spans[i]
+		}
+	}
+	return nil
+}
+
+func attr(span *tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestStartWithoutSpan(t *testing.T) {
+	ctx, span := Start(context.Background(), "PostService.ListPosts")
+	if span.IsRecording() || trace.SpanFromContext(ctx).SpanContext().IsValid() {
+		t.Error("span started without a trace")
+	}
+	End(span, errors.New("ignored"))
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	provider, exporter := recorder(t)
+	r := gin.New()
+	r.Use(Middleware("blog-api", provider))
+	r.GET("/posts/:post_id", func(ctx *gin.Context) {
+		_, span := Start(ctx.Request.Context(), "PostService.GetPostByID")
+		End(span, errors.New("boom"))
+		ctx.Status(http.StatusOK)
+	})
+	r.GET("/healthz", func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/posts/42", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("%d spans recorded; want the request and its service call", len(spans))
+	}
+	server := find(spans, "GET /posts/:post_id")
+	if server == nil {
+		t.Fatalf("no span named after the route in %v", spans)
+	}
+	if server.SpanKind != trace.SpanKindServer || server.SpanContext.TraceID().String() != traceID ||
+		server.Parent.SpanID().String() != "00f067aa0ba902b7" || !server.Parent.IsRemote() {
+		t.Errorf("request span does not continue the caller's trace: %+v", server)
+	}
+	service := find(spans, "PostService.GetPostByID")
+	if service == nil || service.Parent.SpanID() != server.SpanContext.SpanID() {
+		t.Fatalf("service span is not a child of the request span: %+v", service)
+	}
+	if service.Status.Code != codes.Error || len(service.Events) == 0 {
+		t.Errorf("service span status = %+v; want the error recorded", service.Status)
+	}
+}
+
+func TestInstrumentDB(t *testing.T) {
+	// dry runs build the SQL and run the callbacks without a connection
+	db, err := gorm.Open(postgres.Open("postgres://blog@127.0.0.1:1/blog"), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := InstrumentDB(db); err != nil {
+		t.Fatal(err)
+	}
+	type Post struct {
+		ID    uint
+		Title string
+	}
+	provider, exporter := recorder(t)
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "PostService.ListPosts")
+
+	var posts []Post
+	db.WithContext(ctx).Where("title = ?", "secret draft").Find(&posts)
+	db.WithContext(ctx).Create(&Post{Title: "secret draft"})
+	db.Where("title = ?", "untraced").Find(&posts)
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("%d spans recorded; want the two traced queries and their parent", len(spans))
+	}
+	for _, want := range []struct{ name, operation string }{{"SELECT posts", "SELECT"}, {"INSERT posts", "INSERT"}} {
+		span := find(spans, want.name)
+		if span == nil {
+			t.Errorf("no %s span in %v", want.name, spans)
+			continue
+		}
+		if span.Parent.SpanID() != parent.SpanContext().SpanID() || span.SpanKind != trace.SpanKindClient {
+			t.Errorf("%s is not a client span under the service span", want.name)
+		}
+		sql := attr(span, "db.query.text").AsString()
+		if !strings.Contains(sql, "$1") || strings.Contains(sql, "secret") {
+			t.Errorf("%s query text = %q; want placeholders without the values", want.name, sql)
+		}
+		if attr(span, "db.operation.name").AsString() != want.operation || attr(span, "db.collection.name").AsString() != "posts" ||
+			attr(span, "db.system.name").AsString() != "postgresql" {
+			t.Errorf("%s attributes = %v", want.name, span.Attributes)
+		}
+	}
+}
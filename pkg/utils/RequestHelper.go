@@ -3,6 +3,8 @@ package utils
 import (
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -31,6 +33,16 @@ func GetUserIDFromContext(ctx *gin.Context) (uint, bool) {
 	return uint(uid), true
 }
 
+// GetOptionalUserID returns the caller's ID on routes where authentication is optional.
+func GetOptionalUserID(ctx *gin.Context) (uint, bool) {
+	userID, ok := ctx.Get("userID")
+	if !ok {
+		return 0, false
+	}
+	uid, ok := userID.(float64)
+	return uint(uid), ok
+}
+
 func GetPaginationParams(ctx *gin.Context) (page int, pageSize int, ok bool) {
     page = 1
     pageSize = 10
@@ -53,4 +65,31 @@ func GetPaginationParams(ctx *gin.Context) (page int, pageSize int, ok bool) {
         }
     }
     return page, pageSize, true
-}
\ No newline at end of file
+}
+// NotModified sets the validators of a cacheable GET response and reports whether the
+// client's copy is still current, in which case a 304 has been sent and the handler
+// should return. If-None-Match takes precedence over If-Modified-Since.
+func NotModified(ctx *gin.Context, etag string, lastModified time.Time) bool {
+	ctx.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		ctx.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if match := ctx.GetHeader("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				ctx.Status(http.StatusNotModified)
+				return true
+			}
+		}
+		return false
+	}
+	if since, err := http.ParseTime(ctx.GetHeader("If-Modified-Since")); err == nil && !lastModified.IsZero() {
+		if !lastModified.Truncate(time.Second).After(since) {
+			ctx.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
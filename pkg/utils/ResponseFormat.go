@@ -58,6 +58,12 @@ func ParseValidationErrors(err error) map[string]string {
             switch tag {
             case "required":
                 msg = fmt.Sprintf("%s is required", field)
+            case "required_without":
+                msg = fmt.Sprintf("%s is required when %s is not set", field, param)
+            case "excluded_with":
+                msg = fmt.Sprintf("%s cannot be combined with %s", field, param)
+            case "nefield":
+                msg = fmt.Sprintf("%s must be different from %s", field, param)
             case "min":
                 msg = fmt.Sprintf("%s must have at least %s characters", field, param)
             case "max":
@@ -0,0 +1,18 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// botAgent matches the user agents of crawlers, link previewers, monitoring services and
+// HTTP libraries. It is deliberately broad: missing a human view matters less than
+// counting a crawler.
+var botAgent = regexp.MustCompile(`(?i)bot|crawl|spider|slurp|archiver|facebookexternalhit|embedly|preview|headless|lighthouse|pingdom|uptime|monitor|curl|wget|python-|go-http-client|java/|okhttp|axios|node-fetch|httpclient|libwww|scrapy`)
+
+// IsBot reports whether a request with this User-Agent header comes from an automated
+// client. Requests without a user agent are treated as bots.
+func IsBot(userAgent string) bool {
+	userAgent = strings.TrimSpace(userAgent)
+	return userAgent == "" || botAgent.MatchString(userAgent)
+}
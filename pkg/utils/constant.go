@@ -26,6 +26,25 @@ const (
 	ErrCouldNotFetchCategories = "Could not fetch categories"
 	ErrCategoryNotFound        = "Category not found"
 	ErrEndpointNotFound        = "Endpoint not found"
+	ErrCommentsLocked          = "Comments are locked for this post"
+	ErrInvalidParentComment    = "Parent comment does not belong to this post"
+	ErrInvalidNotificationID   = "Invalid notification id"
+	ErrNotificationNotFound    = "Notification not found"
+	ErrInvalidCursorParam      = "Invalid cursor parameter"
+	ErrInvalidLimitParam       = "Invalid limit parameter"
+	ErrInvalidDaysParam        = "Invalid days parameter"
+	ErrInvalidLastEventID      = "Invalid Last-Event-ID"
+	ErrInvalidReaction         = "Unsupported reaction type"
+	ErrBookmarkNotFound        = "Bookmark not found"
+	ErrCannotFollowSelf        = "You cannot follow yourself"
+	ErrFollowNotFound          = "Follow target not found"
+	ErrMissingFile             = "A file must be uploaded in the \"file\" form field"
+	ErrFileTooLarge            = "File is too large"
+	ErrInvalidMediaID          = "Invalid media ID"
+	ErrMediaNotFound           = "Media not found"
+	ErrFeedNotFound            = "Feed not found"
+	ErrSitemapNotFound         = "Sitemap not found"
+	ErrNotReady                = "Service not ready"
 )
 
 const (
@@ -49,6 +68,33 @@ const (
 	MsgCommentCreated         = "Comment created successfully"
 	MsgCommentUpdated         = "Comment updated successfully"
 	MsgCommentDeleted         = "Comment deleted successfully"
+	MsgCommentsLocked         = "Comments locked successfully"
+	MsgCommentsUnlocked       = "Comments unlocked successfully"
+	MsgNotificationsFetched   = "Notifications fetched successfully"
+	MsgNotificationRead       = "Notification marked as read"
+	MsgNotificationsRead      = "All notifications marked as read"
+	MsgPreferencesFetched     = "Notification preferences fetched successfully"
+	MsgPreferencesUpdated     = "Notification preferences updated successfully"
+	MsgReactionUpdated        = "Reaction updated successfully"
+	MsgBookmarkAdded          = "Post bookmarked successfully"
+	MsgBookmarkRemoved        = "Bookmark removed successfully"
+	MsgBookmarksFetched       = "Bookmarks fetched successfully"
+	MsgFollowed               = "Followed successfully"
+	MsgUnfollowed             = "Unfollowed successfully"
+	MsgFeedFetched            = "Feed fetched successfully"
+	MsgPostMetaFetched        = "Post metadata fetched successfully"
+	MsgPostStatsFetched       = "Post statistics fetched successfully"
+	MsgTopPostsFetched        = "Top posts fetched successfully"
+	MsgProfileFetched         = "Profile fetched successfully"
+	MsgProfileUpdated         = "Profile updated successfully"
+	MsgMediaUploaded          = "Media uploaded successfully"
+	MsgMediaFetched           = "Media fetched successfully"
+	MsgAvatarUpdated          = "Avatar updated successfully"
+	MsgEmailChangePending     = "Profile updated; confirm the new email address from the link we sent to it"
+	MsgEmailChanged           = "Email address changed successfully"
+	MsgAlive                  = "Service is alive"
+	MsgReady                  = "Service is ready"
+	MsgVersionFetched         = "Version fetched successfully"
 )
 
 const (
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	scriptBlock = regexp.MustCompile(`(?is)<(script|style)\b.*?</(script|style)>`)
+	htmlTag     = regexp.MustCompile(`<[^>]*>`)
+	codeFence   = regexp.MustCompile("(?m)^\\s*(```|~~~).*$")
+	mdImage     = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLink      = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)`)
+	lineMarker  = regexp.MustCompile(`(?m)^\s{0,3}(#{1,6}\s+|>\s?|[-*+]\s+|\d+\.\s+)`)
+	emphasis    = []*regexp.Regexp{
+		regexp.MustCompile(`\*\*(.+?)\*\*`),
+		regexp.MustCompile(`__(.+?)__`),
+		regexp.MustCompile(`\*(.+?)\*`),
+		regexp.MustCompile(`~~(.+?)~~`),
+		regexp.MustCompile("`([^`]*)`"),
+	}
+)
+
+// StripMarkup turns markdown or HTML content into plain text, keeping the text of links
+// and the alt text of images.
+func StripMarkup(content string) string {
+	text := scriptBlock.ReplaceAllString(content, " ")
+	text = htmlTag.ReplaceAllString(text, " ")
+	text = codeFence.ReplaceAllString(text, "")
+	text = mdImage.ReplaceAllString(text, "$1")
+	text = mdLink.ReplaceAllString(text, "$1")
+	text = lineMarker.ReplaceAllString(text, "")
+	for _, re := range emphasis {
+		text = re.ReplaceAllString(text, "$1")
+	}
+	return strings.Join(strings.Fields(html.UnescapeString(text)), " ")
+}
+
+// Excerpt returns the plain text of content shortened to at most max characters, cut at a
+// word boundary.
+func Excerpt(content string, max int) string {
+	text := StripMarkup(content)
+	if utf8.RuneCountInString(text) <= max {
+		return text
+	}
+	cut := string([]rune(text)[:max])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return cut + "…"
+}
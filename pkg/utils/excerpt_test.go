@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestStripMarkup(t *testing.T) {
+	for _, test := range []struct{ content, want string }{
+		{"# Title\n\nSome **bold**, _plain_ and `code` text.", "Title Some bold, _plain_ and code text."},
+		{"> quoted\n- item one\n2. item two", "quoted item one item two"},
+		{"See [the docs](https://example.com) ![a diagram](/d.png)", "See the docs a diagram"},
+		{"```go\nfmt.Println()\n```", "fmt.Println()"},
+		{"<p>Hello&nbsp;<b>world</b> &amp; co</p><script>alert(1)</script><style>p{}</style>", "Hello world & co"},
+		{"~~old~~ *new*", "old new"},
+	} {
+		if got := StripMarkup(test.content); got != test.want {
+			t.Errorf("StripMarkup(%q) = %q; want %q", test.content, got, test.want)
+		}
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	if got := Excerpt("**Short** post", 20); got != "Short post" {
+		t.Errorf("short content = %q; want it whole", got)
+	}
+	if got := Excerpt("The quick brown fox jumps", 12); got != "The quick…" {
+		t.Errorf("Excerpt = %q; want a cut at the last word boundary", got)
+	}
+	if got := Excerpt("Supercalifragilistic", 5); got != "Super…" {
+		t.Errorf("Excerpt of one long word = %q; want it cut mid-word", got)
+	}
+	// lengths count characters, not bytes
+	vietnamese := strings.Repeat("Tiếng Việt có dấu ", 10)
+	got := Excerpt(vietnamese, 30)
+	if !utf8.ValidString(got) || utf8.RuneCountInString(got) > 31 || !strings.HasSuffix(got, "…") {
+		t.Errorf("Excerpt = %q; want at most 30 characters and the ellipsis", got)
+	}
+}
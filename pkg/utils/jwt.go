@@ -1,33 +1,71 @@
 package utils
 
 import (
-	// "errors"
-	"os"
-	// "strconv"
+	"errors"
+	"strconv"
 	"time"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var JWT_SECRET = []byte(os.Getenv("JWT_SECRET"))
+// Tokens are signed with HS256 using the secret given to each function, the JWT secret of
+// the configuration.
 
-func GenerateToken(userID uint, role string) (string, error){
+func GenerateToken(secret []byte, userID uint, role string) (string, error){
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"user_id" : userID,
 		"role": role,
 		"exp": time.Now().Add(time.Hour * 72).Unix(),
 	})
-	return token.SignedString(JWT_SECRET)
+	return token.SignedString(secret)
 }
 
-func ValidateToken(tokenString string) (*jwt.Token, error){
+func ValidateToken(secret []byte, tokenString string) (*jwt.Token, error){
 	return jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error){
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, jwt.ErrTokenSignatureInvalid
 		}
-		return JWT_SECRET, nil
+		return secret, nil
 	})
 }
 
+// GenerateEmailChangeToken signs a token confirming that userID wants to switch from oldEmail
+// to newEmail. It carries no user_id claim, so it cannot be used to authenticate.
+func GenerateEmailChangeToken(secret []byte, userID uint, oldEmail, newEmail string, duration time.Duration) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":       strconv.FormatUint(uint64(userID), 10),
+		"type":      "email_change",
+		"old_email": oldEmail,
+		"new_email": newEmail,
+		"exp":       time.Now().Add(duration).Unix(),
+	})
+	return token.SignedString(secret)
+}
+
+func ValidateEmailChangeToken(secret []byte, tokenString string) (userID uint, oldEmail, newEmail string, err error) {
+	token, err := ValidateToken(secret, tokenString)
+	if err != nil {
+		return 0, "", "", err
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return 0, "", "", errors.New("invalid token")
+	}
+	if t, ok := claims["type"].(string); !ok || t != "email_change" {
+		return 0, "", "", errors.New("invalid token type")
+	}
+	sub, _ := claims["sub"].(string)
+	id, err := strconv.ParseUint(sub, 10, 64)
+	if err != nil {
+		return 0, "", "", errors.New("invalid subject in token")
+	}
+	oldEmail, _ = claims["old_email"].(string)
+	newEmail, _ = claims["new_email"].(string)
+	if oldEmail == "" || newEmail == "" {
+		return 0, "", "", errors.New("invalid email in token")
+	}
+	return uint(id), oldEmail, newEmail, nil
+}
+
 // func GenerateResetToken(userID uint, duration time.Duration) (string, error) {
 // 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 // 		"user_id": userID,
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// A mention is "@" followed by a valid username, not preceded by a character
+// that would make it part of a word or an email address.
+var mentionPattern = regexp.MustCompile(`(^|[^a-zA-Z0-9_.@-])@([a-zA-Z0-9_-]{3,20})\b`)
+
+// ExtractMentions returns the distinct usernames mentioned in content, in order of appearance.
+func ExtractMentions(content string) []string {
+	var usernames []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		name := match[2]
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		usernames = append(usernames, name)
+	}
+	return usernames
+}
+
+// ProfilePath is the public profile path of a user.
+func ProfilePath(username string) string {
+	return "/users/" + username
+}
+
+// RenderMentions rewrites mentions of the given usernames as markdown links to their profiles.
+// Mentions of anyone else are left as plain text.
+func RenderMentions(content string, usernames map[string]bool) string {
+	if len(usernames) == 0 {
+		return content
+	}
+	return mentionPattern.ReplaceAllStringFunc(content, func(match string) string {
+		at := strings.LastIndex(match, "@")
+		name := match[at+1:]
+		if !usernames[name] {
+			return match
+		}
+		return match[:at] + "[@" + name + "](" + ProfilePath(name) + ")"
+	})
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExtractMentions(t *testing.T) {
+	for _, test := range []struct {
+		content string
+		want    []string
+	}{
+		{"@alice and @bob_1, thanks", []string{"alice", "bob_1"}},
+		{"(@alice) @alice @bob.", []string{"alice", "bob"}},
+		{"@carol-smith: see @dave-", []string{"carol-smith", "dave"}},
+		{"line one\n@erin", []string{"erin"}},
+		// not mentions: an email address, too short or too long, glued to a word or another mention
+		{"mail alice@example.com", nil},
+		{"@al and @abcdefghijklmnopqrstuvwxyz", nil},
+		{"foo@alice x.@bob @alice@bob", []string{"alice"}},
+		{"", nil},
+	} {
+		if got := ExtractMentions(test.content); !slices.Equal(got, test.want) {
+			t.Errorf("ExtractMentions(%q) = %q; want %q", test.content, got, test.want)
+		}
+	}
+}
+
+func TestRenderMentions(t *testing.T) {
+	known := map[string]bool{"alice": true, "bob": true}
+	for _, test := range []struct{ content, want string }{
+		{"hi @alice!", "hi [@alice](/users/alice)!"},
+		{"@bob and @carol", "[@bob](/users/bob) and @carol"},
+		{"(@alice)", "([@alice](/users/alice))"},
+		{"alice@example.com", "alice@example.com"},
+	} {
+		if got := RenderMentions(test.content, known); got != test.want {
+			t.Errorf("RenderMentions(%q) = %q; want %q", test.content, got, test.want)
+		}
+	}
+	if got := RenderMentions("@alice", nil); got != "@alice" {
+		t.Errorf("RenderMentions without users = %q", got)
+	}
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+var (
+	slugPattern     = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+	usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	hasLower        = regexp.MustCompile(`[a-z]`)
+	hasUpper        = regexp.MustCompile(`[A-Z]`)
+	hasDigit        = regexp.MustCompile(`\d`)
+	hasSpecial      = regexp.MustCompile(`[^a-zA-Z0-9]`)
+)
+
+func SlugValidator(fl validator.FieldLevel) bool {
+	return slugPattern.MatchString(fl.Field().String())
+}
+
+func UsernameValidator(fl validator.FieldLevel) bool {
+	return usernamePattern.MatchString(fl.Field().String())
+}
+
+func StrongPasswordValidator(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	if len(value) < 8 {
+		return false
+	}
+	return hasLower.MatchString(value) && hasUpper.MatchString(value) && hasDigit.MatchString(value) && hasSpecial.MatchString(value)
+}
+
+// RegisterValidators adds the custom binding tags used by the request DTOs to gin's
+// validator. Every program that validates DTOs, the server and the CLI alike, calls it
+// once at startup.
+func RegisterValidators() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("unexpected validator engine")
+	}
+	validations := map[string]validator.Func{
+		"slug":      SlugValidator,
+		"username":  UsernameValidator,
+		"strongpwd": StrongPasswordValidator,
+	}
+	for tag, fn := range validations {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateStruct checks a request DTO against its binding tags outside of an HTTP request,
+// returning the same field errors the API would.
+func ValidateStruct(obj interface{}) map[string]string {
+	if err := binding.Validator.ValidateStruct(obj); err != nil {
+		return ParseValidationErrors(err)
+	}
+	return nil
+}
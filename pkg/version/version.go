@@ -0,0 +1,44 @@
+// Package version describes the running build. Commit and BuildTime are set by the linker:
+//
+//	go build -ldflags "-X blog-api/pkg/version.Commit=$(git rev-parse HEAD) \
+//		-X blog-api/pkg/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+var (
+	// Commit is the git commit the binary was built from.
+	Commit string
+	// BuildTime is when the binary was built, in RFC 3339.
+	BuildTime string
+)
+
+// Info is the build information reported by the API.
+type Info struct {
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get returns the build information. Without linker flags, the commit recorded by the go
+// command for builds inside a git checkout is used, if any.
+func Get() Info {
+	info := Info{Commit: Commit, BuildTime: BuildTime, GoVersion: runtime.Version()}
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			if setting.Key == "vcs.revision" && info.Commit == "" {
+				info.Commit = setting.Value
+			}
+		}
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = "unknown"
+	}
+	return info
+}